@@ -331,6 +331,7 @@ func (v *CodeGenerationVisitor) VisitStruct(ctx context.Context, element *domain
 			importsNeeded["github.com/google/uuid"] = true
 		}
 	}
+	addMetadataImports(importsNeeded, element.Metadata)
 
 	// Build imports block if needed
 	var imports strings.Builder
@@ -448,6 +449,7 @@ func {{.FunctionName}}({{.Parameters}}) {{.Returns}} {
 	if strings.Contains(element.Body, "time.Now()") {
 		importsNeeded["time"] = true
 	}
+	addMetadataImports(importsNeeded, element.Metadata)
 
 	// Build imports block if needed
 	if len(importsNeeded) > 0 {
@@ -504,3 +506,32 @@ func {{.FunctionName}}({{.Parameters}}) {{.Returns}} {
 	v.accumulator.AddFile(file)
 	return nil
 }
+
+// VisitFile adds a pre-rendered file to the accumulator as-is
+func (v *CodeGenerationVisitor) VisitFile(ctx context.Context, element *domain.FileElement) error {
+	fmt.Printf("Visiting file: %s\n", element.Name)
+
+	if element.Path == "" {
+		return fmt.Errorf("file element %s has no output path", element.Name)
+	}
+
+	file := domain.GeneratedFile{
+		Path:    element.Path,
+		Content: element.Content,
+		Package: element.Package,
+		Type:    "file",
+		Size:    int64(len(element.Content)),
+	}
+
+	v.accumulator.AddFile(file)
+	return nil
+}
+
+// addMetadataImports adds the comma-separated imports listed in an element's "imports" metadata
+func addMetadataImports(importsNeeded map[string]bool, metadata map[string]string) {
+	for _, imp := range strings.Split(metadata["imports"], ",") {
+		if imp = strings.TrimSpace(imp); imp != "" {
+			importsNeeded[imp] = true
+		}
+	}
+}
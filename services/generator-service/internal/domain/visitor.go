@@ -21,6 +21,7 @@ type CodeElementVisitor interface {
 	VisitInterface(ctx context.Context, element *InterfaceElement) error
 	VisitStruct(ctx context.Context, element *StructElement) error
 	VisitFunction(ctx context.Context, element *FunctionElement) error
+	VisitFile(ctx context.Context, element *FileElement) error
 }
 
 // RepositoryElement represents a repository code element
@@ -139,6 +140,22 @@ func (f *FunctionElement) Accept(visitor CodeElementVisitor) error {
 func (f *FunctionElement) GetType() string { return "function" }
 func (f *FunctionElement) GetName() string { return f.Name }
 
+// FileElement represents a complete, pre-rendered source file
+type FileElement struct {
+	Name     string            `json:"name"`
+	Package  string            `json:"package"`
+	Path     string            `json:"path"`
+	Content  string            `json:"content"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (f *FileElement) Accept(visitor CodeElementVisitor) error {
+	return visitor.VisitFile(context.Background(), f)
+}
+
+func (f *FileElement) GetType() string { return "file" }
+func (f *FileElement) GetName() string { return f.Name }
+
 // Supporting types
 type FieldElement struct {
 	Name string `json:"name"`
@@ -242,6 +259,8 @@ func (r *GenerationRequest) ParseElements() error {
 			element = parseStructElement(rawElement)
 		case "function":
 			element = parseFunctionElement(rawElement)
+		case "file":
+			element = parseFileElement(rawElement)
 		default:
 			continue // Skip unknown types
 		}
@@ -387,6 +406,16 @@ func parseStructElement(raw map[string]interface{}) *StructElement {
 		}
 	}
 
+	// Parse metadata
+	if metadataRaw, ok := raw["metadata"].(map[string]interface{}); ok {
+		element.Metadata = make(map[string]string)
+		for k, v := range metadataRaw {
+			if str, ok := v.(string); ok {
+				element.Metadata[k] = str
+			}
+		}
+	}
+
 	return element
 }
 
@@ -432,6 +461,43 @@ func parseFunctionElement(raw map[string]interface{}) *FunctionElement {
 		}
 	}
 
+	// Parse metadata
+	if metadataRaw, ok := raw["metadata"].(map[string]interface{}); ok {
+		element.Metadata = make(map[string]string)
+		for k, v := range metadataRaw {
+			if str, ok := v.(string); ok {
+				element.Metadata[k] = str
+			}
+		}
+	}
+
+	return element
+}
+
+func parseFileElement(raw map[string]interface{}) *FileElement {
+	element := &FileElement{}
+
+	if name, ok := raw["name"].(string); ok {
+		element.Name = name
+	}
+	if pkg, ok := raw["package"].(string); ok {
+		element.Package = pkg
+	}
+	if body, ok := raw["body"].(string); ok {
+		element.Content = body
+	}
+
+	// Parse metadata; the output path travels as metadata["path"]
+	if metadataRaw, ok := raw["metadata"].(map[string]interface{}); ok {
+		element.Metadata = make(map[string]string)
+		for k, v := range metadataRaw {
+			if str, ok := v.(string); ok {
+				element.Metadata[k] = str
+			}
+		}
+		element.Path = element.Metadata["path"]
+	}
+
 	return element
 }
 
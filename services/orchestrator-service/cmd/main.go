@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
 
 	"go-factory-platform/services/orchestrator-service/internal/application"
 	"go-factory-platform/services/orchestrator-service/internal/interfaces/http/handlers"
@@ -23,6 +24,16 @@ func main() {
 	// Create handlers
 	orchestratorHandler := handlers.NewOrchestratorHandler(orchestratorService)
 
+	// Allow YAML specifications to include files from this directory
+	if specDir := os.Getenv("SPEC_BASE_DIR"); specDir != "" {
+		orchestratorHandler.SetSpecBaseDir(specDir)
+	}
+
+	// Allow introspecting the databases of these comma-separated hosts
+	if hosts := os.Getenv("INTROSPECTION_HOSTS"); hosts != "" {
+		orchestratorHandler.SetIntrospectionHosts(strings.Split(hosts, ",")...)
+	}
+
 	// Setup Gin router
 	router := gin.Default()
 
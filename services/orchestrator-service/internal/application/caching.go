@@ -0,0 +1,592 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// Defaults for configuration.performance.caching
+const (
+	defaultCacheTTL     = 5 * time.Minute
+	defaultCacheSize    = 1000
+	defaultRedisAddress = "localhost:6379"
+)
+
+// redisModule is the client library required by the generated Redis cache
+var redisModule = domain.ModuleDependency{Path: "github.com/redis/go-redis/v9", Version: "v9.7.3"}
+
+// cacheSettings returns the caching configuration of a project with defaults applied. An invalid
+// TTL falls back to the default; ValidateSpecification reports it.
+func (s *OrchestratorService) cacheSettings(spec *domain.ProjectSpecification) (backend string, ttl time.Duration, size int) {
+	backend, ttl, size = "memory", defaultCacheTTL, defaultCacheSize
+	if spec.Configuration.Performance == nil || spec.Configuration.Performance.Caching == nil {
+		return backend, ttl, size
+	}
+
+	caching := spec.Configuration.Performance.Caching
+	if caching.Type != "" {
+		backend = caching.Type
+	}
+	if parsed, err := time.ParseDuration(caching.TTL); err == nil && parsed > 0 {
+		ttl = parsed
+	}
+	if caching.Size > 0 {
+		size = caching.Size
+	}
+	return backend, ttl, size
+}
+
+// usesRedisCache reports whether the project generates the Redis cache
+func (s *OrchestratorService) usesRedisCache(spec *domain.ProjectSpecification) bool {
+	if backend, _, _ := s.cacheSettings(spec); backend != "redis" {
+		return false
+	}
+	for _, entity := range spec.Entities {
+		if s.hasFeature(entity.Features, "cache") {
+			return true
+		}
+	}
+	return false
+}
+
+// durationLiteral renders a duration as a Go expression in the largest whole unit
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// cacheFeatureFiles generates the cache interface, hit/miss metrics and the in-process LRU cache
+// shared by the caching decorators, plus the Redis cache when configured
+func (s *OrchestratorService) cacheFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	backend, ttl, size := s.cacheSettings(spec)
+
+	content := fmt.Sprintf(`package cache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/gob"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults from configuration.performance.caching
+const (
+	DefaultTTL  = %[1]s
+	DefaultSize = %[2]d
+)
+
+// Cache stores encoded values by key for a limited time. Implementations must be safe for
+// concurrent use. LRU is the in-process default; a shared cache such as Redis can be plugged
+// in by implementing this interface.
+type Cache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores a value under key for ttl; a ttl <= 0 never expires
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the values stored under keys
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Metrics counts the cache hits, misses and errors of a decorator
+type Metrics struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+	errors atomic.Int64
+}
+
+// Hits returns the number of lookups served from the cache
+func (m *Metrics) Hits() int64 { return m.hits.Load() }
+
+// Misses returns the number of lookups that went to the repository
+func (m *Metrics) Misses() int64 { return m.misses.Load() }
+
+// Errors returns the number of failed cache operations
+func (m *Metrics) Errors() int64 { return m.errors.Load() }
+
+// HitRatio returns the share of lookups served from the cache, or 0 before the first lookup
+func (m *Metrics) HitRatio() float64 {
+	hits, misses := m.Hits(), m.Misses()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// lookup decodes the value cached under key into dst and counts the hit or miss. Cache
+// failures count as misses, so the repository stays the source of truth.
+func lookup(ctx context.Context, c Cache, m *Metrics, key string, dst interface{}) bool {
+	value, ok, err := c.Get(ctx, key)
+	if err == nil && ok {
+		err = gob.NewDecoder(bytes.NewReader(value)).Decode(dst)
+		if err == nil {
+			m.hits.Add(1)
+			return true
+		}
+	}
+	if err != nil {
+		m.errors.Add(1)
+	}
+	m.misses.Add(1)
+	return false
+}
+
+// store caches value under key. Values are gob-encoded, so every exported field survives
+// the round trip whatever its JSON tags. Failures only skip caching.
+func store(ctx context.Context, c Cache, m *Metrics, key string, value interface{}, ttl time.Duration) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		m.errors.Add(1)
+		return
+	}
+	if err := c.Set(ctx, key, buf.Bytes(), ttl); err != nil {
+		m.errors.Add(1)
+	}
+}
+
+// invalidate removes keys from the cache after a write
+func invalidate(ctx context.Context, c Cache, m *Metrics, keys ...string) error {
+	if err := c.Delete(ctx, keys...); err != nil {
+		m.errors.Add(1)
+		return err
+	}
+	return nil
+}
+
+// LRU is an in-process Cache that evicts the least recently used entry once it holds
+// more than its size, and drops entries when their TTL expires
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // Zero when the entry never expires
+}
+
+var _ Cache = (*LRU)(nil)
+
+// NewLRU creates an LRU cache holding at most size entries; a size <= 0 uses DefaultSize
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &LRU{size: size, entries: make(map[string]*list.Element), order: list.New(), now: time.Now}
+}
+
+// Get returns the value stored under key unless it expired
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set stores a value under key, evicting the least recently used entry when full
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete removes the values stored under keys
+func (c *LRU) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of stored entries, including expired ones not yet dropped
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
+`, durationLiteral(ttl), size)
+
+	elements := []domain.CodeElement{
+		s.newFileElement("cache", "cache", "internal/infrastructure/cache/cache.go", content),
+	}
+	if backend == "redis" {
+		elements = append(elements, s.generateRedisCacheElement())
+	}
+	return elements
+}
+
+// generateRedisCacheElement generates a Cache backed by Redis, shared by every instance of the service
+func (s *OrchestratorService) generateRedisCacheElement() domain.CodeElement {
+	content := `package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Cache backed by Redis, shared by every instance of the service
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ Cache = (*Redis)(nil)
+
+// NewRedis creates a Redis cache whose keys are namespaced by prefix
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Get returns the value stored under key
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores a value under key for ttl
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete removes the values stored under keys
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+`
+
+	return s.newFileElement("redis", "cache", "internal/infrastructure/cache/redis.go", content)
+}
+
+// cacheFeatureElements generates the cache-aside decorator of the entity repository, and its
+// tests when testing is enabled
+func (s *OrchestratorService) cacheFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	key := s.toSnakeCase(name)
+	lower := s.lowerFirst(name)
+
+	// The keys of tenant-scoped entities are those of the tenant of the context, as each tenant
+	// sees its own entities under the same IDs and lists
+	keyImport, listArgs, keyArgs := "", "", ""
+	keys := fmt.Sprintf(`const %[1]sListKey = "%[2]s:list"
+
+func %[1]sKey(id string) string {
+	return "%[2]s:id:" + id
+}`, lower, key)
+	if scoped := s.tenancy(entity, spec); scoped != "" {
+		keyImport, listArgs, keyArgs = "\t\"strconv\"\n", "(ctx)", "ctx, "
+		scope := `strconv.Quote(tenant.ID)`
+		if scoped == "tenant_client" {
+			scope += ` + ":" + strconv.Quote(tenant.ClientID)`
+		}
+		keys = fmt.Sprintf(`// %[1]sScope prefixes the keys of the %[3]s entities of the tenant of ctx
+func %[1]sScope(ctx context.Context) string {
+	tenant, _ := domain.TenantFromContext(ctx)
+	return "%[2]s:" + %[4]s + ":"
+}
+
+func %[1]sListKey(ctx context.Context) string {
+	return %[1]sScope(ctx) + "list"
+}
+
+func %[1]sKey(ctx context.Context, id string) string {
+	return %[1]sScope(ctx) + "id:" + id
+}`, lower, key, name, scope)
+	}
+
+	restore := ""
+	if s.softDeletes(entity) {
+		restore = fmt.Sprintf(`
+// Restore brings back the deleted %[1]s with the given ID and invalidates its cached entries
+func (r *%[1]sRepository) Restore(ctx context.Context, id string) error {
+	if err := r.next.Restore(ctx, id); err != nil {
+		return err
+	}
+	return invalidate(ctx, r.cache, &r.metrics, %[2]sKey(%[3]sid), %[2]sListKey%[4]s)
+}
+`, name, lower, keyArgs, listArgs)
+	}
+
+	content := fmt.Sprintf(`package cache
+
+import (
+	"context"
+%[6]s	"time"
+
+	"%[1]s/internal/domain"
+)
+
+// %[2]sRepository is a cache-aside decorator of domain.%[2]sRepository. GetByID and List are
+// served from the cache when possible; writes invalidate the cached entries they affect.
+type %[2]sRepository struct {
+	next    domain.%[2]sRepository
+	cache   Cache
+	ttl     time.Duration
+	metrics Metrics
+}
+
+var _ domain.%[2]sRepository = (*%[2]sRepository)(nil)
+
+// New%[2]sRepository wraps next with cache; a ttl <= 0 uses DefaultTTL
+func New%[2]sRepository(next domain.%[2]sRepository, cache Cache, ttl time.Duration) *%[2]sRepository {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &%[2]sRepository{next: next, cache: cache, ttl: ttl}
+}
+
+// Metrics returns the hit/miss counters of the decorator
+func (r *%[2]sRepository) Metrics() *Metrics {
+	return &r.metrics
+}
+
+%[7]s
+
+// Create stores a new %[2]s and invalidates the cached list
+func (r *%[2]sRepository) Create(ctx context.Context, item *domain.%[2]s) error {
+	if err := r.next.Create(ctx, item); err != nil {
+		return err
+	}
+	return invalidate(ctx, r.cache, &r.metrics, %[4]sListKey%[8]s)
+}
+
+// GetByID returns the %[2]s with the given ID, from the cache when possible
+func (r *%[2]sRepository) GetByID(ctx context.Context, id string) (*domain.%[2]s, error) {
+	var cached domain.%[2]s
+	if lookup(ctx, r.cache, &r.metrics, %[4]sKey(%[9]sid), &cached) {
+		return &cached, nil
+	}
+
+	item, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	store(ctx, r.cache, &r.metrics, %[4]sKey(%[9]sid), item, r.ttl)
+	return item, nil
+}
+
+// Update replaces an existing %[2]s and invalidates its cached entries
+func (r *%[2]sRepository) Update(ctx context.Context, item *domain.%[2]s) error {
+	if err := r.next.Update(ctx, item); err != nil {
+		return err
+	}
+	return invalidate(ctx, r.cache, &r.metrics, %[4]sKey(%[9]sitem.%[5]s), %[4]sListKey%[8]s)
+}
+
+// Delete removes the %[2]s with the given ID and invalidates its cached entries
+func (r *%[2]sRepository) Delete(ctx context.Context, id string) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	return invalidate(ctx, r.cache, &r.metrics, %[4]sKey(%[9]sid), %[4]sListKey%[8]s)
+}
+
+// List returns all %[2]s entities, from the cache when possible
+func (r *%[2]sRepository) List(ctx context.Context) ([]*domain.%[2]s, error) {
+	var cached []*domain.%[2]s
+	if lookup(ctx, r.cache, &r.metrics, %[4]sListKey%[8]s, &cached) {
+		return cached, nil
+	}
+
+	items, err := r.next.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store(ctx, r.cache, &r.metrics, %[4]sListKey%[8]s, items, r.ttl)
+	return items, nil
+}
+%[10]s`, spec.ModulePath, name, key, lower, id, keyImport, keys, listArgs, keyArgs, restore)
+
+	elements := []domain.CodeElement{
+		s.newFileElement(
+			fmt.Sprintf("%sCachingRepository", name),
+			"cache",
+			fmt.Sprintf("internal/infrastructure/cache/%s_repository.go", key),
+			content,
+		),
+	}
+
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateCacheTestElement(entity, spec))
+	}
+	return elements
+}
+
+// generateCacheTestElement generates tests of the caching decorator over the in-memory repository
+func (s *OrchestratorService) generateCacheTestElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+
+	// The entries of a tenant are not those of another one
+	tenantTest := ""
+	if s.tenancy(entity, spec) != "" {
+		tenantTest = fmt.Sprintf(`
+func Test%[1]sRepositoryScopesEntriesToTenants(t *testing.T) {
+	item := fixtures.New%[1]sBuilder().Build()
+	repo := cache.New%[1]sRepository(memory.New%[1]sRepository(item), cache.NewLRU(0), 0)
+
+	if _, err := repo.GetByID(fixtures.WithTenant(context.Background()), item.%[2]s); err != nil {
+		t.Fatalf("GetByID() error = %%v", err)
+	}
+	other := domain.WithTenant(context.Background(), domain.Tenant{ID: fixtures.NextID(), ClientID: fixtures.NextID()})
+	if _, err := repo.GetByID(other, item.%[2]s); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID() of another tenant error = %%v, want %%v", err, domain.ErrNotFound)
+	}
+}
+`, name, id)
+	}
+
+	content := fmt.Sprintf(`package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"%[1]s/internal/domain"
+	"%[1]s/internal/fixtures"
+	"%[1]s/internal/infrastructure/cache"
+	"%[1]s/internal/infrastructure/memory"
+)
+
+func Test%[2]sRepositoryCachesGetByID(t *testing.T) {
+	ctx := %[4]s
+	item := fixtures.New%[2]sBuilder().Build()
+	repo := cache.New%[2]sRepository(memory.New%[2]sRepository(item), cache.NewLRU(0), 0)
+
+	for i := 0; i < 2; i++ {
+		got, err := repo.GetByID(ctx, item.%[3]s)
+		if err != nil {
+			t.Fatalf("GetByID() error = %%v", err)
+		}
+		if got.%[3]s != item.%[3]s {
+			t.Errorf("GetByID() %[3]s = %%v, want %%v", got.%[3]s, item.%[3]s)
+		}
+	}
+
+	if hits, misses := repo.Metrics().Hits(), repo.Metrics().Misses(); hits != 1 || misses != 1 {
+		t.Errorf("hits = %%d, misses = %%d, want 1 and 1", hits, misses)
+	}
+}
+
+func Test%[2]sRepositoryInvalidatesOnWrite(t *testing.T) {
+	ctx := %[4]s
+	item := fixtures.New%[2]sBuilder().Build()
+	repo := cache.New%[2]sRepository(memory.New%[2]sRepository(item), cache.NewLRU(0), 0)
+
+	if _, err := repo.GetByID(ctx, item.%[3]s); err != nil {
+		t.Fatalf("GetByID() error = %%v", err)
+	}
+	if _, err := repo.List(ctx); err != nil {
+		t.Fatalf("List() error = %%v", err)
+	}
+	if err := repo.Update(ctx, item); err != nil {
+		t.Fatalf("Update() error = %%v", err)
+	}
+	if _, err := repo.GetByID(ctx, item.%[3]s); err != nil {
+		t.Fatalf("GetByID() error = %%v", err)
+	}
+	if _, err := repo.List(ctx); err != nil {
+		t.Fatalf("List() error = %%v", err)
+	}
+	if misses := repo.Metrics().Misses(); misses != 4 {
+		t.Errorf("misses = %%d, want 4 after Update invalidated both entries", misses)
+	}
+
+	if err := repo.Delete(ctx, item.%[3]s); err != nil {
+		t.Fatalf("Delete() error = %%v", err)
+	}
+	if _, err := repo.GetByID(ctx, item.%[3]s); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID() after Delete error = %%v, want %%v", err, domain.ErrNotFound)
+	}
+}
+%[5]s`, spec.ModulePath, name, id, s.testContext(entity, spec), tenantTest)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sCachingRepositoryTest", name),
+		"cache_test",
+		fmt.Sprintf("internal/infrastructure/cache/%s_repository_test.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// cacheBackends are the caching types of configuration.performance.caching; true when an
+// implementation of the Cache interface is generated for the type
+var cacheBackends = map[string]bool{"memory": true, "redis": true, "memcached": false}
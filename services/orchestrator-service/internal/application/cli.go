@@ -0,0 +1,456 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// cobraModule is the command-line library the generated commands are written against
+var cobraModule = domain.ModuleDependency{Path: "github.com/spf13/cobra", Version: "v1.8.1"}
+
+// cliFlagType is the Go type of the option a flag is parsed into and the pflag method defining it
+type cliFlagType struct {
+	goType string
+	define string
+}
+
+// cliFlagTypes are the flag types accepted in FlagSpecification.Type. An empty type is a string.
+var cliFlagTypes = map[string]cliFlagType{
+	"string": {"string", "StringVarP"},
+	"bool":   {"bool", "BoolVarP"},
+	"int":    {"int", "IntVarP"},
+	"array":  {"[]string", "StringSliceVarP"},
+}
+
+// cliReservedHandlers are the names the generated commands package declares itself
+var cliReservedHandlers = map[string]bool{"NewRootCommand": true, "Execute": true, "ErrNotImplemented": true}
+
+// cliCommand is a command of the tree with the names leading to it from the root command
+type cliCommand struct {
+	domain.CommandSpecification
+	Path []string
+}
+
+// GoName returns the identifier fragment of the command (e.g. "user create" -> "UserCreate")
+func (c cliCommand) GoName(s *OrchestratorService) string {
+	return s.toPascalCase(strings.Join(c.Path, " "))
+}
+
+// HandlerName returns the function running the command: its Handler, or Run<GoName> for leaf
+// commands without one. Commands grouping subcommands without a handler only print their help.
+func (c cliCommand) HandlerName(s *OrchestratorService) string {
+	if c.Handler != "" {
+		return s.capitalizeFirst(c.Handler)
+	}
+	if len(c.SubCommands) == 0 {
+		return "Run" + c.GoName(s)
+	}
+	return ""
+}
+
+// usesCLI reports whether the project is a command-line application
+func (s *OrchestratorService) usesCLI(spec *domain.ProjectSpecification) bool {
+	return s.hasFeature(spec.Features, "cli")
+}
+
+// cliCommands flattens the command tree, parents before their subcommands
+func cliCommands(commands []domain.CommandSpecification, parent []string) []cliCommand {
+	var flat []cliCommand
+	for _, command := range commands {
+		path := append(append([]string{}, parent...), command.Name)
+		flat = append(flat, cliCommand{CommandSpecification: command, Path: path})
+		flat = append(flat, cliCommands(command.SubCommands, path)...)
+	}
+	return flat
+}
+
+// binaryName returns the name of the executable (e.g. "MyTool" -> "my-tool")
+func (s *OrchestratorService) binaryName(spec *domain.ProjectSpecification) string {
+	if name := strings.ReplaceAll(s.toSnakeCase(s.toPascalCase(spec.Name)), "_", "-"); name != "" {
+		return name
+	}
+	return "app"
+}
+
+// cliDescriptions splits a description into the one-line summary shown in command lists and the
+// full text shown in the command's help, which is empty when the summary says it all
+func cliDescriptions(description string) (short, long string) {
+	description = strings.TrimSpace(description)
+	short = description
+	if i := strings.IndexAny(short, "\n"); i >= 0 {
+		short = short[:i]
+	}
+	if i := strings.Index(short, ". "); i >= 0 {
+		short = short[:i]
+	}
+	short = strings.TrimSuffix(strings.TrimSpace(short), ".")
+	if short == strings.TrimSuffix(description, ".") {
+		return short, ""
+	}
+	return short, description
+}
+
+// cliFeatureFiles generates the command-line scaffold of the project: the main package, the
+// root command, one file per command of the tree, and their tests when testing is enabled
+func (s *OrchestratorService) cliFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	binary := s.binaryName(spec)
+	commands := cliCommands(spec.Commands, nil)
+
+	elements := []domain.CodeElement{
+		s.newFileElement("main", "main", fmt.Sprintf("cmd/%s/main.go", binary), fmt.Sprintf(`package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"%s/internal/commands"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := commands.Execute(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+`, spec.ModulePath)),
+		s.generateCLIRootElement(spec, binary),
+	}
+	for _, command := range commands {
+		elements = append(elements, s.generateCLICommandElement(command))
+	}
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateCLITestElement(spec, commands))
+	}
+	return elements
+}
+
+// generateCLIRootElement generates the root command, adding the first level of the tree
+func (s *OrchestratorService) generateCLIRootElement(spec *domain.ProjectSpecification, binary string) domain.CodeElement {
+	short, long := cliDescriptions(spec.Description)
+	if short == "" {
+		short = fmt.Sprintf("%s command-line interface", binary)
+	}
+	var add strings.Builder
+	for _, command := range cliCommands(spec.Commands, nil) {
+		if len(command.Path) == 1 {
+			fmt.Fprintf(&add, "\tcmd.AddCommand(new%sCommand())\n", command.GoName(s))
+		}
+	}
+
+	content := fmt.Sprintf(`package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrNotImplemented is returned by the command handlers until they are implemented
+var ErrNotImplemented = errors.New("not implemented")
+
+// NewRootCommand creates the %[1]s command with its subcommands
+func NewRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          %[1]q,
+		Short:        %[2]q,%[3]s
+		SilenceUsage: true,
+	}
+%[4]s	return cmd
+}
+
+// Execute runs the command selected by the command-line arguments
+func Execute(ctx context.Context) error {
+	return NewRootCommand().ExecuteContext(ctx)
+}
+`, binary, short, cliLongField(long), add.String())
+
+	return s.newFileElement("RootCommand", "commands", "internal/commands/root.go", content)
+}
+
+func cliLongField(long string) string {
+	if long == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\t\tLong: %q,", long)
+}
+
+// generateCLICommandElement generates a command with the options its flags are parsed into,
+// and the stub of its handler
+func (s *OrchestratorService) generateCLICommandElement(command cliCommand) domain.CodeElement {
+	name := command.GoName(s)
+	display := strings.Join(command.Path, " ")
+	handler := command.HandlerName(s)
+
+	use := command.Name
+	if usage := strings.TrimSpace(command.Usage); usage != "" {
+		use = usage
+		if first := strings.Fields(usage)[0]; first != command.Name {
+			use = command.Name + " " + usage
+		}
+	}
+	short, long := cliDescriptions(command.Description)
+
+	var options, flags strings.Builder
+	for _, flag := range command.Flags {
+		flagType := cliFlagTypes[flag.Type]
+		if flag.Type == "" {
+			flagType = cliFlagTypes["string"]
+		}
+		field := s.toPascalCase(flag.Name)
+		if flag.Description != "" {
+			fmt.Fprintf(&options, "\t// %s\n", flag.Description)
+		}
+		fmt.Fprintf(&options, "\t%s %s\n", field, flagType.goType)
+
+		usage := flag.Description
+		if flag.Required {
+			usage = strings.TrimSpace(usage + " (required)")
+		}
+		fmt.Fprintf(&flags, "\tcmd.Flags().%s(&opts.%s, %q, %q, %s, %q)\n",
+			flagType.define, field, flag.Name, flag.Short, cliDefaultLiteral(flag), usage)
+		if flag.Required {
+			fmt.Fprintf(&flags, "\tcobra.CheckErr(cmd.MarkFlagRequired(%q))\n", flag.Name)
+		}
+	}
+	for _, sub := range command.SubCommands {
+		fmt.Fprintf(&flags, "\tcmd.AddCommand(new%sCommand())\n", s.toPascalCase(display+" "+sub.Name))
+	}
+
+	imports := map[string]bool{"github.com/spf13/cobra": true}
+	run, stub := "", ""
+	if handler != "" {
+		imports["context"], imports["fmt"] = true, true
+		run = fmt.Sprintf(`
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return %s(cmd.Context(), opts, args)
+		},`, handler)
+		stub = fmt.Sprintf(`
+// %[1]s runs the %[2]q command with its parsed flags and positional arguments
+func %[1]s(ctx context.Context, opts *%[3]sOptions, args []string) error {
+	// TODO: implement the %[2]q command
+	return fmt.Errorf("%[2]s: %%w", ErrNotImplemented)
+}
+`, handler, display, name)
+	}
+	// Commands only grouping subcommands have no options to parse
+	var optionsType, optsVar string
+	if handler != "" || len(command.Flags) > 0 {
+		optionsType = fmt.Sprintf(`
+// %[1]sOptions holds the flags of the %[2]q command
+type %[1]sOptions struct {
+%[3]s}
+`, name, display, options.String())
+		optsVar = fmt.Sprintf("\topts := &%sOptions{}\n", name)
+	}
+
+	content := fmt.Sprintf(`package commands
+
+import (
+%[3]s)
+%[4]s
+// new%[1]sCommand creates the %[2]q command
+func new%[1]sCommand() *cobra.Command {
+%[5]s	cmd := &cobra.Command{
+		Use:   %[6]q,
+		Short: %[7]q,%[8]s%[9]s
+	}
+%[10]s	return cmd
+}
+%[11]s`, name, display, s.formatImports(imports), optionsType, optsVar, use, short, cliLongField(long), run, flags.String(), stub)
+
+	return s.newFileElement(
+		name+"Command",
+		"commands",
+		fmt.Sprintf("internal/commands/%s.go", strings.Join(command.Path, "_")),
+		content,
+	)
+}
+
+// cliDefaultLiteral returns the Go literal of the default value of a flag. Defaults that don't
+// parse, which the specification validation reports, fall back to the zero value.
+func cliDefaultLiteral(flag domain.FlagSpecification) string {
+	switch flag.Type {
+	case "bool":
+		value, _ := strconv.ParseBool(flag.Default)
+		return strconv.FormatBool(value)
+	case "int":
+		value, _ := strconv.Atoi(flag.Default)
+		return strconv.Itoa(value)
+	case "array":
+		values := cliArrayDefault(flag.Default)
+		if len(values) == 0 {
+			return "nil"
+		}
+		quoted := make([]string, len(values))
+		for i, value := range values {
+			quoted[i] = strconv.Quote(value)
+		}
+		return "[]string{" + strings.Join(quoted, ", ") + "}"
+	default:
+		return strconv.Quote(flag.Default)
+	}
+}
+
+// cliDefValue returns the default of a flag as pflag prints it in help texts
+func cliDefValue(flag domain.FlagSpecification) string {
+	switch flag.Type {
+	case "bool":
+		value, _ := strconv.ParseBool(flag.Default)
+		return strconv.FormatBool(value)
+	case "int":
+		value, _ := strconv.Atoi(flag.Default)
+		return strconv.Itoa(value)
+	case "array":
+		return "[" + strings.Join(cliArrayDefault(flag.Default), ",") + "]"
+	default:
+		return flag.Default
+	}
+}
+
+// cliArrayDefault splits the comma-separated default of an array flag
+func cliArrayDefault(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// generateCLITestElement tests the help texts, required flags, shorthands and defaults of the
+// command tree
+func (s *OrchestratorService) generateCLITestElement(spec *domain.ProjectSpecification, commands []cliCommand) domain.CodeElement {
+	var help, required, flags strings.Builder
+	for _, command := range commands {
+		args := make([]string, len(command.Path))
+		for i, name := range command.Path {
+			args[i] = strconv.Quote(name)
+		}
+		path := strings.Join(args, ", ")
+
+		want, _ := cliDescriptions(command.Description)
+		if want == "" {
+			want = command.Name
+		}
+		fmt.Fprintf(&help, "\t\t{[]string{%s, \"--help\"}, %q},\n", path, want)
+		if len(command.Path) == 1 {
+			fmt.Fprintf(&help, "\t\t{[]string{\"--help\"}, %q},\n", command.Name)
+		}
+
+		var names []string
+		for _, flag := range command.Flags {
+			if flag.Required {
+				names = append(names, strconv.Quote(flag.Name))
+			}
+			fmt.Fprintf(&flags, "\t\t{[]string{%s}, %q, %q, %q},\n", path, flag.Name, flag.Short, cliDefValue(flag))
+		}
+		if len(names) > 0 && command.HandlerName(s) != "" {
+			fmt.Fprintf(&required, "\t\t{[]string{%s}, []string{%s}},\n", path, strings.Join(names, ", "))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `package commands_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"%s/internal/commands"
+)
+
+func execute(args ...string) (string, error) {
+	root := commands.NewRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestHelp(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--help"}, "Usage:"},
+%s	}
+	for _, tt := range tests {
+		out, err := execute(tt.args...)
+		if err != nil {
+			t.Fatalf("%%v: error = %%v", tt.args, err)
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%%v: output does not mention %%q:\n%%s", tt.args, tt.want, out)
+		}
+	}
+}
+`, spec.ModulePath, help.String())
+
+	if required.Len() > 0 {
+		fmt.Fprintf(&b, `
+func TestRequiredFlags(t *testing.T) {
+	tests := []struct {
+		args  []string
+		flags []string
+	}{
+%s	}
+	for _, tt := range tests {
+		_, err := execute(tt.args...)
+		if err == nil {
+			t.Fatalf("%%v: error = nil, want missing required flags", tt.args)
+		}
+		for _, flag := range tt.flags {
+			if !strings.Contains(err.Error(), strconv.Quote(flag)) {
+				t.Errorf("%%v: error %%q does not mention flag %%q", tt.args, err, flag)
+			}
+		}
+	}
+}
+`, required.String())
+	}
+
+	if flags.Len() > 0 {
+		fmt.Fprintf(&b, `
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		command   []string
+		flag      string
+		shorthand string
+		defValue  string
+	}{
+%s	}
+	for _, tt := range tests {
+		cmd, _, err := commands.NewRootCommand().Find(tt.command)
+		if err != nil {
+			t.Fatalf("Find(%%v) error = %%v", tt.command, err)
+		}
+		flag := cmd.Flags().Lookup(tt.flag)
+		if flag == nil {
+			t.Fatalf("%%v: flag %%q is not defined", tt.command, tt.flag)
+		}
+		if flag.Shorthand != tt.shorthand || flag.DefValue != tt.defValue {
+			t.Errorf("%%v --%%s: shorthand %%q, default %%q, want %%q, %%q", tt.command, tt.flag, flag.Shorthand, flag.DefValue, tt.shorthand, tt.defValue)
+		}
+	}
+}
+`, flags.String())
+	}
+
+	content := b.String()
+	if required.Len() > 0 {
+		content = strings.Replace(content, "\t\"strings\"\n", "\t\"strconv\"\n\t\"strings\"\n", 1)
+	}
+	return s.newFileElement("CommandsTest", "commands_test", "internal/commands/commands_test.go", content)
+}
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedCLI(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "inventory-tool",
+		ModulePath:  "example.com/inventory",
+		ProjectType: "cli",
+		Features:    []string{"testing"},
+		Commands: []domain.CommandSpecification{
+			{
+				Name:        "items",
+				Description: "Manage items",
+				SubCommands: []domain.CommandSpecification{{
+					Name:        "import",
+					Description: "Import items from files. Existing items are updated.",
+					Handler:     "ImportItems",
+					Flags: []domain.FlagSpecification{
+						{Name: "file", Short: "f", Type: "array", Required: true, Description: "Files to import"},
+						{Name: "batch-size", Type: "int", Default: "100", Description: "Items per batch"},
+						{Name: "dry-run", Short: "n", Type: "bool", Description: "Report without importing"},
+					},
+				}},
+			},
+			{Name: "version", Description: "Print the version"},
+		},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	for _, path := range []string{"internal/commands/items.go", "internal/commands/items_import.go", "internal/commands/version.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("%s is not generated: %v", path, err)
+		}
+	}
+	handler, err := os.ReadFile(filepath.Join(dir, "internal", "commands", "items_import.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(handler), "func ImportItems(ctx context.Context") {
+		t.Errorf("items import does not call the ImportItems handler:\n%s", handler)
+	}
+
+	// A required flag that is missing fails the command
+	out, err := goCommand(dir, "run", "./cmd/inventory-tool", "items", "import")
+	if err == nil || !strings.Contains(out, `required flag(s) "file" not set`) {
+		t.Errorf("items import without --file: err = %v, output:\n%s", err, out)
+	}
+}
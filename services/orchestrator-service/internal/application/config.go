@@ -0,0 +1,1342 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// sqlDrivers are the modules of the database/sql drivers the generated main imports, by database
+// type of configuration.database. The generated DatabaseConfig.Driver names their drivers.
+var sqlDrivers = map[string]domain.ModuleDependency{
+	"postgres": {Path: "github.com/lib/pq", Version: "v1.10.9"},
+	"mysql":    {Path: "github.com/go-sql-driver/mysql", Version: "v1.8.1"},
+	"sqlite":   {Path: "github.com/mattn/go-sqlite3", Version: "v1.14.22"},
+}
+
+// databaseTypes are the database types accepted in configuration.database.type
+var databaseTypes = map[string]bool{"postgres": true, "mysql": true, "sqlite": true, "mongodb": true}
+
+// logLevels and logFormats are the values accepted in configuration.logging
+var (
+	logLevels  = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+	logFormats = map[string]bool{"json": true, "text": true}
+)
+
+// Defaults of the generated configuration for settings the specification leaves out
+const (
+	defaultServerHost      = "0.0.0.0"
+	defaultServerPort      = 8080
+	defaultGRPCPort        = 9090
+	defaultServerTimeout   = 30 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
+	defaultLogLevel        = "info"
+	defaultLogFormat       = "json"
+)
+
+// defaultDatabasePorts are the ports of the database types when configuration.database.port is 0
+var defaultDatabasePorts = map[string]int{"postgres": 5432, "mysql": 3306, "mongodb": 27017}
+
+// usesConfig reports whether the project gets a configuration package
+func (s *OrchestratorService) usesConfig(spec *domain.ProjectSpecification) bool {
+	return s.hasFeature(spec.Features, "config")
+}
+
+// usesServerMain reports whether the project gets the main package serving its HTTP handlers
+func (s *OrchestratorService) usesServerMain(spec *domain.ProjectSpecification) bool {
+	return s.usesConfig(spec) && !s.usesCLI(spec) && spec.ProjectType != "worker"
+}
+
+// servesEntity reports whether the generated main serves an entity, over REST, GraphQL or gRPC
+func (s *OrchestratorService) servesEntity(entity domain.EntitySpecification, spec *domain.ProjectSpecification) bool {
+	return s.usesServerMain(spec) && (s.hasHandler(entity) || s.servesGraphQL(entity) || s.servesGRPC(entity))
+}
+
+// projectSQLDriver returns the driver of the configured database, if it has one
+func (s *OrchestratorService) projectSQLDriver(spec *domain.ProjectSpecification) (domain.ModuleDependency, bool) {
+	if !s.usesConfig(spec) || s.usesCLI(spec) || spec.Configuration.Database == nil {
+		return domain.ModuleDependency{}, false
+	}
+	driver, ok := sqlDrivers[spec.Configuration.Database.Type]
+	return driver, ok
+}
+
+// envPrefix returns the prefix of the environment variables of the project (e.g. "my-shop" -> "MY_SHOP")
+func (s *OrchestratorService) envPrefix(spec *domain.ProjectSpecification) string {
+	return strings.ToUpper(strings.ReplaceAll(s.binaryName(spec), "-", "_"))
+}
+
+// parseDuration returns a duration of the specification, or fallback when it is empty or invalid,
+// which the specification validation reports
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// stringSliceLiteral renders strings as a Go slice literal, nil when empty
+func stringSliceLiteral(values []string) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// configDefaults renders the body of the generated Default function from configuration
+func (s *OrchestratorService) configDefaults(spec *domain.ProjectSpecification) string {
+	config := spec.Configuration
+
+	host, port, grpcPort, tls, timeout := defaultServerHost, defaultServerPort, defaultGRPCPort, false, defaultServerTimeout
+	var cors domain.CORSConfiguration
+	if server := config.Server; server != nil {
+		if server.Host != "" {
+			host = server.Host
+		}
+		if server.Port != 0 {
+			port = server.Port
+		}
+		if server.GRPCPort != 0 {
+			grpcPort = server.GRPCPort
+		}
+		tls = server.TLS
+		timeout = parseDuration(server.Timeout, timeout)
+		if server.CORS != nil {
+			cors = *server.CORS
+		}
+	}
+
+	var database domain.DatabaseConfiguration
+	var pool domain.PoolConfiguration
+	if config.Database != nil {
+		database = *config.Database
+		if database.Port == 0 {
+			database.Port = defaultDatabasePorts[database.Type]
+		}
+		if database.Host == "" && database.Type != "" && database.Type != "sqlite" {
+			database.Host = "localhost"
+		}
+		if database.Database == "" && database.Type != "" {
+			database.Database = s.toSnakeCase(s.toPascalCase(spec.Name))
+			if database.Type == "sqlite" {
+				database.Database += ".db"
+			}
+		}
+		if database.Pooling != nil {
+			pool = *database.Pooling
+		}
+	}
+
+	level, format, output := defaultLogLevel, defaultLogFormat, []string{"stdout"}
+	if logging := config.Logging; logging != nil {
+		if logging.Level != "" {
+			level = logging.Level
+		}
+		if logging.Format != "" {
+			format = logging.Format
+		}
+		if len(logging.Output) > 0 {
+			output = logging.Output
+		}
+	}
+
+	var monitoring domain.MonitoringConfiguration
+	if config.Monitoring != nil {
+		monitoring = *config.Monitoring
+	} else if s.hasFeature(spec.Features, "monitoring") {
+		// The monitoring feature serves the probes, metrics and tracing unless configured otherwise
+		monitoring = domain.MonitoringConfiguration{Metrics: true, Tracing: true, Health: true}
+	}
+
+	var security domain.SecurityConfiguration
+	var encryption domain.EncryptionConfig
+	var rateLimit domain.RateLimitConfig
+	if config.Security != nil {
+		security = *config.Security
+		if security.Encryption != nil {
+			encryption = *security.Encryption
+		}
+		if security.RateLimit != nil {
+			rateLimit = *security.RateLimit
+		}
+	}
+
+	var performance domain.PerformanceConfiguration
+	var buffering domain.BufferingConfig
+	if config.Performance != nil {
+		performance = *config.Performance
+		if performance.Buffering != nil {
+			buffering = *performance.Buffering
+		}
+	}
+	cacheType, cacheTTL, cacheSize := s.cacheSettings(spec)
+	var cacheAddress string
+	if performance.Caching != nil {
+		cacheAddress = performance.Caching.Address
+	}
+	if cacheAddress == "" && cacheType == "redis" {
+		cacheAddress = defaultRedisAddress
+	}
+
+	duration := func(value string) string {
+		if d := parseDuration(value, 0); d > 0 {
+			return fmt.Sprintf("Duration{%s}", durationLiteral(d))
+		}
+		return "Duration{}"
+	}
+
+	return fmt.Sprintf(`	return &Config{
+		Server: ServerConfig{
+			Host:            %q,
+			Port:            %d,
+			GRPCPort:        %d,
+			TLS:             %t,
+			Timeout:         Duration{%s},
+			ShutdownTimeout: Duration{%s},
+			CORS: CORSConfig{
+				Origins: %s,
+				Methods: %s,
+				Headers: %s,
+			},
+		},
+		Database: DatabaseConfig{
+			Type:     %q,
+			Host:     %q,
+			Port:     %d,
+			Name:     %q,
+			SSLMode:  "disable",
+			Pooling: PoolConfig{
+				MaxOpen:     %d,
+				MaxIdle:     %d,
+				MaxLifetime: %s,
+			},
+		},
+		Logging: LoggingConfig{
+			Level:  %q,
+			Format: %q,
+			Output: %s,
+		},
+		Monitoring: MonitoringConfig{
+			Metrics:   %t,
+			Tracing:   %t,
+			Health:    %t,
+			Profiling: %t,
+		},
+		Security: SecurityConfig{
+			Authentication: %s,
+			Authorization:  %s,
+			JWT: JWTConfig{
+				Algorithm: "HS256",
+			},
+			APIKey: APIKeyConfig{
+				Header: "X-API-Key",
+			},
+			Encryption: EncryptionConfig{
+				Algorithm: %q,
+				KeySize:   %d,
+			},
+			RateLimit: RateLimitConfig{
+				Requests: %d,
+				Window:   %s,
+			},
+		},
+		Performance: PerformanceConfig{
+			Compression: %t,
+			Workers:     %d,
+			Caching: CachingConfig{
+				Type:    %q,
+				TTL:     Duration{%s},
+				Size:    %d,
+				Address: %q,
+			},
+			Buffering: BufferingConfig{
+				Size:    %d,
+				Timeout: %s,
+			},
+		},
+	}
+`,
+		host, port, grpcPort, tls, durationLiteral(timeout), durationLiteral(defaultShutdownTimeout),
+		stringSliceLiteral(cors.Origins), stringSliceLiteral(cors.Methods), stringSliceLiteral(cors.Headers),
+		database.Type, database.Host, database.Port, database.Database,
+		pool.MaxOpen, pool.MaxIdle, duration(pool.MaxLifetime),
+		level, format, stringSliceLiteral(output),
+		monitoring.Metrics, monitoring.Tracing, monitoring.Health, monitoring.Profiling,
+		stringSliceLiteral(security.Authentication), stringSliceLiteral(security.Authorization),
+		encryption.Algorithm, encryption.KeySize,
+		rateLimit.Requests, duration(rateLimit.Window),
+		performance.Compression, performance.Workers,
+		cacheType, durationLiteral(cacheTTL), cacheSize, cacheAddress,
+		buffering.Size, duration(buffering.Timeout),
+	)
+}
+
+// configEnvVars are the settings overridable from the environment, as the suffix of the
+// variable name after the project prefix and the Go expression of the setting
+var configEnvVars = [][2]string{
+	{"SERVER_HOST", "stringVar(&c.Server.Host)"},
+	{"SERVER_PORT", "intVar(&c.Server.Port)"},
+	{"SERVER_GRPC_PORT", "intVar(&c.Server.GRPCPort)"},
+	{"SERVER_TLS", "boolVar(&c.Server.TLS)"},
+	{"SERVER_TLS_CERT_FILE", "stringVar(&c.Server.CertFile)"},
+	{"SERVER_TLS_KEY_FILE", "stringVar(&c.Server.KeyFile)"},
+	{"SERVER_TIMEOUT", "durationVar(&c.Server.Timeout)"},
+	{"SERVER_SHUTDOWN_TIMEOUT", "durationVar(&c.Server.ShutdownTimeout)"},
+	{"SERVER_CORS_ORIGINS", "stringsVar(&c.Server.CORS.Origins)"},
+	{"SERVER_CORS_METHODS", "stringsVar(&c.Server.CORS.Methods)"},
+	{"SERVER_CORS_HEADERS", "stringsVar(&c.Server.CORS.Headers)"},
+	{"DATABASE_TYPE", "stringVar(&c.Database.Type)"},
+	{"DATABASE_HOST", "stringVar(&c.Database.Host)"},
+	{"DATABASE_PORT", "intVar(&c.Database.Port)"},
+	{"DATABASE_NAME", "stringVar(&c.Database.Name)"},
+	{"DATABASE_USER", "stringVar(&c.Database.User)"},
+	{"DATABASE_PASSWORD", "stringVar(&c.Database.Password)"},
+	{"DATABASE_SSL_MODE", "stringVar(&c.Database.SSLMode)"},
+	{"DATABASE_MAX_OPEN", "intVar(&c.Database.Pooling.MaxOpen)"},
+	{"DATABASE_MAX_IDLE", "intVar(&c.Database.Pooling.MaxIdle)"},
+	{"DATABASE_MAX_LIFETIME", "durationVar(&c.Database.Pooling.MaxLifetime)"},
+	{"LOG_LEVEL", "stringVar(&c.Logging.Level)"},
+	{"LOG_FORMAT", "stringVar(&c.Logging.Format)"},
+	{"LOG_OUTPUT", "stringsVar(&c.Logging.Output)"},
+	{"MONITORING_METRICS", "boolVar(&c.Monitoring.Metrics)"},
+	{"MONITORING_TRACING", "boolVar(&c.Monitoring.Tracing)"},
+	{"MONITORING_HEALTH", "boolVar(&c.Monitoring.Health)"},
+	{"MONITORING_PROFILING", "boolVar(&c.Monitoring.Profiling)"},
+	{"SECURITY_AUTHENTICATION", "stringsVar(&c.Security.Authentication)"},
+	{"SECURITY_AUTHORIZATION", "stringsVar(&c.Security.Authorization)"},
+	{"SECURITY_JWT_ALGORITHM", "stringVar(&c.Security.JWT.Algorithm)"},
+	{"SECURITY_JWT_SECRET", "stringVar(&c.Security.JWT.Secret)"},
+	{"SECURITY_JWT_PUBLIC_KEY_FILE", "stringVar(&c.Security.JWT.PublicKeyFile)"},
+	{"SECURITY_JWT_ISSUER", "stringVar(&c.Security.JWT.Issuer)"},
+	{"SECURITY_JWT_AUDIENCE", "stringVar(&c.Security.JWT.Audience)"},
+	{"SECURITY_API_KEY_HEADER", "stringVar(&c.Security.APIKey.Header)"},
+	{"SECURITY_API_KEYS", "stringsVar(&c.Security.APIKey.Keys)"},
+	{"SECURITY_RATE_LIMIT_REQUESTS", "intVar(&c.Security.RateLimit.Requests)"},
+	{"SECURITY_RATE_LIMIT_WINDOW", "durationVar(&c.Security.RateLimit.Window)"},
+	{"PERFORMANCE_COMPRESSION", "boolVar(&c.Performance.Compression)"},
+	{"PERFORMANCE_WORKERS", "intVar(&c.Performance.Workers)"},
+	{"CACHE_TYPE", "stringVar(&c.Performance.Caching.Type)"},
+	{"CACHE_TTL", "durationVar(&c.Performance.Caching.TTL)"},
+	{"CACHE_SIZE", "intVar(&c.Performance.Caching.Size)"},
+	{"CACHE_ADDRESS", "stringVar(&c.Performance.Caching.Address)"},
+	{"BUFFER_SIZE", "intVar(&c.Performance.Buffering.Size)"},
+	{"BUFFER_TIMEOUT", "durationVar(&c.Performance.Buffering.Timeout)"},
+}
+
+// configFeatureFiles generates the configuration package of the project and, for services, the
+// main package starting the HTTP server from it
+func (s *OrchestratorService) configFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	prefix := s.envPrefix(spec)
+	var env strings.Builder
+	for _, v := range configEnvVars {
+		fmt.Fprintf(&env, "\t\t{%q, %s},\n", prefix+"_"+v[0], v[1])
+	}
+
+	content := fmt.Sprintf(`package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of the service. Its JSON form is the configuration section of
+// the project specification.
+type Config struct {
+	Server      ServerConfig      `+"`json:\"server\"`"+`
+	Database    DatabaseConfig    `+"`json:\"database\"`"+`
+	Logging     LoggingConfig     `+"`json:\"logging\"`"+`
+	Monitoring  MonitoringConfig  `+"`json:\"monitoring\"`"+`
+	Security    SecurityConfig    `+"`json:\"security\"`"+`
+	Performance PerformanceConfig `+"`json:\"performance\"`"+`
+}
+
+// ServerConfig configures the HTTP server, and the gRPC server of the entities served over gRPC
+type ServerConfig struct {
+	Host            string     `+"`json:\"host\"`"+`
+	Port            int        `+"`json:\"port\"`"+`
+	GRPCPort        int        `+"`json:\"grpc_port\"`"+`
+	TLS             bool       `+"`json:\"tls\"`"+`
+	CertFile        string     `+"`json:\"cert_file,omitempty\"`"+`
+	KeyFile         string     `+"`json:\"key_file,omitempty\"`"+`
+	Timeout         Duration   `+"`json:\"timeout\"`"+`
+	ShutdownTimeout Duration   `+"`json:\"shutdown_timeout\"`"+`
+	CORS            CORSConfig `+"`json:\"cors\"`"+`
+}
+
+// Address returns the host:port the server listens on
+func (c ServerConfig) Address() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// GRPCAddress returns the host:port the gRPC server listens on
+func (c ServerConfig) GRPCAddress() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.GRPCPort))
+}
+
+// CORSConfig lists the origins, methods and headers allowed in cross-origin requests
+type CORSConfig struct {
+	Origins []string `+"`json:\"origins,omitempty\"`"+`
+	Methods []string `+"`json:\"methods,omitempty\"`"+`
+	Headers []string `+"`json:\"headers,omitempty\"`"+`
+}
+
+// DatabaseConfig configures the database connection. An empty type means no database.
+type DatabaseConfig struct {
+	Type     string     `+"`json:\"type\"`"+`
+	Host     string     `+"`json:\"host\"`"+`
+	Port     int        `+"`json:\"port\"`"+`
+	Name     string     `+"`json:\"database\"`"+`
+	User     string     `+"`json:\"user,omitempty\"`"+`
+	Password string     `+"`json:\"password,omitempty\"`"+`
+	SSLMode  string     `+"`json:\"ssl_mode,omitempty\"`"+`
+	Pooling  PoolConfig `+"`json:\"pooling\"`"+`
+}
+
+// PoolConfig configures the connection pool; zero values keep the database/sql defaults
+type PoolConfig struct {
+	MaxOpen     int      `+"`json:\"max_open\"`"+`
+	MaxIdle     int      `+"`json:\"max_idle\"`"+`
+	MaxLifetime Duration `+"`json:\"max_lifetime\"`"+`
+}
+
+// Driver returns the database/sql driver name of the database type
+func (c DatabaseConfig) Driver() string {
+	if c.Type == "sqlite" {
+		return "sqlite3"
+	}
+	return c.Type
+}
+
+// DSN returns the data source name of the database for its driver
+func (c DatabaseConfig) DSN() string {
+	switch c.Type {
+	case "postgres":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.User, c.Password),
+			Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+			Path:     "/" + c.Name,
+			RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+		}
+		return dsn.String()
+	case "mysql":
+		return fmt.Sprintf("%%s:%%s@tcp(%%s)/%%s?parseTime=true", c.User, c.Password, net.JoinHostPort(c.Host, strconv.Itoa(c.Port)), c.Name)
+	default:
+		return c.Name
+	}
+}
+
+// LoggingConfig configures the logger
+type LoggingConfig struct {
+	Level  string   `+"`json:\"level\"`"+`  // "debug", "info", "warn" or "error"
+	Format string   `+"`json:\"format\"`"+` // "json" or "text"
+	Output []string `+"`json:\"output\"`"+` // "stdout" or "stderr"
+}
+
+// MonitoringConfig enables the monitoring endpoints
+type MonitoringConfig struct {
+	Metrics   bool `+"`json:\"metrics\"`"+`
+	Tracing   bool `+"`json:\"tracing\"`"+`
+	Health    bool `+"`json:\"health\"`"+`
+	Profiling bool `+"`json:\"profiling\"`"+`
+}
+
+// SecurityConfig configures authentication, authorization and rate limiting
+type SecurityConfig struct {
+	Authentication []string         `+"`json:\"authentication,omitempty\"`"+` // Schemes of the entity routes and endpoints without their own
+	Authorization  []string         `+"`json:\"authorization,omitempty\"`"+`
+	JWT            JWTConfig        `+"`json:\"jwt\"`"+`
+	APIKey         APIKeyConfig     `+"`json:\"api_key\"`"+`
+	Encryption     EncryptionConfig `+"`json:\"encryption\"`"+`
+	RateLimit      RateLimitConfig  `+"`json:\"rate_limit\"`"+`
+}
+
+// JWTConfig configures the verification of bearer tokens: HS256, HS384 and HS512 tokens are
+// verified with Secret, RS256, RS384 and RS512 tokens with the PEM public key of PublicKeyFile.
+// Tokens must come from Issuer and be intended for Audience when they are set.
+type JWTConfig struct {
+	Algorithm     string `+"`json:\"algorithm\"`"+`
+	Secret        string `+"`json:\"secret,omitempty\"`"+`
+	PublicKeyFile string `+"`json:\"public_key_file,omitempty\"`"+`
+	Issuer        string `+"`json:\"issuer,omitempty\"`"+`
+	Audience      string `+"`json:\"audience,omitempty\"`"+`
+}
+
+// APIKeyConfig lists the API keys accepted in Header
+type APIKeyConfig struct {
+	Header string   `+"`json:\"header\"`"+`
+	Keys   []string `+"`json:\"keys,omitempty\"`"+`
+}
+
+// EncryptionConfig selects the encryption algorithm and key size
+type EncryptionConfig struct {
+	Algorithm string `+"`json:\"algorithm,omitempty\"`"+`
+	KeySize   int    `+"`json:\"key_size,omitempty\"`"+`
+}
+
+// RateLimitConfig allows Requests per Window; zero requests disables rate limiting
+type RateLimitConfig struct {
+	Requests int      `+"`json:\"requests\"`"+`
+	Window   Duration `+"`json:\"window\"`"+`
+}
+
+// PerformanceConfig configures caching, compression, workers and buffering
+type PerformanceConfig struct {
+	Compression bool            `+"`json:\"compression\"`"+`
+	Workers     int             `+"`json:\"workers\"`"+`
+	Caching     CachingConfig   `+"`json:\"caching\"`"+`
+	Buffering   BufferingConfig `+"`json:\"buffering\"`"+`
+}
+
+// CachingConfig configures the cache
+type CachingConfig struct {
+	Type    string   `+"`json:\"type\"`"+`
+	TTL     Duration `+"`json:\"ttl\"`"+`
+	Size    int      `+"`json:\"size\"`"+`
+	Address string   `+"`json:\"address,omitempty\"`"+`
+}
+
+// BufferingConfig configures buffering
+type BufferingConfig struct {
+	Size    int      `+"`json:\"size\"`"+`
+	Timeout Duration `+"`json:\"timeout\"`"+`
+}
+
+// Duration is a time.Duration written as a string such as "30s" in configuration files
+type Duration struct {
+	time.Duration
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %%w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// Default returns the configuration from the project specification
+func Default() *Config {
+%[1]s}
+
+// Load returns the configuration: the defaults, overridden by the JSON file at path when path
+// is not empty, then by the %[2]s_* environment variables. The result is validated.
+func Load(path string) (*Config, error) {
+	c := Default()
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %%w", err)
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open configuration file: %%w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("failed to read configuration file %%s: %%w", path, err)
+	}
+	return nil
+}
+
+// envVar is an environment variable and the setting it overrides
+type envVar struct {
+	name string
+	set  func(value string) error
+}
+
+func (c *Config) envVars() []envVar {
+	return []envVar{
+%[3]s	}
+}
+
+func (c *Config) loadEnv() error {
+	var errs []error
+	for _, v := range c.envVars() {
+		value, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := v.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%%s: %%w", v.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func stringVar(target *string) func(string) error {
+	return func(value string) error {
+		*target = value
+		return nil
+	}
+}
+
+func intVar(target *int) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.Atoi(value)
+		return err
+	}
+}
+
+func boolVar(target *bool) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.ParseBool(value)
+		return err
+	}
+}
+
+func durationVar(target *Duration) func(string) error {
+	return func(value string) (err error) {
+		target.Duration, err = time.ParseDuration(value)
+		return err
+	}
+}
+
+// stringsVar splits comma-separated values
+func stringsVar(target *[]string) func(string) error {
+	return func(value string) error {
+		*target = nil
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				*target = append(*target, v)
+			}
+		}
+		return nil
+	}
+}
+
+// Validate reports every invalid setting
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port %%d is out of range", c.Server.Port)
+	check(c.Server.GRPCPort > 0 && c.Server.GRPCPort <= 65535, "server.grpc_port %%d is out of range", c.Server.GRPCPort)
+	check(c.Server.GRPCPort != c.Server.Port, "server.grpc_port must differ from server.port")
+	check(c.Server.Timeout.Duration > 0, "server.timeout must be positive")
+	check(c.Server.ShutdownTimeout.Duration > 0, "server.shutdown_timeout must be positive")
+	check(!c.Server.TLS || c.Server.CertFile != "" && c.Server.KeyFile != "", "server.tls requires cert_file and key_file")
+
+	switch c.Database.Type {
+	case "":
+	case "postgres", "mysql", "mongodb":
+		check(c.Database.Host != "", "database.host is required")
+		check(c.Database.Port > 0 && c.Database.Port <= 65535, "database.port %%d is out of range", c.Database.Port)
+		check(c.Database.Name != "", "database.database is required")
+	case "sqlite":
+		check(c.Database.Name != "", "database.database is required")
+	default:
+		check(false, "unknown database.type %%q", c.Database.Type)
+	}
+	pool := c.Database.Pooling
+	check(pool.MaxOpen >= 0 && pool.MaxIdle >= 0 && pool.MaxLifetime.Duration >= 0, "database.pooling settings must not be negative")
+	check(pool.MaxOpen == 0 || pool.MaxIdle <= pool.MaxOpen, "database.pooling.max_idle %%d exceeds max_open %%d", pool.MaxIdle, pool.MaxOpen)
+
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "unknown logging.level %%q", c.Logging.Level)
+	}
+	check(c.Logging.Format == "json" || c.Logging.Format == "text", "unknown logging.format %%q", c.Logging.Format)
+
+	for _, scheme := range c.Security.Authentication {
+		switch scheme {
+		case "jwt", "bearer", "oauth", "oauth2", "api_key", "basic":
+		default:
+			check(false, "unknown security.authentication scheme %%q", scheme)
+		}
+	}
+	switch c.Security.JWT.Algorithm {
+	case "HS256", "HS384", "HS512", "RS256", "RS384", "RS512":
+	default:
+		check(false, "unknown security.jwt.algorithm %%q", c.Security.JWT.Algorithm)
+	}
+	check(c.Security.APIKey.Header != "", "security.api_key.header is required")
+	limit := c.Security.RateLimit
+	check(limit.Requests >= 0, "security.rate_limit.requests must not be negative")
+	check(limit.Requests == 0 || limit.Window.Duration > 0, "security.rate_limit.window must be positive")
+	check(c.Performance.Workers >= 0, "performance.workers must not be negative")
+	check(c.Performance.Caching.Size >= 0 && c.Performance.Caching.TTL.Duration >= 0, "performance.caching settings must not be negative")
+	check(c.Performance.Caching.Type != "redis" || c.Performance.Caching.Address != "", "performance.caching.address is required for redis")
+	check(c.Performance.Buffering.Size >= 0 && c.Performance.Buffering.Timeout.Duration >= 0, "performance.buffering settings must not be negative")
+
+	return errors.Join(errs...)
+}
+`, s.configDefaults(spec), prefix, env.String())
+
+	elements := []domain.CodeElement{s.newFileElement("Config", "config", "internal/config/config.go", content)}
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateConfigTestElement(spec))
+	}
+	// Worker projects run the worker main instead of a server
+	if s.usesServerMain(spec) {
+		elements = append(elements, s.generateServerMainElement(spec))
+	}
+	return elements
+}
+
+// newLoggerFunc is the function of the generated main packages creating their logger
+const newLoggerFunc = `
+// newLogger creates the logger from the logging configuration
+func newLogger(cfg config.LoggingConfig) *slog.Logger {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+
+	output := os.Stdout
+	for _, o := range cfg.Output {
+		if o == "stderr" {
+			output = os.Stderr
+		}
+	}
+	options := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(output, options))
+	}
+	return slog.New(slog.NewJSONHandler(output, options))
+}
+`
+
+// openDatabaseFunc is the function of the generated main packages opening the database pool
+const openDatabaseFunc = `
+// openDatabase opens the connection pool of the database and checks that it is reachable
+func openDatabase(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open(cfg.Driver(), cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.Pooling.MaxOpen)
+	db.SetMaxIdleConns(cfg.Pooling.MaxIdle)
+	db.SetConnMaxLifetime(cfg.Pooling.MaxLifetime.Duration)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to reach database: %w", err)
+	}
+	return db, nil
+}
+`
+
+// newRepositoriesFunc renders the function of the generated main creating the repositories the
+// handlers of the served entities use, and the statement calling it. Repositories are generated
+// for PostgreSQL only: other databases, and PostgreSQL with an empty database.type, store the
+// entities in memory. The events and cache decorators of the entities wrap them, in that order.
+func (s *OrchestratorService) newRepositoriesFunc(served []domain.EntitySpecification, spec *domain.ProjectSpecification, imports map[string]bool) (call, content string) {
+	imports[spec.ModulePath+"/internal/domain"] = true
+	imports[spec.ModulePath+"/internal/infrastructure/memory"] = true
+	postgres := s.usesPostgres(spec)
+
+	var fields, inMemory, stored, published, cached strings.Builder
+	for _, entity := range served {
+		fmt.Fprintf(&fields, "\t%[1]s domain.%[1]sRepository\n", entity.Name)
+		fmt.Fprintf(&inMemory, "\t\t%[1]s: memory.New%[1]sRepository(),\n", entity.Name)
+		fmt.Fprintf(&stored, "\t\t%[1]s: postgres.New%[1]sRepository(db),\n", entity.Name)
+		if s.hasFeature(entity.Features, "events") {
+			fmt.Fprintf(&published, "\trepos.%[1]s = events.New%[1]sRepository(repos.%[1]s, publisher)\n", entity.Name)
+		}
+		if s.hasFeature(entity.Features, "cache") {
+			fmt.Fprintf(&cached, "\trepos.%[1]s = cache.New%[1]sRepository(repos.%[1]s, entityCache, caching.TTL.Duration)\n", entity.Name)
+		}
+	}
+
+	var params, args []string
+	var body strings.Builder
+	doc := "// newRepositories creates in-memory repositories, which lose the entities at exit: database\n// repositories are generated for PostgreSQL only\n"
+	if postgres {
+		imports[spec.ModulePath+"/internal/infrastructure/postgres"] = true
+		params, args = append(params, "db *sql.DB"), append(args, "db")
+		doc = "// newRepositories creates the repositories storing the entities in db, or in-memory ones, which\n// lose the entities at exit, when database.type is empty\n"
+		fmt.Fprintf(&body, `	var repos repositories
+	if db != nil {
+		repos = repositories{
+%s		}
+	} else {
+		slog.Warn("no database is configured; entities are stored in memory and lost at exit")
+		repos = repositories{
+%s		}
+	}
+`, stored.String(), inMemory.String())
+	} else {
+		fmt.Fprintf(&body, `	slog.Warn("entities are stored in memory and lost at exit")
+	repos := repositories{
+%s	}
+`, inMemory.String())
+	}
+
+	var helpers string
+	if published.Len() > 0 {
+		imports[spec.ModulePath+"/internal/infrastructure/events"] = true
+		imports["context"] = true
+		if postgres {
+			imports[spec.ModulePath+"/internal/infrastructure/outbox"] = true
+			body.WriteString(`
+	// Events are recorded in the outbox table after the write they follow commits, in a statement
+	// of their own: a failure in between loses them. Repositories and an outbox.Writer created
+	// with the same *sql.Tx record them atomically. An outbox.Relay with the Sink of the message
+	// broker publishes the recorded events; without one, they stay in the table.
+	var publisher domain.EventPublisher = logPublisher{}
+	if db != nil {
+		publisher = outbox.NewWriter(db)
+	}
+`)
+		} else {
+			body.WriteString("\n\t// Without a PostgreSQL outbox, events are only logged\n\tvar publisher domain.EventPublisher = logPublisher{}\n")
+		}
+		body.WriteString(published.String())
+		helpers += `
+// logPublisher is the domain.EventPublisher logging the events of the entities when no outbox
+// records them
+type logPublisher struct{}
+
+// Publish logs events
+func (logPublisher) Publish(ctx context.Context, published ...domain.Event) error {
+	for _, event := range published {
+		slog.InfoContext(ctx, "event", "name", event.EventName(), "aggregate_id", event.AggregateID())
+	}
+	return nil
+}
+`
+	}
+
+	results := "repositories"
+	ret := "\treturn repos\n"
+	if cached.Len() > 0 {
+		imports[spec.ModulePath+"/internal/infrastructure/cache"] = true
+		params, args = append(params, "caching config.CachingConfig"), append(args, "cfg.Performance.Caching")
+		results, ret = "(repositories, error)", "\treturn repos, nil\n"
+		fmt.Fprintf(&body, `
+	entityCache, err := newCache(caching)
+	if err != nil {
+		return repositories{}, err
+	}
+%s`, cached.String())
+
+		redisCase := ""
+		if s.usesRedisCache(spec) {
+			imports["github.com/redis/go-redis/v9"] = true
+			redisCase = fmt.Sprintf(`	case "redis":
+		return cache.NewRedis(redis.NewClient(&redis.Options{Addr: cfg.Address}), %q), nil
+`, s.binaryName(spec)+":")
+		}
+		helpers += fmt.Sprintf(`
+// newCache creates the cache of the caching decorators from the caching configuration
+func newCache(cfg config.CachingConfig) (cache.Cache, error) {
+	switch cfg.Type {
+	case "", "memory":
+		return cache.NewLRU(cfg.Size), nil
+%s	}
+	return nil, fmt.Errorf("performance.caching.type %%q has no cache; implement cache.Cache for it in newCache", cfg.Type)
+}
+`, redisCase)
+	}
+
+	content = fmt.Sprintf(`
+// repositories are the repositories the entity handlers serve from
+type repositories struct {
+%s}
+
+%sfunc newRepositories(%s) %s {
+%s%s}
+%s`, fields.String(), doc, strings.Join(params, ", "), results, body.String(), ret, helpers)
+
+	call = fmt.Sprintf("\trepos := newRepositories(%s)\n", strings.Join(args, ", "))
+	if cached.Len() > 0 {
+		call = fmt.Sprintf("\trepos, err := newRepositories(%s)\n\tif err != nil {\n\t\treturn err\n\t}\n", strings.Join(args, ", "))
+	}
+	return call, content
+}
+
+// generateServerMainElement generates the main package of a service: it loads the configuration,
+// opens the database pool and serves the HTTP handlers until interrupted
+func (s *OrchestratorService) generateServerMainElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	binary := s.binaryName(spec)
+	prefix := s.envPrefix(spec)
+	imports := map[string]bool{
+		"context": true, "errors": true, "fmt": true, "log/slog": true, "net/http": true, "os": true, "os/signal": true, "syscall": true,
+		spec.ModulePath + "/internal/config": true,
+	}
+
+	var entityRoutes strings.Builder
+	var served []domain.EntitySpecification
+	var graphqlServices, grpcServers strings.Builder
+	schema := s.protoSchema(spec)
+	for _, entity := range spec.Entities {
+		if !s.servesEntity(entity, spec) {
+			continue
+		}
+		served = append(served, entity)
+		if s.hasHandler(entity) {
+			imports[spec.ModulePath+"/internal/interfaces/http/handlers"] = true
+			fmt.Fprintf(&entityRoutes, "\thandlers.New%[1]sHandler(repos.%[1]s).RegisterRoutes(%%s)\n", entity.Name)
+		}
+		if s.servesGraphQL(entity) {
+			fmt.Fprintf(&graphqlServices, "\t\t%[1]s: application.New%[1]sService(repos.%[1]s),\n", entity.Name)
+		}
+		if s.servesGRPC(entity) {
+			fmt.Fprintf(&grpcServers, "\t%[1]s.Register%[2]sServiceServer(grpcServer, servers.New%[2]sServer(application.New%[2]sService(repos.%[2]s)))\n", schema.GoPackage, entity.Name)
+		}
+	}
+	if graphqlServices.Len() > 0 {
+		imports[spec.ModulePath+"/internal/application"] = true
+		imports[spec.ModulePath+"/internal/interfaces/graphql/resolvers"] = true
+		fmt.Fprintf(&entityRoutes, `	graphqlHandler, err := resolvers.NewHandler(resolvers.Services{
+%s	})
+	if err != nil {
+		return err
+	}
+	%%s.Handle("POST /graphql", graphqlHandler)
+`, graphqlServices.String())
+	}
+	endpoints := s.resolveEndpoints(spec, make(map[string]bool))
+	security := s.usesSecurity(spec)
+	observe := s.usesObservability(spec)
+	authenticate := security && (entityRoutes.Len() > 0 || grpcServers.Len() > 0 || secured(endpoints))
+
+	var routes strings.Builder
+	if authenticate {
+		imports["strings"] = true
+		schemes := make(map[string]bool)
+		for _, e := range endpoints {
+			for _, scheme := range e.Schemes {
+				schemes[scheme] = true
+			}
+		}
+		fmt.Fprintf(&routes, `	authenticators, err := newAuthenticators(cfg.Security%s)
+	if err != nil {
+		return err
+	}
+
+`, strings.TrimSuffix(", "+quotedList(sortedKeys(schemes)), ", "))
+	}
+	routes.WriteString("\tmux := http.NewServeMux()\n")
+	if authenticate && entityRoutes.Len() > 0 {
+		routes.WriteString("\t// The entity routes require the configured authentication\n\tentities := http.NewServeMux()\n")
+		routes.WriteString(strings.ReplaceAll(entityRoutes.String(), "%s", "entities"))
+		protected := "entities"
+		if observe {
+			protected = "observability.Route(entities)"
+		}
+		fmt.Fprintf(&routes, "\tvar protected http.Handler = %s\n", protected)
+		// Authenticate runs first, so that the principal is known to ResolveActor and ResolveTenant
+		if s.anyEntity(spec, s.audited) {
+			routes.WriteString("\tprotected = middleware.ResolveActor(protected)\n")
+		}
+		if s.usesTenancy(spec) {
+			routes.WriteString(`	protected = middleware.ResolveTenant(
+		middleware.TenantFromClaims("tenant_id", "client_id"),
+		middleware.TenantFromHeaders("X-Tenant-ID", "X-Client-ID"),
+	)(protected)
+`)
+		}
+		routes.WriteString(`	if len(cfg.Security.Authentication) > 0 {
+		chain, err := authenticators.Of(cfg.Security.Authentication...)
+		if err != nil {
+			return err
+		}
+		protected = middleware.Authenticate(chain...)(protected)
+	}
+	mux.Handle("/", protected)
+`)
+	} else {
+		routes.WriteString(strings.ReplaceAll(entityRoutes.String(), "%s", "mux"))
+	}
+
+	if len(endpoints) > 0 {
+		imports[spec.ModulePath+"/internal/interfaces/http/handlers"] = true
+		names := make(map[string]bool)
+		for _, e := range endpoints {
+			for _, name := range e.Middleware {
+				names[name] = true
+			}
+		}
+		wrappers := "nil"
+		routes.WriteString("\n")
+		if len(names) > 0 {
+			routes.WriteString("\t// The custom endpoints answer 501 until EndpointService is implemented, and their middleware\n\t// let requests through until implemented\n\tpassThrough := func(next http.Handler) http.Handler { return next }\n")
+			entries := make([]string, 0, len(names))
+			for _, name := range sortedKeys(names) {
+				entries = append(entries, fmt.Sprintf("%q: passThrough", name))
+			}
+			fmt.Fprintf(&routes, "\tendpointMiddleware := map[string]handlers.Middleware{%s}\n", strings.Join(entries, ", "))
+			wrappers = "endpointMiddleware"
+		} else {
+			routes.WriteString("\t// The custom endpoints answer 501 until EndpointService is implemented\n")
+		}
+		if secured(endpoints) {
+			wrappers += ", authenticators"
+		}
+		fmt.Fprintf(&routes, "\thandlers.NewEndpointHandler(handlers.UnimplementedEndpointService{}, %s).RegisterRoutes(mux)\n", wrappers)
+	}
+
+	if observe {
+		imports[spec.ModulePath+"/internal/observability"] = true
+		routes.WriteString(`
+	metrics := observability.NewMetrics()
+	if cfg.Monitoring.Health {
+		mux.Handle("GET /health/live", health.LivenessHandler())
+		mux.Handle("GET /health/ready", health.ReadinessHandler())
+	}
+	if cfg.Monitoring.Metrics {
+		mux.Handle("GET /metrics", metrics.Handler())
+	}
+	if cfg.Monitoring.Profiling {
+		observability.RegisterProfiling(mux)
+	}
+`)
+	}
+
+	handler := "mux"
+	if security || observe {
+		handler = "handler"
+		if observe {
+			routes.WriteString("\n\tvar handler http.Handler = observability.Route(mux)\n")
+		} else {
+			routes.WriteString("\n\tvar handler http.Handler = mux\n")
+		}
+	}
+	if security {
+		imports[spec.ModulePath+"/internal/interfaces/http/middleware"] = true
+		routes.WriteString(`	// CORS answers preflight requests before they are rate limited
+	if cfg.Security.RateLimit.Requests > 0 {
+		handler = middleware.NewRateLimiter(cfg.Security.RateLimit.Requests, cfg.Security.RateLimit.Window.Duration).Middleware(handler)
+	}
+	if len(cfg.Server.CORS.Origins) > 0 {
+		handler = middleware.CORS(cfg.Server.CORS.Origins, cfg.Server.CORS.Methods, cfg.Server.CORS.Headers)(handler)
+	}
+`)
+	}
+	if observe {
+		routes.WriteString(`	// Metrics and tracing are outermost, observing the requests the middleware rejects too
+	if cfg.Monitoring.Metrics {
+		handler = metrics.Middleware(handler)
+	}
+	if cfg.Monitoring.Tracing {
+		handler = observability.Tracing(handler)
+	}
+`)
+	}
+
+	database := ""
+	if observe {
+		database = "\n\t// The readiness probe runs the checks of the dependencies added to health\n\thealth := observability.NewHealth()\n"
+	}
+	driver, hasDriver := s.projectSQLDriver(spec)
+	if hasDriver {
+		imports["database/sql"] = true
+		check := ""
+		if observe {
+			check = "\t\thealth.AddCheck(\"database\", db.PingContext)\n"
+		}
+		database += fmt.Sprintf(`
+	// An empty database.type disables the pool
+	var db *sql.DB
+	if cfg.Database.Type != "" {
+		if db, err = openDatabase(ctx, cfg.Database); err != nil {
+			return err
+		}
+		defer db.Close()
+%s	}
+`, check)
+	}
+	var repositoriesFunc string
+	if len(served) > 0 {
+		var call string
+		call, repositoriesFunc = s.newRepositoriesFunc(served, spec, imports)
+		database += call
+	}
+
+	var grpc grpcMain
+	listeners := 1
+	if grpcServers.Len() > 0 {
+		grpc = s.serveGRPC(grpcServers.String(), authenticate, spec, imports)
+		listeners++
+	}
+	importBlock := s.formatImports(imports)
+	if grpcServers.Len() > 0 {
+		importBlock += fmt.Sprintf("\t%s %q\n", schema.GoPackage, schema.GoImport)
+	}
+
+	content := fmt.Sprintf(`package main
+
+import (
+%[1]s)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("%[2]s stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load(os.Getenv("%[3]s_CONFIG_FILE"))
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(newLogger(cfg.Logging))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+%[4]s
+%[5]s%[7]s
+	server := &http.Server{
+		Addr:              cfg.Server.Address(),
+		Handler:           %[6]s,
+		ReadHeaderTimeout: cfg.Server.Timeout.Duration,
+		ReadTimeout:       cfg.Server.Timeout.Duration,
+		WriteTimeout:      cfg.Server.Timeout.Duration,
+	}
+	errc := make(chan error, %[9]d)
+	go func() {
+		slog.Info("listening", "address", server.Addr, "tls", cfg.Server.TLS)
+		if cfg.Server.TLS {
+			errc <- server.ListenAndServeTLS(cfg.Server.CertFile, cfg.Server.KeyFile)
+		} else {
+			errc <- server.ListenAndServe()
+		}
+	}()
+%[8]s
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server failed: %%w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+%[10]s	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("shutdown failed: %%w", err)
+	}
+%[11]s	return nil
+}
+`, importBlock, binary, prefix, database, routes.String(), handler,
+		grpc.setup, grpc.serve, listeners, grpc.stop, grpc.wait)
+	content += newLoggerFunc
+	content += repositoriesFunc
+
+	if hasDriver {
+		content = strings.Replace(content, "\t\"os/signal\"\n", "\t\"os/signal\"\n\t\"time\"\n", 1)
+		content = strings.Replace(content, "\n\t\""+spec.ModulePath+"/internal/config\"", fmt.Sprintf("\n\t_ %q\n\n\t%q", driver.Path, spec.ModulePath+"/internal/config"), 1)
+		content += openDatabaseFunc
+	}
+
+	if authenticate {
+		content += `
+// newAuthenticators creates the authenticators of the schemes and of the configured
+// authentication from the security configuration
+func newAuthenticators(cfg config.SecurityConfig, schemes ...string) (middleware.Authenticators, error) {
+	authenticators := make(middleware.Authenticators)
+	var verifier *middleware.JWTVerifier
+	for _, scheme := range append(schemes, cfg.Authentication...) {
+		if _, ok := authenticators[scheme]; ok {
+			continue
+		}
+		switch scheme {
+		case "jwt", "bearer", "oauth", "oauth2":
+			if verifier == nil {
+				var err error
+				if verifier, err = newJWTVerifier(cfg.JWT); err != nil {
+					return nil, fmt.Errorf("security scheme %s: %w", scheme, err)
+				}
+			}
+			authenticators[scheme] = verifier
+		case "api_key":
+			if len(cfg.APIKey.Keys) == 0 {
+				return nil, fmt.Errorf("security scheme %s: security.api_key.keys is empty", scheme)
+			}
+			authenticators[scheme] = middleware.NewAPIKeyAuthenticator(cfg.APIKey.Header, cfg.APIKey.Keys...)
+		default:
+			return nil, fmt.Errorf("security scheme %s has no authenticator; add it to newAuthenticators", scheme)
+		}
+	}
+	return authenticators, nil
+}
+
+// newJWTVerifier creates the verifier of bearer tokens: RS* algorithms verify them with the
+// public key file, HS* algorithms with the secret
+func newJWTVerifier(cfg config.JWTConfig) (*middleware.JWTVerifier, error) {
+	var verifier *middleware.JWTVerifier
+	if strings.HasPrefix(cfg.Algorithm, "RS") {
+		key, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read security.jwt.public_key_file: %w", err)
+		}
+		if verifier, err = middleware.NewRSAVerifier(cfg.Algorithm, key); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if verifier, err = middleware.NewHMACVerifier(cfg.Algorithm, []byte(cfg.Secret)); err != nil {
+			return nil, err
+		}
+	}
+	return verifier.WithIssuer(cfg.Issuer).WithAudience(cfg.Audience), nil
+}
+`
+	}
+
+	return s.newFileElement("main", "main", fmt.Sprintf("cmd/%s/main.go", binary), content)
+}
+
+// generateConfigTestElement tests the defaults, file and environment overrides and validation of
+// the configuration
+func (s *OrchestratorService) generateConfigTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	prefix := s.envPrefix(spec)
+	host, port, setup := defaultServerHost, defaultServerPort, ""
+	if server := spec.Configuration.Server; server != nil {
+		if server.TLS {
+			setup = fmt.Sprintf("\t// TLS is enabled by default; the certificate is deployment-specific\n\tt.Setenv(%q, \"cert.pem\")\n\tt.Setenv(%q, \"key.pem\")\n\n",
+				prefix+"_SERVER_TLS_CERT_FILE", prefix+"_SERVER_TLS_KEY_FILE")
+		}
+		if server.Host != "" {
+			host = server.Host
+		}
+		if server.Port != 0 {
+			port = server.Port
+		}
+	}
+
+	content := fmt.Sprintf(`package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"%[1]s/internal/config"
+)
+
+func TestDefaultsAreValid(t *testing.T) {
+%[5]s	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %%v", err)
+	}
+	if cfg.Server.Host != %[3]q || cfg.Server.Port != %[4]d {
+		t.Errorf("server = %%s:%%d, want %[3]s:%[4]d", cfg.Server.Host, cfg.Server.Port)
+	}
+}
+
+func TestEnvironmentOverrides(t *testing.T) {
+	t.Setenv("%[2]s_SERVER_PORT", "9000")
+	t.Setenv("%[2]s_SERVER_TIMEOUT", "5s")
+	t.Setenv("%[2]s_LOG_LEVEL", "debug")
+	t.Setenv("%[2]s_SERVER_CORS_ORIGINS", "https://a.example, https://b.example")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %%v", err)
+	}
+	if cfg.Server.Port != 9000 || cfg.Server.Timeout.Duration != 5*time.Second || cfg.Logging.Level != "debug" {
+		t.Errorf("overrides not applied: port %%d, timeout %%v, level %%q", cfg.Server.Port, cfg.Server.Timeout, cfg.Logging.Level)
+	}
+	if len(cfg.Server.CORS.Origins) != 2 || cfg.Server.CORS.Origins[1] != "https://b.example" {
+		t.Errorf("cors origins = %%v", cfg.Server.CORS.Origins)
+	}
+}
+
+func TestFileOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	file := `+"`"+`{"server": {"port": 7070, "timeout": "2s"}, "logging": {"level": "warn"}}`+"`"+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("%[2]s_LOG_LEVEL", "error")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %%v", err)
+	}
+	if cfg.Server.Port != 7070 || cfg.Server.Timeout.Duration != 2*time.Second {
+		t.Errorf("file not applied: port %%d, timeout %%v", cfg.Server.Port, cfg.Server.Timeout)
+	}
+	if cfg.Logging.Level != "error" {
+		t.Errorf("logging.level = %%q, want the environment to override the file", cfg.Logging.Level)
+	}
+}
+
+func TestLoadRejectsInvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{"port out of range", map[string]string{"%[2]s_SERVER_PORT": "70000"}, "", "server.port"},
+		{"not a number", map[string]string{"%[2]s_SERVER_PORT": "http"}, "", "%[2]s_SERVER_PORT"},
+		{"negative timeout", map[string]string{"%[2]s_SERVER_TIMEOUT": "-1s"}, "", "server.timeout"},
+		{"unknown log level", map[string]string{"%[2]s_LOG_LEVEL": "verbose"}, "", "logging.level"},
+		{"tls without certificate", map[string]string{"%[2]s_SERVER_TLS": "true"}, "", "server.tls"},
+		{"idle above open", map[string]string{"%[2]s_DATABASE_MAX_OPEN": "1", "%[2]s_DATABASE_MAX_IDLE": "2"}, "", "max_idle"},
+		{"unsigned tokens", map[string]string{"%[2]s_SECURITY_JWT_ALGORITHM": "none"}, "", "security.jwt.algorithm"},
+		{"unknown file field", nil, `+"`"+`{"server": {"prot": 80}}`+"`"+`, "prot"},
+		{"invalid file duration", nil, `+"`"+`{"server": {"timeout": 30}}`+"`"+`, "duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := ""
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := config.Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %%v, want it to mention %%q", err, tt.wantErr)
+			}
+		})
+	}
+}
+`, spec.ModulePath, prefix, host, port, setup)
+
+	return s.newFileElement("ConfigTest", "config_test", "internal/config/config_test.go", content)
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedMainComposesDecorators(t *testing.T) {
+	tests := []struct {
+		name     string
+		database string
+		caching  string
+		want     []string
+	}{
+		{
+			name:     "postgres outbox and LRU cache",
+			database: "postgres",
+			caching:  "memory",
+			want: []string{
+				"repos, err := newRepositories(db, cfg.Performance.Caching)",
+				"publisher = outbox.NewWriter(db)",
+				"repos.Order = events.NewOrderRepository(repos.Order, publisher)",
+				"repos.Order = cache.NewOrderRepository(repos.Order, entityCache, caching.TTL.Duration)",
+				"return cache.NewLRU(cfg.Size), nil",
+			},
+		},
+		{
+			name:    "logged events and Redis cache",
+			caching: "redis",
+			want: []string{
+				"repos, err := newRepositories(cfg.Performance.Caching)",
+				"var publisher domain.EventPublisher = logPublisher{}",
+				"repos.Order = events.NewOrderRepository(repos.Order, publisher)",
+				"repos.Order = cache.NewOrderRepository(repos.Order, entityCache, caching.TTL.Duration)",
+				`return cache.NewRedis(redis.NewClient(&redis.Options{Addr: cfg.Address}), "shop:"), nil`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &domain.ProjectSpecification{
+				Name:        "shop",
+				ModulePath:  "example.com/shop",
+				ProjectType: "microservice",
+				Features:    []string{"config"},
+				Configuration: domain.ProjectConfiguration{
+					Performance: &domain.PerformanceConfiguration{Caching: &domain.CachingConfiguration{Type: tt.caching}},
+				},
+				Entities: []domain.EntitySpecification{{
+					Name:     "Order",
+					Features: []string{"crud", "rest_api", "events", "cache"},
+					Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+				}},
+			}
+			if tt.database != "" {
+				spec.Configuration.Database = &domain.DatabaseConfiguration{Type: tt.database}
+			}
+
+			dir := checkGeneratedProject(t, spec)
+			main, err := os.ReadFile(filepath.Join(dir, "cmd", "shop", "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(main), want) {
+					t.Errorf("main.go does not contain %s:\n%s", want, main)
+				}
+			}
+			// Events are published by the repository the cache wraps, so that cached reads publish nothing
+			if strings.Index(string(main), "events.NewOrderRepository") > strings.Index(string(main), "cache.NewOrderRepository") {
+				t.Errorf("main.go wraps the cache decorator with the events one:\n%s", main)
+			}
+		})
+	}
+}
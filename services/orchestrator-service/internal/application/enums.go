@@ -0,0 +1,178 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// isTypedEnum reports whether a named Go type is generated for the field
+func (s *OrchestratorService) isTypedEnum(field domain.FieldSpecification) bool {
+	return field.Type == "enum" && len(field.Enum) > 0
+}
+
+// enumTypeName returns the name of the Go type generated for an enum field (e.g. "UserStatus")
+func (s *OrchestratorService) enumTypeName(entity domain.EntitySpecification, field domain.FieldSpecification) string {
+	return entity.Name + s.toPascalCase(field.Name)
+}
+
+// enumConstName returns the name of the constant generated for an enum value (e.g. "UserStatusActive")
+func (s *OrchestratorService) enumConstName(entity domain.EntitySpecification, field domain.FieldSpecification, value string) string {
+	return s.enumTypeName(entity, field) + s.toPascalCase(value)
+}
+
+// toPascalCase converts an arbitrary value to an exported Go identifier fragment
+// (e.g. "in_progress" -> "InProgress", "on-hold" -> "OnHold")
+func (s *OrchestratorService) toPascalCase(value string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// generateEnumElement generates a named string type for an enum field with constants, parsing,
+// JSON and database/sql support
+func (s *OrchestratorService) generateEnumElement(entity domain.EntitySpecification, field domain.FieldSpecification) domain.CodeElement {
+	typeName := s.enumTypeName(entity, field)
+
+	var constants, cases strings.Builder
+	for i, value := range field.Enum {
+		constName := s.enumConstName(entity, field, value)
+		fmt.Fprintf(&constants, "\t%s %s = %s\n", constName, typeName, strconv.Quote(value))
+		if i > 0 {
+			cases.WriteString(", ")
+		}
+		cases.WriteString(constName)
+	}
+	// The allowed values are an argument of the error, never part of its format
+	allowed := strconv.Quote(strings.Join(field.Enum, ", "))
+
+	description := fmt.Sprintf("%s enumerates the allowed values of %s.%s", typeName, entity.Name, s.capitalizeFirst(field.Name))
+	if field.Description != "" {
+		description = fmt.Sprintf("%s: %s", typeName, field.Description)
+	}
+
+	content := fmt.Sprintf(`package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// %[2]s
+type %[1]s string
+
+// Allowed %[1]s values
+const (
+%[3]s)
+
+// %[1]sValues returns every allowed %[1]s value
+func %[1]sValues() []%[1]s {
+	return []%[1]s{%[4]s}
+}
+
+// Parse%[1]s converts a string to a %[1]s, rejecting unknown values
+func Parse%[1]s(value string) (%[1]s, error) {
+	candidate := %[1]s(value)
+	if !candidate.IsValid() {
+		return "", fmt.Errorf("invalid %[1]s %%q: must be one of: %%s", value, %[5]s)
+	}
+	return candidate, nil
+}
+
+// String implements fmt.Stringer
+func (e %[1]s) String() string {
+	return string(e)
+}
+
+// IsValid reports whether e is one of the allowed values
+func (e %[1]s) IsValid() bool {
+	switch e {
+	case %[4]s:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler
+func (e %[1]s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, rejecting unknown values. An empty string
+// is accepted so that optional fields can be omitted.
+func (e *%[1]s) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		*e = ""
+		return nil
+	}
+	parsed, err := Parse%[1]s(value)
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (e *%[1]s) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*e = ""
+		return nil
+	case string:
+		return e.scanString(value)
+	case []byte:
+		return e.scanString(string(value))
+	default:
+		return fmt.Errorf("cannot scan %%T into %[1]s", src)
+	}
+}
+
+func (e *%[1]s) scanString(value string) error {
+	parsed, err := Parse%[1]s(value)
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// Value implements driver.Valuer. The empty value is stored as NULL.
+func (e %[1]s) Value() (driver.Value, error) {
+	if e == "" {
+		return nil, nil
+	}
+	if !e.IsValid() {
+		return nil, fmt.Errorf("invalid %[1]s %%q", string(e))
+	}
+	return string(e), nil
+}
+`, typeName, description, constants.String(), cases.String(), allowed)
+
+	return s.newFileElement(
+		typeName,
+		"domain",
+		fmt.Sprintf("internal/domain/%s_%s.go", s.toSnakeCase(entity.Name), s.toSnakeCase(s.toPascalCase(field.Name))),
+		content,
+	)
+}
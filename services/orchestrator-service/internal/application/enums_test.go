@@ -0,0 +1,86 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// orderLabelTest parses the values of an enum whose values need quoting in Go source
+const orderLabelTest = `package domain
+
+import "testing"
+
+func TestParseOrderLabel(t *testing.T) {
+	for _, value := range []string{` + "`" + `say "hi"` + "`" + `, "50% off", ` + "`" + `back\slash` + "`" + `} {
+		if label, err := ParseOrderLabel(value); err != nil || string(label) != value {
+			t.Errorf("ParseOrderLabel(%q) = %q, %v", value, label, err)
+		}
+	}
+	_, err := ParseOrderLabel("other")
+	want := ` + "`" + `invalid OrderLabel "other": must be one of: say "hi", 50% off, back\slash` + "`" + `
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseOrderLabel(other) error = %v, want %s", err, want)
+	}
+}
+`
+
+func TestValidateEnumConstantNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"distinct", []string{"active", "on-hold"}, ""},
+		{"exact duplicate", []string{"active", "active"}, "duplicate_name"},
+		{"differing by case", []string{"Active", "active"}, "duplicate_name"},
+		{"differing by punctuation", []string{"in-progress", "in_progress"}, "duplicate_name"},
+		{"empty", []string{"active", ""}, "invalid_value"},
+		{"punctuation only", []string{"active", "--"}, "invalid_value"},
+	}
+	s := NewOrchestratorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &domain.ProjectSpecification{
+				Name:        "shop",
+				ModulePath:  "example.com/shop",
+				ProjectType: "microservice",
+				Entities: []domain.EntitySpecification{{
+					Name:   "Order",
+					Fields: []domain.FieldSpecification{{Name: "status", Type: "enum", Enum: tt.values}},
+				}},
+			}
+			var got string
+			for _, issue := range s.ValidateSpecification(spec).Issues {
+				if issue.Path == "/entities/0/fields/0/enum/1" {
+					got = issue.Code
+				}
+			}
+			if got != tt.want {
+				t.Errorf("issue code of %q = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedEnumQuotesValues(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "validation"},
+			Fields:   []domain.FieldSpecification{{Name: "label", Type: "enum", Enum: []string{`say "hi"`, "50% off", `back\slash`}}},
+		}},
+	}
+
+	dir := generateProject(t, NewOrchestratorService(), spec)
+	test := filepath.Join(dir, "internal", "domain", "order_label_test.go")
+	if err := os.WriteFile(test, []byte(orderLabelTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkProject(t, dir)
+}
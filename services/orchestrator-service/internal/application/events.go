@@ -0,0 +1,776 @@
+package application
+
+import (
+	"fmt"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// eventsFeatureFiles generates the event and publisher interfaces, the transactional outbox with
+// its migration and relay, and the in-memory event bus
+func (s *OrchestratorService) eventsFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	elements := []domain.CodeElement{
+		s.generateDomainEventsElement(),
+		s.generateOutboxElement(spec),
+		s.generateOutboxRelayElement(),
+		s.generateOutboxMemoryStoreElement(),
+		s.generateEventBusElement(spec),
+	}
+	elements = append(elements, s.generateOutboxMigrationElements(spec)...)
+
+	if s.hasFeature(spec.Features, "testing") || s.anyEntity(spec, func(entity domain.EntitySpecification) bool {
+		return s.hasFeature(entity.Features, "events") && s.hasTestingFeature(entity, spec)
+	}) {
+		elements = append(elements, s.generateOutboxRelayTestElement(spec))
+	}
+	return elements
+}
+
+// anyEntity reports whether any entity of the project matches
+func (s *OrchestratorService) anyEntity(spec *domain.ProjectSpecification, match func(domain.EntitySpecification) bool) bool {
+	for _, entity := range spec.Entities {
+		if match(entity) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateDomainEventsElement generates the interfaces shared by all domain events
+func (s *OrchestratorService) generateDomainEventsElement() domain.CodeElement {
+	content := `package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Event is something that happened to an entity
+type Event interface {
+	// EventName identifies the kind of event, e.g. "user.created"
+	EventName() string
+	// AggregateID is the ID of the entity the event happened to
+	AggregateID() string
+	// OccurredAt is when the event happened
+	OccurredAt() time.Time
+}
+
+// EventPublisher publishes domain events. The outbox implementation records them in the
+// transaction of the entity write, so that events are never lost nor published for rolled
+// back writes.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+`
+
+	return s.newFileElement("events", "domain", "internal/domain/events.go", content)
+}
+
+// generateOutboxElement generates the outbox message, the writer recording events in the outbox
+// table and the SQL store the relay reads from
+func (s *OrchestratorService) generateOutboxElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := fmt.Sprintf(`package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"%[1]s/internal/domain"
+)
+
+// Message is a domain event stored in the outbox table until the relay publishes it
+type Message struct {
+	ID          int64
+	EventName   string
+	AggregateID string
+	Payload     []byte // JSON encoding of the event
+	OccurredAt  time.Time
+	Attempts    int
+	LastError   string
+}
+
+// DBTX is implemented by *sql.DB and *sql.Tx
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Writer is a domain.EventPublisher that records events in the outbox table. Create it with
+// the transaction of the entity write, so that events are committed or rolled back with it.
+type Writer struct {
+	db DBTX
+}
+
+var _ domain.EventPublisher = (*Writer)(nil)
+
+// NewWriter creates a writer recording events through db, usually a *sql.Tx
+func NewWriter(db DBTX) *Writer {
+	return &Writer{db: db}
+}
+
+// Publish records events in the outbox table
+func (w *Writer) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("encode %%s event: %%w", event.EventName(), err)
+		}
+		if _, err := w.db.ExecContext(ctx,
+			"INSERT INTO %[2]s (event_name, aggregate_id, payload, occurred_at) VALUES ($1, $2, $3, $4)",
+			event.EventName(), event.AggregateID(), payload, event.OccurredAt(),
+		); err != nil {
+			return fmt.Errorf("record %%s event: %%w", event.EventName(), err)
+		}
+	}
+	return nil
+}
+
+// Store holds the outbox messages waiting to be relayed
+type Store interface {
+	// Pending returns up to limit unpublished messages that are due for an attempt at now,
+	// oldest first, skipping messages that used up maxAttempts
+	Pending(ctx context.Context, limit, maxAttempts int, now time.Time) ([]Message, error)
+	// MarkPublished records that a message was published
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed records a failed attempt and when the message may be retried
+	MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time) error
+}
+
+// SQLStore is the Store of the outbox table. Run a single relay per database: pending
+// messages are not locked while they are published.
+type SQLStore struct {
+	db DBTX
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore creates a store reading the outbox table through db
+func NewSQLStore(db DBTX) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Pending returns the messages due for an attempt, oldest first
+func (s *SQLStore) Pending(ctx context.Context, limit, maxAttempts int, now time.Time) ([]Message, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`+"`"+`SELECT id, event_name, aggregate_id, payload, occurred_at, attempts, COALESCE(last_error, '')
+		FROM %[2]s
+		WHERE published_at IS NULL AND attempts < $1 AND (next_attempt_at IS NULL OR next_attempt_at <= $2)
+		ORDER BY id
+		LIMIT $3`+"`"+`,
+		maxAttempts, now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ID, &m.EventName, &m.AggregateID, &m.Payload, &m.OccurredAt, &m.Attempts, &m.LastError); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// MarkPublished records that a message was published
+func (s *SQLStore) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE %[2]s SET published_at = $1 WHERE id = $2", at, id)
+	return err
+}
+
+// MarkFailed records a failed attempt and when the message may be retried
+func (s *SQLStore) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE %[2]s SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3",
+		cause.Error(), retryAt, id,
+	)
+	return err
+}
+`, spec.ModulePath, outboxTable)
+
+	return s.newFileElement("outbox", "outbox", "internal/infrastructure/outbox/outbox.go", content)
+}
+
+// outboxTable is the table holding outbox messages
+const outboxTable = "outbox_messages"
+
+// generateOutboxRelayElement generates the worker publishing outbox messages with retries
+func (s *OrchestratorService) generateOutboxRelayElement() domain.CodeElement {
+	content := `package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Sink delivers outbox messages to a message broker
+type Sink interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Relay publishes pending outbox messages to a sink. A message that fails is retried with
+// exponential backoff until it has been attempted MaxAttempts times.
+type Relay struct {
+	store Store
+	sink  Sink
+
+	BatchSize   int           // Messages per poll
+	Interval    time.Duration // Time between polls
+	MaxAttempts int           // Attempts before a message is left for inspection
+	Backoff     time.Duration // Delay before the first retry, doubled on every further attempt
+	OnError     func(error)   // Optional, called with store errors; the batch is retried on the next poll
+
+	now func() time.Time
+}
+
+// NewRelay creates a relay with default settings
+func NewRelay(store Store, sink Sink) *Relay {
+	return &Relay{
+		store:       store,
+		sink:        sink,
+		BatchSize:   100,
+		Interval:    time.Second,
+		MaxAttempts: 10,
+		Backoff:     time.Second,
+		now:         time.Now,
+	}
+}
+
+// Run relays messages every Interval until ctx is done
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayOnce(ctx); err != nil && ctx.Err() == nil && r.OnError != nil {
+			r.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending messages and returns how many were published
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	now := r.now()
+	messages, err := r.store.Pending(ctx, r.BatchSize, r.MaxAttempts, now)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, message := range messages {
+		if err := r.sink.Send(ctx, message); err != nil {
+			if err := r.store.MarkFailed(ctx, message.ID, err, now.Add(r.backoff(message.Attempts))); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := r.store.MarkPublished(ctx, message.ID, now); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// backoff returns the delay before retrying a message that failed attempts times before
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.Backoff
+	for i := 0; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return delay
+}
+`
+
+	return s.newFileElement("relay", "outbox", "internal/infrastructure/outbox/relay.go", content)
+}
+
+// generateOutboxMemoryStoreElement generates an in-memory outbox store for tests
+func (s *OrchestratorService) generateOutboxMemoryStoreElement() domain.CodeElement {
+	content := `package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store for tests
+type MemoryStore struct {
+	mu        sync.Mutex
+	messages  []Message
+	published map[int64]time.Time
+	retryAt   map[int64]time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a store holding messages
+func NewMemoryStore(messages ...Message) *MemoryStore {
+	s := &MemoryStore{published: make(map[int64]time.Time), retryAt: make(map[int64]time.Time)}
+	for _, message := range messages {
+		s.Add(message)
+	}
+	return s
+}
+
+// Add stores a message, assigning the next ID when it has none
+func (s *MemoryStore) Add(message Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if message.ID == 0 {
+		message.ID = int64(len(s.messages) + 1)
+	}
+	s.messages = append(s.messages, message)
+}
+
+// Message returns a stored message and whether it was published
+func (s *MemoryStore) Message(id int64) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, message := range s.messages {
+		if message.ID == id {
+			_, published := s.published[id]
+			return message, published
+		}
+	}
+	return Message{}, false
+}
+
+// Pending returns the messages due for an attempt, oldest first
+func (s *MemoryStore) Pending(ctx context.Context, limit, maxAttempts int, now time.Time) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []Message
+	for _, message := range s.messages {
+		if len(pending) == limit {
+			break
+		}
+		if _, published := s.published[message.ID]; published || message.Attempts >= maxAttempts {
+			continue
+		}
+		if retryAt, ok := s.retryAt[message.ID]; ok && now.Before(retryAt) {
+			continue
+		}
+		pending = append(pending, message)
+	}
+	return pending, nil
+}
+
+// MarkPublished records that a message was published
+func (s *MemoryStore) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.published[id] = at
+	return nil
+}
+
+// MarkFailed records a failed attempt and when the message may be retried
+func (s *MemoryStore) MarkFailed(ctx context.Context, id int64, cause error, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.messages {
+		if s.messages[i].ID == id {
+			s.messages[i].Attempts++
+			s.messages[i].LastError = cause.Error()
+		}
+	}
+	s.retryAt[id] = retryAt
+	return nil
+}
+`
+
+	return s.newFileElement("memory_store", "outbox", "internal/infrastructure/outbox/memory_store.go", content)
+}
+
+// generateEventBusElement generates the in-memory event bus used in tests and single-process setups
+func (s *OrchestratorService) generateEventBusElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := fmt.Sprintf(`package events
+
+import (
+	"context"
+	"sync"
+
+	"%[1]s/internal/domain"
+)
+
+// Handler handles a published domain event
+type Handler func(ctx context.Context, event domain.Event) error
+
+// Bus is an in-memory domain.EventPublisher delivering events synchronously to the handlers
+// subscribed to their name. It records every published event for assertions in tests.
+type Bus struct {
+	mu        sync.RWMutex
+	handlers  map[string][]Handler
+	published []domain.Event
+}
+
+var _ domain.EventPublisher = (*Bus)(nil)
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for the events with the given name
+func (b *Bus) Subscribe(eventName string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+// Publish records events and delivers them to their handlers, stopping at the first error
+func (b *Bus) Publish(ctx context.Context, events ...domain.Event) error {
+	for _, event := range events {
+		b.mu.Lock()
+		b.published = append(b.published, event)
+		handlers := append([]Handler(nil), b.handlers[event.EventName()]...)
+		b.mu.Unlock()
+
+		for _, handler := range handlers {
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Published returns the events published so far, in order
+func (b *Bus) Published() []domain.Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]domain.Event(nil), b.published...)
+}
+`, spec.ModulePath)
+
+	return s.newFileElement("bus", "events", "internal/infrastructure/events/bus.go", content)
+}
+
+// generateOutboxMigrationElements generates the migration of the outbox table, numbered after
+// the entity migrations
+func (s *OrchestratorService) generateOutboxMigrationElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	base := fmt.Sprintf("%06d_create_%s", len(s.migrationOrder(spec))+1, outboxTable)
+
+	up := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+    id BIGSERIAL PRIMARY KEY,
+    event_name VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_%[1]s_pending ON %[1]s (id) WHERE published_at IS NULL;
+`, outboxTable)
+	down := fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", outboxTable)
+
+	return []domain.CodeElement{
+		{
+			Type:     "file",
+			Name:     base + ".up",
+			Package:  "migrations",
+			Body:     up,
+			Metadata: map[string]interface{}{"path": "migrations/" + base + ".up.sql"},
+		},
+		{
+			Type:     "file",
+			Name:     base + ".down",
+			Package:  "migrations",
+			Body:     down,
+			Metadata: map[string]interface{}{"path": "migrations/" + base + ".down.sql"},
+		},
+	}
+}
+
+// eventsFeatureElements generates the Created/Updated/Deleted events of an entity and the
+// repository decorator publishing them, with its tests when testing is enabled
+func (s *OrchestratorService) eventsFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	prefix := s.toSnakeCase(name)
+
+	events := fmt.Sprintf(`package domain
+
+import "time"
+
+// Names of the %[1]s events
+const (
+	%[1]sCreatedEvent = "%[3]s.created"
+	%[1]sUpdatedEvent = "%[3]s.updated"
+	%[1]sDeletedEvent = "%[3]s.deleted"
+)
+
+// %[1]sCreated is raised when a %[1]s is created
+type %[1]sCreated struct {
+	%[1]s %[1]s    `+"`"+`json:"%[3]s"`+"`"+`
+	At   time.Time `+"`"+`json:"occurred_at"`+"`"+`
+}
+
+// %[1]sUpdated is raised when a %[1]s is updated
+type %[1]sUpdated struct {
+	%[1]s %[1]s    `+"`"+`json:"%[3]s"`+"`"+`
+	At   time.Time `+"`"+`json:"occurred_at"`+"`"+`
+}
+
+// %[1]sDeleted is raised when a %[1]s is deleted
+type %[1]sDeleted struct {
+	ID string    `+"`"+`json:"id"`+"`"+`
+	At time.Time `+"`"+`json:"occurred_at"`+"`"+`
+}
+
+// New%[1]sCreated returns the event of item being created now
+func New%[1]sCreated(item *%[1]s) %[1]sCreated {
+	return %[1]sCreated{%[1]s: *item, At: time.Now()}
+}
+
+// New%[1]sUpdated returns the event of item being updated now
+func New%[1]sUpdated(item *%[1]s) %[1]sUpdated {
+	return %[1]sUpdated{%[1]s: *item, At: time.Now()}
+}
+
+// New%[1]sDeleted returns the event of the %[1]s with the given ID being deleted now
+func New%[1]sDeleted(id string) %[1]sDeleted {
+	return %[1]sDeleted{ID: id, At: time.Now()}
+}
+
+func (e %[1]sCreated) EventName() string     { return %[1]sCreatedEvent }
+func (e %[1]sCreated) AggregateID() string   { return e.%[1]s.%[2]s }
+func (e %[1]sCreated) OccurredAt() time.Time { return e.At }
+
+func (e %[1]sUpdated) EventName() string     { return %[1]sUpdatedEvent }
+func (e %[1]sUpdated) AggregateID() string   { return e.%[1]s.%[2]s }
+func (e %[1]sUpdated) OccurredAt() time.Time { return e.At }
+
+func (e %[1]sDeleted) EventName() string     { return %[1]sDeletedEvent }
+func (e %[1]sDeleted) AggregateID() string   { return e.ID }
+func (e %[1]sDeleted) OccurredAt() time.Time { return e.At }
+`, name, id, prefix)
+
+	elements := []domain.CodeElement{
+		s.newFileElement(name+"Events", "domain", fmt.Sprintf("internal/domain/%s_events.go", prefix), events),
+		s.generateEventsRepositoryElement(entity, spec),
+	}
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateEventsRepositoryTestElement(entity, spec))
+	}
+	return elements
+}
+
+// generateEventsRepositoryElement generates a repository decorator publishing the entity events
+// after each successful write
+func (s *OrchestratorService) generateEventsRepositoryElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	// A restored entity is back as it was, which its subscribers learn as an update
+	restore := ""
+	if s.softDeletes(entity) {
+		restore = fmt.Sprintf(`
+// Restore brings back the deleted %[1]s with the given ID and publishes %[1]sUpdated
+func (r *%[1]sRepository) Restore(ctx context.Context, id string) error {
+	if err := r.%[1]sRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+	item, err := r.%[1]sRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, domain.New%[1]sUpdated(item))
+}
+`, entity.Name)
+	}
+	content := fmt.Sprintf(`package events
+
+import (
+	"context"
+
+	"%[1]s/internal/domain"
+)
+
+// %[2]sRepository decorates domain.%[2]sRepository, publishing %[2]sCreated, %[2]sUpdated and
+// %[2]sDeleted after each successful write. For a transactional outbox, wrap the repository
+// bound to a transaction with an outbox.Writer on the same transaction.
+type %[2]sRepository struct {
+	domain.%[2]sRepository
+	publisher domain.EventPublisher
+}
+
+// New%[2]sRepository wraps next, publishing its events with publisher
+func New%[2]sRepository(next domain.%[2]sRepository, publisher domain.EventPublisher) *%[2]sRepository {
+	return &%[2]sRepository{%[2]sRepository: next, publisher: publisher}
+}
+
+// Create stores a new %[2]s and publishes %[2]sCreated
+func (r *%[2]sRepository) Create(ctx context.Context, item *domain.%[2]s) error {
+	if err := r.%[2]sRepository.Create(ctx, item); err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, domain.New%[2]sCreated(item))
+}
+
+// Update replaces an existing %[2]s and publishes %[2]sUpdated
+func (r *%[2]sRepository) Update(ctx context.Context, item *domain.%[2]s) error {
+	if err := r.%[2]sRepository.Update(ctx, item); err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, domain.New%[2]sUpdated(item))
+}
+
+// Delete removes the %[2]s with the given ID and publishes %[2]sDeleted
+func (r *%[2]sRepository) Delete(ctx context.Context, id string) error {
+	if err := r.%[2]sRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, domain.New%[2]sDeleted(id))
+}
+%[3]s`, spec.ModulePath, entity.Name, restore)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sEventsRepository", entity.Name),
+		"events",
+		fmt.Sprintf("internal/infrastructure/events/%s_repository.go", s.toSnakeCase(entity.Name)),
+		content,
+	)
+}
+
+// generateEventsRepositoryTestElement tests that the decorator publishes events through the bus
+func (s *OrchestratorService) generateEventsRepositoryTestElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	content := fmt.Sprintf(`package events_test
+
+import (
+	"context"
+	"testing"
+
+	"%[1]s/internal/domain"
+	"%[1]s/internal/fixtures"
+	"%[1]s/internal/infrastructure/events"
+	"%[1]s/internal/infrastructure/memory"
+)
+
+func Test%[2]sRepositoryPublishesEvents(t *testing.T) {
+	ctx := %[4]s
+	bus := events.NewBus()
+	repo := events.New%[2]sRepository(memory.New%[2]sRepository(), bus)
+	item := fixtures.New%[2]sBuilder().Build()
+
+	if err := repo.Create(ctx, item); err != nil {
+		t.Fatalf("Create() error = %%v", err)
+	}
+	if err := repo.Update(ctx, item); err != nil {
+		t.Fatalf("Update() error = %%v", err)
+	}
+	if err := repo.Delete(ctx, item.%[3]s); err != nil {
+		t.Fatalf("Delete() error = %%v", err)
+	}
+	if err := repo.Delete(ctx, item.%[3]s); err == nil {
+		t.Fatal("Delete() of a missing item succeeded")
+	}
+
+	want := []string{domain.%[2]sCreatedEvent, domain.%[2]sUpdatedEvent, domain.%[2]sDeletedEvent}
+	published := bus.Published()
+	if len(published) != len(want) {
+		t.Fatalf("published %%d events, want %%d", len(published), len(want))
+	}
+	for i, event := range published {
+		if event.EventName() != want[i] {
+			t.Errorf("event %%d = %%s, want %%s", i, event.EventName(), want[i])
+		}
+		if event.AggregateID() != item.%[3]s {
+			t.Errorf("event %%d aggregate = %%s, want %%s", i, event.AggregateID(), item.%[3]s)
+		}
+	}
+}
+`, spec.ModulePath, entity.Name, s.idFieldName(entity), s.testContext(entity, spec))
+
+	return s.newFileElement(
+		fmt.Sprintf("%sEventsRepositoryTest", entity.Name),
+		"events_test",
+		fmt.Sprintf("internal/infrastructure/events/%s_repository_test.go", s.toSnakeCase(entity.Name)),
+		content,
+	)
+}
+
+// generateOutboxRelayTestElement tests the relay retries against the in-memory store
+func (s *OrchestratorService) generateOutboxRelayTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"` + spec.ModulePath + `/internal/infrastructure/outbox"
+)
+
+type flakySink struct {
+	failures int
+	sent     []outbox.Message
+}
+
+func (s *flakySink) Send(ctx context.Context, message outbox.Message) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("broker unavailable")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestRelayRetriesFailedMessages(t *testing.T) {
+	ctx := context.Background()
+	store := outbox.NewMemoryStore(outbox.Message{EventName: "test.created", AggregateID: "1", Payload: []byte("{}")})
+	sink := &flakySink{failures: 1}
+
+	relay := outbox.NewRelay(store, sink)
+	relay.Backoff = 0
+
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("first RelayOnce() = %d, %v, want 0 published", published, err)
+	}
+	if message, _ := store.Message(1); message.Attempts != 1 || message.LastError == "" {
+		t.Errorf("after failure attempts = %d, last error = %q", message.Attempts, message.LastError)
+	}
+
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 1 {
+		t.Fatalf("second RelayOnce() = %d, %v, want 1 published", published, err)
+	}
+	if _, published := store.Message(1); !published || len(sink.sent) != 1 {
+		t.Errorf("message published = %v, sent %d messages", published, len(sink.sent))
+	}
+}
+
+func TestRelayStopsAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	store := outbox.NewMemoryStore(outbox.Message{EventName: "test.created", AggregateID: "1", Payload: []byte("{}")})
+	sink := &flakySink{failures: 10}
+
+	relay := outbox.NewRelay(store, sink)
+	relay.Backoff = 0
+	relay.MaxAttempts = 2
+
+	for i := 0; i < 3; i++ {
+		if _, err := relay.RelayOnce(ctx); err != nil {
+			t.Fatalf("RelayOnce() error = %v", err)
+		}
+	}
+	if message, published := store.Message(1); published || message.Attempts != 2 {
+		t.Errorf("attempts = %d, published = %v, want 2 attempts and unpublished", message.Attempts, published)
+	}
+}
+`
+
+	return s.newFileElement("RelayTest", "outbox_test", "internal/infrastructure/outbox/relay_test.go", content)
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"fmt"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// featureRequest is a group of features enabled for the same reason
+type featureRequest struct {
+	features []string
+	reason   string
+}
+
+// resolveFeatures enables the requested features and, transitively, every feature they require
+// in FeatureMapping or through their plugin. Features keep the order in which they were enabled,
+// each followed by its requirements. Unknown features are kept as they are.
+func (s *OrchestratorService) resolveFeatures(entity string, requests ...featureRequest) (domain.FeatureResolution, error) {
+	resolution := domain.FeatureResolution{
+		Entity:   entity,
+		Features: []string{},
+		Reasons:  make(map[string]string),
+	}
+
+	var enable func(feature, reason string)
+	enable = func(feature, reason string) {
+		if _, ok := resolution.Reasons[feature]; ok {
+			return
+		}
+		resolution.Features = append(resolution.Features, feature)
+		resolution.Reasons[feature] = reason
+		for _, required := range s.featureRequires(feature) {
+			enable(required, "required by "+feature)
+		}
+	}
+	for _, request := range requests {
+		for _, feature := range request.features {
+			enable(feature, request.reason)
+		}
+	}
+
+	return resolution, featureConflict(resolution)
+}
+
+// featureConflict returns an error naming the first pair of enabled features that conflict
+func featureConflict(resolution domain.FeatureResolution) error {
+	for _, feature := range resolution.Features {
+		for _, other := range domain.FeatureMapping[feature].Conflicts {
+			if reason, ok := resolution.Reasons[other]; ok {
+				return fmt.Errorf("feature %s (%s) conflicts with %s (%s)",
+					feature, resolution.Reasons[feature], other, reason)
+			}
+		}
+	}
+	return nil
+}
+
+// entityFeatureConflict returns an error naming the first feature of an entity that conflicts
+// with a feature enabled for the whole project, in either direction
+func entityFeatureConflict(project, entity domain.FeatureResolution) error {
+	for _, feature := range entity.Features {
+		for _, other := range domain.FeatureMapping[feature].Conflicts {
+			if reason, ok := project.Reasons[other]; ok {
+				return fmt.Errorf("feature %s (%s) conflicts with project feature %s (%s)",
+					feature, entity.Reasons[feature], other, reason)
+			}
+		}
+	}
+	for _, feature := range project.Features {
+		for _, other := range domain.FeatureMapping[feature].Conflicts {
+			if reason, ok := entity.Reasons[other]; ok {
+				return fmt.Errorf("feature %s (%s) conflicts with project feature %s (%s)",
+					other, reason, feature, project.Reasons[feature])
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestEntityFeaturesConflictingWithProjectFeatures(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "tool",
+		ModulePath:  "example.com/tool",
+		ProjectType: "cli",
+		Entities: []domain.EntitySpecification{{
+			Name:     "Note",
+			Features: []string{"crud", "rest_api"},
+			Fields:   []domain.FieldSpecification{{Name: "title", Type: "string"}},
+		}},
+	}
+	s := NewOrchestratorService()
+
+	_, _, err := s.enhanceProjectSpecification(spec)
+	if err == nil || !strings.Contains(err.Error(), "entity Note: feature rest_api (requested by the entity) conflicts with project feature cli") {
+		t.Fatalf("enhanceProjectSpecification() error = %v, want a rest_api/cli conflict", err)
+	}
+
+	report := s.ValidateSpecification(spec)
+	var found bool
+	for _, issue := range report.Issues {
+		if issue.Code == "feature_conflict" && issue.Path == "/entities/0/features" && issue.Severity == "error" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ValidateSpecification() issues = %+v, want a feature_conflict error at /entities/0/features", report.Issues)
+	}
+}
+
+func TestEntityFeaturesWithoutConflicts(t *testing.T) {
+	project, err := NewOrchestratorService().resolveFeatures("", featureRequest{[]string{"rest_api"}, "requested by the project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entity, err := NewOrchestratorService().resolveFeatures("Note", featureRequest{[]string{"crud", "testing"}, "requested by the entity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entityFeatureConflict(project, entity); err != nil {
+		t.Errorf("entityFeatureConflict() = %v, want nil", err)
+	}
+}
+
+func TestResolveFeaturesTransitively(t *testing.T) {
+	resolution, err := NewOrchestratorService().resolveFeatures("Order", featureRequest{[]string{"rest_api", "cache"}, "requested by the entity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"rest_api":   "requested by the entity",
+		"handler":    "required by rest_api",
+		"service":    "required by handler",
+		"validation": "required by rest_api",
+		"cache":      "requested by the entity",
+		"repository": "required by cache",
+	}
+	for feature, reason := range want {
+		if got := resolution.Reasons[feature]; got != reason {
+			t.Errorf("reason of %s = %q, want %q", feature, got, reason)
+		}
+	}
+	if len(resolution.Features) != len(resolution.Reasons) {
+		t.Errorf("features = %v, reasons = %v", resolution.Features, resolution.Reasons)
+	}
+}
+
+func TestGeneratedProjectWithRequiredFeatures(t *testing.T) {
+	// The entity asks for the REST API and the cache only: the handler, service, validation and
+	// repository they require are generated too
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"rest_api", "cache"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+	result, err := NewOrchestratorService().OrchestrateMicroservice(spec)
+	if err != nil {
+		t.Fatalf("OrchestrateMicroservice() error = %v", err)
+	}
+	var reported bool
+	for _, resolution := range result.Features {
+		if resolution.Entity == "Order" {
+			reported = resolution.Reasons["repository"] == "required by cache"
+		}
+	}
+	if !reported {
+		t.Errorf("result features = %+v, want the repository of Order required by cache", result.Features)
+	}
+
+	checkGeneratedProject(t, spec)
+}
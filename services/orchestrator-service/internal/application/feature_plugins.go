@@ -0,0 +1,186 @@
+package application
+
+import (
+	"fmt"
+	"sync"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// FeaturePlugin generates the code of a feature. Built-in features and in-house ones added
+// with RegisterFeature go through the same plugin registry.
+type FeaturePlugin interface {
+	// Name is the feature name used in specifications
+	Name() string
+	// Dependencies lists features enabled along with this one, in addition to its FeatureMapping requires
+	Dependencies() []string
+	// Elements generates the code elements of the feature for an entity that has it enabled
+	Elements(entity domain.EntitySpecification, project *domain.ProjectSpecification) []domain.CodeElement
+	// Files generates project-wide files, once per project that enables the feature for itself or any entity
+	Files(project *domain.ProjectSpecification) []domain.CodeElement
+}
+
+// FeatureRegistry holds the plugins that generate code for features. It is safe for concurrent
+// use, so plugins can be registered while specifications are orchestrated.
+type FeatureRegistry struct {
+	mu      sync.RWMutex
+	plugins map[string]FeaturePlugin
+}
+
+// NewFeatureRegistry creates an empty feature registry
+func NewFeatureRegistry() *FeatureRegistry {
+	return &FeatureRegistry{plugins: make(map[string]FeaturePlugin)}
+}
+
+// Register adds a plugin, replacing any plugin of the same name
+func (r *FeatureRegistry) Register(plugin FeaturePlugin) error {
+	if plugin == nil || plugin.Name() == "" {
+		return fmt.Errorf("feature plugin name is required")
+	}
+	for _, dependency := range plugin.Dependencies() {
+		if dependency == plugin.Name() {
+			return fmt.Errorf("feature %s depends on itself", plugin.Name())
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plugins[plugin.Name()] = plugin
+	return nil
+}
+
+// Lookup returns the plugin of a feature
+func (r *FeatureRegistry) Lookup(name string) (FeaturePlugin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	plugin, ok := r.plugins[name]
+	return plugin, ok
+}
+
+// Names returns the names of the registered plugins in sorted order
+func (r *FeatureRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sortedKeys(r.plugins)
+}
+
+// bind returns a copy of the registry whose built-in plugins generate code with s
+func (r *FeatureRegistry) bind(s *OrchestratorService) *FeatureRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c := NewFeatureRegistry()
+	for name, plugin := range r.plugins {
+		if builtin, ok := plugin.(*builtinFeature); ok {
+			bound := *builtin
+			bound.service = s
+			plugin = &bound
+		}
+		c.plugins[name] = plugin
+	}
+	return c
+}
+
+// builtinFeature adapts a generation method of the service to FeaturePlugin
+type builtinFeature struct {
+	name         string
+	dependencies []string
+	service      *OrchestratorService
+	elements     func(s *OrchestratorService, entity domain.EntitySpecification, project *domain.ProjectSpecification) []domain.CodeElement // Optional
+	files        func(s *OrchestratorService, project *domain.ProjectSpecification) []domain.CodeElement                                    // Optional
+}
+
+func (f *builtinFeature) Name() string           { return f.name }
+func (f *builtinFeature) Dependencies() []string { return f.dependencies }
+
+func (f *builtinFeature) Elements(entity domain.EntitySpecification, project *domain.ProjectSpecification) []domain.CodeElement {
+	if f.elements == nil {
+		return nil
+	}
+	return f.elements(f.service, entity, project)
+}
+
+func (f *builtinFeature) Files(project *domain.ProjectSpecification) []domain.CodeElement {
+	if f.files == nil {
+		return nil
+	}
+	return f.files(f.service, project)
+}
+
+// registerBuiltinFeatures registers the plugins of the features generated by the service itself.
+// Aliases such as "crud" and "rest_api" have no plugin of their own and generate code through
+// the features they require.
+func (s *OrchestratorService) registerBuiltinFeatures() {
+	builtins := []*builtinFeature{
+		{name: "repository", elements: (*OrchestratorService).repositoryFeatureElements},
+		{name: "validation", elements: (*OrchestratorService).validationFeatureElements},
+		{name: "handler", elements: (*OrchestratorService).handlerFeatureElements},
+		{name: "service", elements: (*OrchestratorService).serviceFeatureElements},
+		{name: "grpc_api", elements: (*OrchestratorService).grpcFeatureElements, files: (*OrchestratorService).grpcFeatureFiles},
+		{name: "graphql_api", elements: (*OrchestratorService).graphqlFeatureElements, files: (*OrchestratorService).graphqlFeatureFiles},
+		{name: "cli", files: (*OrchestratorService).cliFeatureFiles},
+		{name: "queue_processing", files: (*OrchestratorService).workerFeatureFiles},
+		{name: "config", files: (*OrchestratorService).configFeatureFiles},
+		{name: "cache", elements: (*OrchestratorService).cacheFeatureElements, files: (*OrchestratorService).cacheFeatureFiles},
+		{name: "events", elements: (*OrchestratorService).eventsFeatureElements, files: (*OrchestratorService).eventsFeatureFiles},
+	}
+	for _, builtin := range builtins {
+		builtin.service = s
+		if err := s.features.Register(builtin); err != nil {
+			panic(fmt.Sprintf("invalid built-in feature %s: %v", builtin.name, err))
+		}
+	}
+}
+
+// RegisterFeature adds a feature plugin, replacing the plugin of a built-in feature of the same name
+func (s *OrchestratorService) RegisterFeature(plugin FeaturePlugin) error {
+	return s.features.Register(plugin)
+}
+
+// Features returns the registry of feature plugins
+func (s *OrchestratorService) Features() *FeatureRegistry {
+	return s.features
+}
+
+// featureRequires returns the features required by a feature, from FeatureMapping and its plugin
+func (s *OrchestratorService) featureRequires(feature string) []string {
+	requires := domain.FeatureMapping[feature].Requires
+	if plugin, ok := s.features.Lookup(feature); ok {
+		requires = append(append([]string{}, requires...), plugin.Dependencies()...)
+	}
+	return requires
+}
+
+// repositoryFeatureElements generates the repository interface, its PostgreSQL implementation
+// when the project is configured with PostgreSQL, its in-memory implementation when tests or the
+// generated main use it, and its mock when testing is enabled
+func (s *OrchestratorService) repositoryFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	repoInterface := s.generateRepositoryInterface(entity)
+	elements := []domain.CodeElement{repoInterface}
+
+	if s.usesPostgres(spec) {
+		elements = append(elements, s.generatePostgresRepositoryElement(entity, spec))
+	}
+	testing := s.hasTestingFeature(entity, spec)
+	if testing || s.servesEntity(entity, spec) {
+		elements = append(elements, s.generateInMemoryRepositoryElement(entity, spec))
+	}
+	if testing {
+		elements = append(elements, s.generateInterfaceMockElement(repoInterface, spec))
+	}
+	return elements
+}
+
+// validationFeatureElements generates the Validate method and ValidateX function
+func (s *OrchestratorService) validationFeatureElements(entity domain.EntitySpecification, _ *domain.ProjectSpecification) []domain.CodeElement {
+	return []domain.CodeElement{s.generateValidationElement(entity)}
+}
+
+// handlerFeatureElements generates the service function and, when there is a repository to serve
+// from, the HTTP handler
+func (s *OrchestratorService) handlerFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	elements := []domain.CodeElement{s.generateServiceFunction(entity)}
+	if s.hasRepository(entity) {
+		elements = append(elements, s.generateHandlerElement(entity, spec))
+	}
+	return elements
+}
@@ -0,0 +1,177 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// auditLogFeature is an in-house feature logging the changes of entities, as teams register
+// them without forking the orchestrator
+type auditLogFeature struct {
+	s *OrchestratorService
+}
+
+func (f auditLogFeature) Name() string           { return "audit_log" }
+func (f auditLogFeature) Dependencies() []string { return []string{"repository"} }
+
+func (f auditLogFeature) Elements(entity domain.EntitySpecification, project *domain.ProjectSpecification) []domain.CodeElement {
+	content := fmt.Sprintf(`package audit
+
+import (
+	"context"
+
+	"%[1]s/internal/domain"
+)
+
+// Log%[2]s records a change of a %[2]s
+func Log%[2]s(ctx context.Context, action Action, item *domain.%[2]s) {
+	record(ctx, action, "%[2]s", item.ID)
+}
+`, project.ModulePath, entity.Name)
+	return []domain.CodeElement{f.s.newFileElement(entity.Name+"Audit", "audit", "internal/audit/"+f.s.toSnakeCase(entity.Name)+".go", content)}
+}
+
+func (f auditLogFeature) Files(project *domain.ProjectSpecification) []domain.CodeElement {
+	content := `package audit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Action is the kind of change recorded
+type Action string
+
+func record(ctx context.Context, action Action, entity, id string) {
+	slog.InfoContext(ctx, "audit", "action", action, "entity", entity, "id", id)
+}
+`
+	return []domain.CodeElement{f.s.newFileElement("Audit", "audit", "internal/audit/audit.go", content)}
+}
+
+func TestFeatureRegistryRegister(t *testing.T) {
+	r := NewFeatureRegistry()
+	if err := r.Register(&builtinFeature{}); err == nil {
+		t.Error("Register() of a plugin without a name succeeded")
+	}
+	if err := r.Register(&builtinFeature{name: "audit_log", dependencies: []string{"audit_log"}}); err == nil {
+		t.Error("Register() of a plugin depending on itself succeeded")
+	}
+	if err := r.Register(auditLogFeature{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, ok := r.Lookup("audit_log"); !ok {
+		t.Errorf("Lookup(audit_log) found nothing in %v", r.Names())
+	}
+}
+
+func TestGeneratedProjectWithRegisteredFeature(t *testing.T) {
+	s := NewOrchestratorService()
+	if err := s.RegisterFeature(auditLogFeature{s}); err != nil {
+		t.Fatal(err)
+	}
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"audit_log"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	dir := checkProjectGeneratedBy(t, s, spec)
+	for _, path := range []string{"internal/audit/audit.go", "internal/audit/order.go", "internal/infrastructure/memory/order_repository.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("%s is not generated: %v", path, err)
+		}
+	}
+}
+
+// settingsFeature replaces the built-in config feature with a settings file of its own
+type settingsFeature struct {
+	s *OrchestratorService
+}
+
+func (f settingsFeature) Name() string           { return "config" }
+func (f settingsFeature) Dependencies() []string { return nil }
+
+func (f settingsFeature) Elements(entity domain.EntitySpecification, project *domain.ProjectSpecification) []domain.CodeElement {
+	return nil
+}
+
+func (f settingsFeature) Files(project *domain.ProjectSpecification) []domain.CodeElement {
+	return []domain.CodeElement{f.s.newFileElement("Settings", "settings", "internal/settings/settings.go", "package settings\n")}
+}
+
+func TestRegisterFeatureReplacesBuiltin(t *testing.T) {
+	s := NewOrchestratorService()
+	if err := s.RegisterFeature(settingsFeature{s}); err != nil {
+		t.Fatal(err)
+	}
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	result, err := s.OrchestrateMicroservice(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]bool)
+	for _, element := range result.GeneratorPayload.Elements {
+		if path, ok := element.Metadata["path"].(string); ok {
+			paths[path] = true
+		}
+	}
+	if !paths["internal/settings/settings.go"] || paths["internal/config/config.go"] {
+		t.Errorf("generated files %v, want the settings of the registered plugin instead of the built-in configuration", sortedKeys(paths))
+	}
+}
+
+func TestFeatureRegistryConcurrentUse(t *testing.T) {
+	s := NewOrchestratorService()
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := s.RegisterFeature(auditLogFeature{s}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := s.OrchestrateMicroservice(spec); err != nil {
+				t.Error(err)
+			}
+			s.Features().Names()
+		}()
+	}
+	wg.Wait()
+}
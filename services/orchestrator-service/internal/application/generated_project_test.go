@@ -0,0 +1,187 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// elementDirs maps the packages of struct, interface and function elements to their directories
+var elementDirs = map[string]string{
+	"domain":         "internal/domain",
+	"application":    "internal/application",
+	"handlers":       "internal/interfaces/http/handlers",
+	"infrastructure": "internal/infrastructure",
+}
+
+// bodyImports are the imports of the standard and common packages that element bodies use
+// without listing them in their metadata; the generator service adds them the same way
+var bodyImports = map[string]string{
+	"context.": "context", "errors.": "errors", "fmt.": "fmt", "json.": "encoding/json",
+	"mail.": "net/mail", "regexp.": "regexp", "strings.": "strings", "time.": "time",
+	"url.": "net/url", "utf8.": "unicode/utf8", "uuid.": "github.com/google/uuid",
+}
+
+// generateProject orchestrates spec with s and writes the generated project to a temporary
+// directory, rendering struct, interface and function elements the way the generator service does
+func generateProject(t *testing.T, s *OrchestratorService, spec *domain.ProjectSpecification) string {
+	t.Helper()
+	result, err := s.OrchestrateMicroservice(spec)
+	if err != nil {
+		t.Fatalf("OrchestrateMicroservice() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	write := func(path, content string) {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if _, err := os.Stat(full); err == nil {
+			t.Fatalf("%s is generated twice", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, element := range result.GeneratorPayload.Elements {
+		var body string
+		switch element.Type {
+		case "file":
+			write(element.Metadata["path"].(string), element.Body)
+			continue
+		case "struct":
+			var fields strings.Builder
+			for _, field := range element.Fields {
+				fmt.Fprintf(&fields, "\t%s %s", field.Name, field.Type)
+				if field.Tags != "" {
+					fmt.Fprintf(&fields, " `%s`", field.Tags)
+				}
+				fields.WriteString("\n")
+			}
+			body = fmt.Sprintf("type %s struct {\n%s}\n", element.Name, fields.String())
+		case "interface":
+			var methods strings.Builder
+			for _, method := range element.Methods {
+				fmt.Fprintf(&methods, "\t%s(%s) (%s)\n", method.Name, parameterList(method.Parameters), returnList(method.Returns))
+			}
+			body = fmt.Sprintf("type %s interface {\n%s}\n", element.Name, methods.String())
+		case "function":
+			body = fmt.Sprintf("func %s(%s) (%s) {\n%s\n}\n", element.Name, parameterList(element.Parameters), returnList(element.Returns), element.Body)
+		default:
+			t.Fatalf("element %s has unknown type %s", element.Name, element.Type)
+		}
+
+		imports := make(map[string]bool)
+		for selector, path := range bodyImports {
+			if strings.Contains(body, selector) {
+				imports[path] = true
+			}
+		}
+		if element.Package != "domain" && strings.Contains(body, "domain.") {
+			imports[spec.ModulePath+"/internal/domain"] = true
+		}
+		if listed, ok := element.Metadata["imports"].(string); ok {
+			for _, path := range strings.Split(listed, ",") {
+				if path != "" {
+					imports[path] = true
+				}
+			}
+		}
+		var importBlock string
+		if len(imports) > 0 {
+			importBlock = "import (\n"
+			for _, path := range sortedKeys(imports) {
+				importBlock += fmt.Sprintf("\t%q\n", path)
+			}
+			importBlock += ")\n\n"
+		}
+		name := fmt.Sprintf("%s_%s.go", element.Type, strings.ToLower(element.Name))
+		write(filepath.Join(elementDirs[element.Package], name), "package "+element.Package+"\n\n"+importBlock+body)
+	}
+	write("go.mod", "module "+spec.ModulePath+"\n\ngo 1.22\n")
+	return dir
+}
+
+func parameterList(parameters []domain.ParameterElement) string {
+	list := make([]string, 0, len(parameters))
+	for _, p := range parameters {
+		list = append(list, p.Name+" "+p.Type)
+	}
+	return strings.Join(list, ", ")
+}
+
+func returnList(returns []domain.ReturnElement) string {
+	list := make([]string, 0, len(returns))
+	for _, r := range returns {
+		list = append(list, r.Type)
+	}
+	return strings.Join(list, ", ")
+}
+
+// checkGeneratedProject generates the project of spec, then builds, vets and tests it. It is
+// skipped in short mode and when the go tool or the dependencies of the project are unavailable.
+func checkGeneratedProject(t *testing.T, spec *domain.ProjectSpecification) string {
+	t.Helper()
+	return checkProjectGeneratedBy(t, NewOrchestratorService(), spec)
+}
+
+// checkProjectGeneratedBy is checkGeneratedProject for the project s generates, such as a
+// service with additional feature plugins
+func checkProjectGeneratedBy(t *testing.T, s *OrchestratorService, spec *domain.ProjectSpecification) string {
+	t.Helper()
+	dir := generateProject(t, s, spec)
+	checkProject(t, dir)
+	return dir
+}
+
+// checkProject builds, vets and tests the project in dir, such as a generated project to which a
+// test added files
+func checkProject(t *testing.T, dir string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("generated projects are not compiled in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go tool is not available")
+	}
+
+	if out, err := goCommand(dir, "mod", "tidy"); err != nil {
+		t.Skipf("dependencies of the generated project are not available: %v\n%s", err, out)
+	}
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}, {"test", "-count=1", "./..."}} {
+		if out, err := goCommand(dir, args...); err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+// withProtocGenGo builds the protoc-gen-go plugin of the protobuf module the orchestrator
+// requires and puts it first on the PATH, for the projects serving entities over gRPC. It skips
+// the test when the plugin cannot be built.
+func withProtocGenGo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go tool is not available")
+	}
+	dir := t.TempDir()
+	out, err := exec.Command("go", "build", "-o", filepath.Join(dir, protocGenGo), protobufModule.Path+"/cmd/protoc-gen-go").CombinedOutput()
+	if err != nil {
+		t.Skipf("protoc-gen-go cannot be built: %v\n%s", err, out)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func goCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
@@ -0,0 +1,1358 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// graphqlModule is the GraphQL server library the generated resolvers are written against
+var graphqlModule = domain.ModuleDependency{Path: "github.com/graph-gophers/graphql-go", Version: "v1.5.0"}
+
+// graphqlConversion is how a field value is converted between the domain and its GraphQL type
+type graphqlConversion int
+
+const (
+	graphqlAssign graphqlConversion = iota // Same Go type on both sides
+	graphqlCast                            // Numeric conversion to Int (int32) or Float (float64)
+	graphqlTime                            // time.Time to graphql.Time
+	graphqlEnum                            // Domain enum to the GraphQL enum value name
+	graphqlJSON                            // Any other type, carried by the JSON scalar
+)
+
+// graphqlScalar is the GraphQL type of a Go type and the Go type graphql-go resolves it with
+type graphqlScalar struct {
+	graphqlType string
+	goType      string
+}
+
+// graphqlScalars maps the Go types of fields to GraphQL types. Int is 32-bit in GraphQL, so wider
+// integers than Go's int are served as Float.
+var graphqlScalars = map[string]graphqlScalar{
+	"string":   {"String", "string"},
+	"bool":     {"Boolean", "bool"},
+	"[]string": {"[String!]", "[]string"},
+	"int":      {"Int", "int32"},
+	"int8":     {"Int", "int32"},
+	"int16":    {"Int", "int32"},
+	"int32":    {"Int", "int32"},
+	"uint8":    {"Int", "int32"},
+	"uint16":   {"Int", "int32"},
+	"int64":    {"Float", "float64"},
+	"uint":     {"Float", "float64"},
+	"uint32":   {"Float", "float64"},
+	"uint64":   {"Float", "float64"},
+	"float32":  {"Float", "float64"},
+	"float64":  {"Float", "float64"},
+}
+
+// graphqlObject is the GraphQL API of an entity: its object type, input type, connection and
+// the query and mutation fields serving it
+type graphqlObject struct {
+	Entity    domain.EntitySpecification
+	Name      string // e.g. "OrderItem"
+	Plural    string // e.g. "OrderItems"
+	ID        string // Go name of the ID field
+	Fields    []graphqlField
+	Enums     []graphqlEnumType
+	Relations []graphqlRelation
+}
+
+// graphqlField is a field of an object type, also part of the input type unless it is a timestamp
+type graphqlField struct {
+	Name        string // GraphQL name, e.g. "buyerId"
+	GoName      string // Name of the resolver method and input struct field
+	Description string
+	DomainField string
+	DomainType  string
+	Type        string // Named GraphQL type, e.g. "String" or "UserStatus"
+	GoType      string // Go type graphql-go resolves Type with
+	Conversion  graphqlConversion
+	Required    bool // Non-null in the input type
+}
+
+// OutputType returns the GraphQL type of the field in its object type. Enums and JSON values may
+// hold values GraphQL can't represent, so they are nullable.
+func (f graphqlField) OutputType() string {
+	if f.Conversion == graphqlEnum || f.Conversion == graphqlJSON {
+		return f.Type
+	}
+	return f.Type + "!"
+}
+
+// InputType returns the GraphQL type of the field in its input type
+func (f graphqlField) InputType() string {
+	if f.Required && f.Conversion != graphqlJSON {
+		return f.Type + "!"
+	}
+	return f.Type
+}
+
+// InputGoType returns the Go type of the field in the input struct
+func (f graphqlField) InputGoType() string {
+	if f.Required && f.Conversion != graphqlJSON {
+		return f.GoType
+	}
+	return "*" + f.GoType
+}
+
+// graphqlEnumType is the GraphQL enum of an enum field
+type graphqlEnumType struct {
+	Name       string // Also the name of the domain type
+	Values     []string
+	DomainVals []string // Domain constants, in the order of Values
+}
+
+// graphqlRelation is a relationship field resolved through a loader. to-one relationships
+// (belongs_to, one_to_one) resolve to the target, one_to_many ones to a connection of targets.
+type graphqlRelation struct {
+	Name       string // GraphQL name
+	GoName     string
+	Target     string
+	Many       bool
+	ForeignKey string // Go name of the key field, on the entity for to-one, on the target for one_to_many
+	Loader     graphqlLoader
+}
+
+// graphqlLoader batches the lookups of a relationship: by ID for to-one relationships, by
+// foreign key for one_to_many ones
+type graphqlLoader struct {
+	Name       string // e.g. "UserByID", "OrderItemsByBuyerId"
+	Target     string
+	Many       bool
+	ForeignKey string // Go name of the target field the loader matches keys against
+}
+
+// servesGraphQL reports whether an entity is served by the GraphQL resolvers, which need a
+// repository for the application service they delegate to
+func (s *OrchestratorService) servesGraphQL(entity domain.EntitySpecification) bool {
+	return s.hasFeature(entity.Features, "graphql_api") && s.hasRepository(entity)
+}
+
+// usesGraphQL reports whether any entity of the project is served over GraphQL
+func (s *OrchestratorService) usesGraphQL(spec *domain.ProjectSpecification) bool {
+	return s.anyEntity(spec, s.servesGraphQL)
+}
+
+// graphqlObjects builds the GraphQL API of the entities served over GraphQL
+func (s *OrchestratorService) graphqlObjects(spec *domain.ProjectSpecification) []graphqlObject {
+	var objects []graphqlObject
+	for _, entity := range spec.Entities {
+		if s.servesGraphQL(entity) {
+			objects = append(objects, s.graphqlObjectOf(entity))
+		}
+	}
+	for i := range objects {
+		objects[i].Relations = s.graphqlRelations(objects[i], objects)
+	}
+	return objects
+}
+
+// graphqlObjectOf maps the fields of an entity to GraphQL fields
+func (s *OrchestratorService) graphqlObjectOf(entity domain.EntitySpecification) graphqlObject {
+	object := graphqlObject{Entity: entity, Name: entity.Name, Plural: s.pluralize(entity.Name), ID: s.idFieldName(entity)}
+	for _, field := range entity.Fields {
+		if strings.ToLower(field.Name) == "id" {
+			continue
+		}
+		f := graphqlField{
+			Name:        s.lowerFirst(s.toPascalCase(field.Name)),
+			GoName:      s.toPascalCase(field.Name),
+			Description: field.Description,
+			DomainField: s.capitalizeFirst(field.Name),
+			DomainType:  s.fieldGoType(entity, field),
+			Required:    field.Required,
+		}
+		scalar, isScalar := graphqlScalars[f.DomainType]
+		switch {
+		case s.isTypedEnum(field):
+			enum := graphqlEnumType{Name: f.DomainType}
+			for _, value := range field.Enum {
+				enum.Values = append(enum.Values, s.graphqlEnumValue(value))
+				enum.DomainVals = append(enum.DomainVals, s.enumConstName(entity, field, value))
+			}
+			object.Enums = append(object.Enums, enum)
+			f.Type, f.GoType, f.Conversion = enum.Name, "string", graphqlEnum
+		case f.DomainType == "time.Time":
+			f.Type, f.GoType, f.Conversion = "Time", "graphql.Time", graphqlTime
+		case isScalar:
+			f.Type, f.GoType, f.Conversion = scalar.graphqlType, scalar.goType, graphqlAssign
+			if scalar.goType != f.DomainType {
+				f.Conversion = graphqlCast
+			}
+		default:
+			f.Type, f.GoType, f.Conversion = "JSON", "JSON", graphqlJSON
+		}
+		object.Fields = append(object.Fields, f)
+	}
+	return object
+}
+
+// graphqlEnumValue returns the GraphQL name of an enum value (e.g. "in-progress" -> "IN_PROGRESS")
+func (s *OrchestratorService) graphqlEnumValue(value string) string {
+	name := strings.ToUpper(s.toSnakeCase(s.toPascalCase(value)))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+// graphqlRelations returns the relationship fields of an object. Relationships are skipped when
+// their target isn't served over GraphQL, their key isn't a string field, their name is taken by
+// a field, or they are many_to_many, which has no join entity to load through.
+func (s *OrchestratorService) graphqlRelations(object graphqlObject, objects []graphqlObject) []graphqlRelation {
+	taken := map[string]bool{"id": true, "createdat": true, "updatedat": true}
+	for _, field := range object.Fields {
+		taken[strings.ToLower(field.Name)] = true
+	}
+
+	var relations []graphqlRelation
+	for _, rel := range object.Entity.Relationships {
+		var target *graphqlObject
+		for i := range objects {
+			if objects[i].Name == rel.Target {
+				target = &objects[i]
+			}
+		}
+		name := s.lowerFirst(s.toPascalCase(rel.Name))
+		if target == nil || rel.ForeignKey == "" || rel.Type == "many_to_many" || taken[strings.ToLower(name)] {
+			continue
+		}
+
+		many := rel.Type == "one_to_many"
+		owner := object
+		if many {
+			owner = *target
+		}
+		key, ok := s.graphqlStringField(owner, rel.ForeignKey)
+		if !ok {
+			continue
+		}
+		taken[strings.ToLower(name)] = true
+
+		relation := graphqlRelation{Name: name, GoName: s.toPascalCase(rel.Name), Target: target.Name, Many: many, ForeignKey: key}
+		if many {
+			relation.Loader = graphqlLoader{Name: target.Plural + "By" + s.toPascalCase(rel.ForeignKey), Target: target.Name, Many: true, ForeignKey: key}
+		} else {
+			relation.Loader = graphqlLoader{Name: target.Name + "ByID", Target: target.Name, ForeignKey: target.ID}
+		}
+		relations = append(relations, relation)
+	}
+	return relations
+}
+
+// graphqlStringField returns the Go name of a string field of an object
+func (s *OrchestratorService) graphqlStringField(object graphqlObject, name string) (string, bool) {
+	for _, field := range object.Fields {
+		if strings.EqualFold(field.DomainField, name) && field.DomainType == "string" {
+			return field.DomainField, true
+		}
+	}
+	return "", false
+}
+
+// graphqlLoaders returns the loaders used by the relationships of the objects, once each
+func graphqlLoaders(objects []graphqlObject) []graphqlLoader {
+	seen := make(map[string]bool)
+	var loaders []graphqlLoader
+	for _, object := range objects {
+		for _, relation := range object.Relations {
+			if !seen[relation.Loader.Name] {
+				seen[relation.Loader.Name] = true
+				loaders = append(loaders, relation.Loader)
+			}
+		}
+	}
+	return loaders
+}
+
+// graphqlFeatureFiles generates the schema of the entities served over GraphQL, the root
+// resolver, the relationship loaders and the test helpers shared by the resolver tests
+func (s *OrchestratorService) graphqlFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	objects := s.graphqlObjects(spec)
+	if len(objects) == 0 {
+		return nil
+	}
+	elements := []domain.CodeElement{
+		s.newFileElement("GraphQLSchema", "resolvers", "internal/interfaces/graphql/resolvers/schema.graphql", s.graphqlSDL(objects)),
+		s.generateGraphQLRootElement(objects, spec),
+		s.generateGraphQLLoaderElement(),
+		s.generateGraphQLLoadersElement(objects, spec),
+	}
+	if s.anyEntity(spec, func(entity domain.EntitySpecification) bool {
+		return s.servesGraphQL(entity) && s.hasTestingFeature(entity, spec)
+	}) {
+		elements = append(elements, s.generateGraphQLTestHelpersElement(objects, spec))
+	}
+	return elements
+}
+
+// graphqlFeatureElements generates the resolvers of an entity served over GraphQL, with their
+// tests when testing is enabled
+func (s *OrchestratorService) graphqlFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	if !s.servesGraphQL(entity) {
+		return nil
+	}
+	objects := s.graphqlObjects(spec)
+	var object graphqlObject
+	for _, o := range objects {
+		if o.Name == entity.Name {
+			object = o
+		}
+	}
+
+	elements := []domain.CodeElement{s.generateGraphQLResolverElement(object, spec)}
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateGraphQLResolverTestElement(object, objects, spec))
+	}
+	return elements
+}
+
+// graphqlSDL renders the schema: one object type, input type and connection per entity, enums
+// for enum fields, and CRUD queries and mutations
+func (s *OrchestratorService) graphqlSDL(objects []graphqlObject) string {
+	var b strings.Builder
+	b.WriteString("# Code generated from the project specification. DO NOT EDIT.\n\n")
+	b.WriteString("\"An RFC 3339 date and time\"\nscalar Time\n")
+	usesJSON := false
+	for _, object := range objects {
+		for _, field := range object.Fields {
+			usesJSON = usesJSON || field.Conversion == graphqlJSON
+		}
+	}
+	if usesJSON {
+		b.WriteString("\n\"A value without a GraphQL counterpart, as JSON\"\nscalar JSON\n")
+	}
+
+	b.WriteString(`
+type PageInfo {
+  hasNextPage: Boolean!
+  endCursor: String
+}
+`)
+	for _, object := range objects {
+		for _, enum := range object.Enums {
+			fmt.Fprintf(&b, "\nenum %s {\n", enum.Name)
+			for _, value := range enum.Values {
+				fmt.Fprintf(&b, "  %s\n", value)
+			}
+			b.WriteString("}\n")
+		}
+
+		b.WriteString("\n")
+		if object.Entity.Description != "" {
+			b.WriteString(graphqlDescription(object.Entity.Description, ""))
+		}
+		fmt.Fprintf(&b, "type %s {\n  id: ID!\n", object.Name)
+		for _, field := range object.Fields {
+			if field.Description != "" {
+				b.WriteString(graphqlDescription(field.Description, "  "))
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", field.Name, field.OutputType())
+		}
+		b.WriteString("  createdAt: Time!\n  updatedAt: Time!\n")
+		for _, relation := range object.Relations {
+			if relation.Many {
+				fmt.Fprintf(&b, "  %s(first: Int, after: String): %sConnection!\n", relation.Name, relation.Target)
+			} else {
+				fmt.Fprintf(&b, "  %s: %s\n", relation.Name, relation.Target)
+			}
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\ninput %sInput {\n", object.Name)
+		for _, field := range object.Fields {
+			fmt.Fprintf(&b, "  %s: %s\n", field.Name, field.InputType())
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, `
+type %[1]sEdge {
+  cursor: String!
+  node: %[1]s!
+}
+
+type %[1]sConnection {
+  edges: [%[1]sEdge!]!
+  nodes: [%[1]s!]!
+  pageInfo: PageInfo!
+  totalCount: Int!
+}
+`, object.Name)
+	}
+
+	b.WriteString("\ntype Query {\n")
+	for _, object := range objects {
+		fmt.Fprintf(&b, "  %s(id: ID!): %s\n", s.lowerFirst(object.Name), object.Name)
+		fmt.Fprintf(&b, "  %s(first: Int, after: String): %sConnection!\n", s.lowerFirst(object.Plural), object.Name)
+	}
+	b.WriteString("}\n\ntype Mutation {\n")
+	for _, object := range objects {
+		fmt.Fprintf(&b, "  create%[1]s(input: %[1]sInput!): %[1]s!\n", object.Name)
+		fmt.Fprintf(&b, "  update%[1]s(id: ID!, input: %[1]sInput!): %[1]s!\n", object.Name)
+		fmt.Fprintf(&b, "  delete%[1]s(id: ID!): Boolean!\n", object.Name)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// graphqlDescription renders a description as a block string
+func graphqlDescription(text, indent string) string {
+	return fmt.Sprintf("%s\"\"\"\n%s%s\n%s\"\"\"\n", indent, indent, strings.ReplaceAll(text, `"""`, `\"""`), indent)
+}
+
+// generateGraphQLRootElement generates the root resolver, the schema binding and HTTP handler,
+// and the paging and scalar helpers shared by the entity resolvers
+func (s *OrchestratorService) generateGraphQLRootElement(objects []graphqlObject, spec *domain.ProjectSpecification) domain.CodeElement {
+	usesJSON := false
+	var services strings.Builder
+	for _, object := range objects {
+		fmt.Fprintf(&services, "\t%[1]s *application.%[1]sService\n", object.Name)
+		for _, field := range object.Fields {
+			usesJSON = usesJSON || field.Conversion == graphqlJSON
+		}
+	}
+
+	imports := map[string]bool{
+		"context": true, "embed": true, "encoding/base64": true, "fmt": true, "net/http": true,
+		"sort": true, "strings": true, "time": true,
+		"github.com/graph-gophers/graphql-go/relay": true,
+		spec.ModulePath + "/internal/application":   true,
+	}
+	jsonScalar := ""
+	if usesJSON {
+		imports["encoding/json"] = true
+		jsonScalar = `
+// JSON is the JSON scalar, carrying the fields that have no GraphQL counterpart as their JSON encoding
+type JSON json.RawMessage
+
+// ImplementsGraphQLType binds JSON to the JSON scalar
+func (JSON) ImplementsGraphQLType(name string) bool { return name == "JSON" }
+
+// UnmarshalGraphQL encodes an input value as JSON
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	*j = data
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding as is
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// toJSON encodes a value as JSON, returning nil for a null value
+func toJSON(value interface{}) (*JSON, error) {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	j := JSON(data)
+	return &j, nil
+}
+
+// fromJSON decodes a JSON value into a fresh value of the type of current, which only carries the type
+func fromJSON[T any](j JSON, current T) (T, error) {
+	var value T
+	err := json.Unmarshal(j, &value)
+	return value, err
+}
+`
+	}
+
+	content := fmt.Sprintf(`package resolvers
+
+import (
+%[1]s	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Schema is the GraphQL schema the resolvers implement
+//
+//go:embed schema.graphql
+var Schema string
+
+// maxParallelism bounds the resolvers run in parallel per request, and so the number of keys a
+// loader can collect in one batch
+const maxParallelism = 64
+
+// Services are the application services the resolvers delegate to
+type Services struct {
+%[2]s}
+
+// Resolver resolves the queries and mutations of the schema
+type Resolver struct {
+	services Services
+}
+
+// NewResolver creates the root resolver delegating to services
+func NewResolver(services Services) *Resolver {
+	return &Resolver{services: services}
+}
+
+// NewSchema parses the schema and binds it to the resolvers of services
+func NewSchema(services Services) (*graphql.Schema, error) {
+	return graphql.ParseSchema(Schema, NewResolver(services), graphql.MaxParallelism(maxParallelism))
+}
+
+// NewHandler serves the schema over HTTP, giving every request its own loaders
+func NewHandler(services Services) (http.Handler, error) {
+	schema, err := NewSchema(services)
+	if err != nil {
+		return nil, err
+	}
+	api := &relay.Handler{Schema: schema}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.ServeHTTP(w, r.WithContext(WithLoaders(r.Context(), NewLoaders(services))))
+	}), nil
+}
+
+// loaders returns the loaders of the request, or new ones when the request has none, in which
+// case lookups are not batched with the other resolvers
+func (r *Resolver) loaders(ctx context.Context) *Loaders {
+	if loaders, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return loaders
+	}
+	return NewLoaders(r.services)
+}
+
+// ConnectionArgs are the paging arguments of connection fields. Entities are ordered by creation
+// time then ID, and after is the cursor of the last entity of the previous page.
+type ConnectionArgs struct {
+	First *int32
+	After *string
+}
+
+// connection is a page of entities with their cursors
+type connection[T any] struct {
+	items       []T
+	cursors     []string
+	hasNextPage bool
+	total       int
+}
+
+// paginate orders items by the creation time and ID returned by key and returns the requested page
+func paginate[T any](items []T, key func(T) (time.Time, string), args ConnectionArgs) (connection[T], error) {
+	sorted := append([]T(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, idi := key(sorted[i])
+		tj, idj := key(sorted[j])
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return idi < idj
+	})
+
+	start := 0
+	if args.After != nil {
+		after, err := decodeCursor(*args.After)
+		if err != nil {
+			return connection[T]{}, err
+		}
+		start = -1
+		for i, item := range sorted {
+			if _, id := key(item); id == after {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return connection[T]{}, fmt.Errorf("cursor %%q does not match any entity", *args.After)
+		}
+	}
+	end := len(sorted)
+	if args.First != nil {
+		if *args.First < 0 {
+			return connection[T]{}, fmt.Errorf("first must not be negative")
+		}
+		if n := start + int(*args.First); n < end {
+			end = n
+		}
+	}
+
+	page := connection[T]{items: sorted[start:end], hasNextPage: end < len(sorted), total: len(sorted)}
+	for _, item := range page.items {
+		_, id := key(item)
+		page.cursors = append(page.cursors, encodeCursor(id))
+	}
+	return page, nil
+}
+
+// PageInfo returns whether more entities follow the page and the cursor of its last entity
+func (c connection[T]) PageInfo() *PageInfoResolver {
+	info := &PageInfoResolver{hasNextPage: c.hasNextPage}
+	if len(c.cursors) > 0 {
+		info.endCursor = &c.cursors[len(c.cursors)-1]
+	}
+	return info
+}
+
+// TotalCount returns the number of entities across all pages
+func (c connection[T]) TotalCount() int32 {
+	return int32(c.total)
+}
+
+// PageInfoResolver resolves the PageInfo of a connection
+type PageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *PageInfoResolver) HasNextPage() bool  { return p.hasNextPage }
+func (p *PageInfoResolver) EndCursor() *string { return p.endCursor }
+
+const cursorPrefix = "cursor:"
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return "", fmt.Errorf("invalid cursor %%q", cursor)
+	}
+	return strings.TrimPrefix(string(data), cursorPrefix), nil
+}
+
+// ptr returns a pointer to a copy of value
+func ptr[T any](value T) *T {
+	return &value
+}
+%[3]s`, s.formatImports(imports), services.String(), jsonScalar)
+
+	// embed is imported for its side effect only
+	content = strings.Replace(content, "\t\"embed\"\n", "\t_ \"embed\"\n", 1)
+	return s.newFileElement("GraphQLResolver", "resolvers", "internal/interfaces/graphql/resolvers/schema.go", content)
+}
+
+// generateGraphQLLoaderElement generates the batching loader the relationship fields go through
+func (s *OrchestratorService) generateGraphQLLoaderElement() domain.CodeElement {
+	content := `package resolvers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchWindow is how long a loader collects keys before loading them in one batch
+var BatchWindow = 2 * time.Millisecond
+
+// BatchFunc loads the values of keys. Keys missing from the result load the zero value.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader batches the keys loaded within BatchWindow of each other into a single call of its batch
+// function, and caches the values for its lifetime, which is one request. Resolving a relationship
+// for every entity of a list thus costs one load instead of one per entity.
+type Loader[K comparable, V any] struct {
+	batch BatchFunc[K, V]
+	wait  time.Duration
+
+	mu      sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+}
+
+// NewLoader creates a loader calling batch
+func NewLoader[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{batch: batch, wait: BatchWindow, cache: make(map[K]*loaderResult[V])}
+}
+
+// Load returns the value of key, loading it with the other keys requested within the batch window
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = result
+		if l.pending == nil {
+			batch := &loaderBatch[K, V]{}
+			l.pending = batch
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, batch) })
+		}
+		l.pending.keys = append(l.pending.keys, key)
+		l.pending.results = append(l.pending.results, result)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch loads a batch and hands each key its value
+func (l *Loader[K, V]) dispatch(ctx context.Context, batch *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending == batch {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.batch(ctx, batch.keys)
+	for i, key := range batch.keys {
+		result := batch.results[i]
+		result.value, result.err = values[key], err
+		close(result.done)
+	}
+}
+`
+	return s.newFileElement("GraphQLLoader", "resolvers", "internal/interfaces/graphql/resolvers/loader.go", content)
+}
+
+// generateGraphQLLoadersElement generates the loaders of the relationship fields. Repositories
+// have no batch lookup, so a batch lists the targets once and keeps those matching its keys.
+func (s *OrchestratorService) generateGraphQLLoadersElement(objects []graphqlObject, spec *domain.ProjectSpecification) domain.CodeElement {
+	loaders := graphqlLoaders(objects)
+	imports := map[string]bool{"context": true}
+	var fields, constructors strings.Builder
+	for _, loader := range loaders {
+		imports[spec.ModulePath+"/internal/domain"] = true
+		value, add := "*domain."+loader.Target, "loaded[item.%[1]s] = item"
+		if loader.Many {
+			value, add = "[]*domain."+loader.Target, "loaded[item.%[1]s] = append(loaded[item.%[1]s], item)"
+		}
+		fmt.Fprintf(&fields, "\t%s *Loader[string, %s]\n", loader.Name, value)
+		fmt.Fprintf(&constructors, `		%[1]s: NewLoader(func(ctx context.Context, keys []string) (map[string]%[2]s, error) {
+			items, err := services.%[3]s.List(ctx)
+			if err != nil {
+				return nil, err
+			}
+			wanted := keySet(keys)
+			loaded := make(map[string]%[2]s, len(keys))
+			for _, item := range items {
+				if wanted[item.%[4]s] {
+					%[5]s
+				}
+			}
+			return loaded, nil
+		}),
+`, loader.Name, value, loader.Target, loader.ForeignKey, fmt.Sprintf(add, loader.ForeignKey))
+	}
+
+	content := fmt.Sprintf(`package resolvers
+
+import (
+%[1]s)
+
+// Loaders batch the relationship lookups of a request
+type Loaders struct {
+%[2]s}
+
+// NewLoaders creates the loaders of a request, loading from services
+func NewLoaders(services Services) *Loaders {
+	return &Loaders{
+%[3]s	}
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns a context carrying the loaders of a request
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+`, s.formatImports(imports), fields.String(), constructors.String())
+
+	return s.newFileElement("GraphQLLoaders", "resolvers", "internal/interfaces/graphql/resolvers/loaders.go", content)
+}
+
+// generateGraphQLResolverElement generates the queries and mutations of an entity, the resolvers
+// of its object, edge and connection types, and its input type
+func (s *OrchestratorService) generateGraphQLResolverElement(object graphqlObject, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := object.Name
+	imports := map[string]bool{
+		"context": true, "errors": true, "time": true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	var methods, relations, inputFields, toInput, toInputPost, apply, enumMaps strings.Builder
+	usesJSON := false
+	for _, field := range object.Fields {
+		item := "r.item." + field.DomainField
+		fmt.Fprintf(&inputFields, "\t%s %s `json:\"%s", field.GoName, field.InputGoType(), field.Name)
+		if field.InputGoType() != field.GoType {
+			inputFields.WriteString(",omitempty")
+		}
+		inputFields.WriteString("\"`\n")
+
+		switch field.Conversion {
+		case graphqlAssign:
+			if field.GoType == "[]string" {
+				fmt.Fprintf(&methods, "\nfunc (r *%[1]sResolver) %[2]s() []string {\n\tif %[3]s == nil {\n\t\treturn []string{}\n\t}\n\treturn %[3]s\n}\n", name, field.GoName, item)
+			} else {
+				fmt.Fprintf(&methods, "\nfunc (r *%sResolver) %s() %s { return %s }\n", name, field.GoName, field.GoType, item)
+			}
+		case graphqlCast:
+			fmt.Fprintf(&methods, "\nfunc (r *%sResolver) %s() %s { return %s(%s) }\n", name, field.GoName, field.GoType, field.GoType, item)
+		case graphqlTime:
+			fmt.Fprintf(&methods, "\nfunc (r *%sResolver) %s() graphql.Time { return graphql.Time{Time: %s} }\n", name, field.GoName, item)
+		case graphqlEnum:
+			fmt.Fprintf(&methods, `
+func (r *%[1]sResolver) %[2]s() *string {
+	if value, ok := %[3]sToGraphQL[%[4]s]; ok {
+		return &value
+	}
+	return nil
+}
+`, name, field.GoName, s.lowerFirst(field.DomainType), item)
+		case graphqlJSON:
+			usesJSON = true
+			fmt.Fprintf(&methods, "\nfunc (r *%sResolver) %s() (*JSON, error) { return toJSON(%s) }\n", name, field.GoName, item)
+		}
+
+		s.writeGraphQLInputConversions(name, field, &toInput, &toInputPost, &apply)
+	}
+	for _, enum := range object.Enums {
+		maps := s.lowerFirst(enum.Name)
+		fmt.Fprintf(&enumMaps, "\nvar %sToGraphQL = map[domain.%s]string{\n", maps, enum.Name)
+		for i, value := range enum.Values {
+			fmt.Fprintf(&enumMaps, "\tdomain.%s: %q,\n", enum.DomainVals[i], value)
+		}
+		fmt.Fprintf(&enumMaps, "}\n\nvar %sFromGraphQL = map[string]domain.%s{\n", maps, enum.Name)
+		for i, value := range enum.Values {
+			fmt.Fprintf(&enumMaps, "\t%q: domain.%s,\n", value, enum.DomainVals[i])
+		}
+		enumMaps.WriteString("}\n")
+	}
+	declareErr := ""
+	if usesJSON {
+		imports["fmt"] = true
+		declareErr = "\tvar err error\n"
+	}
+
+	for _, relation := range object.Relations {
+		if relation.Many {
+			fmt.Fprintf(&relations, `
+// %[2]s resolves a page of the %[3]s relationship, loaded in one batch with the other lookups of the request
+func (r *%[1]sResolver) %[2]s(ctx context.Context, args ConnectionArgs) (*%[4]sConnectionResolver, error) {
+	items, err := r.root.loaders(ctx).%[5]s.Load(ctx, r.item.%[6]s)
+	if err != nil {
+		return nil, err
+	}
+	return new%[4]sConnection(r.root, items, args)
+}
+`, name, relation.GoName, relation.Name, relation.Target, relation.Loader.Name, object.ID)
+		} else {
+			fmt.Fprintf(&relations, `
+// %[2]s resolves the %[3]s relationship, loaded in one batch with the other lookups of the request
+func (r *%[1]sResolver) %[2]s(ctx context.Context) (*%[4]sResolver, error) {
+	if r.item.%[6]s == "" {
+		return nil, nil
+	}
+	item, err := r.root.loaders(ctx).%[5]s.Load(ctx, r.item.%[6]s)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return &%[4]sResolver{root: r.root, item: item}, nil
+}
+`, name, relation.GoName, relation.Name, relation.Target, relation.Loader.Name, relation.ForeignKey)
+		}
+	}
+
+	content := fmt.Sprintf(`package resolvers
+
+import (
+%[1]s	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// %[2]s returns the %[2]s with the given ID, or null when there is none
+func (r *Resolver) %[2]s(ctx context.Context, args struct{ ID graphql.ID }) (*%[2]sResolver, error) {
+	item, err := r.services.%[2]s.Get(ctx, string(args.ID))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]sResolver{root: r, item: item}, nil
+}
+
+// %[3]s returns a page of %[2]s entities
+func (r *Resolver) %[3]s(ctx context.Context, args ConnectionArgs) (*%[2]sConnectionResolver, error) {
+	items, err := r.services.%[2]s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new%[2]sConnection(r, items, args)
+}
+
+// Create%[2]sArgs are the arguments of the create%[2]s mutation
+type Create%[2]sArgs struct {
+	Input %[2]sInput
+}
+
+// Create%[2]s creates a %[2]s from its input
+func (r *Resolver) Create%[2]s(ctx context.Context, args Create%[2]sArgs) (*%[2]sResolver, error) {
+	item := &domain.%[2]s{}
+	if err := args.Input.apply(item); err != nil {
+		return nil, err
+	}
+	created, err := r.services.%[2]s.Create(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]sResolver{root: r, item: created}, nil
+}
+
+// Update%[2]sArgs are the arguments of the update%[2]s mutation
+type Update%[2]sArgs struct {
+	ID    graphql.ID
+	Input %[2]sInput
+}
+
+// Update%[2]s updates an existing %[2]s. Optional fields left out of the input keep their value.
+func (r *Resolver) Update%[2]s(ctx context.Context, args Update%[2]sArgs) (*%[2]sResolver, error) {
+	item, err := r.services.%[2]s.Get(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Input.apply(item); err != nil {
+		return nil, err
+	}
+	updated, err := r.services.%[2]s.Update(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]sResolver{root: r, item: updated}, nil
+}
+
+// Delete%[2]s removes the %[2]s with the given ID
+func (r *Resolver) Delete%[2]s(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := r.services.%[2]s.Delete(ctx, string(args.ID)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// %[2]sResolver resolves the fields of a %[2]s
+type %[2]sResolver struct {
+	root *Resolver
+	item *domain.%[2]s
+}
+
+func (r *%[2]sResolver) ID() graphql.ID { return graphql.ID(r.item.%[4]s) }
+%[5]s
+func (r *%[2]sResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.item.CreatedAt} }
+
+func (r *%[2]sResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.item.UpdatedAt} }
+%[12]s
+// %[2]sInput is the input of the create%[2]s and update%[2]s mutations
+type %[2]sInput struct {
+%[6]s}
+
+// New%[2]sInput returns the input holding the fields of item, e.g. to build mutation variables
+func New%[2]sInput(item *domain.%[2]s) (%[2]sInput, error) {
+%[7]s	in := %[2]sInput{
+%[8]s	}
+%[9]s	return in, nil
+}
+
+// apply sets the fields of item from the input, leaving the optional fields it omits unchanged
+func (in %[2]sInput) apply(item *domain.%[2]s) error {
+%[10]s	return nil
+}
+
+// %[2]sConnectionResolver resolves a page of %[2]s entities
+type %[2]sConnectionResolver struct {
+	connection[*domain.%[2]s]
+	root *Resolver
+}
+
+func new%[2]sConnection(root *Resolver, items []*domain.%[2]s, args ConnectionArgs) (*%[2]sConnectionResolver, error) {
+	page, err := paginate(items, func(item *domain.%[2]s) (time.Time, string) { return item.CreatedAt, item.%[4]s }, args)
+	if err != nil {
+		return nil, err
+	}
+	return &%[2]sConnectionResolver{connection: page, root: root}, nil
+}
+
+// Edges returns the entities of the page with their cursors
+func (c *%[2]sConnectionResolver) Edges() []*%[2]sEdgeResolver {
+	edges := make([]*%[2]sEdgeResolver, len(c.items))
+	for i, item := range c.items {
+		edges[i] = &%[2]sEdgeResolver{cursor: c.cursors[i], node: &%[2]sResolver{root: c.root, item: item}}
+	}
+	return edges
+}
+
+// Nodes returns the entities of the page
+func (c *%[2]sConnectionResolver) Nodes() []*%[2]sResolver {
+	nodes := make([]*%[2]sResolver, len(c.items))
+	for i, item := range c.items {
+		nodes[i] = &%[2]sResolver{root: c.root, item: item}
+	}
+	return nodes
+}
+
+// %[2]sEdgeResolver resolves a %[2]s of a page with its cursor
+type %[2]sEdgeResolver struct {
+	cursor string
+	node   *%[2]sResolver
+}
+
+func (e *%[2]sEdgeResolver) Cursor() string        { return e.cursor }
+func (e *%[2]sEdgeResolver) Node() *%[2]sResolver { return e.node }
+%[11]s`, s.formatImports(imports), name, object.Plural, object.ID, methods.String(),
+		inputFields.String(), declareErr, toInput.String(), toInputPost.String(), apply.String(), enumMaps.String(),
+		relations.String())
+
+	return s.newFileElement(
+		fmt.Sprintf("%sGraphQLResolver", name),
+		"resolvers",
+		fmt.Sprintf("internal/interfaces/graphql/resolvers/%s_resolver.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// writeGraphQLInputConversions writes the conversions of a field from a domain item to the input
+// (in the struct literal, or after it when the conversion can fail or be skipped) and back
+func (s *OrchestratorService) writeGraphQLInputConversions(entity string, field graphqlField, toInput, toInputPost, apply *strings.Builder) {
+	item := "item." + field.DomainField
+	input := "in." + field.GoName
+	maps := s.lowerFirst(field.DomainType)
+	optional := field.InputGoType() != field.GoType
+
+	// Domain value to input value, and input value to domain value
+	var out, back string
+	switch field.Conversion {
+	case graphqlAssign:
+		out, back = item, "%s"
+	case graphqlCast:
+		out, back = fmt.Sprintf("%s(%s)", field.GoType, item), field.DomainType+"(%s)"
+	case graphqlTime:
+		out, back = fmt.Sprintf("graphql.Time{Time: %s}", item), "%s.Time"
+	case graphqlEnum:
+		back = maps + "FromGraphQL[%s]"
+		if optional {
+			fmt.Fprintf(toInputPost, "\tif value, ok := %sToGraphQL[%s]; ok {\n\t\t%s = &value\n\t}\n", maps, item, input)
+		} else {
+			out = fmt.Sprintf("%sToGraphQL[%s]", maps, item)
+		}
+	case graphqlJSON:
+		fmt.Fprintf(toInputPost, "\tif %s, err = toJSON(%s); err != nil {\n\t\treturn %sInput{}, fmt.Errorf(\"%s: %%w\", err)\n\t}\n",
+			input, item, entity, field.Name)
+		fmt.Fprintf(apply, `	if %[1]s != nil {
+		value, err := fromJSON(*%[1]s, %[2]s)
+		if err != nil {
+			return fmt.Errorf("%[3]s: %%w", err)
+		}
+		%[2]s = value
+	}
+`, input, item, field.Name)
+		return
+	}
+
+	switch {
+	case out == "":
+	case optional:
+		fmt.Fprintf(toInput, "\t\t%s: ptr(%s),\n", field.GoName, out)
+	default:
+		fmt.Fprintf(toInput, "\t\t%s: %s,\n", field.GoName, out)
+		if field.GoType == "[]string" {
+			fmt.Fprintf(toInputPost, "\tif %[1]s == nil {\n\t\t%[1]s = []string{}\n\t}\n", input)
+		}
+	}
+	if optional {
+		value := "*" + input
+		if field.Conversion == graphqlTime {
+			value = input // Selectors dereference the pointer
+		}
+		fmt.Fprintf(apply, "\tif %s != nil {\n\t\t%s = %s\n\t}\n", input, item, fmt.Sprintf(back, value))
+	} else {
+		fmt.Fprintf(apply, "\t%s = %s\n", item, fmt.Sprintf(back, input))
+	}
+}
+
+// generateGraphQLTestHelpersElement generates the helpers shared by the resolver tests, including
+// repositories counting their List calls to check that relationships are loaded in batches
+func (s *OrchestratorService) generateGraphQLTestHelpersElement(objects []graphqlObject, spec *domain.ProjectSpecification) domain.CodeElement {
+	var counting strings.Builder
+	for _, object := range objects {
+		if !s.hasTestingFeature(object.Entity, spec) {
+			continue
+		}
+		fmt.Fprintf(&counting, `
+// counting%[1]sRepository counts the List calls made to a %[1]s repository
+type counting%[1]sRepository struct {
+	domain.%[1]sRepository
+	lists atomic.Int32
+}
+
+func (r *counting%[1]sRepository) List(ctx context.Context) ([]*domain.%[1]s, error) {
+	r.lists.Add(1)
+	return r.%[1]sRepository.List(ctx)
+}
+`, object.Name)
+	}
+
+	// The requests are those of the tenant of the fixtures when entities are tenant-scoped
+	withTenant, fixturesImport := "", ""
+	if s.usesTenancy(spec) {
+		withTenant = "\treq = req.WithContext(fixtures.WithTenant(req.Context()))\n"
+		fixturesImport = fmt.Sprintf("\t%q\n", spec.ModulePath+"/internal/fixtures")
+	}
+
+	content := fmt.Sprintf(`package resolvers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"%[1]s/internal/domain"
+%[4]s	"%[1]s/internal/interfaces/graphql/resolvers"
+)
+
+func newHandler(t *testing.T, services resolvers.Services) http.Handler {
+	t.Helper()
+	handler, err := resolvers.NewHandler(services)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %%v", err)
+	}
+	return handler
+}
+
+// execute runs a GraphQL request and decodes its data into out, failing on any error
+func execute(t *testing.T, handler http.Handler, query string, variables map[string]interface{}, out interface{}) {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatalf("encoding request: %%v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+%[3]s	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var response struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding response %%q: %%v", rec.Body.String(), err)
+	}
+	if len(response.Errors) > 0 {
+		t.Fatalf("%%s: errors = %%+v", query, response.Errors)
+	}
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		t.Fatalf("decoding data %%s: %%v", response.Data, err)
+	}
+}
+%[2]s`, spec.ModulePath, counting.String(), withTenant, fixturesImport)
+
+	return s.newFileElement("GraphQLTestHelpers", "resolvers_test", "internal/interfaces/graphql/resolvers/resolvers_test.go", content)
+}
+
+// generateGraphQLResolverTestElement tests the resolvers of an entity over HTTP against the
+// in-memory repository, and checks that its relationships are loaded in one batch
+func (s *OrchestratorService) generateGraphQLResolverTestElement(object graphqlObject, objects []graphqlObject, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := object.Name
+	selection := []string{"id"}
+	for _, field := range object.Fields {
+		selection = append(selection, field.Name)
+	}
+	selection = append(selection, "createdAt", "updatedAt")
+
+	var batching strings.Builder
+	imports := map[string]bool{
+		"testing": true,
+		spec.ModulePath + "/internal/application":                  true,
+		spec.ModulePath + "/internal/fixtures":                     true,
+		spec.ModulePath + "/internal/infrastructure/memory":        true,
+		spec.ModulePath + "/internal/interfaces/graphql/resolvers": true,
+	}
+	for _, relation := range object.Relations {
+		target, ok := s.findEntity(spec, relation.Target)
+		if !ok || !s.hasTestingFeature(target, spec) {
+			continue
+		}
+		imports["context"] = true
+		imports[spec.ModulePath+"/internal/domain"] = true
+
+		// The referenced entities are created first, then the entities holding the key
+		referenced, holder, referencedID := relation.Target, name, s.idFieldName(target)
+		check := fmt.Sprintf(`	var data struct {
+		%[1]s struct {
+			Nodes []struct{ %[2]s *struct{ ID string } }
+		}
+	}
+	execute(t, handler, "{ %[3]s { nodes { %[4]s { id } } } }", nil, &data)
+	if len(data.%[1]s.Nodes) != len(holders) {
+		t.Fatalf("%[3]s = %%d nodes, want %%d", len(data.%[1]s.Nodes), len(holders))
+	}
+	for i, node := range data.%[1]s.Nodes {
+		if node.%[2]s == nil {
+			t.Errorf("node %%d: %[4]s = null", i)
+		}
+	}
+`, object.Plural, relation.GoName, s.lowerFirst(object.Plural), relation.Name)
+		if relation.Many {
+			referenced, holder, referencedID = name, relation.Target, object.ID
+			check = fmt.Sprintf(`	var data struct {
+		%[1]s struct {
+			Nodes []struct{ %[2]s struct{ TotalCount int } }
+		}
+	}
+	execute(t, handler, "{ %[3]s { nodes { %[4]s { totalCount } } } }", nil, &data)
+	total := 0
+	for _, node := range data.%[1]s.Nodes {
+		total += node.%[2]s.TotalCount
+	}
+	if total != len(holders) {
+		t.Errorf("%[4]s totalCount sum = %%d, want %%d", total, len(holders))
+	}
+`, object.Plural, relation.GoName, s.lowerFirst(object.Plural), relation.Name)
+		}
+
+		ctx := "context.Background()"
+		if s.usesTenancy(spec) {
+			ctx = "fixtures.WithTenant(context.Background())"
+		}
+		services := fmt.Sprintf("\t\t%[1]s: application.New%[1]sService(counting),\n", relation.Target)
+		if relation.Target != name {
+			services += fmt.Sprintf("\t\t%[1]s: application.New%[1]sService(memory.New%[1]sRepository()),\n", name)
+		}
+		fmt.Fprintf(&batching, `
+func Test%[1]s%[2]sIsBatched(t *testing.T) {
+	ctx := %[10]s
+	counting := &counting%[3]sRepository{%[3]sRepository: memory.New%[3]sRepository()}
+	services := resolvers.Services{
+%[4]s	}
+
+	var referenced []*domain.%[5]s
+	for i := 0; i < 3; i++ {
+		item, err := services.%[5]s.Create(ctx, fixtures.New%[5]sBuilder().Build())
+		if err != nil {
+			t.Fatalf("creating %[5]s: %%v", err)
+		}
+		referenced = append(referenced, item)
+	}
+	var holders []*domain.%[6]s
+	for i := 0; i < 6; i++ {
+		holder := fixtures.New%[6]sBuilder().Build()
+		holder.%[7]s = referenced[i%%len(referenced)].%[8]s
+		item, err := services.%[6]s.Create(ctx, holder)
+		if err != nil {
+			t.Fatalf("creating %[6]s: %%v", err)
+		}
+		holders = append(holders, item)
+	}
+
+	// Leave room for every resolver to join the batch on a busy machine
+	defer func(window time.Duration) { resolvers.BatchWindow = window }(resolvers.BatchWindow)
+	resolvers.BatchWindow = 50 * time.Millisecond
+	handler := newHandler(t, services)
+	counting.lists.Store(0)
+
+%[9]s
+	if calls := counting.lists.Load(); calls != 1 {
+		t.Errorf("%[3]s List() calls = %%d, want 1", calls)
+	}
+}
+`, name, relation.GoName, relation.Target, services, referenced, holder, relation.ForeignKey,
+			referencedID, check, ctx)
+		imports["time"] = true
+	}
+
+	content := fmt.Sprintf(`package resolvers_test
+
+import (
+%[1]s)
+
+func Test%[2]sResolverLifecycle(t *testing.T) {
+	handler := newHandler(t, resolvers.Services{%[2]s: application.New%[2]sService(memory.New%[2]sRepository())})
+
+	input, err := resolvers.New%[2]sInput(fixtures.New%[2]sBuilder().Build())
+	if err != nil {
+		t.Fatalf("New%[2]sInput() error = %%v", err)
+	}
+	var created struct{ Create%[2]s struct{ ID string } }
+	execute(t, handler, "mutation($input: %[2]sInput!) { create%[2]s(input: $input) { id } }",
+		map[string]interface{}{"input": input}, &created)
+	id := created.Create%[2]s.ID
+	if id == "" {
+		t.Fatal("create%[2]s did not assign an ID")
+	}
+
+	var got struct{ %[2]s *struct{ ID string } }
+	execute(t, handler, "query($id: ID!) { %[3]s(id: $id) { %[5]s } }", map[string]interface{}{"id": id}, &got)
+	if got.%[2]s == nil || got.%[2]s.ID != id {
+		t.Fatalf("%[3]s = %%+v, want ID %%s", got.%[2]s, id)
+	}
+
+	var updated struct{ Update%[2]s struct{ ID string } }
+	execute(t, handler, "mutation($id: ID!, $input: %[2]sInput!) { update%[2]s(id: $id, input: $input) { id } }",
+		map[string]interface{}{"id": id, "input": input}, &updated)
+	if updated.Update%[2]s.ID != id {
+		t.Errorf("update%[2]s ID = %%s, want %%s", updated.Update%[2]s.ID, id)
+	}
+
+	var list struct {
+		%[4]s struct {
+			TotalCount int
+			Edges      []struct {
+				Cursor string
+				Node   struct{ ID string }
+			}
+			PageInfo struct{ HasNextPage bool }
+		}
+	}
+	execute(t, handler, "{ %[6]s(first: 1) { totalCount edges { cursor node { id } } pageInfo { hasNextPage } } }", nil, &list)
+	if list.%[4]s.TotalCount != 1 || len(list.%[4]s.Edges) != 1 || list.%[4]s.Edges[0].Node.ID != id || list.%[4]s.PageInfo.HasNextPage {
+		t.Errorf("%[6]s = %%+v, want one page holding %%s", list.%[4]s, id)
+	}
+
+	var deleted struct{ Delete%[2]s bool }
+	execute(t, handler, "mutation($id: ID!) { delete%[2]s(id: $id) }", map[string]interface{}{"id": id}, &deleted)
+	if !deleted.Delete%[2]s {
+		t.Error("delete%[2]s = false, want true")
+	}
+	got.%[2]s = nil
+	execute(t, handler, "query($id: ID!) { %[3]s(id: $id) { id } }", map[string]interface{}{"id": id}, &got)
+	if got.%[2]s != nil {
+		t.Errorf("%[3]s after delete%[2]s = %%+v, want null", got.%[2]s)
+	}
+}
+%[7]s`, s.formatImports(imports), name, s.lowerFirst(name), object.Plural, strings.Join(selection, " "),
+		s.lowerFirst(object.Plural), batching.String())
+
+	return s.newFileElement(
+		fmt.Sprintf("%sGraphQLResolverTest", name),
+		"resolvers_test",
+		fmt.Sprintf("internal/interfaces/graphql/resolvers/%s_resolver_test.go", s.toSnakeCase(name)),
+		content,
+	)
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedMainMountsGraphQL(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config", "testing"},
+		Entities: []domain.EntitySpecification{
+			{
+				Name:     "Author",
+				Features: []string{"crud", "graphql_api"},
+				Fields:   []domain.FieldSpecification{{Name: "name", Type: "string", Required: true}},
+			},
+			{
+				Name:     "Book",
+				Features: []string{"crud", "rest_api", "graphql_api"},
+				Fields:   []domain.FieldSpecification{{Name: "title", Type: "string", Required: true}},
+			},
+		},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	main, err := os.ReadFile(filepath.Join(dir, "cmd", "shop", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Author: application.NewAuthorService(repos.Author),",
+		"Book:   application.NewBookService(repos.Book),",
+		`mux.Handle("POST /graphql", graphqlHandler)`,
+		"handlers.NewBookHandler(repos.Book)",
+	} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go does not contain %s:\n%s", want, main)
+		}
+	}
+	// Entities served over GraphQL only get no REST handler
+	if strings.Contains(string(main), "NewAuthorHandler") {
+		t.Errorf("main.go serves Author over REST:\n%s", main)
+	}
+}
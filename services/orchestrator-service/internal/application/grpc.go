@@ -0,0 +1,721 @@
+package application
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// Modules required by the generated protobuf messages and gRPC services. The protobuf version
+// is that of the protoc-gen-go plugin generating the messages.
+var (
+	grpcModule     = domain.ModuleDependency{Path: "google.golang.org/grpc", Version: "v1.64.1"}
+	protobufModule = domain.ModuleDependency{Path: "google.golang.org/protobuf", Version: "v1.34.1"}
+)
+
+// servesGRPC reports whether an entity is served by a gRPC server, which needs a repository for
+// the application service it delegates to
+func (s *OrchestratorService) servesGRPC(entity domain.EntitySpecification) bool {
+	return s.hasFeature(entity.Features, "grpc_api") && s.hasRepository(entity)
+}
+
+// grpcMain holds the statements of the generated main serving the gRPC servers: setup creates
+// the server and its listener before the HTTP server, serve runs it, and stop and wait stop it
+// gracefully around the shutdown of the HTTP server
+type grpcMain struct {
+	setup, serve, stop, wait string
+}
+
+// serveGRPC generates the statements serving the gRPC servers registered by register, with the
+// authentication and tenant resolution of the HTTP entity routes
+func (s *OrchestratorService) serveGRPC(register string, authenticate bool, spec *domain.ProjectSpecification, imports map[string]bool) grpcMain {
+	imports["net"] = true
+	imports["google.golang.org/grpc"] = true
+	imports["google.golang.org/grpc/credentials"] = true
+	imports[spec.ModulePath+"/internal/application"] = true
+	imports[spec.ModulePath+"/internal/interfaces/grpc/servers"] = true
+
+	var interceptors strings.Builder
+	if authenticate {
+		interceptors.WriteString(`	if len(cfg.Security.Authentication) > 0 {
+		chain, err := authenticators.Of(cfg.Security.Authentication...)
+		if err != nil {
+			return err
+		}
+		interceptors = append(interceptors, servers.AuthInterceptor(chain...))
+	}
+`)
+	}
+	if s.usesTenancy(spec) {
+		interceptors.WriteString("\tinterceptors = append(interceptors, servers.TenantInterceptor(\n")
+		if s.usesSecurity(spec) {
+			interceptors.WriteString("\t\tservers.TenantFromClaims(\"tenant_id\", \"client_id\"),\n")
+		}
+		interceptors.WriteString("\t\tservers.TenantFromMetadata(\"x-tenant-id\", \"x-client-id\"),\n\t))\n")
+	}
+	options := "grpcOptions..."
+	if interceptors.Len() > 0 {
+		options = "append(grpcOptions, grpc.ChainUnaryInterceptor(interceptors...))..."
+	}
+	declare := ""
+	if interceptors.Len() > 0 {
+		declare = "\t// Calls are authenticated and resolve their tenant as the HTTP entity routes do\n\tvar interceptors []grpc.UnaryServerInterceptor\n"
+	}
+
+	return grpcMain{
+		setup: fmt.Sprintf(`
+	var grpcOptions []grpc.ServerOption
+	if cfg.Server.TLS {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Server.CertFile, cfg.Server.KeyFile)
+		if err != nil {
+			return err
+		}
+		grpcOptions = append(grpcOptions, grpc.Creds(creds))
+	}
+%s%s	grpcServer := grpc.NewServer(%s)
+%s	grpcListener, err := net.Listen("tcp", cfg.Server.GRPCAddress())
+	if err != nil {
+		return err
+	}
+`, declare, interceptors.String(), options, register),
+		serve: `	go func() {
+		slog.Info("serving gRPC", "address", grpcListener.Addr().String(), "tls", cfg.Server.TLS)
+		errc <- grpcServer.Serve(grpcListener)
+	}()
+`,
+		stop: `	// The gRPC server drains its calls while the HTTP server shuts down
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+`,
+		wait: `	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
+`,
+	}
+}
+
+// grpcFeatureFiles generates the .proto file of the entities served over gRPC, its Go messages
+// and service stubs, the numbering lockfile and the helpers shared by the servers
+func (s *OrchestratorService) grpcFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	schema := s.protoSchema(spec)
+	if len(schema.Services) == 0 {
+		return nil
+	}
+	dir, base := path.Split(schema.Path)
+	goDir := "gen/proto/" + strings.TrimSuffix(dir, "/")
+	stem := strings.TrimSuffix(base, ".proto")
+
+	messages, err := schema.generateGoCode()
+	if err != nil {
+		// Names are checked by the specification validation, so this is not expected; keep the
+		// cause in the output rather than failing the whole project
+		messages = fmt.Sprintf("// protoc-gen-go failed on %s: %v\npackage %s\n", schema.Path, err, schema.GoPackage)
+	}
+
+	elements := []domain.CodeElement{
+		s.newFileElement(schema.GoPackage+"Proto", "proto", "api/proto/"+schema.Path, schema.render()),
+		s.newFileElement("ProtoLock", "proto", "api/proto/proto.lock.json", schema.lockFile()),
+		s.newFileElement(schema.GoPackage+"Messages", schema.GoPackage, goDir+"/"+stem+".pb.go", messages),
+		s.generateGRPCStubsElement(schema, goDir+"/"+stem+"_grpc.pb.go"),
+	}
+	if s.anyEntity(spec, s.servesGRPC) {
+		elements = append(elements, s.generateGRPCConvertElement(schema, spec))
+		if s.usesSecurity(spec) {
+			elements = append(elements, s.generateGRPCAuthElement(spec))
+		}
+		if s.usesTenancy(spec) {
+			elements = append(elements, s.generateGRPCTenantElement(spec))
+		}
+	}
+	return elements
+}
+
+// generateGRPCAuthElement generates the interceptor authenticating gRPC calls with the
+// authenticators of the HTTP middleware
+func (s *OrchestratorService) generateGRPCAuthElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	imports := map[string]bool{
+		"context": true, "errors": true, "net/http": true,
+		"google.golang.org/grpc": true, "google.golang.org/grpc/codes": true,
+		"google.golang.org/grpc/metadata": true, "google.golang.org/grpc/status": true,
+		spec.ModulePath + "/internal/interfaces/http/middleware": true,
+	}
+	actor := ""
+	if s.anyEntity(spec, s.audited) {
+		imports[spec.ModulePath+"/internal/domain"] = true
+		actor = `			if principal.Subject != "" {
+				ctx = domain.WithActor(ctx, principal.Subject)
+			}
+`
+	}
+
+	content := fmt.Sprintf(`package servers
+
+import (
+%[1]s)
+
+// AuthInterceptor authenticates each call with the first authenticator its metadata has
+// credentials for, as middleware.Authenticate does for HTTP requests: the authenticators read the
+// metadata as the headers of a POST to the method. The principal is stored in the context of the
+// call. Calls without credentials or with invalid ones fail with Unauthenticated.
+func AuthInterceptor(authenticators ...middleware.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, info.FullMethod, nil)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		for key, values := range md {
+			for _, value := range values {
+				r.Header.Add(key, value)
+			}
+		}
+		for _, authenticator := range authenticators {
+			principal, err := authenticator.Authenticate(r)
+			if errors.Is(err, middleware.ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+			}
+			ctx = middleware.WithPrincipal(ctx, principal)
+%[2]s			return handler(ctx, req)
+		}
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+}
+`, s.formatImports(imports), actor)
+	return s.newFileElement("auth", "servers", "internal/interfaces/grpc/servers/auth.go", content)
+}
+
+// grpcFeatureElements generates the gRPC server of an entity that has a repository to serve
+// from, with its tests when testing is enabled
+func (s *OrchestratorService) grpcFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	if !s.hasRepository(entity) {
+		return nil
+	}
+	schema := s.protoSchema(spec)
+	var message protoMessage
+	for _, m := range schema.Messages {
+		if m.Name == entity.Name {
+			message = m
+		}
+	}
+
+	elements := []domain.CodeElement{s.generateGRPCServerElement(entity, schema, message, spec)}
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateGRPCServerTestElement(entity, schema, spec))
+	}
+	return elements
+}
+
+// generateGRPCStubsElement generates the client, server interface and service descriptor of each
+// service, as protoc-gen-go-grpc would
+func (s *OrchestratorService) generateGRPCStubsElement(schema protoSchema, file string) domain.CodeElement {
+	var b strings.Builder
+	fmt.Fprintf(&b, `// Code generated from %[1]s. DO NOT EDIT.
+
+package %[2]s
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Requires gRPC-Go v1.64.0 or later
+const _ = grpc.SupportPackageIsVersion9
+`, schema.Path, schema.GoPackage)
+
+	for _, service := range schema.Services {
+		name := service.Name
+		methods := service.Methods()
+		goType := func(message string) string {
+			if message == "google.protobuf.Empty" {
+				return "emptypb.Empty"
+			}
+			return message
+		}
+
+		b.WriteString("\nconst (\n")
+		for _, method := range methods {
+			fmt.Fprintf(&b, "\t%s_%s_FullMethodName = \"/%s.%s/%s\"\n", name, method.Name, schema.Package, name, method.Name)
+		}
+		b.WriteString(")\n")
+
+		// Client
+		fmt.Fprintf(&b, "\n// %[1]sClient is the client API for %[1]s\ntype %[1]sClient interface {\n", name)
+		for _, method := range methods {
+			fmt.Fprintf(&b, "\t%s(ctx context.Context, in *%s, opts ...grpc.CallOption) (*%s, error)\n", method.Name, method.Input, goType(method.Output))
+		}
+		fmt.Fprintf(&b, `}
+
+type %[2]sClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// New%[1]sClient creates a %[1]s client on cc
+func New%[1]sClient(cc grpc.ClientConnInterface) %[1]sClient {
+	return &%[2]sClient{cc}
+}
+`, name, s.lowerFirst(name))
+		for _, method := range methods {
+			fmt.Fprintf(&b, `
+func (c *%[1]sClient) %[2]s(ctx context.Context, in *%[3]s, opts ...grpc.CallOption) (*%[4]s, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(%[4]s)
+	if err := c.cc.Invoke(ctx, %[5]s_%[2]s_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+`, s.lowerFirst(name), method.Name, method.Input, goType(method.Output), name)
+		}
+
+		// Server
+		fmt.Fprintf(&b, "\n// %[1]sServer is the server API for %[1]s. Implementations must embed\n// Unimplemented%[1]sServer for forward compatibility.\ntype %[1]sServer interface {\n", name)
+		for _, method := range methods {
+			fmt.Fprintf(&b, "\t%s(context.Context, *%s) (*%s, error)\n", method.Name, method.Input, goType(method.Output))
+		}
+		fmt.Fprintf(&b, "\tmustEmbedUnimplemented%sServer()\n}\n", name)
+
+		fmt.Fprintf(&b, "\n// Unimplemented%[1]sServer returns codes.Unimplemented for every method\ntype Unimplemented%[1]sServer struct{}\n", name)
+		for _, method := range methods {
+			fmt.Fprintf(&b, `
+func (Unimplemented%[1]sServer) %[2]s(context.Context, *%[3]s) (*%[4]s, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method %[2]s not implemented")
+}
+`, name, method.Name, method.Input, goType(method.Output))
+		}
+		fmt.Fprintf(&b, `
+func (Unimplemented%[1]sServer) mustEmbedUnimplemented%[1]sServer() {}
+
+// Register%[1]sServer registers srv on s
+func Register%[1]sServer(s grpc.ServiceRegistrar, srv %[1]sServer) {
+	s.RegisterService(&%[1]s_ServiceDesc, srv)
+}
+`, name)
+
+		for _, method := range methods {
+			fmt.Fprintf(&b, `
+func _%[1]s_%[2]s_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(%[3]s)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(%[1]sServer).%[2]s(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: %[1]s_%[2]s_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(%[1]sServer).%[2]s(ctx, req.(*%[3]s))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+`, name, method.Name, method.Input)
+		}
+
+		fmt.Fprintf(&b, `
+// %[1]s_ServiceDesc is the grpc.ServiceDesc of %[1]s
+var %[1]s_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "%[2]s.%[1]s",
+	HandlerType: (*%[1]sServer)(nil),
+	Methods: []grpc.MethodDesc{
+`, name, schema.Package)
+		for _, method := range methods {
+			fmt.Fprintf(&b, "\t\t{MethodName: %q, Handler: _%s_%s_Handler},\n", method.Name, name, method.Name)
+		}
+		fmt.Fprintf(&b, "\t},\n\tStreams:  []grpc.StreamDesc{},\n\tMetadata: %q,\n}\n", schema.Path)
+	}
+
+	return s.newFileElement(schema.GoPackage+"GRPC", schema.GoPackage, file, b.String())
+}
+
+// generateGRPCConvertElement generates the conversions and error mapping shared by the servers
+func (s *OrchestratorService) generateGRPCConvertElement(schema protoSchema, spec *domain.ProjectSpecification) domain.CodeElement {
+	needsJSON := false
+	for _, message := range schema.Messages {
+		for _, field := range message.Fields {
+			needsJSON = needsJSON || field.Conversion == convertJSON
+		}
+	}
+	validation := s.anyEntity(spec, func(entity domain.EntitySpecification) bool {
+		return s.hasFeature(entity.Features, "validation")
+	})
+
+	imports := map[string]bool{
+		"errors": true, "time": true,
+		"google.golang.org/grpc/codes": true, "google.golang.org/grpc/status": true,
+		"google.golang.org/protobuf/types/known/timestamppb": true,
+		spec.ModulePath + "/internal/domain":                 true,
+	}
+	var b strings.Builder
+	b.WriteString(`
+// timeToProto converts a time to a timestamp, leaving the zero time unset
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timeFromProto converts a timestamp to a time, an unset timestamp to the zero time
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+`)
+	if needsJSON {
+		imports["encoding/json"] = true
+		b.WriteString(`
+// marshalJSON encodes a value that has no protobuf counterpart as JSON text
+func marshalJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// unmarshalJSON decodes JSON text produced by marshalJSON, leaving target unchanged when empty
+func unmarshalJSON(text string, target interface{}) error {
+	if text == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(text), target)
+}
+`)
+	}
+
+	invalid, invalidCase := "", ""
+	if validation {
+		invalid = "\tvar invalid domain.ValidationErrors\n"
+		invalidCase = "\tcase errors.As(err, &invalid):\n\t\treturn status.Error(codes.InvalidArgument, err.Error())\n"
+	}
+	if s.usesTenancy(spec) {
+		invalidCase += "\tcase errors.Is(err, domain.ErrTenantRequired):\n\t\treturn status.Error(codes.InvalidArgument, err.Error())\n"
+	}
+	if s.anyEntity(spec, s.versioned) {
+		invalidCase += "\tcase errors.Is(err, domain.ErrConflict):\n\t\treturn status.Error(codes.Aborted, err.Error())\n"
+	}
+	fmt.Fprintf(&b, `
+// toStatus maps application errors to gRPC status errors
+func toStatus(err error) error {
+%sswitch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+%s	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+`, invalid, invalidCase)
+
+	content := "package servers\n\nimport (\n" + s.formatImports(imports) + ")\n" + b.String()
+	return s.newFileElement("convert", "servers", "internal/interfaces/grpc/servers/convert.go", content)
+}
+
+// generateGRPCServerElement generates the server implementing the gRPC service of an entity with
+// its application service, and the conversions between the entity and its message
+func (s *OrchestratorService) generateGRPCServerElement(entity domain.EntitySpecification, schema protoSchema, message protoMessage, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	pb := schema.GoPackage
+	itemField := protoGoName(s.toSnakeCase(name))
+	plural := s.pluralize(name)
+	usesErr := false
+
+	var toProto, fromProto, toProtoJSON, fromProtoJSON, enumMaps strings.Builder
+	for _, field := range message.Fields {
+		goName := protoGoName(field.Name)
+		domainValue := "item." + field.DomainField
+		switch field.Conversion {
+		case convertAssign:
+			fmt.Fprintf(&toProto, "\t\t%s: %s,\n", goName, domainValue)
+			fmt.Fprintf(&fromProto, "\t\t%s: m.Get%s(),\n", field.DomainField, goName)
+		case convertCast:
+			fmt.Fprintf(&toProto, "\t\t%s: %s(%s),\n", goName, field.MessageType, domainValue)
+			fmt.Fprintf(&fromProto, "\t\t%s: %s(m.Get%s()),\n", field.DomainField, field.DomainType, goName)
+		case convertTimestamp:
+			fmt.Fprintf(&toProto, "\t\t%s: timeToProto(%s),\n", goName, domainValue)
+			fmt.Fprintf(&fromProto, "\t\t%s: timeFromProto(m.Get%s()),\n", field.DomainField, goName)
+		case convertEnum:
+			maps := s.lowerFirst(field.DomainType)
+			fmt.Fprintf(&toProto, "\t\t%s: %sToProto[%s],\n", goName, maps, domainValue)
+			fmt.Fprintf(&fromProto, "\t\t%s: %sFromProto[m.Get%s()],\n", field.DomainField, maps, goName)
+			for _, enum := range schema.Enums {
+				if enum.Name == field.DomainType {
+					s.writeGRPCEnumMaps(&enumMaps, enum, pb)
+				}
+			}
+		case convertJSON:
+			usesErr = true
+			fmt.Fprintf(&toProtoJSON, `	if m.%[1]s, err = marshalJSON(%[2]s); err != nil {
+		return nil, fmt.Errorf("%[3]s: %%w", err)
+	}
+`, goName, domainValue, field.Name)
+			fmt.Fprintf(&fromProtoJSON, `	if err := unmarshalJSON(m.Get%[1]s(), &%[2]s); err != nil {
+		return nil, fmt.Errorf("%[3]s: %%w", err)
+	}
+`, goName, domainValue, field.Name)
+		}
+	}
+
+	imports := map[string]bool{
+		"context":                      true,
+		"google.golang.org/grpc/codes": true, "google.golang.org/grpc/status": true,
+		"google.golang.org/protobuf/types/known/emptypb": true,
+		spec.ModulePath + "/internal/application":        true,
+		spec.ModulePath + "/internal/domain":             true,
+	}
+	declareErr := ""
+	if usesErr {
+		imports["fmt"] = true
+		declareErr = "\tvar err error\n"
+	}
+
+	content := fmt.Sprintf(`package servers
+
+import (
+%[11]s)
+
+// %[1]sServer serves %[2]s.%[1]sService with the %[1]s application service
+type %[1]sServer struct {
+	%[2]s.Unimplemented%[1]sServiceServer
+	service *application.%[1]sService
+}
+
+// New%[1]sServer creates the gRPC server of the %[1]s service
+func New%[1]sServer(service *application.%[1]sService) *%[1]sServer {
+	return &%[1]sServer{service: service}
+}
+
+// Create%[1]s creates a %[1]s
+func (s *%[1]sServer) Create%[1]s(ctx context.Context, req *%[2]s.Create%[1]sRequest) (*%[2]s.%[1]s, error) {
+	if req.Get%[3]s() == nil {
+		return nil, status.Error(codes.InvalidArgument, "%[4]s is required")
+	}
+	item, err := %[1]sFromProto(req.Get%[3]s())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	created, err := s.service.Create(ctx, item)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.reply(created)
+}
+
+// Get%[1]s returns the %[1]s with the requested ID
+func (s *%[1]sServer) Get%[1]s(ctx context.Context, req *%[2]s.Get%[1]sRequest) (*%[2]s.%[1]s, error) {
+	item, err := s.service.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.reply(item)
+}
+
+// Update%[1]s replaces an existing %[1]s
+func (s *%[1]sServer) Update%[1]s(ctx context.Context, req *%[2]s.Update%[1]sRequest) (*%[2]s.%[1]s, error) {
+	if req.Get%[3]s().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "%[4]s.id is required")
+	}
+	item, err := %[1]sFromProto(req.Get%[3]s())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	updated, err := s.service.Update(ctx, item)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.reply(updated)
+}
+
+// Delete%[1]s removes the %[1]s with the requested ID
+func (s *%[1]sServer) Delete%[1]s(ctx context.Context, req *%[2]s.Delete%[1]sRequest) (*emptypb.Empty, error) {
+	if err := s.service.Delete(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// List%[5]s returns all %[1]s entities
+func (s *%[1]sServer) List%[5]s(ctx context.Context, req *%[2]s.List%[5]sRequest) (*%[2]s.List%[5]sResponse, error) {
+	items, err := s.service.List(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	reply := &%[2]s.List%[5]sResponse{%[6]s: make([]*%[2]s.%[1]s, 0, len(items))}
+	for _, item := range items {
+		m, err := %[1]sToProto(item)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		reply.%[6]s = append(reply.%[6]s, m)
+	}
+	return reply, nil
+}
+
+func (s *%[1]sServer) reply(item *domain.%[1]s) (*%[2]s.%[1]s, error) {
+	m, err := %[1]sToProto(item)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return m, nil
+}
+
+// %[1]sToProto converts a %[1]s to its protobuf message
+func %[1]sToProto(item *domain.%[1]s) (*%[2]s.%[1]s, error) {
+%[12]s	m := &%[2]s.%[1]s{
+%[7]s	}
+%[8]s	return m, nil
+}
+
+// %[1]sFromProto converts a protobuf message to a %[1]s
+func %[1]sFromProto(m *%[2]s.%[1]s) (*domain.%[1]s, error) {
+	item := &domain.%[1]s{
+%[9]s	}
+%[10]s	return item, nil
+}
+%[13]s`, name, pb, itemField, s.toSnakeCase(name), plural, protoGoName(s.toSnakeCase(plural)),
+		toProto.String(), toProtoJSON.String(), fromProto.String(), fromProtoJSON.String(),
+		s.formatImports(imports)+fmt.Sprintf("\t%s %q\n", pb, schema.GoImport), declareErr, enumMaps.String())
+
+	return s.newFileElement(
+		fmt.Sprintf("%sServer", name),
+		"servers",
+		fmt.Sprintf("internal/interfaces/grpc/servers/%s_server.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// writeGRPCEnumMaps writes the lookup tables between a domain enum and its protobuf enum. Values
+// missing from a table map to the UNSPECIFIED value and the empty domain value respectively.
+func (s *OrchestratorService) writeGRPCEnumMaps(b *strings.Builder, enum protoEnum, pb string) {
+	maps := s.lowerFirst(enum.DomainType)
+	goType := protoGoName(enum.Name)
+
+	fmt.Fprintf(b, "\nvar %sToProto = map[domain.%s]%s.%s{\n", maps, enum.DomainType, pb, goType)
+	for _, value := range enum.Values {
+		if value.DomainConst != "" {
+			fmt.Fprintf(b, "\tdomain.%s: %s.%s_%s,\n", value.DomainConst, pb, goType, value.Name)
+		}
+	}
+	fmt.Fprintf(b, "}\n\nvar %sFromProto = map[%s.%s]domain.%s{\n", maps, pb, goType, enum.DomainType)
+	for _, value := range enum.Values {
+		if value.DomainConst != "" {
+			fmt.Fprintf(b, "\t%s.%s_%s: domain.%s,\n", pb, goType, value.Name, value.DomainConst)
+		}
+	}
+	b.WriteString("}\n")
+}
+
+// generateGRPCServerTestElement tests the server against the in-memory repository
+func (s *OrchestratorService) generateGRPCServerTestElement(entity domain.EntitySpecification, schema protoSchema, spec *domain.ProjectSpecification) domain.CodeElement {
+	content := fmt.Sprintf(`package servers_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"%[1]s/internal/application"
+	"%[1]s/internal/fixtures"
+	"%[1]s/internal/infrastructure/memory"
+	"%[1]s/internal/interfaces/grpc/servers"
+	%[3]s "%[2]s"
+)
+
+func new%[4]sServer() *servers.%[4]sServer {
+	return servers.New%[4]sServer(application.New%[4]sService(memory.New%[4]sRepository()))
+}
+
+func Test%[4]sServerLifecycle(t *testing.T) {
+	ctx := %[8]s
+	server := new%[4]sServer()
+
+	message, err := servers.%[4]sToProto(fixtures.New%[4]sBuilder().Build())
+	if err != nil {
+		t.Fatalf("%[4]sToProto() error = %%v", err)
+	}
+	created, err := server.Create%[4]s(ctx, &%[3]s.Create%[4]sRequest{%[5]s: message})
+	if err != nil {
+		t.Fatalf("Create%[4]s() error = %%v", err)
+	}
+
+	got, err := server.Get%[4]s(ctx, &%[3]s.Get%[4]sRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("Get%[4]s() error = %%v", err)
+	}
+	if !proto.Equal(got, created) {
+		t.Errorf("Get%[4]s() = %%v, want %%v", got, created)
+	}
+
+	if _, err := server.Update%[4]s(ctx, &%[3]s.Update%[4]sRequest{%[5]s: got}); err != nil {
+		t.Fatalf("Update%[4]s() error = %%v", err)
+	}
+
+	list, err := server.List%[6]s(ctx, &%[3]s.List%[6]sRequest{})
+	if err != nil || len(list.Get%[7]s()) != 1 {
+		t.Fatalf("List%[6]s() = %%v, %%v, want 1 item", list, err)
+	}
+
+	if _, err := server.Delete%[4]s(ctx, &%[3]s.Delete%[4]sRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("Delete%[4]s() error = %%v", err)
+	}
+	if _, err := server.Get%[4]s(ctx, &%[3]s.Get%[4]sRequest{Id: created.GetId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get%[4]s() after Delete%[4]s() code = %%v, want NotFound", status.Code(err))
+	}
+}
+
+func Test%[4]sServerRejectsMissingMessage(t *testing.T) {
+	server := new%[4]sServer()
+	if _, err := server.Create%[4]s(context.Background(), &%[3]s.Create%[4]sRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Create%[4]s() code = %%v, want InvalidArgument", status.Code(err))
+	}
+	if _, err := server.Update%[4]s(context.Background(), &%[3]s.Update%[4]sRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Update%[4]s() code = %%v, want InvalidArgument", status.Code(err))
+	}
+}
+
+func Test%[4]sProtoRoundTrip(t *testing.T) {
+	message, err := servers.%[4]sToProto(fixtures.New%[4]sBuilder().Build())
+	if err != nil {
+		t.Fatalf("%[4]sToProto() error = %%v", err)
+	}
+	item, err := servers.%[4]sFromProto(message)
+	if err != nil {
+		t.Fatalf("%[4]sFromProto() error = %%v", err)
+	}
+	back, err := servers.%[4]sToProto(item)
+	if err != nil {
+		t.Fatalf("%[4]sToProto() error = %%v", err)
+	}
+	if !proto.Equal(back, message) {
+		t.Errorf("round trip = %%v, want %%v", back, message)
+	}
+}
+`, spec.ModulePath, schema.GoImport, schema.GoPackage, entity.Name,
+		protoGoName(s.toSnakeCase(entity.Name)), s.pluralize(entity.Name), protoGoName(s.toSnakeCase(s.pluralize(entity.Name))), s.testContext(entity, spec))
+
+	return s.newFileElement(
+		fmt.Sprintf("%sServerTest", entity.Name),
+		"servers_test",
+		fmt.Sprintf("internal/interfaces/grpc/servers/%s_server_test.go", s.toSnakeCase(entity.Name)),
+		content,
+	)
+}
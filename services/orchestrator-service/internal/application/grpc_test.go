@@ -0,0 +1,73 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedMainServesGRPC(t *testing.T) {
+	withProtocGenGo(t)
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config", "testing"},
+		Options:     map[string]string{"tenancy": "tenant"},
+		Configuration: domain.ProjectConfiguration{
+			Security: &domain.SecurityConfiguration{Authentication: []string{"jwt"}},
+		},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "grpc_api"},
+			Options:  map[string]string{"audit": "true"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	main, err := os.ReadFile(filepath.Join(dir, "cmd", "shop", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"shopv1.RegisterOrderServiceServer(grpcServer, servers.NewOrderServer(application.NewOrderService(repos.Order)))",
+		"servers.AuthInterceptor(chain...)",
+		`servers.TenantFromClaims("tenant_id", "client_id")`,
+		"net.Listen(\"tcp\", cfg.Server.GRPCAddress())",
+		"grpcServer.GracefulStop()",
+	} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go does not contain %s:\n%s", want, main)
+		}
+	}
+	auth, err := os.ReadFile(filepath.Join(dir, "internal", "interfaces", "grpc", "servers", "auth.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(auth), "domain.WithActor(ctx, principal.Subject)") {
+		t.Errorf("AuthInterceptor does not record the actor of audited entities:\n%s", auth)
+	}
+}
+
+func TestOrchestrateRequiresProtocGenGo(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "grpc_api"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	_, err := NewOrchestratorService().OrchestrateMicroservice(spec)
+	if err == nil || !strings.Contains(err.Error(), "protoc-gen-go@"+protobufModule.Version) {
+		t.Errorf("OrchestrateMicroservice() error = %v, want the protoc-gen-go install hint", err)
+	}
+}
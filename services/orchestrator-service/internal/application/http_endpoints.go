@@ -0,0 +1,959 @@
+package application
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// endpointParamDecoders are the Go types a request parameter can be converted to, with the
+// decoder of the generated params.go storing the converted value
+var endpointParamDecoders = map[string]string{
+	"string":    "stringParam",
+	"int":       "intParam",
+	"int64":     "int64Param",
+	"float64":   "float64Param",
+	"bool":      "boolParam",
+	"time.Time": "timeParam",
+	"[]string":  "stringsParam",
+}
+
+// endpointSampleValues are the raw values the generated tests send for each parameter type,
+// with the Go value they convert to
+var endpointSampleValues = map[string][2]string{
+	"string":    {"sample", `"sample"`},
+	"int":       {"42", "42"},
+	"int64":     {"42", "42"},
+	"float64":   {"1.5", "1.5"},
+	"bool":      {"true", "true"},
+	"time.Time": {"2024-01-02T03:04:05Z", "time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)"},
+	"[]string":  {"a,b", `[]string{"a", "b"}`},
+}
+
+// httpStatusNames are the net/http constants of the status codes endpoints commonly declare
+var httpStatusNames = map[int]string{
+	http.StatusOK: "StatusOK", http.StatusCreated: "StatusCreated", http.StatusAccepted: "StatusAccepted",
+	http.StatusNoContent: "StatusNoContent", http.StatusMovedPermanently: "StatusMovedPermanently",
+	http.StatusFound: "StatusFound", http.StatusSeeOther: "StatusSeeOther", http.StatusNotModified: "StatusNotModified",
+}
+
+// httpStatusExpr returns the Go expression of a status code
+func httpStatusExpr(status int) string {
+	if name, ok := httpStatusNames[status]; ok {
+		return "http." + name
+	}
+	return fmt.Sprint(status)
+}
+
+// endpoint is an endpoint of the specification resolved for code generation
+type endpoint struct {
+	domain.EndpointSpecification
+	Name    string // Go name of the handler and service method
+	Method  string
+	Pattern string   // Path in net/http ServeMux syntax
+	Schemes []string // Security schemes the endpoint authenticates with, any of
+	Params  []endpointParam
+
+	Body        string // Go type the request body is decoded into, empty without a request
+	BodyEntity  string // Entity the body is, to validate it
+	BodyKind    string // "json", "multipart" or "raw"
+	Result      string // Go type the service returns for the response body, empty without one
+	ResultJSON  bool
+	ContentType string // Content type of the response
+	Status      int
+}
+
+// endpointParam is a path, query or header parameter with the Go type it is converted to
+type endpointParam struct {
+	domain.ParameterSpecification
+	In      string
+	Field   string
+	GoType  string
+	Decoder string
+}
+
+// endpointParamGoType returns the Go type of a parameter data type, or false when the type has
+// no conversion from a string
+func (s *OrchestratorService) endpointParamGoType(dataType string) (string, bool) {
+	if dataType == "" {
+		return "string", true
+	}
+	def, ok := s.types.Lookup(dataType)
+	if !ok {
+		return "", false
+	}
+	_, ok = endpointParamDecoders[def.GoType]
+	return def.GoType, ok
+}
+
+// endpointName returns the Go name of an endpoint: its Handler, or the method followed by the
+// path segments (e.g. "GET /users/{id}/orders" -> "GetUsersByIDOrders")
+func (s *OrchestratorService) endpointName(e domain.EndpointSpecification) string {
+	if e.Handler != "" {
+		return s.capitalizeFirst(e.Handler)
+	}
+	method := strings.ToUpper(e.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	name := s.toPascalCase(strings.ToLower(method))
+	path := pathParamPattern.ReplaceAllString(e.Path, "{$1}")
+	for _, segment := range strings.Split(path, "/") {
+		if match := templateParamPattern.FindStringSubmatch(segment); match != nil {
+			name += "By" + s.endpointFieldName(strings.TrimSuffix(match[1], "..."))
+		} else {
+			name += s.toPascalCase(segment)
+		}
+	}
+	return name
+}
+
+// endpointFieldName returns the Go name of a parameter (e.g. "X-Request-Id" -> "XRequestId")
+func (s *OrchestratorService) endpointFieldName(name string) string {
+	if strings.EqualFold(name, "id") {
+		return "ID"
+	}
+	return s.toPascalCase(name)
+}
+
+// endpointSchemaType returns the Go type of a request or response schema: a pointer to an
+// entity, a slice of them ("[]User"), or the Go type of a known type
+func (s *OrchestratorService) endpointSchemaType(name string, spec *domain.ProjectSpecification, imports map[string]bool) string {
+	if element := strings.TrimPrefix(name, "[]"); element != name {
+		return "[]" + s.endpointSchemaType(element, spec, imports)
+	}
+	name = strings.TrimPrefix(name, "*")
+	if _, ok := s.findEntity(spec, name); ok {
+		imports[spec.ModulePath+"/internal/domain"] = true
+		return "*domain." + name
+	}
+	if def, ok := s.types.Lookup(name); ok {
+		if def.Import != "" {
+			imports[def.Import] = true
+		}
+		return def.GoType
+	}
+	return "json.RawMessage"
+}
+
+// resolveEndpoints resolves the endpoints of the specification, in the order they are declared
+func (s *OrchestratorService) resolveEndpoints(spec *domain.ProjectSpecification, imports map[string]bool) []endpoint {
+	var endpoints []endpoint
+	for _, decl := range spec.Endpoints {
+		e := endpoint{EndpointSpecification: decl, Name: s.endpointName(decl), Method: strings.ToUpper(decl.Method), Status: http.StatusOK}
+		if e.Method == "" {
+			e.Method = http.MethodGet
+		}
+		path := pathParamPattern.ReplaceAllString(decl.Path, "{$1}")
+		e.Pattern = path
+		e.Schemes = endpointSchemes(decl, spec)
+
+		declared := make(map[string]bool)
+		for _, param := range decl.Parameters {
+			p := endpointParam{ParameterSpecification: param, In: param.Type, Field: s.endpointFieldName(param.Name)}
+			if p.In == "" {
+				p.In = "query"
+			}
+			if p.In == "path" {
+				declared[param.Name] = true
+			}
+			goType, ok := s.endpointParamGoType(param.DataType)
+			if !ok {
+				goType = "string"
+			}
+			if goType == "time.Time" {
+				imports["time"] = true
+			}
+			p.GoType, p.Decoder = goType, endpointParamDecoders[goType]
+			e.Params = append(e.Params, p)
+		}
+		// Templated path segments are string parameters unless described otherwise
+		for _, match := range templateParamPattern.FindAllStringSubmatch(path, -1) {
+			name := strings.TrimSuffix(match[1], "...")
+			if !declared[name] {
+				e.Params = append(e.Params, endpointParam{
+					ParameterSpecification: domain.ParameterSpecification{Name: name, Required: true},
+					In:                     "path", Field: s.endpointFieldName(name), GoType: "string", Decoder: "stringParam",
+				})
+			}
+		}
+
+		if request := decl.Request; request != nil {
+			switch request.ContentType {
+			case "", "application/json":
+				e.BodyKind = "json"
+				e.Body = s.endpointSchemaType(request.Schema, spec, imports)
+				if entity, ok := s.findEntity(spec, strings.TrimPrefix(request.Schema, "*")); ok && s.hasFeature(entity.Features, "validation") {
+					e.BodyEntity = entity.Name
+				}
+			case "multipart/form-data":
+				e.BodyKind, e.Body = "multipart", "*multipart.Form"
+				imports["mime/multipart"] = true
+			default:
+				e.BodyKind, e.Body = "raw", "[]byte"
+			}
+		}
+
+		if response := decl.Response; response != nil {
+			if response.StatusCode != 0 {
+				e.Status = response.StatusCode
+			}
+			e.ContentType = response.ContentType
+			if e.ContentType == "" {
+				e.ContentType = "application/json"
+			}
+			if response.Schema != "" {
+				e.ResultJSON = e.ContentType == "application/json"
+				if e.ResultJSON {
+					e.Result = s.endpointSchemaType(response.Schema, spec, imports)
+				} else {
+					e.Result = "[]byte"
+				}
+			}
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints
+}
+
+// signature returns the parameters and results of the service method of the endpoint, with
+// the parameter struct qualified by its package outside of it
+func (e endpoint) signature(qualifier string) string {
+	params := "ctx context.Context"
+	if len(e.Params) > 0 {
+		params += fmt.Sprintf(", params %s%sParams", qualifier, e.Name)
+	}
+	if e.Body != "" {
+		params += ", body " + e.Body
+	}
+	if e.Result != "" {
+		return fmt.Sprintf("(%s) (%s, error)", params, e.Result)
+	}
+	return fmt.Sprintf("(%s) error", params)
+}
+
+// route returns the ServeMux pattern of the endpoint
+func (e endpoint) route() string {
+	return e.Method + " " + e.Pattern
+}
+
+// handler returns the Go expression of the handler of the endpoint, behind its security
+func (e endpoint) handler() string {
+	handler := fmt.Sprintf("http.HandlerFunc(h.%s)", e.Name)
+	if len(e.Schemes) == 0 {
+		return handler
+	}
+	return fmt.Sprintf("h.secure(%s, %s, %s)", handler, stringSliceLiteral(e.Roles), quotedList(e.Schemes))
+}
+
+// secured reports whether one of the endpoints authenticates its requests
+func secured(endpoints []endpoint) bool {
+	for _, e := range endpoints {
+		if len(e.Schemes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedList renders strings as a comma-separated list of Go string literals
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// generateEndpointElements generates the handlers of the custom endpoints of the specification:
+// parameter structs, the service interface they delegate to, and their routes
+func (s *OrchestratorService) generateEndpointElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	imports := map[string]bool{"context": true, "errors": true, "fmt": true, "net/http": true}
+	endpoints := s.resolveEndpoints(spec, imports)
+
+	var service, unimplemented, params, routes, handlers strings.Builder
+	for _, e := range endpoints {
+		summary := e.route()
+		if e.Description != "" {
+			summary += ": " + e.Description
+		}
+		fmt.Fprintf(&service, "\t// %s handles %s\n\t%s%s\n", e.Name, summary, e.Name, e.signature(""))
+
+		zero := ""
+		if e.Result != "" {
+			zero = zeroValueOf(e.Result) + ", "
+		}
+		fmt.Fprintf(&unimplemented, "\nfunc (UnimplementedEndpointService) %s%s {\n\treturn %serrNotImplemented\n}\n", e.Name, e.signature(""), zero)
+
+		if len(e.Params) > 0 {
+			fmt.Fprintf(&params, "\n// %sParams holds the parameters of %s\ntype %[1]sParams struct {\n", e.Name, e.route())
+			for _, p := range e.Params {
+				if p.Description != "" {
+					fmt.Fprintf(&params, "\t// %s\n", p.Description)
+				}
+				fmt.Fprintf(&params, "\t%s %s\n", p.Field, p.GoType)
+			}
+			params.WriteString("}\n")
+		}
+
+		fmt.Fprintf(&routes, "\tmux.Handle(%q, h.wrap(%s%s))\n", e.route(), e.handler(), strings.TrimSuffix(", "+quotedList(e.Middleware), ", "))
+
+		handlers.WriteString(s.generateEndpointHandler(e))
+	}
+
+	endpointHandler := fmt.Sprintf(`
+// EndpointHandler serves the custom endpoints of the API
+type EndpointHandler struct {
+	service    EndpointService
+	middleware map[string]Middleware
+}
+
+// NewEndpointHandler creates the handler of the custom endpoints. middleware holds, by name,
+// the middleware the endpoints are declared with.
+func NewEndpointHandler(service EndpointService, middleware map[string]Middleware) *EndpointHandler {
+	return &EndpointHandler{service: service, middleware: middleware}
+}
+
+// RegisterRoutes registers the endpoint routes on the given mux, each wrapped in its middleware.
+// It panics when one of them is missing.
+func (h *EndpointHandler) RegisterRoutes(mux *http.ServeMux) {
+%s}
+`, routes.String())
+	if secured(endpoints) {
+		imports[spec.ModulePath+"/internal/interfaces/http/middleware"] = true
+		endpointHandler = fmt.Sprintf(`
+// EndpointHandler serves the custom endpoints of the API
+type EndpointHandler struct {
+	service        EndpointService
+	middleware     map[string]Middleware
+	authenticators middleware.Authenticators
+}
+
+// NewEndpointHandler creates the handler of the custom endpoints. middleware holds, by name,
+// the middleware the endpoints are declared with, and authenticators the authenticators of
+// their security schemes.
+func NewEndpointHandler(service EndpointService, middleware map[string]Middleware, authenticators middleware.Authenticators) *EndpointHandler {
+	return &EndpointHandler{service: service, middleware: middleware, authenticators: authenticators}
+}
+
+// RegisterRoutes registers the endpoint routes on the given mux, each wrapped in its middleware
+// then its security. It panics when one of them is missing, so that no endpoint is ever served
+// without its security.
+func (h *EndpointHandler) RegisterRoutes(mux *http.ServeMux) {
+%s}
+
+// secure requires the requests to a handler to authenticate with one of the schemes and, with
+// roles, the principal to have one of them
+func (h *EndpointHandler) secure(handler http.Handler, roles []string, schemes ...string) http.Handler {
+	authenticators, err := h.authenticators.Of(schemes...)
+	if err != nil {
+		panic("handlers: " + err.Error())
+	}
+	if len(roles) > 0 {
+		handler = middleware.RequireRoles(roles...)(handler)
+	}
+	return middleware.Authenticate(authenticators...)(handler)
+}
+`, routes.String())
+	}
+
+	if strings.Contains(handlers.String(), "json.") || strings.Contains(service.String(), "json.") {
+		imports["encoding/json"] = true
+	}
+	if strings.Contains(handlers.String(), "io.ReadAll") {
+		imports["io"] = true
+	}
+
+	content := fmt.Sprintf(`package handlers
+
+import (
+%s)
+
+// Middleware wraps the handler of an endpoint
+type Middleware func(http.Handler) http.Handler
+
+// errNotImplemented is returned by UnimplementedEndpointService
+var errNotImplemented = errors.New("not implemented")
+
+// EndpointService implements the custom endpoints of the API. Handlers parse and check the
+// parameters and request bodies before calling it, and write what it returns as the response.
+type EndpointService interface {
+%s}
+
+// UnimplementedEndpointService answers every endpoint with 501 Not Implemented. Embed it in
+// EndpointService implementations to implement the endpoints one at a time.
+type UnimplementedEndpointService struct{}
+%s%s
+%s
+// wrap wraps a handler in the named middleware, the first name being the outermost
+func (h *EndpointHandler) wrap(handler http.Handler, names ...string) http.Handler {
+	for i := len(names) - 1; i >= 0; i-- {
+		middleware, ok := h.middleware[names[i]]
+		if !ok {
+			panic(fmt.Sprintf("handlers: middleware %%q is not registered", names[i]))
+		}
+		handler = middleware(handler)
+	}
+	return handler
+}
+
+// writeEndpointError maps the errors of the endpoint service to HTTP status codes
+func writeEndpointError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotImplemented) {
+		writeError(w, http.StatusNotImplemented, err.Error())
+		return
+	}
+	writeRepositoryError(w, err)
+}
+%s`, s.formatImports(imports), service.String(), unimplemented.String(), params.String(), endpointHandler, handlers.String())
+
+	elements := []domain.CodeElement{
+		s.newFileElement("Endpoints", "handlers", "internal/interfaces/http/handlers/endpoints.go", content),
+		s.generateEndpointParamsElement(),
+	}
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateEndpointTestElement(spec))
+	}
+	return elements
+}
+
+// generateEndpointHandler generates the HTTP handler of an endpoint
+func (s *OrchestratorService) generateEndpointHandler(e endpoint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n// %s handles %s\nfunc (h *EndpointHandler) %[1]s(w http.ResponseWriter, r *http.Request) {\n", e.Name, e.route())
+
+	args := "r.Context()"
+	if len(e.Params) > 0 {
+		fmt.Fprintf(&b, "\tvar params %sParams\n\tif err := decodeParams(r,\n", e.Name)
+		for _, p := range e.Params {
+			fmt.Fprintf(&b, "\t\tparam{%q, %q, %t, %s(&params.%s)},\n", p.In, p.Name, p.Required || p.In == "path", p.Decoder, p.Field)
+		}
+		b.WriteString("\t); err != nil {\n\t\twriteError(w, http.StatusBadRequest, err.Error())\n\t\treturn\n\t}\n")
+		args += ", params"
+	}
+
+	switch e.BodyKind {
+	case "json":
+		target, arg := e.Body, "body"
+		if strings.HasPrefix(target, "*") {
+			target, arg = target[1:], "&body"
+		}
+		fmt.Fprintf(&b, `
+	var body %s
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+`, target)
+		if e.BodyEntity != "" {
+			fmt.Fprintf(&b, `	if err := domain.Validate%s(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+`, e.BodyEntity)
+		}
+		args += ", " + arg
+	case "multipart":
+		b.WriteString(`
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+`)
+		args += ", r.MultipartForm"
+	case "raw":
+		b.WriteString(`
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+`)
+		args += ", body"
+	}
+
+	if e.Result == "" {
+		fmt.Fprintf(&b, `
+	if err := h.service.%s(%s); err != nil {
+		writeEndpointError(w, err)
+		return
+	}
+
+	w.WriteHeader(%s)
+}
+`, e.Name, args, httpStatusExpr(e.Status))
+		return trimHandlerStart(b.String())
+	}
+
+	fmt.Fprintf(&b, `
+	result, err := h.service.%s(%s)
+	if err != nil {
+		writeEndpointError(w, err)
+		return
+	}
+
+`, e.Name, args)
+	if e.ResultJSON {
+		fmt.Fprintf(&b, "\twriteJSON(w, %s, result)\n}\n", httpStatusExpr(e.Status))
+	} else {
+		fmt.Fprintf(&b, "\tw.Header().Set(\"Content-Type\", %q)\n\tw.WriteHeader(%s)\n\t_, _ = w.Write(result)\n}\n", e.ContentType, httpStatusExpr(e.Status))
+	}
+	return trimHandlerStart(b.String())
+}
+
+// trimHandlerStart removes the blank line opening handlers without parameters to decode
+func trimHandlerStart(handler string) string {
+	return strings.Replace(handler, "{\n\n", "{\n", 1)
+}
+
+// generateEndpointParamsElement generates the conversion of request parameters to the Go types
+// of the endpoint parameter structs
+func (s *OrchestratorService) generateEndpointParamsElement() domain.CodeElement {
+	content := `package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// param describes a request parameter and stores its converted value
+type param struct {
+	in       string // "path", "query" or "header"
+	name     string
+	required bool
+	decode   func(value string) error
+}
+
+// decodeParams converts the parameters of a request, failing on missing required parameters
+// and values that don't convert to the parameter type
+func decodeParams(r *http.Request, params ...param) error {
+	query := r.URL.Query()
+	for _, p := range params {
+		var value string
+		switch p.in {
+		case "path":
+			value = r.PathValue(p.name)
+		case "header":
+			value = r.Header.Get(p.name)
+		default:
+			value = query.Get(p.name)
+		}
+		if value == "" {
+			if p.required {
+				return fmt.Errorf("missing required %s parameter %q", p.in, p.name)
+			}
+			continue
+		}
+		if err := p.decode(value); err != nil {
+			return fmt.Errorf("invalid %s parameter %q: %v", p.in, p.name, err)
+		}
+	}
+	return nil
+}
+
+func stringParam(target *string) func(string) error {
+	return func(value string) error {
+		*target = value
+		return nil
+	}
+}
+
+func intParam(target *int) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.Atoi(value)
+		return err
+	}
+}
+
+func int64Param(target *int64) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.ParseInt(value, 10, 64)
+		return err
+	}
+}
+
+func float64Param(target *float64) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.ParseFloat(value, 64)
+		return err
+	}
+}
+
+func boolParam(target *bool) func(string) error {
+	return func(value string) (err error) {
+		*target, err = strconv.ParseBool(value)
+		return err
+	}
+}
+
+// timeParam accepts RFC 3339 times and dates (2006-01-02)
+func timeParam(target *time.Time) func(string) error {
+	return func(value string) (err error) {
+		if *target, err = time.Parse(time.RFC3339, value); err != nil {
+			*target, err = time.Parse(time.DateOnly, value)
+		}
+		return err
+	}
+}
+
+// stringsParam splits comma-separated values
+func stringsParam(target *[]string) func(string) error {
+	return func(value string) error {
+		*target = strings.Split(value, ",")
+		return nil
+	}
+}
+`
+	return s.newFileElement("EndpointParams", "handlers", "internal/interfaces/http/handlers/params.go", content)
+}
+
+// endpointRequest returns the target and headers of a request to an endpoint with sample
+// parameter values, authenticated with the first scheme of the endpoint and its first role.
+// overrides replaces the raw value of the named parameters or test credential headers, or
+// leaves them out when empty.
+func endpointRequest(e endpoint, overrides map[string]string) (target string, headers []string) {
+	if len(e.Schemes) > 0 {
+		credentials := [][2]string{{"X-Test-Scheme", e.Schemes[0]}}
+		if len(e.Roles) > 0 {
+			credentials = append(credentials, [2]string{"X-Test-Roles", e.Roles[0]})
+		}
+		for _, credential := range credentials {
+			value := credential[1]
+			if override, ok := overrides[credential[0]]; ok {
+				value = override
+			}
+			if value != "" {
+				headers = append(headers, fmt.Sprintf("%q: %q", credential[0], value))
+			}
+		}
+	}
+
+	target = e.Pattern
+	var query []string
+	for _, p := range e.Params {
+		value := endpointSampleValues[p.GoType][0]
+		if override, ok := overrides[p.Name]; ok {
+			value = override
+		}
+		switch {
+		case p.In == "path":
+			target = strings.NewReplacer("{"+p.Name+"}", value, "{"+p.Name+"...}", value).Replace(target)
+		case value == "":
+		case p.In == "header":
+			headers = append(headers, fmt.Sprintf("%q: %q", p.Name, value))
+		default:
+			query = append(query, p.Name+"="+value)
+		}
+	}
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+	return target, headers
+}
+
+// headersLiteral returns the map literal of request headers, nil without any
+func headersLiteral(headers []string) string {
+	if len(headers) == 0 {
+		return "nil"
+	}
+	return "map[string]string{" + strings.Join(headers, ", ") + "}"
+}
+
+// generateEndpointTestElement tests each endpoint against a service recording its calls: the
+// declared status, the converted parameters, the middleware run, and rejected parameters
+func (s *OrchestratorService) generateEndpointTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	imports := map[string]bool{
+		"context": true, "net/http": true, "net/http/httptest": true, "reflect": true, "strings": true, "testing": true,
+		spec.ModulePath + "/internal/interfaces/http/handlers": true,
+	}
+	endpoints := s.resolveEndpoints(spec, imports)
+
+	var methods, cases, rejected, unauthorized strings.Builder
+	middleware, schemes := make(map[string]bool), make(map[string]bool)
+	for _, e := range endpoints {
+		record := "nil"
+		if len(e.Params) > 0 {
+			record = "params"
+		}
+		result := "nil"
+		if e.Result != "" {
+			result = zeroValueOf(e.Result) + ", nil"
+		}
+		fmt.Fprintf(&methods, "\nfunc (s *recordingService) %s%s {\n\ts.calls[%q] = %s\n\treturn %s\n}\n",
+			e.Name, e.signature("handlers."), e.Name, record, result)
+
+		var want []string
+		for _, p := range e.Params {
+			want = append(want, fmt.Sprintf("%s: %s", p.Field, endpointSampleValues[p.GoType][1]))
+			if p.GoType == "time.Time" {
+				imports["time"] = true
+			}
+		}
+		wantParams := "nil"
+		if len(e.Params) > 0 {
+			wantParams = fmt.Sprintf("handlers.%sParams{%s}", e.Name, strings.Join(want, ", "))
+		}
+
+		contentType, body := `""`, `""`
+		switch e.BodyKind {
+		case "json":
+			contentType, body = `"application/json"`, `"null"`
+			schema := strings.TrimPrefix(e.Request.Schema, "*")
+			if entity, ok := s.findEntity(spec, schema); ok {
+				imports[spec.ModulePath+"/internal/fixtures"] = true
+				body = fmt.Sprintf("mustMarshal(t, fixtures.New%sBuilder().Build())", entity.Name)
+			} else if entity, ok := s.findEntity(spec, strings.TrimPrefix(schema, "[]")); ok {
+				imports[spec.ModulePath+"/internal/fixtures"] = true
+				body = fmt.Sprintf("mustMarshal(t, []interface{}{fixtures.New%sBuilder().Build()})", entity.Name)
+			}
+		case "multipart":
+			imports["bytes"], imports["mime/multipart"] = true, true
+			contentType, body = "multipartContentType", "multipartBody"
+		case "raw":
+			contentType, body = fmt.Sprintf("%q", e.Request.ContentType), `"sample"`
+		}
+
+		for _, name := range e.Middleware {
+			middleware[name] = true
+		}
+		// The middleware run, then the authenticator of the first scheme accepting the request
+		ran := e.Middleware
+		if len(e.Schemes) > 0 {
+			ran = append(append([]string{}, ran...), e.Schemes[0])
+			for _, scheme := range e.Schemes {
+				schemes[scheme] = true
+			}
+
+			target, headers := endpointRequest(e, map[string]string{"X-Test-Scheme": ""})
+			fmt.Fprintf(&unauthorized, "\t\t{%q, %q, %s, http.StatusUnauthorized},\n", e.Method, target, headersLiteral(headers))
+			if len(e.Roles) > 0 {
+				target, headers := endpointRequest(e, map[string]string{"X-Test-Roles": ""})
+				fmt.Fprintf(&unauthorized, "\t\t{%q, %q, %s, http.StatusForbidden},\n", e.Method, target, headersLiteral(headers))
+			}
+		}
+
+		target, headers := endpointRequest(e, nil)
+		fmt.Fprintf(&cases, "\t\t{%q, %q, %q, %s, %s, %s, %s, %s, []string{%s}},\n",
+			e.Name, e.Method, target, headersLiteral(headers), contentType, body, httpStatusExpr(e.Status), wantParams, quotedList(ran))
+
+		for _, p := range e.Params {
+			if p.Required && p.In != "path" {
+				target, headers := endpointRequest(e, map[string]string{p.Name: ""})
+				fmt.Fprintf(&rejected, "\t\t{%q, %q, %s},\n", e.Method, target, headersLiteral(headers))
+			}
+			if p.GoType != "string" && p.GoType != "[]string" {
+				target, headers := endpointRequest(e, map[string]string{p.Name: "invalid"})
+				fmt.Fprintf(&rejected, "\t\t{%q, %q, %s},\n", e.Method, target, headersLiteral(headers))
+			}
+		}
+	}
+
+	names := quotedList(sortedKeys(middleware))
+	server := `	mux := http.NewServeMux()
+	handlers.NewEndpointHandler(service, wrappers).RegisterRoutes(mux)
+	return mux, ran
+}
+`
+	if len(schemes) > 0 {
+		imports[spec.ModulePath+"/internal/interfaces/http/middleware"] = true
+		server = fmt.Sprintf(`	authenticators := make(middleware.Authenticators)
+	for _, scheme := range []string{%s} {
+		authenticators[scheme] = testAuthenticator{scheme: scheme, ran: ran}
+	}
+	mux := http.NewServeMux()
+	handlers.NewEndpointHandler(service, wrappers, authenticators).RegisterRoutes(mux)
+	return mux, ran
+}
+
+// testAuthenticator accepts the requests whose X-Test-Scheme header names its scheme, with the
+// roles of the X-Test-Roles header, and records that it ran
+type testAuthenticator struct {
+	scheme string
+	ran    *[]string
+}
+
+func (a testAuthenticator) Authenticate(r *http.Request) (*middleware.Principal, error) {
+	*a.ran = append(*a.ran, a.scheme)
+	if r.Header.Get("X-Test-Scheme") != a.scheme {
+		return nil, middleware.ErrNoCredentials
+	}
+	principal := &middleware.Principal{Subject: "tester", Scheme: a.scheme}
+	if roles := r.Header.Get("X-Test-Roles"); roles != "" {
+		principal.Roles = strings.Split(roles, ",")
+	}
+	return principal, nil
+}
+`, quotedList(sortedKeys(schemes)))
+	}
+	multipart := ""
+	if imports["mime/multipart"] {
+		multipart = `
+// multipartBody is an empty multipart form and multipartContentType its content type
+var multipartBody, multipartContentType = func() (string, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.Close()
+	return body.String(), writer.FormDataContentType()
+}()
+`
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `package handlers_test
+
+import (
+%s)
+
+// recordingService records the parameters each endpoint is called with
+type recordingService struct {
+	calls map[string]interface{}
+}
+%s%s
+// newEndpointServer serves the endpoints with middleware recording the order it runs in
+func newEndpointServer(service handlers.EndpointService) (*http.ServeMux, *[]string) {
+	ran := &[]string{}
+	wrappers := make(map[string]handlers.Middleware)
+	for _, name := range []string{%s} {
+		name := name
+		wrappers[name] = func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				*ran = append(*ran, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+%s
+func serveEndpoint(mux *http.ServeMux, method, target string, headers map[string]string, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEndpoints(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		headers        map[string]string
+		contentType    string
+		body           string
+		wantStatus     int
+		wantParams     interface{}
+		wantMiddleware []string
+	}{
+%s	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &recordingService{calls: make(map[string]interface{})}
+			mux, ran := newEndpointServer(service)
+
+			rec := serveEndpoint(mux, tt.method, tt.target, tt.headers, tt.contentType, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %%d, want %%d: %%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			params, called := service.calls[tt.name]
+			if !called {
+				t.Fatalf("%%s was not called", tt.name)
+			}
+			if tt.wantParams != nil && !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("params = %%+v, want %%+v", params, tt.wantParams)
+			}
+			if !reflect.DeepEqual(*ran, tt.wantMiddleware) {
+				t.Errorf("middleware ran = %%v, want %%v", *ran, tt.wantMiddleware)
+			}
+
+			mux, _ = newEndpointServer(handlers.UnimplementedEndpointService{})
+			rec = serveEndpoint(mux, tt.method, tt.target, tt.headers, tt.contentType, tt.body)
+			if rec.Code != http.StatusNotImplemented {
+				t.Errorf("unimplemented status = %%d, want %%d", rec.Code, http.StatusNotImplemented)
+			}
+		})
+	}
+}
+`, s.formatImports(imports), methods.String(), multipart, names, server, cases.String())
+
+	if len(schemes) > 0 {
+		b.WriteString(`
+func TestEndpointsRequireTheirMiddleware(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterRoutes did not panic without the middleware and authenticators of the endpoints")
+		}
+	}()
+	handlers.NewEndpointHandler(handlers.UnimplementedEndpointService{}, nil, nil).RegisterRoutes(http.NewServeMux())
+}
+`)
+		fmt.Fprintf(&b, `
+func TestEndpointsRequireAuthorization(t *testing.T) {
+	tests := []struct {
+		method     string
+		target     string
+		headers    map[string]string
+		wantStatus int
+	}{
+%s	}
+	for _, tt := range tests {
+		service := &recordingService{calls: make(map[string]interface{})}
+		mux, _ := newEndpointServer(service)
+		rec := serveEndpoint(mux, tt.method, tt.target, tt.headers, "", "")
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%%s %%s: status = %%d, want %%d", tt.method, tt.target, rec.Code, tt.wantStatus)
+		}
+		if len(service.calls) != 0 {
+			t.Errorf("%%s %%s: service called without authorization", tt.method, tt.target)
+		}
+	}
+}
+`, unauthorized.String())
+	} else if len(middleware) > 0 {
+		b.WriteString(`
+func TestEndpointsRequireTheirMiddleware(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterRoutes did not panic without the middleware of the endpoints")
+		}
+	}()
+	handlers.NewEndpointHandler(handlers.UnimplementedEndpointService{}, nil).RegisterRoutes(http.NewServeMux())
+}
+`)
+	}
+
+	if rejected.Len() > 0 {
+		fmt.Fprintf(&b, `
+func TestEndpointsRejectInvalidParams(t *testing.T) {
+	tests := []struct {
+		method  string
+		target  string
+		headers map[string]string
+	}{
+%s	}
+	for _, tt := range tests {
+		service := &recordingService{calls: make(map[string]interface{})}
+		mux, _ := newEndpointServer(service)
+		rec := serveEndpoint(mux, tt.method, tt.target, tt.headers, "", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%%s %%s: status = %%d, want %%d", tt.method, tt.target, rec.Code, http.StatusBadRequest)
+		}
+		if len(service.calls) != 0 {
+			t.Errorf("%%s %%s: service called with invalid parameters", tt.method, tt.target)
+		}
+	}
+}
+`, rejected.String())
+	}
+
+	return s.newFileElement("EndpointsTest", "handlers_test", "internal/interfaces/http/handlers/endpoints_test.go", b.String())
+}
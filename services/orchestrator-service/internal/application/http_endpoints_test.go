@@ -0,0 +1,100 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// refundEndpointTest calls the generated handler of the refund endpoint through its route
+const refundEndpointTest = `package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/shop/internal/domain"
+)
+
+type refundService struct {
+	UnimplementedEndpointService
+	params RefundOrderParams
+}
+
+func (s *refundService) RefundOrder(ctx context.Context, params RefundOrderParams, body *domain.Order) (*domain.Order, error) {
+	s.params = params
+	return body, nil
+}
+
+func TestRefundOrderEndpoint(t *testing.T) {
+	service := &refundService{}
+	mux := http.NewServeMux()
+	NewEndpointHandler(service, nil).RegisterRoutes(mux)
+
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		body   string
+		want   int
+	}{
+		{"valid", "?notify=true", "3", ` + "`" + `{"total": 5}` + "`" + `, http.StatusCreated},
+		{"missing header", "", "", ` + "`" + `{"total": 5}` + "`" + `, http.StatusBadRequest},
+		{"invalid query", "?notify=maybe", "3", ` + "`" + `{"total": 5}` + "`" + `, http.StatusBadRequest},
+		{"invalid body", "", "3", "{", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders/o-1/refunds"+tt.query, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("X-Attempt", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	if want := (RefundOrderParams{ID: "o-1", Notify: true, XAttempt: 3}); service.params != want {
+		t.Errorf("params = %+v, want %+v", service.params, want)
+	}
+}
+`
+
+func TestGeneratedEndpointHandlers(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+		Endpoints: []domain.EndpointSpecification{{
+			Path:    "/orders/{id}/refunds",
+			Method:  "POST",
+			Handler: "RefundOrder",
+			Parameters: []domain.ParameterSpecification{
+				{Name: "id", Type: "path", DataType: "string", Required: true},
+				{Name: "notify", Type: "query", DataType: "boolean"},
+				{Name: "X-Attempt", Type: "header", DataType: "integer", Required: true},
+			},
+			Request:  &domain.RequestSpecification{ContentType: "application/json", Schema: "Order"},
+			Response: &domain.ResponseSpecification{StatusCode: 201, ContentType: "application/json", Schema: "Order"},
+		}},
+	}
+
+	dir := generateProject(t, NewOrchestratorService(), spec)
+	test := filepath.Join(dir, "internal", "interfaces", "http", "handlers", "refund_endpoint_test.go")
+	if err := os.WriteFile(test, []byte(refundEndpointTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkProject(t, dir)
+}
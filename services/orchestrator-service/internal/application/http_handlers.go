@@ -0,0 +1,211 @@
+package application
+
+import (
+	"fmt"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// generateHandlerElement generates a net/http CRUD handler serving the entity from its repository
+func (s *OrchestratorService) generateHandlerElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+
+	validate := ""
+	if s.hasFeature(entity.Features, "validation") {
+		validate = fmt.Sprintf(`
+	if err := domain.Validate%s(&item); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}`, name)
+	}
+
+	// Soft-deleted entities are restored by POST <path>/{id}/restore
+	restoreRoute, restore := "", ""
+	if s.softDeletes(entity) {
+		restoreRoute = fmt.Sprintf("\tmux.HandleFunc(\"POST %s/{id}/restore\", h.Restore)\n", s.resourcePath(entity))
+		restore = fmt.Sprintf(`
+// Restore handles POST %[2]s/{id}/restore
+func (h *%[1]sHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.repo.Restore(r.Context(), id); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+	item, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+`, name, s.resourcePath(entity))
+	}
+
+	content := fmt.Sprintf(`package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"%[1]s/internal/domain"
+)
+
+// %[2]sHandler serves the %[2]s REST resource
+type %[2]sHandler struct {
+	repo domain.%[2]sRepository
+}
+
+// New%[2]sHandler creates a new %[2]s handler
+func New%[2]sHandler(repo domain.%[2]sRepository) *%[2]sHandler {
+	return &%[2]sHandler{repo: repo}
+}
+
+// RegisterRoutes registers the %[2]s routes on the given mux
+func (h *%[2]sHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST %[3]s", h.Create)
+	mux.HandleFunc("GET %[3]s", h.List)
+	mux.HandleFunc("GET %[3]s/{id}", h.Get)
+	mux.HandleFunc("PUT %[3]s/{id}", h.Update)
+	mux.HandleFunc("DELETE %[3]s/{id}", h.Delete)
+%[6]s}
+
+// Create handles POST %[3]s
+func (h *%[2]sHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var item domain.%[2]s
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if item.%[4]s == "" {
+		item.%[4]s = uuid.New().String()
+	}
+	now := time.Now()
+	item.CreatedAt, item.UpdatedAt = now, now
+%[5]s
+	if err := h.repo.Create(r.Context(), &item); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &item)
+}
+
+// Get handles GET %[3]s/{id}
+func (h *%[2]sHandler) Get(w http.ResponseWriter, r *http.Request) {
+	item, err := h.repo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// List handles GET %[3]s
+func (h *%[2]sHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, items)
+}
+
+// Update handles PUT %[3]s/{id}
+func (h *%[2]sHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, err := h.repo.GetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	var item domain.%[2]s
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	item.%[4]s = existing.%[4]s
+	item.CreatedAt = existing.CreatedAt
+	item.UpdatedAt = time.Now()
+%[5]s
+	if err := h.repo.Update(r.Context(), &item); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &item)
+}
+
+// Delete handles DELETE %[3]s/{id}
+func (h *%[2]sHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.repo.Delete(r.Context(), r.PathValue("id")); err != nil {
+		writeRepositoryError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+%[7]s`, spec.ModulePath, name, s.resourcePath(entity), id, validate, restoreRoute, restore)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sHandler", name),
+		"handlers",
+		fmt.Sprintf("internal/interfaces/http/handlers/%s_handler.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// generateResponseHelpersElement generates the JSON response helpers shared by generated handlers
+func (s *OrchestratorService) generateResponseHelpersElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	errorCases := ""
+	if s.usesTenancy(spec) {
+		errorCases = "\tcase errors.Is(err, domain.ErrTenantRequired):\n\t\twriteError(w, http.StatusBadRequest, err.Error())\n"
+	}
+	if s.anyEntity(spec, s.versioned) {
+		errorCases += "\tcase errors.Is(err, domain.ErrConflict):\n\t\twriteError(w, http.StatusConflict, err.Error())\n"
+	}
+	content := fmt.Sprintf(`package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"%[1]s/internal/domain"
+)
+
+// writeJSON writes value as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeRepositoryError maps repository errors to HTTP status codes
+func writeRepositoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrAlreadyExists):
+		writeError(w, http.StatusConflict, err.Error())
+%[2]s	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+`, spec.ModulePath, errorCases)
+
+	return s.newFileElement("response", "handlers", "internal/interfaces/http/handlers/response.go", content)
+}
@@ -0,0 +1,672 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+	"go-factory-platform/shared/pkg/database"
+)
+
+// catalogColumn is a column read from information_schema.columns
+type catalogColumn struct {
+	Table     string
+	Name      string
+	DataType  string
+	UDTName   string
+	Nullable  bool
+	Default   sql.NullString
+	MaxLength sql.NullInt64
+}
+
+// catalogConstraint is a primary key, unique, foreign key or check constraint read from pg_constraint
+type catalogConstraint struct {
+	Table      string
+	Name       string
+	Type       string // "p", "u", "f", "c"
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string // pg_constraint.confdeltype
+	OnUpdate   string // pg_constraint.confupdtype
+	Definition string
+}
+
+// catalogIndex is an index read from pg_index that does not back a constraint
+type catalogIndex struct {
+	Table     string
+	Name      string
+	Method    string
+	Unique    bool
+	Columns   []string
+	Predicate string
+}
+
+// databaseCatalog is the introspected structure of a database schema
+type databaseCatalog struct {
+	Tables      []string
+	Columns     map[string][]catalogColumn
+	Constraints map[string][]catalogConstraint
+	Indexes     map[string][]catalogIndex
+	Enums       map[string][]string
+}
+
+// introspectionIgnoredTables are migration bookkeeping tables that never become entities
+var introspectionIgnoredTables = map[string]bool{"schema_migrations": true, "goose_db_version": true, "gorp_migrations": true}
+
+// referentialActionNames maps pg_constraint action codes to RelationshipSpecification actions
+var referentialActionNames = map[string]string{"c": "cascade", "n": "set_null", "r": "restrict"}
+
+// checkInPattern matches the ARRAY[...] of a CHECK (column = ANY (ARRAY[...])) definition,
+// which is how PostgreSQL stores column IN (...) checks
+var checkInPattern = regexp.MustCompile(`^CHECK \(+\(?"?\w+"?\)?(?:::[\w ]+)? = ANY \(+ARRAY\[(.*)\](?:\)?::[\w ]+\[\])?\)+$`)
+
+// quotedLiteralPattern matches a SQL string literal
+var quotedLiteralPattern = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// Limits of an introspection, so that unreachable or slow databases don't hold requests
+const (
+	introspectionConnectTimeout = 10 * time.Second
+	introspectionTimeout        = 60 * time.Second
+)
+
+// IntrospectDatabase connects to a PostgreSQL database and reverse-engineers a project specification
+// from its tables, columns, constraints, indexes and foreign keys
+func (s *OrchestratorService) IntrospectDatabase(ctx context.Context, req *domain.IntrospectionRequest) (*domain.ProjectSpecification, error) {
+	ctx, cancel := context.WithTimeout(ctx, introspectionTimeout)
+	defer cancel()
+
+	config := &database.Config{
+		Host:           req.Connection.Host,
+		Port:           req.Connection.Port,
+		User:           req.Connection.User,
+		Password:       req.Connection.Password,
+		Database:       req.Connection.Database,
+		SSLMode:        req.Connection.SSLMode,
+		ConnectTimeout: introspectionConnectTimeout,
+	}
+	if config.Port == 0 {
+		config.Port = 5432
+	}
+	if config.SSLMode == "" {
+		config.SSLMode = "disable"
+	}
+
+	conn, err := database.NewConnection(config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	schema := req.Schema
+	if schema == "" {
+		schema = "public"
+	}
+
+	catalog, err := s.readPostgresCatalog(ctx, conn.SqlDB, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect schema %s: %w", schema, err)
+	}
+
+	return s.buildIntrospectedSpecification(catalog, req), nil
+}
+
+// readPostgresCatalog reads the tables of a schema from information_schema and pg_catalog
+func (s *OrchestratorService) readPostgresCatalog(ctx context.Context, db *sql.DB, schema string) (*databaseCatalog, error) {
+	catalog := &databaseCatalog{
+		Columns:     make(map[string][]catalogColumn),
+		Constraints: make(map[string][]catalogConstraint),
+		Indexes:     make(map[string][]catalogIndex),
+		Enums:       make(map[string][]string),
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+		ORDER BY table_name`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		catalog.Tables = append(catalog.Tables, table)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT table_name, column_name, data_type, udt_name, is_nullable = 'YES', column_default, character_maximum_length
+		FROM information_schema.columns
+		WHERE table_schema = $1
+		ORDER BY table_name, ordinal_position`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var c catalogColumn
+		if err := rows.Scan(&c.Table, &c.Name, &c.DataType, &c.UDTName, &c.Nullable, &c.Default, &c.MaxLength); err != nil {
+			return err
+		}
+		catalog.Columns[c.Table] = append(catalog.Columns[c.Table], c)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT rel.relname, con.conname, con.contype::text,
+			array_to_string(ARRAY(
+				SELECT att.attname FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = k.attnum
+				ORDER BY k.ord), ','),
+			COALESCE(frel.relname, ''),
+			array_to_string(ARRAY(
+				SELECT att.attname FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute att ON att.attrelid = con.confrelid AND att.attnum = k.attnum
+				ORDER BY k.ord), ','),
+			con.confdeltype::text, con.confupdtype::text, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+		LEFT JOIN pg_class frel ON frel.oid = con.confrelid
+		WHERE nsp.nspname = $1 AND con.contype IN ('p', 'u', 'f', 'c')
+		ORDER BY rel.relname, con.conname`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var c catalogConstraint
+		var columns, refColumns string
+		if err := rows.Scan(&c.Table, &c.Name, &c.Type, &columns, &c.RefTable, &refColumns, &c.OnDelete, &c.OnUpdate, &c.Definition); err != nil {
+			return err
+		}
+		c.Columns, c.RefColumns = splitList(columns), splitList(refColumns)
+		catalog.Constraints[c.Table] = append(catalog.Constraints[c.Table], c)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+
+	// Expression indexes (attnum 0) are skipped because they cannot be expressed as fields
+	rows, err = db.QueryContext(ctx, `
+		SELECT t.relname, i.relname, am.amname, ix.indisunique,
+			array_to_string(ARRAY(
+				SELECT att.attname FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute att ON att.attrelid = t.oid AND att.attnum = k.attnum
+				ORDER BY k.ord), ','),
+			COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '')
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		JOIN pg_namespace nsp ON nsp.oid = t.relnamespace
+		WHERE nsp.nspname = $1
+			AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = ix.indexrelid)
+			AND NOT (0 = ANY (ix.indkey::int2[]))
+		ORDER BY t.relname, i.relname`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var index catalogIndex
+		var columns string
+		if err := rows.Scan(&index.Table, &index.Name, &index.Method, &index.Unique, &columns, &index.Predicate); err != nil {
+			return err
+		}
+		index.Columns = splitList(columns)
+		catalog.Indexes[index.Table] = append(catalog.Indexes[index.Table], index)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT t.typname, e.enumlabel
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace nsp ON nsp.oid = t.typnamespace
+		WHERE nsp.nspname = $1
+		ORDER BY t.typname, e.enumsortorder`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enum types: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var name, label string
+		if err := rows.Scan(&name, &label); err != nil {
+			return err
+		}
+		catalog.Enums[name] = append(catalog.Enums[name], label)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enum types: %w", err)
+	}
+
+	return catalog, nil
+}
+
+// scanRows calls scan for every row and closes rows
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// splitList splits a comma separated list, returning nil for an empty string
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// buildIntrospectedSpecification converts a database catalog to a project specification. Tables
+// that only join two other tables become many_to_many relationships instead of entities.
+func (s *OrchestratorService) buildIntrospectedSpecification(catalog *databaseCatalog, req *domain.IntrospectionRequest) *domain.ProjectSpecification {
+	spec := &domain.ProjectSpecification{
+		Name:        req.ProjectName,
+		ModulePath:  req.ModulePath,
+		OutputPath:  req.OutputPath,
+		ProjectType: req.ProjectType,
+		Configuration: domain.ProjectConfiguration{
+			Database: &domain.DatabaseConfiguration{
+				Type:     "postgres",
+				Host:     req.Connection.Host,
+				Port:     req.Connection.Port,
+				Database: req.Connection.Database,
+			},
+		},
+	}
+	if spec.Name == "" {
+		spec.Name = req.Connection.Database
+	}
+	if spec.ProjectType == "" {
+		spec.ProjectType = "microservice"
+	}
+	if spec.Configuration.Database.Port == 0 {
+		spec.Configuration.Database.Port = 5432
+	}
+
+	features := req.Features
+	if len(features) == 0 {
+		features = defaultImportedFeatures
+	}
+
+	var tables []string
+	for _, table := range catalog.Tables {
+		if s.includeTable(table, req) {
+			tables = append(tables, table)
+		}
+	}
+	included := make(map[string]bool)
+	for _, table := range tables {
+		included[table] = true
+	}
+
+	entities := make(map[string]*domain.EntitySpecification)
+	var joinTables []string
+	for _, table := range tables {
+		if s.isJoinTable(catalog, table, included) {
+			joinTables = append(joinTables, table)
+			continue
+		}
+		entity := s.introspectEntity(catalog, table, features)
+		entities[table] = &entity
+	}
+
+	// Foreign keys become belongs_to relationships on the referencing entity and
+	// one_to_many relationships on the referenced one
+	for _, table := range tables {
+		entity, ok := entities[table]
+		if !ok {
+			continue
+		}
+		for _, constraint := range catalog.Constraints[table] {
+			target, ok := entities[constraint.RefTable]
+			if constraint.Type != "f" || len(constraint.Columns) != 1 || !ok {
+				continue
+			}
+			field := s.introspectedFieldName(constraint.Columns[0])
+			name := s.introspectedFieldName(strings.TrimSuffix(constraint.Columns[0], "_id"))
+			if name == "" || strings.EqualFold(name, field) {
+				name = s.lowerFirst(target.Name)
+			}
+			entity.Relationships = append(entity.Relationships, domain.RelationshipSpecification{
+				Name:       name,
+				Type:       "belongs_to",
+				Target:     target.Name,
+				ForeignKey: field,
+				OnDelete:   referentialActionNames[constraint.OnDelete],
+				OnUpdate:   referentialActionNames[constraint.OnUpdate],
+			})
+			target.Relationships = append(target.Relationships, domain.RelationshipSpecification{
+				Name:       s.lowerFirst(s.toPascalCase(table)),
+				Type:       "one_to_many",
+				Target:     entity.Name,
+				ForeignKey: field,
+			})
+		}
+	}
+
+	for _, table := range joinTables {
+		var targets []*domain.EntitySpecification
+		for _, constraint := range catalog.Constraints[table] {
+			if constraint.Type == "f" {
+				targets = append(targets, entities[constraint.RefTable])
+			}
+		}
+		left, right := targets[0], targets[1]
+		left.Relationships = append(left.Relationships, domain.RelationshipSpecification{
+			Name: s.lowerFirst(s.toPascalCase(s.pluralize(s.toSnakeCase(right.Name)))), Type: "many_to_many", Target: right.Name, JoinTable: table,
+		})
+		if left != right {
+			right.Relationships = append(right.Relationships, domain.RelationshipSpecification{
+				Name: s.lowerFirst(s.toPascalCase(s.pluralize(s.toSnakeCase(left.Name)))), Type: "many_to_many", Target: left.Name, JoinTable: table,
+			})
+		}
+	}
+
+	for _, table := range tables {
+		if entity, ok := entities[table]; ok {
+			spec.Entities = append(spec.Entities, *entity)
+		}
+	}
+
+	return spec
+}
+
+// includeTable reports whether a table is selected by the request filters
+func (s *OrchestratorService) includeTable(table string, req *domain.IntrospectionRequest) bool {
+	if introspectionIgnoredTables[table] {
+		return false
+	}
+	for _, excluded := range req.ExcludeTables {
+		if excluded == table {
+			return false
+		}
+	}
+	if len(req.Tables) == 0 {
+		return true
+	}
+	for _, selected := range req.Tables {
+		if selected == table {
+			return true
+		}
+	}
+	return false
+}
+
+// isJoinTable reports whether a table only links two introspected tables: its columns, apart from
+// timestamps, are two single-column foreign keys that together form the primary key
+func (s *OrchestratorService) isJoinTable(catalog *databaseCatalog, table string, included map[string]bool) bool {
+	var columns []string
+	for _, column := range catalog.Columns[table] {
+		if column.Name != "created_at" && column.Name != "updated_at" {
+			columns = append(columns, column.Name)
+		}
+	}
+	if len(columns) != 2 {
+		return false
+	}
+
+	foreignKeys, primaryKey := 0, false
+	for _, constraint := range catalog.Constraints[table] {
+		switch constraint.Type {
+		case "f":
+			if len(constraint.Columns) != 1 || !included[constraint.RefTable] {
+				return false
+			}
+			foreignKeys++
+		case "p":
+			primaryKey = len(constraint.Columns) == 2
+		}
+	}
+	return foreignKeys == 2 && primaryKey
+}
+
+// introspectEntity converts a table to an entity specification
+func (s *OrchestratorService) introspectEntity(catalog *databaseCatalog, table string, features []string) domain.EntitySpecification {
+	entity := domain.EntitySpecification{
+		Name:     s.toPascalCase(s.singularize(table)),
+		Features: append([]string(nil), features...),
+	}
+	if s.tableName(entity) != table {
+		entity.Options = map[string]string{"table": table}
+	}
+
+	constraints := catalog.Constraints[table]
+	columnConstraints := func(column string) []catalogConstraint {
+		var matches []catalogConstraint
+		for _, constraint := range constraints {
+			if len(constraint.Columns) == 1 && constraint.Columns[0] == column {
+				matches = append(matches, constraint)
+			}
+		}
+		return matches
+	}
+	fieldNames := func(columns []string) []string {
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = s.introspectedFieldName(column)
+		}
+		return names
+	}
+
+	for _, column := range catalog.Columns[table] {
+		// Timestamps and the "id" primary key are generated for every entity
+		if column.Name == "created_at" || column.Name == "updated_at" {
+			continue
+		}
+		primaryKey := false
+		for _, constraint := range columnConstraints(column.Name) {
+			primaryKey = primaryKey || constraint.Type == "p"
+		}
+		if column.Name == "id" && primaryKey {
+			if column.UDTName != "uuid" && column.UDTName != "text" && column.UDTName != "varchar" {
+				entity.Description = fmt.Sprintf("Primary key id is %s in table %s; generated entities use string UUID identifiers", column.DataType, table)
+			}
+			continue
+		}
+
+		field := s.introspectField(catalog, column)
+		for _, constraint := range columnConstraints(column.Name) {
+			switch constraint.Type {
+			case "u":
+				field.Unique = true
+			case "f":
+				if target := constraint.RefTable; target != "" {
+					field.Reference = s.toPascalCase(s.singularize(target))
+				}
+			case "c":
+				if values, ok := s.parseCheckIn(constraint.Definition); ok && field.Type == "string" {
+					field.Type, field.Enum, field.Max = "enum", values, nil
+				} else {
+					entity.Constraints = append(entity.Constraints, domain.ConstraintSpecification{
+						Name: constraint.Name, Type: "check", Fields: []string{field.Name}, Expression: s.checkExpression(constraint.Definition),
+					})
+				}
+			}
+		}
+		entity.Fields = append(entity.Fields, field)
+	}
+
+	for _, constraint := range constraints {
+		if len(constraint.Columns) == 1 && constraint.Type != "p" {
+			continue
+		}
+		switch constraint.Type {
+		case "p":
+			if len(constraint.Columns) == 1 && constraint.Columns[0] == "id" {
+				continue
+			}
+			entity.Constraints = append(entity.Constraints, domain.ConstraintSpecification{
+				Name: constraint.Name, Type: "primary_key", Fields: fieldNames(constraint.Columns),
+			})
+		case "u":
+			entity.Constraints = append(entity.Constraints, domain.ConstraintSpecification{
+				Name: constraint.Name, Type: "unique", Fields: fieldNames(constraint.Columns),
+			})
+		case "f":
+			entity.Constraints = append(entity.Constraints, domain.ConstraintSpecification{
+				Name: constraint.Name, Type: "foreign_key", Fields: fieldNames(constraint.Columns), Reference: constraint.RefTable,
+			})
+		case "c":
+			entity.Constraints = append(entity.Constraints, domain.ConstraintSpecification{
+				Name: constraint.Name, Type: "check", Fields: fieldNames(constraint.Columns), Expression: s.checkExpression(constraint.Definition),
+			})
+		}
+	}
+
+	for _, index := range catalog.Indexes[table] {
+		spec := domain.IndexSpecification{
+			Name:    index.Name,
+			Fields:  fieldNames(index.Columns),
+			Unique:  index.Unique,
+			Partial: index.Predicate,
+		}
+		if index.Method != "btree" {
+			spec.Type = index.Method
+		}
+		entity.Indexes = append(entity.Indexes, spec)
+	}
+
+	return entity
+}
+
+// introspectField converts a column to a field specification
+func (s *OrchestratorService) introspectField(catalog *databaseCatalog, column catalogColumn) domain.FieldSpecification {
+	field := domain.FieldSpecification{
+		Name:     s.introspectedFieldName(column.Name),
+		Type:     "string",
+		Nullable: column.Nullable,
+		Required: !column.Nullable && !column.Default.Valid,
+	}
+	if strings.ToLower(field.Name) != column.Name {
+		field.Tags = map[string]string{"json": column.Name, "db": column.Name}
+	}
+
+	switch column.UDTName {
+	case "uuid":
+		field.Type = "uuid"
+	case "text", "citext", "bpchar":
+		field.Type = "string"
+	case "varchar":
+		field.Type = "string"
+		if column.MaxLength.Valid {
+			max := int(column.MaxLength.Int64)
+			field.Max = &max
+		}
+	case "int2", "int4":
+		field.Type = "integer"
+	case "int8":
+		field.Type = "int64"
+	case "float4":
+		field.Type = "float32"
+	case "float8":
+		field.Type = "float64"
+	case "numeric":
+		field.Type = "decimal"
+	case "bool":
+		field.Type = "boolean"
+	case "timestamp", "timestamptz":
+		field.Type = "timestamp"
+	case "date":
+		field.Type = "date"
+	case "time", "timetz":
+		field.Type = "time"
+	case "json":
+		field.Type = "json"
+	case "jsonb":
+		field.Type = "jsonb"
+	case "bytea":
+		field.Type = "bytes"
+	case "_text", "_varchar":
+		field.Type = "slice"
+	default:
+		if values, ok := catalog.Enums[column.UDTName]; ok {
+			field.Type, field.Enum = "enum", values
+			field.Options = map[string]string{"enum_storage": "type"}
+		} else if strings.HasPrefix(column.UDTName, "_") {
+			field.Type = "array"
+		} else {
+			field.Description = fmt.Sprintf("Introspected from column type %s", column.DataType)
+		}
+	}
+
+	field.Default = s.introspectedDefault(column.Default)
+	return field
+}
+
+// introspectedFieldName converts a column name to a field name (e.g. "user_id" -> "userId")
+func (s *OrchestratorService) introspectedFieldName(column string) string {
+	return s.lowerFirst(s.toPascalCase(column))
+}
+
+// introspectedDefault converts a column default to a field default. Sequence defaults are dropped
+// because the database assigns them.
+func (s *OrchestratorService) introspectedDefault(value sql.NullString) string {
+	if !value.Valid || strings.HasPrefix(value.String, "nextval(") {
+		return ""
+	}
+	def := value.String
+	if match := regexp.MustCompile(`^'((?:[^']|'')*)'(?:::[\w .\[\]"]+)?$`).FindStringSubmatch(def); match != nil {
+		return strings.ReplaceAll(match[1], "''", "'")
+	}
+	if strings.EqualFold(def, "CURRENT_TIMESTAMP") {
+		return "now()"
+	}
+	return def
+}
+
+// parseCheckIn extracts the allowed values of a CHECK (column IN (...)) constraint
+func (s *OrchestratorService) parseCheckIn(definition string) ([]string, bool) {
+	match := checkInPattern.FindStringSubmatch(definition)
+	if match == nil {
+		return nil, false
+	}
+	var values []string
+	for _, literal := range quotedLiteralPattern.FindAllStringSubmatch(match[1], -1) {
+		values = append(values, strings.ReplaceAll(literal[1], "''", "'"))
+	}
+	return values, len(values) > 0
+}
+
+// checkExpression strips the CHECK keyword and outer parentheses of a constraint definition
+func (s *OrchestratorService) checkExpression(definition string) string {
+	expression := strings.TrimSpace(strings.TrimPrefix(definition, "CHECK"))
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		expression = expression[1 : len(expression)-1]
+	}
+	return expression
+}
+
+// singularize returns a naive English singular of a lowercase plural noun, reversing pluralize
+func (s *OrchestratorService) singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
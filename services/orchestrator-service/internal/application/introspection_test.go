@@ -0,0 +1,134 @@
+package application
+
+import (
+	"database/sql"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// shopCatalog is the catalog of a schema with customers, their orders, tags linked to the orders
+// by a join table, an enum type, a check constraint and a partial index
+func shopCatalog() *databaseCatalog {
+	column := func(table, name, dataType, udt string, nullable bool) catalogColumn {
+		return catalogColumn{Table: table, Name: name, DataType: dataType, UDTName: udt, Nullable: nullable}
+	}
+	return &databaseCatalog{
+		Tables: []string{"customers", "orders", "order_tags", "tags", "schema_migrations"},
+		Columns: map[string][]catalogColumn{
+			"customers": {
+				column("customers", "id", "uuid", "uuid", false),
+				column("customers", "email", "character varying", "varchar", false),
+				column("customers", "tier", "USER-DEFINED", "customer_tier", false),
+				column("customers", "created_at", "timestamp with time zone", "timestamptz", false),
+			},
+			"orders": {
+				column("orders", "id", "uuid", "uuid", false),
+				column("orders", "customer_id", "uuid", "uuid", false),
+				column("orders", "total", "numeric", "numeric", false),
+				column("orders", "status", "text", "text", false),
+				column("orders", "notes", "text", "text", true),
+			},
+			"order_tags": {
+				column("order_tags", "order_id", "uuid", "uuid", false),
+				column("order_tags", "tag_id", "uuid", "uuid", false),
+			},
+			"tags": {
+				column("tags", "id", "uuid", "uuid", false),
+				column("tags", "name", "text", "text", false),
+			},
+		},
+		Constraints: map[string][]catalogConstraint{
+			"customers": {
+				{Table: "customers", Name: "customers_pkey", Type: "p", Columns: []string{"id"}},
+				{Table: "customers", Name: "customers_email_key", Type: "u", Columns: []string{"email"}},
+			},
+			"orders": {
+				{Table: "orders", Name: "orders_pkey", Type: "p", Columns: []string{"id"}},
+				{Table: "orders", Name: "orders_customer_id_fkey", Type: "f", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}, OnDelete: "c", OnUpdate: "a"},
+				{Table: "orders", Name: "orders_status_check", Type: "c", Columns: []string{"status"}, Definition: "CHECK ((status = ANY (ARRAY['open'::text, 'paid'::text])))"},
+			},
+			"order_tags": {
+				{Table: "order_tags", Name: "order_tags_pkey", Type: "p", Columns: []string{"order_id", "tag_id"}},
+				{Table: "order_tags", Name: "order_tags_order_id_fkey", Type: "f", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+				{Table: "order_tags", Name: "order_tags_tag_id_fkey", Type: "f", Columns: []string{"tag_id"}, RefTable: "tags", RefColumns: []string{"id"}},
+			},
+			"tags": {
+				{Table: "tags", Name: "tags_pkey", Type: "p", Columns: []string{"id"}},
+			},
+		},
+		Indexes: map[string][]catalogIndex{
+			"orders": {{Table: "orders", Name: "orders_open_idx", Method: "btree", Columns: []string{"status"}, Predicate: "(status = 'open'::text)"}},
+		},
+		Enums: map[string][]string{"customer_tier": {"standard", "gold"}},
+	}
+}
+
+func TestBuildIntrospectedSpecification(t *testing.T) {
+	s := NewOrchestratorService()
+	spec := s.buildIntrospectedSpecification(shopCatalog(), &domain.IntrospectionRequest{
+		Connection: domain.DatabaseConnection{Host: "localhost", Database: "shop"},
+		ModulePath: "example.com/shop",
+		Features:   []string{"crud", "validation", "rest_api", "testing"},
+	})
+
+	entities := make(map[string]domain.EntitySpecification)
+	for _, entity := range spec.Entities {
+		entities[entity.Name] = entity
+	}
+	if len(entities) != 3 {
+		t.Fatalf("entities = %v, want Customer, Order and Tag", spec.Entities)
+	}
+
+	fields := make(map[string]domain.FieldSpecification)
+	for _, field := range entities["Customer"].Fields {
+		fields[field.Name] = field
+	}
+	if email := fields["email"]; !email.Required || !email.Unique {
+		t.Errorf("email = %+v, want a required unique field", email)
+	}
+	if tier := fields["tier"]; tier.Type != "enum" || len(tier.Enum) != 2 {
+		t.Errorf("tier = %+v, want an enum of the customer_tier values", tier)
+	}
+
+	relationships := make(map[string]domain.RelationshipSpecification)
+	for _, entity := range spec.Entities {
+		for _, relationship := range entity.Relationships {
+			relationships[entity.Name+"."+relationship.Name] = relationship
+		}
+	}
+	if customer := relationships["Order.customer"]; customer.Type != "belongs_to" || customer.Target != "Customer" || customer.OnDelete != "cascade" {
+		t.Errorf("Order.customer = %+v, want belongs_to Customer deleted in cascade", customer)
+	}
+	if orders := relationships["Customer.orders"]; orders.Type != "one_to_many" {
+		t.Errorf("Customer.orders = %+v, want one_to_many", orders)
+	}
+	if tags := relationships["Order.tags"]; tags.Type != "many_to_many" || tags.JoinTable != "order_tags" {
+		t.Errorf("Order.tags = %+v, want many_to_many through order_tags", tags)
+	}
+	if indexes := entities["Order"].Indexes; len(indexes) != 1 || indexes[0].Partial == "" {
+		t.Errorf("Order indexes = %+v, want the partial index on status", indexes)
+	}
+
+	// The specification is ready to be generated
+	checkGeneratedProject(t, spec)
+}
+
+func TestIntrospectedDefault(t *testing.T) {
+	s := NewOrchestratorService()
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"'open'::text", "open"},
+		{"'it''s'::character varying", "it's"},
+		{"nextval('orders_id_seq'::regclass)", ""},
+		{"CURRENT_TIMESTAMP", "now()"},
+		{"0", "0"},
+	}
+	for _, tt := range tests {
+		if got := s.introspectedDefault(sql.NullString{String: tt.value, Valid: true}); got != tt.want {
+			t.Errorf("introspectedDefault(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
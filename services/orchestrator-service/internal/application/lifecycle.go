@@ -0,0 +1,120 @@
+package application
+
+import (
+	"strconv"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// lifecycleOptions are the boolean options of entities adding generated fields: "soft_delete"
+// keeps deleted entities as DeletedAt, "optimistic_locking" rejects stale updates by their
+// Version, and "audit" records who created and last updated entities
+var lifecycleOptions = map[string][]string{
+	"soft_delete":        {"deletedAt"},
+	"optimistic_locking": {"version"},
+	"audit":              {"createdBy", "updatedBy"},
+}
+
+// entityOption reports whether a boolean option of an entity is set
+func (s *OrchestratorService) entityOption(entity domain.EntitySpecification, name string) bool {
+	enabled, _ := strconv.ParseBool(entity.Options[name])
+	return enabled
+}
+
+// softDeletes reports whether deleting an entity only marks it as deleted
+func (s *OrchestratorService) softDeletes(entity domain.EntitySpecification) bool {
+	return s.entityOption(entity, "soft_delete")
+}
+
+// versioned reports whether updates of an entity are checked against its version
+func (s *OrchestratorService) versioned(entity domain.EntitySpecification) bool {
+	return s.entityOption(entity, "optimistic_locking")
+}
+
+// audited reports whether an entity records the actors creating and updating it
+func (s *OrchestratorService) audited(entity domain.EntitySpecification) bool {
+	return s.entityOption(entity, "audit")
+}
+
+// lifecycleFields returns the struct fields the lifecycle options of an entity add after its
+// timestamps. They are maintained by the repository, whatever callers set.
+func (s *OrchestratorService) lifecycleFields(entity domain.EntitySpecification) []domain.FieldElement {
+	var fields []domain.FieldElement
+	if s.audited(entity) {
+		fields = append(fields,
+			domain.FieldElement{Name: "CreatedBy", Type: "string", Tags: `json:"created_by" db:"created_by"`},
+			domain.FieldElement{Name: "UpdatedBy", Type: "string", Tags: `json:"updated_by" db:"updated_by"`},
+		)
+	}
+	if s.softDeletes(entity) {
+		fields = append(fields, domain.FieldElement{Name: "DeletedAt", Type: "*time.Time", Tags: `json:"deleted_at,omitempty" db:"deleted_at"`})
+	}
+	if s.versioned(entity) {
+		fields = append(fields, domain.FieldElement{Name: "Version", Type: "int64", Tags: `json:"version" db:"version"`})
+	}
+	return fields
+}
+
+// lifecycleColumns returns the SQL columns of the lifecycle fields of an entity
+func (s *OrchestratorService) lifecycleColumns(entity domain.EntitySpecification) []string {
+	var columns []string
+	if s.audited(entity) {
+		columns = append(columns, "created_by TEXT NOT NULL DEFAULT ''", "updated_by TEXT NOT NULL DEFAULT ''")
+	}
+	if s.softDeletes(entity) {
+		columns = append(columns, "deleted_at TIMESTAMPTZ")
+	}
+	if s.versioned(entity) {
+		columns = append(columns, "version BIGINT NOT NULL DEFAULT 1")
+	}
+	return columns
+}
+
+// generateActorElement generates the actor of the domain, carried by contexts to the
+// repositories of audited entities
+func (s *OrchestratorService) generateActorElement() domain.CodeElement {
+	content := `package domain
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor, such as the subject of a token, that the
+// repositories of audited entities record as CreatedBy and UpdatedBy
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of ctx, or "" for anonymous contexts
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+`
+	return s.newFileElement("Actor", "domain", "internal/domain/actor.go", content)
+}
+
+// generateActorMiddlewareElement generates the middleware making the authenticated principal the
+// actor of requests
+func (s *OrchestratorService) generateActorMiddlewareElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package middleware
+
+import (
+	"net/http"
+
+	"` + spec.ModulePath + `/internal/domain"
+)
+
+// ResolveActor stores the subject of the authenticated principal of each request as the actor of
+// its context, recorded by the repositories of audited entities
+func ResolveActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := PrincipalFromContext(r.Context()); ok && principal.Subject != "" {
+			r = r.WithContext(domain.WithActor(r.Context(), principal.Subject))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+`
+	return s.newFileElement("ActorMiddleware", "middleware", "internal/interfaces/http/middleware/actor.go", content)
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// orderLifecycleTest exercises the fields the lifecycle options add through the generated
+// in-memory repository
+const orderLifecycleTest = `package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/shop/internal/domain"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	repo := NewOrderRepository()
+	ctx := domain.WithActor(context.Background(), "alice")
+
+	order := &domain.Order{ID: "o-1", Total: 5}
+	if err := repo.Create(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if order.CreatedBy != "alice" || order.Version != 1 {
+		t.Fatalf("created order = %+v, want CreatedBy alice and Version 1", order)
+	}
+
+	stale := *order
+	order.Total = 7
+	if err := repo.Update(domain.WithActor(context.Background(), "bob"), order); err != nil {
+		t.Fatal(err)
+	}
+	if order.CreatedBy != "alice" || order.UpdatedBy != "bob" || order.Version != 2 {
+		t.Fatalf("updated order = %+v, want CreatedBy alice, UpdatedBy bob and Version 2", order)
+	}
+	if err := repo.Update(ctx, &stale); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Update() of a stale order error = %v, want ErrConflict", err)
+	}
+
+	if err := repo.Delete(ctx, "o-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(ctx, "o-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID() of a deleted order error = %v, want ErrNotFound", err)
+	}
+	if items, _ := repo.List(ctx); len(items) != 0 {
+		t.Fatalf("List() = %d orders, want the deleted order left out", len(items))
+	}
+	if err := repo.Restore(ctx, "o-1"); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := repo.GetByID(ctx, "o-1")
+	if err != nil || restored.Total != 7 || restored.DeletedAt != nil {
+		t.Fatalf("GetByID() of a restored order = %+v, %v", restored, err)
+	}
+}
+`
+
+func TestGeneratedEntityLifecycleOptions(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api"},
+			Options:  map[string]string{"soft_delete": "true", "optimistic_locking": "true", "audit": "true"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+
+	dir := generateProject(t, NewOrchestratorService(), spec)
+	test := filepath.Join(dir, "internal", "infrastructure", "memory", "order_lifecycle_test.go")
+	if err := os.WriteFile(test, []byte(orderLifecycleTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkProject(t, dir)
+}
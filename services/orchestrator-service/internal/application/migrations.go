@@ -0,0 +1,435 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// hasMigrations reports whether SQL migrations are generated for the entity, either through the
+// entity or project "migrations" feature or through the database configuration. Tenancy implies
+// them for the entities stored in PostgreSQL, whose tables isolate the tenants with row-level
+// security.
+func (s *OrchestratorService) hasMigrations(entity domain.EntitySpecification, spec *domain.ProjectSpecification) bool {
+	if s.hasFeature(entity.Features, "migrations") || s.hasFeature(spec.Features, "migrations") {
+		return true
+	}
+	if s.usesTenancy(spec) && s.usesPostgres(spec) && s.hasRepository(entity) {
+		return true
+	}
+	return spec.Configuration.Database != nil && spec.Configuration.Database.Migrations
+}
+
+// tableName returns the SQL table of an entity; Options["table"] overrides the default plural snake_case name
+func (s *OrchestratorService) tableName(entity domain.EntitySpecification) string {
+	if table := entity.Options["table"]; table != "" {
+		return table
+	}
+	return s.pluralize(s.toSnakeCase(entity.Name))
+}
+
+// columnName returns the SQL column of a field; Tags["db"] overrides the lowercase field name
+func (s *OrchestratorService) columnName(field domain.FieldSpecification) string {
+	if column := field.Tags["db"]; column != "" {
+		return column
+	}
+	return strings.ToLower(field.Name)
+}
+
+// findEntity returns the entity with the given name
+func (s *OrchestratorService) findEntity(spec *domain.ProjectSpecification, name string) (domain.EntitySpecification, bool) {
+	for _, entity := range spec.Entities {
+		if entity.Name == name {
+			return entity, true
+		}
+	}
+	return domain.EntitySpecification{}, false
+}
+
+// referencedTable returns the table referenced by an entity name
+func (s *OrchestratorService) referencedTable(spec *domain.ProjectSpecification, target string) string {
+	if entity, ok := s.findEntity(spec, target); ok {
+		return s.tableName(entity)
+	}
+	return s.pluralize(s.toSnakeCase(target))
+}
+
+// generateMigrationElements generates numbered up/down SQL migrations for every entity with migrations,
+// ordered so that referenced tables are created first
+func (s *OrchestratorService) generateMigrationElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	var elements []domain.CodeElement
+	joinTables := make(map[string]bool)
+	created := make(map[string]bool)
+
+	for i, entity := range s.migrationOrder(spec) {
+		up, down := s.generateCreateTableSQL(entity, spec, created, joinTables)
+		created[entity.Name] = true
+
+		base := fmt.Sprintf("%06d_create_%s", i+1, s.tableName(entity))
+		elements = append(elements,
+			domain.CodeElement{
+				Type:     "file",
+				Name:     base + ".up",
+				Package:  "migrations",
+				Body:     up,
+				Metadata: map[string]interface{}{"path": "migrations/" + base + ".up.sql"},
+			},
+			domain.CodeElement{
+				Type:     "file",
+				Name:     base + ".down",
+				Package:  "migrations",
+				Body:     down,
+				Metadata: map[string]interface{}{"path": "migrations/" + base + ".down.sql"},
+			},
+		)
+	}
+
+	return elements
+}
+
+// migrationOrder returns the entities with migrations, referenced entities first
+func (s *OrchestratorService) migrationOrder(spec *domain.ProjectSpecification) []domain.EntitySpecification {
+	var ordered []domain.EntitySpecification
+	visited := make(map[string]bool)
+
+	var visit func(entity domain.EntitySpecification)
+	visit = func(entity domain.EntitySpecification) {
+		if visited[entity.Name] {
+			return
+		}
+		visited[entity.Name] = true
+
+		for _, target := range s.entityReferences(entity) {
+			if dependency, ok := s.findEntity(spec, target); ok && s.hasMigrations(dependency, spec) {
+				visit(dependency)
+			}
+		}
+		ordered = append(ordered, entity)
+	}
+
+	for _, entity := range spec.Entities {
+		if s.hasMigrations(entity, spec) {
+			visit(entity)
+		}
+	}
+	return ordered
+}
+
+// entityReferences returns the names of entities referenced through foreign keys
+func (s *OrchestratorService) entityReferences(entity domain.EntitySpecification) []string {
+	var targets []string
+	for _, field := range entity.Fields {
+		if field.Reference != "" && field.Reference != entity.Name {
+			targets = append(targets, field.Reference)
+		}
+	}
+	for _, rel := range entity.Relationships {
+		if rel.Type == "belongs_to" && rel.Target != entity.Name {
+			targets = append(targets, rel.Target)
+		}
+	}
+	return targets
+}
+
+// generateCreateTableSQL renders the up and down migration of an entity table
+func (s *OrchestratorService) generateCreateTableSQL(entity domain.EntitySpecification, spec *domain.ProjectSpecification, created, joinTables map[string]bool) (string, string) {
+	table := s.tableName(entity)
+
+	var before, columns, after, down []string
+
+	hasPrimaryKeyConstraint := false
+	for _, constraint := range entity.Constraints {
+		if constraint.Type == "primary_key" {
+			hasPrimaryKeyConstraint = true
+		}
+	}
+	if s.idFieldName(entity) == "ID" {
+		column := "id UUID NOT NULL"
+		if !hasPrimaryKeyConstraint {
+			column += " PRIMARY KEY"
+		}
+		columns = append(columns, column)
+	}
+	// Unique values are unique to the tenant of the rows, and among the rows not soft-deleted
+	scope := s.tenantColumns(entity, spec)
+	live := ""
+	if s.softDeletes(entity) {
+		live = " WHERE deleted_at IS NULL"
+	}
+	var uniques []string
+	for _, column := range scope {
+		columns = append(columns, column+" UUID NOT NULL")
+	}
+
+	for _, field := range entity.Fields {
+		column := s.columnName(field)
+		sqlType := s.sqlColumnType(field)
+
+		var enumCheck string
+		if s.isTypedEnum(field) {
+			quoted := make([]string, len(field.Enum))
+			for i, value := range field.Enum {
+				quoted[i] = s.sqlQuote(value)
+			}
+			if field.Options["enum_storage"] == "type" {
+				enumType := fmt.Sprintf("%s_%s", table, column)
+				before = append(before, fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", enumType, strings.Join(quoted, ", ")))
+				down = append(down, fmt.Sprintf("DROP TYPE IF EXISTS %s;", enumType))
+				sqlType = enumType
+			} else {
+				enumCheck = fmt.Sprintf("CONSTRAINT %s_%s_check CHECK (%s IN (%s))", table, column, column, strings.Join(quoted, ", "))
+			}
+		}
+
+		definition := []string{column, sqlType}
+		// Typed enums store their empty value as NULL, so only required ones can be NOT NULL
+		if field.Required || (!field.Nullable && !s.isTypedEnum(field)) {
+			definition = append(definition, "NOT NULL")
+		}
+		if strings.ToLower(field.Name) == "id" && !hasPrimaryKeyConstraint {
+			definition = append(definition, "PRIMARY KEY")
+		}
+		if field.Unique && live != "" {
+			after = append(after, fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s_%s_key ON %s (%s)%s;", table, column, table, strings.Join(append(scope[:len(scope):len(scope)], column), ", "), live))
+		} else if field.Unique && len(scope) > 0 {
+			uniques = append(uniques, fmt.Sprintf("CONSTRAINT %s_%s_key UNIQUE (%s)", table, column, strings.Join(append(scope[:len(scope):len(scope)], column), ", ")))
+		} else if field.Unique {
+			definition = append(definition, "UNIQUE")
+		}
+		if field.Default != "" {
+			definition = append(definition, "DEFAULT "+s.sqlDefault(field))
+		}
+		if field.Reference != "" {
+			definition = append(definition, fmt.Sprintf("REFERENCES %s (id)", s.referencedTable(spec, field.Reference)))
+			if rel, ok := s.relationshipForForeignKey(entity, field.Name); ok {
+				definition = append(definition, s.referentialActions(rel)...)
+			}
+		}
+		if enumCheck != "" {
+			definition = append(definition, enumCheck)
+		}
+
+		columns = append(columns, strings.Join(definition, " "))
+	}
+
+	columns = append(columns,
+		"created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+		"updated_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+	)
+	columns = append(columns, s.lifecycleColumns(entity)...)
+	columns = append(columns, uniques...)
+
+	for _, constraint := range entity.Constraints {
+		if constraint.Type == "unique" {
+			constraint.Fields = append(scope[:len(scope):len(scope)], constraint.Fields...)
+		}
+		if clause := s.constraintSQL(entity, spec, constraint); clause != "" {
+			columns = append(columns, clause)
+		}
+	}
+
+	if len(scope) > 0 {
+		after = append(after, fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s (%s);", table, strings.Join(scope, "_"), table, strings.Join(scope, ", ")))
+	}
+	for _, index := range entity.Indexes {
+		if index.Unique {
+			index.Fields = append(scope[:len(scope):len(scope)], index.Fields...)
+			if live != "" && index.Partial != "" {
+				index.Partial = "(" + index.Partial + ") AND deleted_at IS NULL"
+			} else if live != "" {
+				index.Partial = "deleted_at IS NULL"
+			}
+		}
+		after = append(after, s.indexSQL(entity, table, index))
+	}
+	if len(scope) > 0 {
+		after = append(after, s.rowLevelSecuritySQL(table, scope))
+	}
+
+	// Join tables are created together with whichever side of the relationship comes last
+	for _, rel := range entity.Relationships {
+		if rel.Type != "many_to_many" || joinTables[s.joinTableName(entity, rel)] {
+			continue
+		}
+		target, ok := s.findEntity(spec, rel.Target)
+		if !ok || (!created[target.Name] && target.Name != entity.Name) {
+			continue
+		}
+		joinTable := s.joinTableName(entity, rel)
+		joinTables[joinTable] = true
+
+		left := s.toSnakeCase(entity.Name) + "_id"
+		right := s.toSnakeCase(target.Name) + "_id"
+		if left == right {
+			right = "related_" + right
+		}
+		after = append(after, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    %s UUID NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+    %s UUID NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+    PRIMARY KEY (%s, %s)
+);`, joinTable, left, table, right, s.tableName(target), left, right))
+		down = append([]string{fmt.Sprintf("DROP TABLE IF EXISTS %s;", joinTable)}, down...)
+	}
+
+	var up strings.Builder
+	fmt.Fprintf(&up, "-- Create %s table for the %s entity\n", table, entity.Name)
+	for _, statement := range before {
+		up.WriteString(statement + "\n\n")
+	}
+	fmt.Fprintf(&up, "CREATE TABLE IF NOT EXISTS %s (\n    %s\n);\n", table, strings.Join(columns, ",\n    "))
+	for _, statement := range after {
+		up.WriteString("\n" + statement + "\n")
+	}
+
+	downSQL := fmt.Sprintf("-- Drop %s table\n", table)
+	var drops []string
+	for _, statement := range down {
+		if strings.HasPrefix(statement, "DROP TABLE") {
+			drops = append(drops, statement)
+		}
+	}
+	drops = append(drops, fmt.Sprintf("DROP TABLE IF EXISTS %s;", table))
+	for _, statement := range down {
+		if !strings.HasPrefix(statement, "DROP TABLE") {
+			drops = append(drops, statement)
+		}
+	}
+	downSQL += strings.Join(drops, "\n") + "\n"
+
+	return up.String(), downSQL
+}
+
+// sqlColumnType returns the PostgreSQL column type of a field
+func (s *OrchestratorService) sqlColumnType(field domain.FieldSpecification) string {
+	sqlType := s.fieldType(field).SQLType
+	if sqlType == "TEXT" && field.Type == "string" && field.Max != nil {
+		return fmt.Sprintf("VARCHAR(%d)", *field.Max)
+	}
+	return sqlType
+}
+
+// sqlDefault renders a field default as a SQL literal; numbers, booleans and function calls are kept as-is
+func (s *OrchestratorService) sqlDefault(field domain.FieldSpecification) string {
+	value := field.Default
+	switch s.valueCategory(s.fieldType(field).GoType) {
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(value); err == nil {
+			return strings.ToUpper(value)
+		}
+	}
+	if strings.HasSuffix(value, "()") {
+		return value
+	}
+	return s.sqlQuote(value)
+}
+
+// sqlQuote quotes a SQL string literal
+func (s *OrchestratorService) sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// relationshipForForeignKey returns the relationship declared for a foreign key field
+func (s *OrchestratorService) relationshipForForeignKey(entity domain.EntitySpecification, fieldName string) (domain.RelationshipSpecification, bool) {
+	for _, rel := range entity.Relationships {
+		if strings.EqualFold(rel.ForeignKey, fieldName) {
+			return rel, true
+		}
+	}
+	return domain.RelationshipSpecification{}, false
+}
+
+// referentialActions renders ON DELETE / ON UPDATE clauses of a relationship
+func (s *OrchestratorService) referentialActions(rel domain.RelationshipSpecification) []string {
+	actions := map[string]string{"cascade": "CASCADE", "set_null": "SET NULL", "restrict": "RESTRICT"}
+
+	var clauses []string
+	if action, ok := actions[rel.OnDelete]; ok {
+		clauses = append(clauses, "ON DELETE "+action)
+	}
+	if action, ok := actions[rel.OnUpdate]; ok {
+		clauses = append(clauses, "ON UPDATE "+action)
+	}
+	return clauses
+}
+
+// fieldColumns maps field names of a constraint or index to their columns
+func (s *OrchestratorService) fieldColumns(entity domain.EntitySpecification, fields []string) []string {
+	columns := make([]string, len(fields))
+	for i, name := range fields {
+		columns[i] = strings.ToLower(name)
+		for _, field := range entity.Fields {
+			if strings.EqualFold(field.Name, name) {
+				columns[i] = s.columnName(field)
+			}
+		}
+	}
+	return columns
+}
+
+// constraintSQL renders a table constraint clause
+func (s *OrchestratorService) constraintSQL(entity domain.EntitySpecification, spec *domain.ProjectSpecification, constraint domain.ConstraintSpecification) string {
+	columns := strings.Join(s.fieldColumns(entity, constraint.Fields), ", ")
+
+	prefix := ""
+	if constraint.Name != "" {
+		prefix = fmt.Sprintf("CONSTRAINT %s ", constraint.Name)
+	}
+
+	switch constraint.Type {
+	case "check":
+		return fmt.Sprintf("%sCHECK (%s)", prefix, constraint.Expression)
+	case "unique":
+		return fmt.Sprintf("%sUNIQUE (%s)", prefix, columns)
+	case "primary_key":
+		return fmt.Sprintf("%sPRIMARY KEY (%s)", prefix, columns)
+	case "foreign_key":
+		table := constraint.Reference
+		if target, ok := s.findEntity(spec, constraint.Reference); ok {
+			table = s.tableName(target)
+		}
+		return fmt.Sprintf("%sFOREIGN KEY (%s) REFERENCES %s (id)", prefix, columns, table)
+	}
+	return ""
+}
+
+// indexSQL renders a CREATE INDEX statement
+func (s *OrchestratorService) indexSQL(entity domain.EntitySpecification, table string, index domain.IndexSpecification) string {
+	columns := s.fieldColumns(entity, index.Fields)
+
+	name := index.Name
+	if name == "" {
+		name = fmt.Sprintf("idx_%s_%s", table, strings.Join(columns, "_"))
+	}
+
+	statement := "CREATE INDEX"
+	if index.Unique {
+		statement = "CREATE UNIQUE INDEX"
+	}
+	statement += fmt.Sprintf(" IF NOT EXISTS %s ON %s", name, table)
+	if index.Type != "" && index.Type != "btree" {
+		statement += " USING " + index.Type
+	}
+	statement += fmt.Sprintf(" (%s)", strings.Join(columns, ", "))
+	if index.Partial != "" {
+		statement += " WHERE " + index.Partial
+	}
+	return statement + ";"
+}
+
+// joinTableName returns the join table of a many_to_many relationship
+func (s *OrchestratorService) joinTableName(entity domain.EntitySpecification, rel domain.RelationshipSpecification) string {
+	if rel.JoinTable != "" {
+		return rel.JoinTable
+	}
+	names := []string{s.toSnakeCase(entity.Name), s.toSnakeCase(rel.Target)}
+	if names[0] > names[1] {
+		names[0], names[1] = names[1], names[0]
+	}
+	return strings.Join(names, "_")
+}
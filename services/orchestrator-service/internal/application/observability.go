@@ -0,0 +1,661 @@
+package application
+
+import (
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// observabilityRoutes are the routes the generated main serves the probes, metrics and profiles on
+var observabilityRoutes = map[string]bool{
+	"GET /health/live": true, "GET /health/ready": true, "GET /metrics": true,
+	"GET /debug/pprof/": true, "GET /debug/pprof/cmdline": true, "GET /debug/pprof/profile": true,
+	"GET /debug/pprof/symbol": true, "POST /debug/pprof/symbol": true, "GET /debug/pprof/trace": true,
+}
+
+// usesObservability reports whether the project gets the observability package: the monitoring
+// feature is enabled or configuration.monitoring enables one of its flags
+func (s *OrchestratorService) usesObservability(spec *domain.ProjectSpecification) bool {
+	if s.usesCLI(spec) {
+		return false
+	}
+	if s.hasFeature(spec.Features, "monitoring") {
+		return true
+	}
+	monitoring := spec.Configuration.Monitoring
+	return monitoring != nil && (monitoring.Metrics || monitoring.Tracing || monitoring.Health || monitoring.Profiling)
+}
+
+// generateObservabilityElements generates the observability package: health probes, Prometheus
+// metrics, W3C trace context propagation and pprof registration
+func (s *OrchestratorService) generateObservabilityElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	elements := []domain.CodeElement{
+		s.generateHealthElement(),
+		s.generateMetricsElement(),
+		s.generateTracingElement(),
+		s.generateProfilingElement(),
+	}
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateObservabilityTestElement(spec))
+	}
+	return elements
+}
+
+// generateHealthElement generates the liveness and readiness probes, readiness running the checks of the dependencies
+func (s *OrchestratorService) generateHealthElement() domain.CodeElement {
+	content := `package observability
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds the checks of a readiness probe
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency of the service, such as its database, is available
+type Check func(ctx context.Context) error
+
+// Health answers the liveness and readiness probes of the service
+type Health struct {
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// NewHealth creates the probes of a service without dependencies
+func NewHealth() *Health {
+	return &Health{checks: make(map[string]Check)}
+}
+
+// AddCheck adds a dependency the service is ready only with
+func (h *Health) AddCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// LivenessHandler answers 200 while the service is serving requests
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// ReadinessHandler runs the checks concurrently, answering 200 when all pass and 503 otherwise,
+// with the result of each check
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		h.mu.RLock()
+		names := make([]string, 0, len(h.checks))
+		for name := range h.checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		errs := make([]error, len(names))
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, check Check) {
+				defer wg.Done()
+				errs[i] = check(ctx)
+			}(i, h.checks[name])
+		}
+		h.mu.RUnlock()
+		wg.Wait()
+
+		status, checks := "ok", make(map[string]string, len(names))
+		for i, name := range names {
+			checks[name] = "ok"
+			if errs[i] != nil {
+				status, checks[name] = "unavailable", errs[i].Error()
+			}
+		}
+		code := http.StatusOK
+		if status != "ok" {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, map[string]interface{}{"status": status, "checks": checks})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+`
+	return s.newFileElement("Health", "observability", "internal/observability/health.go", content)
+}
+
+// generateMetricsElement generates the request counters and latency histograms, labelled by route, and their Prometheus text exposition
+func (s *OrchestratorService) generateMetricsElement() domain.CodeElement {
+	content := `package observability
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histograms
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels the requests no route served, so that unknown paths do not add series
+const unmatchedRoute = "unmatched"
+
+// Metrics counts the HTTP requests by method, route and status, and records their latency by
+// method and route, exposing them in the Prometheus text format
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[routeKey]*histogram
+}
+
+type routeKey struct {
+	method, route string
+}
+
+type requestKey struct {
+	routeKey
+	status int
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket, with +Inf last
+	sum    float64
+}
+
+// NewMetrics creates the metrics of the HTTP requests with the DefaultBuckets
+func NewMetrics() *Metrics {
+	return &Metrics{
+		buckets:   DefaultBuckets,
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[routeKey]*histogram),
+	}
+}
+
+// routeKeyType is the context key of the route label of a request
+type routeKeyType struct{}
+
+// Route labels the requests mux serves with the pattern of their route. It wraps every mux of
+// the service, nested ones included, as the innermost mux has the most specific pattern.
+func Route(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := r.Context().Value(routeKeyType{}).(*string); ok {
+			if _, pattern := mux.Handler(r); pattern != "" {
+				// Patterns may start with their method, which is a label of its own
+				if i := strings.IndexByte(pattern, ' '); i >= 0 {
+					pattern = strings.TrimLeft(pattern[i:], " ")
+				}
+				*route = pattern
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Middleware records the requests, labelled with the route their Route mux set
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := unmatchedRoute
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), routeKeyType{}, &route)))
+		m.Observe(r.Method, route, recorder.status, time.Since(start))
+	})
+}
+
+// Observe records a request of the method and route answered with status after duration
+func (m *Metrics) Observe(method, route string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := routeKey{method: method, route: route}
+	m.requests[requestKey{routeKey: key, status: status}]++
+	h, ok := m.latencies[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets)+1)}
+		m.latencies[key] = h
+	}
+	seconds := duration.Seconds()
+	h.counts[sort.SearchFloat64s(m.buckets, seconds)]++
+	h.sum += seconds
+}
+
+// Handler exposes the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		buf := bufio.NewWriter(w)
+		m.write(buf)
+		_ = buf.Flush()
+	})
+}
+
+func (m *Metrics) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	requests := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		requests = append(requests, key)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].routeKey != requests[j].routeKey {
+			return requests[i].routeKey.less(requests[j].routeKey)
+		}
+		return requests[i].status < requests[j].status
+	})
+	fmt.Fprintln(w, "# HELP http_requests_total Number of HTTP requests by method, route and status.")
+	fmt.Fprintln(w, "# TYPE http_requests_total counter")
+	for _, key := range requests {
+		fmt.Fprintf(w, "http_requests_total{method=%s,route=%s,status=\"%d\"} %d\n",
+			labelValue(key.method), labelValue(key.route), key.status, m.requests[key])
+	}
+
+	routes := make([]routeKey, 0, len(m.latencies))
+	for key := range m.latencies {
+		routes = append(routes, key)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].less(routes[j]) })
+	fmt.Fprintln(w, "# HELP http_request_duration_seconds Latency of HTTP requests by method and route.")
+	fmt.Fprintln(w, "# TYPE http_request_duration_seconds histogram")
+	for _, key := range routes {
+		h := m.latencies[key]
+		labels := fmt.Sprintf("method=%s,route=%s", labelValue(key.method), labelValue(key.route))
+		var count uint64
+		for i, bound := range m.buckets {
+			count += h.counts[i]
+			fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, strconv.FormatFloat(bound, 'g', -1, 64), count)
+		}
+		count += h.counts[len(m.buckets)]
+		fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, count)
+		fmt.Fprintf(w, "http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "http_request_duration_seconds_count{%s} %d\n", labels, count)
+	}
+}
+
+func (k routeKey) less(other routeKey) bool {
+	if k.route != other.route {
+		return k.route < other.route
+	}
+	return k.method < other.method
+}
+
+// labelValue quotes a label value, escaping backslashes, quotes and newlines
+func labelValue(value string) string {
+	return ` + "`" + `"` + "`" + ` + strings.NewReplacer(` + "`" + `\` + "`" + `, ` + "`" + `\\` + "`" + `, ` + "`" + `"` + "`" + `, ` + "`" + `\"` + "`" + `, "\n", ` + "`" + `\n` + "`" + `).Replace(value) + ` + "`" + `"` + "`" + `
+}
+
+// statusRecorder records the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the flusher and deadlines of the response
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+`
+	return s.newFileElement("Metrics", "observability", "internal/observability/metrics.go", content)
+}
+
+// generateTracingElement generates the parsing and propagation of W3C traceparent headers and the tracing middleware
+func (s *OrchestratorService) generateTracingElement() domain.CodeElement {
+	content := `package observability
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Trace context headers of the W3C Trace Context recommendation
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// ErrInvalidTraceParent is returned for traceparent values not in the W3C format
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+// SpanContext identifies a span of a trace, as propagated by the traceparent and tracestate headers
+type SpanContext struct {
+	TraceID  [16]byte
+	SpanID   [8]byte
+	ParentID [8]byte // Span of the caller; zero for the root span of a trace
+	Sampled  bool
+	State    string // Vendor-specific tracestate, propagated unchanged
+}
+
+// TraceParent formats the span context as a version 00 traceparent value
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// TraceIDString returns the trace ID in hex, as logged and reported to tracing backends
+func (sc SpanContext) TraceIDString() string {
+	return hex.EncodeToString(sc.TraceID[:])
+}
+
+// ParseTraceParent parses a traceparent value; the span ID of the result is that of the caller.
+// Versions above 00 are parsed by their version 00 fields, as the recommendation requires.
+func ParseTraceParent(value string) (SpanContext, error) {
+	var sc SpanContext
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+	version, ok := decodeHex(value[:2], 1)
+	if !ok || version[0] == 0xff {
+		return sc, ErrInvalidTraceParent
+	}
+	// Version 00 has these fields only; later versions may append fields after a '-'
+	if version[0] == 0 && len(value) != 55 || len(value) > 55 && value[55] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+	traceID, ok := decodeHex(value[3:35], 16)
+	if !ok || isZero(traceID) {
+		return sc, ErrInvalidTraceParent
+	}
+	spanID, ok := decodeHex(value[36:52], 8)
+	if !ok || isZero(spanID) {
+		return sc, ErrInvalidTraceParent
+	}
+	flags, ok := decodeHex(value[53:55], 1)
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&1 == 1
+	return sc, nil
+}
+
+// decodeHex decodes lowercase hex of n bytes
+func decodeHex(value string, n int) ([]byte, bool) {
+	if len(value) != 2*n || strings.ToLower(value) != value {
+		return nil, false
+	}
+	b, err := hex.DecodeString(value)
+	return b, err == nil
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// spanKey is the context key of the span of a request
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying the span
+func ContextWithSpan(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanKey{}, sc)
+}
+
+// SpanFromContext returns the span of a request served by Tracing
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanKey{}).(SpanContext)
+	return sc, ok
+}
+
+// Inject sets the trace context headers of an outgoing request, so that the services it calls
+// continue the trace of ctx
+func Inject(ctx context.Context, header http.Header) {
+	sc, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	header.Set(TraceParentHeader, sc.TraceParent())
+	if sc.State != "" {
+		header.Set(TraceStateHeader, sc.State)
+	}
+}
+
+// Tracing starts a span for each request, continuing the trace of its traceparent header or
+// starting a sampled trace when it has none or an invalid one. The span is in the context of the
+// request, for its logs and its outgoing requests.
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc, err := ParseTraceParent(r.Header.Get(TraceParentHeader))
+		if err == nil {
+			sc.ParentID = sc.SpanID
+			sc.State = r.Header.Get(TraceStateHeader)
+		} else {
+			sc = SpanContext{Sampled: true}
+			_, _ = rand.Read(sc.TraceID[:])
+		}
+		_, _ = rand.Read(sc.SpanID[:])
+		next.ServeHTTP(w, r.WithContext(ContextWithSpan(r.Context(), sc)))
+	})
+}
+`
+	return s.newFileElement("Tracing", "observability", "internal/observability/tracing.go", content)
+}
+
+// generateProfilingElement generates the registration of the pprof handlers
+func (s *OrchestratorService) generateProfilingElement() domain.CodeElement {
+	content := `package observability
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// RegisterProfiling serves the pprof profiles under /debug/pprof/. They expose the internals of
+// the service: enable them on internal deployments only.
+func RegisterProfiling(mux *http.ServeMux) {
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+}
+`
+	return s.newFileElement("Profiling", "observability", "internal/observability/profiling.go", content)
+}
+
+// generateObservabilityTestElement tests the probes, the metrics of nested muxes and the propagation of trace context
+func (s *OrchestratorService) generateObservabilityTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package observability_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"` + spec.ModulePath + `/internal/observability"
+)
+
+func TestReadiness(t *testing.T) {
+	health := observability.NewHealth()
+	health.AddCheck("cache", func(ctx context.Context) error { return nil })
+
+	ready := func() (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		health.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		var body map[string]interface{}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, body
+	}
+
+	if status, body := ready(); status != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("ready with passing checks: status = %d, body = %v", status, body)
+	}
+	health.AddCheck("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	status, body := ready()
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("ready with a failing check: status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+	checks, _ := body["checks"].(map[string]interface{})
+	if checks["database"] != "connection refused" || checks["cache"] != "ok" {
+		t.Errorf("checks = %v", body["checks"])
+	}
+
+	rec := httptest.NewRecorder()
+	health.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("live: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := observability.NewMetrics()
+	mux := http.NewServeMux()
+	items := http.NewServeMux()
+	items.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	// Nested muxes label the requests with the pattern of the innermost one
+	mux.Handle("/", observability.Route(items))
+	mux.Handle("GET /metrics", metrics.Handler())
+	server := httptest.NewServer(metrics.Middleware(observability.Route(mux)))
+	defer server.Close()
+
+	for _, path := range []string{"/items/1", "/items/2", "/items/missing", "/unknown"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		` + "`" + `http_requests_total{method="GET",route="/items/{id}",status="200"} 2` + "`" + `,
+		` + "`" + `http_requests_total{method="GET",route="/items/{id}",status="404"} 1` + "`" + `,
+		` + "`" + `http_requests_total{method="GET",route="/",status="404"} 1` + "`" + `,
+		` + "`" + `http_request_duration_seconds_bucket{method="GET",route="/items/{id}",le="+Inf"} 3` + "`" + `,
+		` + "`" + `http_request_duration_seconds_count{method="GET",route="/items/{id}"} 3` + "`" + `,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("metrics lack %s:\n%s", line, body)
+		}
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantErr     bool
+		wantSampled bool
+	}{
+		{"sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, true},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false, false},
+		{"later version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, true},
+		{"version 00 with extra fields", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, false},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, false},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", true, false},
+		{"zero parent id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", true, false},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01", true, false},
+		{"empty", "", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := observability.ParseTraceParent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceParent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if sc.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.Sampled != tt.wantSampled {
+				t.Errorf("ParseTraceParent() = %+v", sc)
+			}
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	var span observability.SpanContext
+	handler := observability.Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span, _ = observability.SpanFromContext(r.Context())
+	}))
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", parent)
+	req.Header.Set("tracestate", "vendor=value")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if span.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want that of the traceparent", span.TraceIDString())
+	}
+	if span.TraceParent() == parent {
+		t.Error("span id = that of the caller, want a new one")
+	}
+
+	// The trace continues in the outgoing requests of the span
+	header := make(http.Header)
+	observability.Inject(observability.ContextWithSpan(context.Background(), span), header)
+	if header.Get("traceparent") != span.TraceParent() || header.Get("tracestate") != "vendor=value" {
+		t.Errorf("injected headers = %v", header)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "invalid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if _, err := observability.ParseTraceParent(span.TraceParent()); err != nil || !span.Sampled {
+		t.Errorf("span of a request without a valid traceparent = %+v, want a new sampled trace", span)
+	}
+}
+`
+	return s.newFileElement("ObservabilityTest", "observability_test", "internal/observability/observability_test.go", content)
+}
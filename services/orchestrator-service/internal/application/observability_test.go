@@ -0,0 +1,83 @@
+package application
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedServiceObservability(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config", "testing"},
+		Configuration: domain.ProjectConfiguration{
+			Monitoring: &domain.MonitoringConfiguration{Metrics: true, Tracing: true, Health: true, Profiling: true},
+		},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+	dir := checkGeneratedProject(t, spec)
+
+	// The service serves the probes, the metrics of its routes and the profiles
+	binary := filepath.Join(t.TempDir(), "shop")
+	if out, err := goCommand(dir, "build", "-o", binary, "./cmd/shop"); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cmd := exec.Command(binary)
+	cmd.Env = append(os.Environ(), "SHOP_SERVER_HOST=127.0.0.1", fmt.Sprintf("SHOP_SERVER_PORT=%d", port))
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		url := fmt.Sprintf("http://127.0.0.1:%d%s", port, path)
+		for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+			resp, err := http.Get(url)
+			if err != nil {
+				if time.Now().After(deadline) {
+					t.Fatalf("GET %s: %v", path, err)
+				}
+				continue
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return resp.StatusCode, string(body)
+		}
+	}
+
+	for _, path := range []string{"/health/live", "/health/ready", "/orders", "/debug/pprof/cmdline"} {
+		if status, body := get(path); status != http.StatusOK {
+			t.Errorf("GET %s = %d: %s", path, status, body)
+		}
+	}
+	status, metrics := get("/metrics")
+	if status != http.StatusOK || !strings.Contains(metrics, `http_requests_total{method="GET",route="/orders",status="200"} 1`) {
+		t.Errorf("GET /metrics = %d, want the request of GET /orders counted:\n%s", status, metrics)
+	}
+}
@@ -0,0 +1,656 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// openAPIDocument is the subset of the OpenAPI 3.0 document model produced by the orchestrator.
+// Struct field order is kept by both encoders, so the generated files read top-down.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi" yaml:"openapi"`
+	Info       openAPIInfo                             `json:"info" yaml:"info"`
+	Servers    []openAPIServer                         `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security   []map[string][]string                   `json:"security,omitempty" yaml:"security,omitempty"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths" yaml:"paths"`
+	Components openAPIComponents                       `json:"components" yaml:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Responses       map[string]*openAPIResponse       `json:"responses,omitempty" yaml:"responses,omitempty"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Tags        []string                    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses" yaml:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name" yaml:"name"`
+	In          string         `json:"in" yaml:"in"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool           `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema" yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content" yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Ref         string                       `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                       `json:"description,omitempty" yaml:"description,omitempty"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema  *openAPISchema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example interface{}    `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string                    `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default              interface{}               `json:"default,omitempty" yaml:"default,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Minimum              *int                      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *int                      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	ReadOnly             bool                      `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Required             []string                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties interface{}               `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string             `json:"type" yaml:"type"`
+	Description  string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Scheme       string             `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	BearerFormat string             `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+	Name         string             `json:"name,omitempty" yaml:"name,omitempty"`
+	In           string             `json:"in,omitempty" yaml:"in,omitempty"`
+	Flows        *openAPIOAuthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`
+}
+
+type openAPIOAuthFlows struct {
+	ClientCredentials *openAPIOAuthFlow `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
+}
+
+type openAPIOAuthFlow struct {
+	TokenURL string            `json:"tokenUrl" yaml:"tokenUrl"`
+	Scopes   map[string]string `json:"scopes" yaml:"scopes"`
+}
+
+// openAPISecuritySchemes are the security schemes generated for authentication methods
+var openAPISecuritySchemes = map[string]bool{"bearerAuth": true, "basicAuth": true, "apiKeyAuth": true, "oauth2": true}
+
+// pathParamPattern matches gin-style path parameters (e.g. ":id")
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// templateParamPattern matches OpenAPI path parameters (e.g. "{id}")
+var templateParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// generateOpenAPIElements generates api/openapi.yaml and api/openapi.json describing the project API
+func (s *OrchestratorService) generateOpenAPIElements(spec *domain.ProjectSpecification) ([]domain.CodeElement, error) {
+	document := s.buildOpenAPIDocument(spec)
+
+	var yamlContent bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlContent)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document as YAML: %w", err)
+	}
+	jsonContent, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document as JSON: %w", err)
+	}
+
+	return []domain.CodeElement{
+		s.newFileElement("openapi.yaml", "api", "api/openapi.yaml", yamlContent.String()),
+		s.newFileElement("openapi.json", "api", "api/openapi.json", string(jsonContent)+"\n"),
+	}, nil
+}
+
+// buildOpenAPIDocument builds the OpenAPI document of a project: component schemas for every entity,
+// CRUD operations for entities with the crud feature or a generated handler, the declared endpoints,
+// and security schemes from the security configuration
+func (s *OrchestratorService) buildOpenAPIDocument(spec *domain.ProjectSpecification) *openAPIDocument {
+	version := spec.Options["version"]
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	conflict := "The resource already exists"
+	if s.anyEntity(spec, s.versioned) {
+		conflict = "The resource already exists, or changed since the version it was read at"
+	}
+
+	document := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       spec.Name,
+			Description: spec.Description,
+			Version:     version,
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{
+				"Error": {
+					Type:       "object",
+					Required:   []string{"error"},
+					Properties: map[string]*openAPISchema{"error": {Type: "string"}},
+				},
+			},
+			Responses: map[string]*openAPIResponse{
+				"BadRequest":    s.openAPIErrorResponse("The request is malformed or fails validation"),
+				"NotFound":      s.openAPIErrorResponse("The resource does not exist"),
+				"Conflict":      s.openAPIErrorResponse(conflict),
+				"InternalError": s.openAPIErrorResponse("Unexpected server error"),
+			},
+		},
+	}
+
+	if server := spec.Configuration.Server; server != nil && server.Port != 0 {
+		scheme, host := "http", server.Host
+		if server.TLS {
+			scheme = "https"
+		}
+		if host == "" || host == "0.0.0.0" {
+			host = "localhost"
+		}
+		document.Servers = []openAPIServer{{URL: fmt.Sprintf("%s://%s:%d", scheme, host, server.Port)}}
+	}
+
+	for _, entity := range spec.Entities {
+		document.Components.Schemas[entity.Name] = s.entityOpenAPISchema(entity, spec)
+	}
+
+	if security := spec.Configuration.Security; security != nil {
+		for _, name := range security.Authentication {
+			s.addOpenAPISecurityScheme(document, name)
+		}
+		document.Security = s.openAPISecurityRequirements(security.Authentication)
+	}
+
+	for _, entity := range spec.Entities {
+		if s.hasFeature(entity.Features, "crud") || s.hasHandler(entity) {
+			s.addCRUDOperations(document, entity)
+		}
+	}
+
+	for _, endpoint := range spec.Endpoints {
+		s.addEndpointOperation(document, endpoint, spec)
+	}
+
+	return document
+}
+
+// openAPIErrorResponse returns a response carrying the Error schema
+func (s *OrchestratorService) openAPIErrorResponse(description string) *openAPIResponse {
+	return &openAPIResponse{
+		Description: description,
+		Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: &openAPISchema{Ref: "#/components/schemas/Error"}},
+		},
+	}
+}
+
+// openAPIRef returns a reference to a component schema or response
+func (s *OrchestratorService) openAPIRef(kind, name string) string {
+	return fmt.Sprintf("#/components/%s/%s", kind, name)
+}
+
+// entityOpenAPISchema converts an entity to an object schema mirroring the generated struct's JSON shape
+func (s *OrchestratorService) entityOpenAPISchema(entity domain.EntitySpecification, spec *domain.ProjectSpecification) *openAPISchema {
+	schema := &openAPISchema{
+		Type:        "object",
+		Description: entity.Description,
+		Properties:  make(map[string]*openAPISchema),
+	}
+
+	if s.idFieldName(entity) == "ID" {
+		schema.Properties["id"] = &openAPISchema{Type: "string", ReadOnly: true}
+	}
+
+	for _, field := range entity.Fields {
+		name := s.jsonName(field)
+		property := s.fieldOpenAPISchema(entity, field, spec)
+		if strings.EqualFold(field.Name, "id") {
+			property.ReadOnly = true
+		}
+		schema.Properties[name] = property
+		if field.Required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	schema.Properties["created_at"] = &openAPISchema{Type: "string", Format: "date-time", ReadOnly: true}
+	schema.Properties["updated_at"] = &openAPISchema{Type: "string", Format: "date-time", ReadOnly: true}
+	if s.audited(entity) {
+		schema.Properties["created_by"] = &openAPISchema{Type: "string", ReadOnly: true}
+		schema.Properties["updated_by"] = &openAPISchema{Type: "string", ReadOnly: true}
+	}
+	if s.versioned(entity) {
+		schema.Properties["version"] = &openAPISchema{
+			Type: "integer", Format: "int64",
+			Description: "Version the entity was read at; updates of a changed entity are rejected with 409",
+		}
+	}
+
+	return schema
+}
+
+// fieldOpenAPISchema converts a field to a property schema, applying its validation rules as
+// OpenAPI keywords where an equivalent exists
+func (s *OrchestratorService) fieldOpenAPISchema(entity domain.EntitySpecification, field domain.FieldSpecification, spec *domain.ProjectSpecification) *openAPISchema {
+	schema := s.typeOpenAPISchema(field.Type, spec)
+	schema.Description = field.Description
+	schema.Nullable = field.Nullable
+
+	category := s.valueCategory(s.fieldType(field).GoType)
+	if s.isTypedEnum(field) {
+		category = "string"
+	}
+
+	for _, rule := range s.fieldRules(field) {
+		n := rule.N
+		switch rule.Kind {
+		case "min", "max", "len":
+			bounds := map[string][2]**int{
+				"string":     {&schema.MinLength, &schema.MaxLength},
+				"number":     {&schema.Minimum, &schema.Maximum},
+				"collection": {&schema.MinItems, &schema.MaxItems},
+			}[category]
+			if bounds[0] == nil {
+				continue
+			}
+			if rule.Kind != "max" {
+				*bounds[0] = &n
+			}
+			if rule.Kind != "min" {
+				*bounds[1] = &n
+			}
+		case "enum":
+			schema.Enum = rule.Values
+		case "regex":
+			if schema.Pattern == "" {
+				schema.Pattern = rule.Arg
+			}
+		case "email", "uuid":
+			schema.Format = rule.Kind
+		case "url":
+			schema.Format = "uri"
+		case "base64":
+			schema.Format = "byte"
+		case "alpha":
+			schema.Pattern = "^[a-zA-Z]+$"
+		case "alphanum":
+			schema.Pattern = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			schema.Pattern = `^[-+]?[0-9]+(\.[0-9]+)?$`
+		case "hexadecimal":
+			schema.Pattern = "^(0[xX])?[0-9a-fA-F]+$"
+		}
+	}
+
+	if field.Default != "" {
+		schema.Default = s.openAPIDefault(field.Default, category)
+	}
+
+	return schema
+}
+
+// typeOpenAPISchema returns the schema of a specification type from its JSON type and format in
+// the type registry. Entity names become references, unknown types an untyped object.
+func (s *OrchestratorService) typeOpenAPISchema(fieldType string, spec *domain.ProjectSpecification) *openAPISchema {
+	if def, ok := s.types.Lookup(fieldType); ok {
+		schema := &openAPISchema{Type: def.JSONType, Format: def.JSONFormat}
+		switch def.JSONType {
+		case "array":
+			schema.Items = &openAPISchema{}
+			if def.GoType == "[]string" {
+				schema.Items.Type = "string"
+			}
+		case "object":
+			if strings.HasPrefix(def.GoType, "map[") {
+				schema.AdditionalProperties = true
+			}
+		}
+		return schema
+	}
+
+	if _, ok := s.findEntity(spec, fieldType); ok {
+		return &openAPISchema{Ref: s.openAPIRef("schemas", fieldType)}
+	}
+	return &openAPISchema{Type: "object"}
+}
+
+// openAPIDefault converts a default value to the JSON type of the field
+func (s *OrchestratorService) openAPIDefault(value, category string) interface{} {
+	switch category {
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// addOperation registers an operation on a path, keeping the first definition of a method
+func (s *OrchestratorService) addOperation(document *openAPIDocument, path, method string, operation *openAPIOperation) {
+	item, ok := document.Paths[path]
+	if !ok {
+		item = make(map[string]*openAPIOperation)
+		document.Paths[path] = item
+	}
+	method = strings.ToLower(method)
+	if _, exists := item[method]; !exists {
+		item[method] = operation
+	}
+}
+
+// addCRUDOperations adds the operations served by the generated CRUD handler of an entity
+func (s *OrchestratorService) addCRUDOperations(document *openAPIDocument, entity domain.EntitySpecification) {
+	name := entity.Name
+	collection := s.resourcePath(entity)
+	item := collection + "/{id}"
+	ref := &openAPISchema{Ref: s.openAPIRef("schemas", name)}
+	tags := []string{name}
+
+	jsonBody := func(status string, schema *openAPISchema) *openAPIResponse {
+		return &openAPIResponse{
+			Description: status,
+			Content:     map[string]*openAPIMediaType{"application/json": {Schema: schema}},
+		}
+	}
+	errorRef := func(name string) *openAPIResponse {
+		return &openAPIResponse{Ref: s.openAPIRef("responses", name)}
+	}
+	idParam := &openAPIParameter{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+	requestBody := &openAPIRequestBody{
+		Required: true,
+		Content:  map[string]*openAPIMediaType{"application/json": {Schema: ref}},
+	}
+
+	s.addOperation(document, collection, http.MethodPost, &openAPIOperation{
+		OperationID: "create" + name,
+		Summary:     fmt.Sprintf("Create %s", name),
+		Tags:        tags,
+		RequestBody: requestBody,
+		Responses: map[string]*openAPIResponse{
+			"201": jsonBody("Created", ref),
+			"400": errorRef("BadRequest"),
+			"409": errorRef("Conflict"),
+			"500": errorRef("InternalError"),
+		},
+	})
+	s.addOperation(document, collection, http.MethodGet, &openAPIOperation{
+		OperationID: "list" + s.capitalizeFirst(s.toPascalCase(s.pluralize(s.toSnakeCase(name)))),
+		Summary:     fmt.Sprintf("List %s entities", name),
+		Tags:        tags,
+		Responses: map[string]*openAPIResponse{
+			"200": jsonBody("OK", &openAPISchema{Type: "array", Items: ref}),
+			"500": errorRef("InternalError"),
+		},
+	})
+	s.addOperation(document, item, http.MethodGet, &openAPIOperation{
+		OperationID: "get" + name,
+		Summary:     fmt.Sprintf("Get %s by ID", name),
+		Tags:        tags,
+		Parameters:  []*openAPIParameter{idParam},
+		Responses: map[string]*openAPIResponse{
+			"200": jsonBody("OK", ref),
+			"404": errorRef("NotFound"),
+			"500": errorRef("InternalError"),
+		},
+	})
+	updateResponses := map[string]*openAPIResponse{
+		"200": jsonBody("OK", ref),
+		"400": errorRef("BadRequest"),
+		"404": errorRef("NotFound"),
+		"500": errorRef("InternalError"),
+	}
+	if s.versioned(entity) {
+		updateResponses["409"] = errorRef("Conflict")
+	}
+	s.addOperation(document, item, http.MethodPut, &openAPIOperation{
+		OperationID: "update" + name,
+		Summary:     fmt.Sprintf("Update %s", name),
+		Tags:        tags,
+		Parameters:  []*openAPIParameter{idParam},
+		RequestBody: requestBody,
+		Responses:   updateResponses,
+	})
+	s.addOperation(document, item, http.MethodDelete, &openAPIOperation{
+		OperationID: "delete" + name,
+		Summary:     fmt.Sprintf("Delete %s", name),
+		Tags:        tags,
+		Parameters:  []*openAPIParameter{idParam},
+		Responses: map[string]*openAPIResponse{
+			"204": {Description: "No Content"},
+			"404": errorRef("NotFound"),
+			"500": errorRef("InternalError"),
+		},
+	})
+	if s.softDeletes(entity) {
+		s.addOperation(document, item+"/restore", http.MethodPost, &openAPIOperation{
+			OperationID: "restore" + name,
+			Summary:     fmt.Sprintf("Restore deleted %s", name),
+			Tags:        tags,
+			Parameters:  []*openAPIParameter{idParam},
+			Responses: map[string]*openAPIResponse{
+				"200": jsonBody("OK", ref),
+				"404": errorRef("NotFound"),
+				"500": errorRef("InternalError"),
+			},
+		})
+	}
+}
+
+// addEndpointOperation adds an operation for an endpoint declared in the specification
+func (s *OrchestratorService) addEndpointOperation(document *openAPIDocument, endpoint domain.EndpointSpecification, spec *domain.ProjectSpecification) {
+	path := pathParamPattern.ReplaceAllString(endpoint.Path, "{$1}")
+	method := strings.ToUpper(endpoint.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	operation := &openAPIOperation{
+		OperationID: endpoint.Handler,
+		Summary:     endpoint.Description,
+		Responses:   make(map[string]*openAPIResponse),
+	}
+
+	declared := make(map[string]bool)
+	for _, param := range endpoint.Parameters {
+		in := param.Type
+		if in == "" {
+			in = "query"
+		}
+		if in == "path" {
+			declared[param.Name] = true
+		}
+		operation.Parameters = append(operation.Parameters, &openAPIParameter{
+			Name:        param.Name,
+			In:          in,
+			Description: param.Description,
+			Required:    param.Required || in == "path",
+			Schema:      s.typeOpenAPISchema(param.DataType, spec),
+		})
+	}
+	// Every templated path segment must be described as a parameter
+	for _, match := range templateParamPattern.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			operation.Parameters = append(operation.Parameters, &openAPIParameter{
+				Name: match[1], In: "path", Required: true, Schema: &openAPISchema{Type: "string"},
+			})
+		}
+	}
+
+	if request := endpoint.Request; request != nil {
+		contentType := request.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]*openAPIMediaType{
+				contentType: {Schema: s.schemaReference(request.Schema, spec), Example: s.openAPIExample(request.Example)},
+			},
+		}
+	}
+
+	status, description := http.StatusOK, "OK"
+	response := &openAPIResponse{}
+	if endpoint.Response != nil {
+		if endpoint.Response.StatusCode != 0 {
+			status = endpoint.Response.StatusCode
+		}
+		contentType := endpoint.Response.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		if endpoint.Response.Schema != "" || endpoint.Response.Example != "" {
+			response.Content = map[string]*openAPIMediaType{
+				contentType: {
+					Schema:  s.schemaReference(endpoint.Response.Schema, spec),
+					Example: s.openAPIExample(endpoint.Response.Example),
+				},
+			}
+		}
+	}
+	if text := http.StatusText(status); text != "" {
+		description = text
+	}
+	response.Description = description
+	operation.Responses[strconv.Itoa(status)] = response
+	operation.Responses["default"] = &openAPIResponse{Ref: s.openAPIRef("responses", "InternalError")}
+
+	for _, name := range endpoint.Security {
+		s.addOpenAPISecurityScheme(document, name)
+	}
+	operation.Security = s.openAPISecurityRequirements(endpoint.Security)
+
+	s.addOperation(document, path, method, operation)
+}
+
+// schemaReference resolves the schema named by a request or response: an entity, a list of
+// entities ("[]User") or a plain type
+func (s *OrchestratorService) schemaReference(name string, spec *domain.ProjectSpecification) *openAPISchema {
+	if name == "" {
+		return nil
+	}
+	if element := strings.TrimPrefix(name, "[]"); element != name {
+		return &openAPISchema{Type: "array", Items: s.schemaReference(element, spec)}
+	}
+	return s.typeOpenAPISchema(strings.TrimPrefix(name, "*"), spec)
+}
+
+// openAPIExample decodes a JSON example, falling back to the raw string
+func (s *OrchestratorService) openAPIExample(example string) interface{} {
+	if example == "" {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(example), &value); err == nil {
+		return value
+	}
+	return example
+}
+
+// openAPISecuritySchemeName normalizes an authentication method to its security scheme name
+func (s *OrchestratorService) openAPISecuritySchemeName(method string) string {
+	switch strings.ToLower(method) {
+	case "jwt", "bearer":
+		return "bearerAuth"
+	case "basic":
+		return "basicAuth"
+	case "api_key", "apikey":
+		return "apiKeyAuth"
+	case "oauth", "oauth2":
+		return "oauth2"
+	}
+	return method
+}
+
+// addOpenAPISecurityScheme registers the security scheme of an authentication method
+func (s *OrchestratorService) addOpenAPISecurityScheme(document *openAPIDocument, method string) {
+	var scheme *openAPISecurityScheme
+	switch s.openAPISecuritySchemeName(method) {
+	case "bearerAuth":
+		scheme = &openAPISecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+	case "basicAuth":
+		scheme = &openAPISecurityScheme{Type: "http", Scheme: "basic"}
+	case "apiKeyAuth":
+		scheme = &openAPISecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}
+	case "oauth2":
+		scheme = &openAPISecurityScheme{
+			Type: "oauth2",
+			Flows: &openAPIOAuthFlows{
+				ClientCredentials: &openAPIOAuthFlow{TokenURL: "/oauth/token", Scopes: map[string]string{}},
+			},
+		}
+	default:
+		// Authorization models such as rbac have no OpenAPI security scheme
+		return
+	}
+
+	if document.Components.SecuritySchemes == nil {
+		document.Components.SecuritySchemes = make(map[string]*openAPISecurityScheme)
+	}
+	document.Components.SecuritySchemes[s.openAPISecuritySchemeName(method)] = scheme
+}
+
+// openAPISecurityRequirements returns alternative security requirements, one per known method
+func (s *OrchestratorService) openAPISecurityRequirements(methods []string) []map[string][]string {
+	var requirements []map[string][]string
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		name := s.openAPISecuritySchemeName(method)
+		if seen[name] || !openAPISecuritySchemes[name] {
+			continue
+		}
+		seen[name] = true
+		requirements = append(requirements, map[string][]string{name: {}})
+	}
+	return requirements
+}
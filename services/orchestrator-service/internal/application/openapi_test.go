@@ -0,0 +1,139 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// routePattern matches the routes registered on the mux of a generated project
+var routePattern = regexp.MustCompile(`mux\.Handle(?:Func)?\("([A-Z]+) ([^"]+)"`)
+
+func TestGeneratedOpenAPIDocument(t *testing.T) {
+	min := 1
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config"},
+		Configuration: domain.ProjectConfiguration{
+			Security: &domain.SecurityConfiguration{Authentication: []string{"jwt"}},
+		},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api", "validation"},
+			Fields: []domain.FieldSpecification{
+				{Name: "total", Type: "integer", Required: true, Min: &min},
+				{Name: "status", Type: "enum", Enum: []string{"open", "paid"}},
+				{Name: "email", Type: "email"},
+			},
+		}},
+		Endpoints: []domain.EndpointSpecification{{
+			Path:       "/reports",
+			Method:     "GET",
+			Handler:    "GetReports",
+			Security:   []string{"api_key"},
+			Parameters: []domain.ParameterSpecification{{Name: "from", Type: "query", DataType: "string"}},
+		}},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+
+	// The YAML and JSON documents describe the same API
+	var fromJSON, fromYAML openAPIDocument
+	content, err := os.ReadFile(filepath.Join(dir, "api", "openapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &fromJSON); err != nil {
+		t.Fatalf("api/openapi.json: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "api", "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(content, &fromYAML); err != nil {
+		t.Fatalf("api/openapi.yaml: %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("api/openapi.json and api/openapi.yaml differ:\n%+v\n%+v", fromJSON, fromYAML)
+	}
+	doc := fromJSON
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q, want 3.0.3", doc.OpenAPI)
+	}
+
+	// Every route served by the project is documented, and only those
+	handlers, err := filepath.Glob(filepath.Join(dir, "internal", "interfaces", "http", "handlers", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := 0
+	for _, file := range handlers {
+		code, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range routePattern.FindAllStringSubmatch(string(code), -1) {
+			routes++
+			if doc.Paths[match[2]][strings.ToLower(match[1])] == nil {
+				t.Errorf("%s %s is served but not documented", match[1], match[2])
+			}
+		}
+	}
+	operations := 0
+	for _, methods := range doc.Paths {
+		operations += len(methods)
+	}
+	if routes != 6 || operations != routes {
+		t.Errorf("%d routes are served and %d operations documented, want 6 of each", routes, operations)
+	}
+
+	order := doc.Components.Schemas["Order"]
+	if order == nil {
+		t.Fatalf("no Order schema in %+v", doc.Components.Schemas)
+	}
+	if !reflect.DeepEqual(order.Required, []string{"total"}) {
+		t.Errorf("Order required = %v, want [total]", order.Required)
+	}
+	if total := order.Properties["total"]; total == nil || total.Type != "integer" || total.Minimum == nil || *total.Minimum != 1 {
+		t.Errorf("Order total = %+v, want an integer of minimum 1", total)
+	}
+	if status := order.Properties["status"]; status == nil || !reflect.DeepEqual(status.Enum, []string{"open", "paid"}) {
+		t.Errorf("Order status = %+v, want the enum open, paid", status)
+	}
+	if email := order.Properties["email"]; email == nil || email.Format != "email" {
+		t.Errorf("Order email = %+v, want the email format", email)
+	}
+	if id := order.Properties["id"]; id == nil || !id.ReadOnly {
+		t.Errorf("Order id = %+v, want it read-only", id)
+	}
+	if body := doc.Paths["/orders"]["post"].RequestBody; body == nil || body.Content["application/json"].Schema.Ref != "#/components/schemas/Order" {
+		t.Errorf("createOrder request body = %+v, want the Order schema", body)
+	}
+
+	// The project authenticates with JWT, the endpoint with its own API key
+	if !reflect.DeepEqual(doc.Security, []map[string][]string{{"bearerAuth": {}}}) {
+		t.Errorf("security = %v, want bearerAuth", doc.Security)
+	}
+	reports := doc.Paths["/reports"]["get"]
+	if !reflect.DeepEqual(reports.Security, []map[string][]string{{"apiKeyAuth": {}}}) {
+		t.Errorf("GET /reports security = %v, want apiKeyAuth", reports.Security)
+	}
+	if len(reports.Parameters) != 1 || reports.Parameters[0].Name != "from" || reports.Parameters[0].In != "query" {
+		t.Errorf("GET /reports parameters = %+v, want the query parameter from", reports.Parameters)
+	}
+	for name, kind := range map[string]string{"bearerAuth": "http", "apiKeyAuth": "apiKey"} {
+		if scheme := doc.Components.SecuritySchemes[name]; scheme == nil || scheme.Type != kind {
+			t.Errorf("security scheme %s = %+v, want type %s", name, scheme, kind)
+		}
+	}
+}
@@ -2,19 +2,26 @@ package application
 
 import (
 	"fmt"
+	"go/format"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"go-factory-platform/services/orchestrator-service/internal/domain"
 )
 
 // OrchestratorService handles the conversion from user specifications to generator payloads
 type OrchestratorService struct {
+	types    *TypeRegistry    // Built-in field types, extended per project by forProject
+	features *FeatureRegistry // Plugins generating the code of each feature
 }
 
 // NewOrchestratorService creates a new orchestrator service
 func NewOrchestratorService() *OrchestratorService {
-	return &OrchestratorService{}
+	s := &OrchestratorService{types: NewTypeRegistry(), features: NewFeatureRegistry()}
+	s.registerBuiltinFeatures()
+	return s
 }
 
 // OrchestrateMicroservice converts a project specification to a generator payload
@@ -27,13 +34,22 @@ func (s *OrchestratorService) OrchestrateMicroservice(spec *domain.ProjectSpecif
 		CreatedAt:   time.Now(),
 	}
 
+	// Resolve the custom field types of the project
+	s, err := s.forProject(spec)
+	if err != nil {
+		result.Success = false
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+
 	// Validate and enhance project specification based on type
-	enhancedSpec, err := s.enhanceProjectSpecification(spec)
+	enhancedSpec, features, err := s.enhanceProjectSpecification(spec)
 	if err != nil {
 		result.Success = false
 		result.ErrorMessage = err.Error()
 		return result, err
 	}
+	result.Features = features
 
 	// Convert specification to generator payload
 	payload, err := s.convertToGeneratorPayload(enhancedSpec)
@@ -66,6 +82,14 @@ func (s *OrchestratorService) convertToGeneratorPayload(spec *domain.ProjectSpec
 		OutputPath: spec.OutputPath,
 		ModulePath: spec.ModulePath,
 		Elements:   []domain.CodeElement{},
+		Modules:    s.projectModules(spec),
+	}
+
+	// The protobuf messages of gRPC services are generated by the protoc-gen-go plugin
+	if s.usesGRPC(spec) {
+		if _, err := protocGenGoPath(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Process each entity
@@ -77,9 +101,116 @@ func (s *OrchestratorService) convertToGeneratorPayload(spec *domain.ProjectSpec
 		payload.Elements = append(payload.Elements, elements...)
 	}
 
+	// Project-wide support files shared by the entity elements
+	payload.Elements = append(payload.Elements, s.generateProjectElements(spec)...)
+
+	// Command-line projects serve no HTTP API to describe
+	if !s.usesCLI(spec) {
+		apiElements, err := s.generateOpenAPIElements(spec)
+		if err != nil {
+			return nil, err
+		}
+		payload.Elements = append(payload.Elements, apiElements...)
+	}
+
 	return payload, nil
 }
 
+// generateProjectElements generates code elements shared by all entities of a project
+func (s *OrchestratorService) generateProjectElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	var elements []domain.CodeElement
+
+	needsDomainErrors, needsMocks, needsHandlers, needsFixtures, needsHandlerTests, needsValidation :=
+		false, false, false, false, false, false
+	needsPostgres := false
+	for _, entity := range spec.Entities {
+		testing := s.hasTestingFeature(entity, spec)
+		if testing {
+			needsFixtures = true
+		}
+		if s.hasRepository(entity) && testing {
+			needsDomainErrors, needsMocks = true, true
+		}
+		if s.hasRepository(entity) && (s.usesPostgres(spec) || s.servesEntity(entity, spec)) {
+			needsDomainErrors = true
+			needsPostgres = needsPostgres || s.usesPostgres(spec)
+		}
+		if s.hasHandler(entity) {
+			needsDomainErrors, needsHandlers = true, true
+			needsHandlerTests = needsHandlerTests || testing
+		}
+		if s.hasRepository(entity) && (s.hasFeature(entity.Features, "grpc_api") || s.hasFeature(entity.Features, "graphql_api")) {
+			needsDomainErrors = true
+		}
+		if s.hasFeature(entity.Features, "validation") {
+			needsValidation = true
+		}
+	}
+	// Custom endpoints are served next to the entity handlers, except by command-line projects
+	needsEndpoints := len(spec.Endpoints) > 0 && !s.usesCLI(spec)
+	if needsEndpoints {
+		needsDomainErrors, needsHandlers = true, true
+		needsHandlerTests = needsHandlerTests || s.hasTestingFeature(domain.EntitySpecification{}, spec)
+	}
+
+	if needsDomainErrors {
+		elements = append(elements, s.generateDomainErrorsElement(spec))
+	}
+	if needsMocks {
+		elements = append(elements, s.generateMockRecorderElement())
+	}
+	if needsPostgres {
+		elements = append(elements, s.generatePostgresSupportElement(spec))
+	}
+	if needsHandlers {
+		elements = append(elements, s.generateResponseHelpersElement(spec))
+	}
+	if needsFixtures {
+		elements = append(elements, s.generateFixturesSupportElement(spec))
+	}
+	if needsHandlerTests {
+		elements = append(elements, s.generateHandlerTestHelpersElement())
+	}
+	if needsValidation {
+		elements = append(elements, s.generateValidationHelpersElement())
+	}
+	if needsEndpoints {
+		elements = append(elements, s.generateEndpointElements(spec)...)
+	}
+	if s.usesSecurity(spec) {
+		elements = append(elements, s.generateSecurityElements(spec)...)
+	}
+	if s.usesTenancy(spec) {
+		elements = append(elements, s.generateTenantElement())
+	}
+	if s.anyEntity(spec, s.audited) {
+		elements = append(elements, s.generateActorElement())
+	}
+	if s.usesObservability(spec) {
+		elements = append(elements, s.generateObservabilityElements(spec)...)
+	}
+
+	elements = append(elements, s.generateMigrationElements(spec)...)
+
+	// Project-wide files of the plugins of every feature enabled in the project
+	enabled := make(map[string]bool)
+	for _, feature := range spec.Features {
+		enabled[feature] = true
+	}
+	for _, entity := range spec.Entities {
+		for _, feature := range entity.Features {
+			enabled[feature] = true
+		}
+	}
+	for _, feature := range sortedKeys(enabled) {
+		if plugin, ok := s.features.Lookup(feature); ok {
+			elements = append(elements, plugin.Files(spec)...)
+		}
+	}
+
+	return elements
+}
+
 // convertToGenerationRequest converts a generator payload to the format expected by the generator service
 func (s *OrchestratorService) convertToGenerationRequest(spec *domain.ProjectSpecification, payload *domain.GeneratorPayload) (*domain.GenerationRequest, error) {
 	// Convert CodeElement structs to map[string]interface{} for the generator service
@@ -124,6 +255,19 @@ func (s *OrchestratorService) convertToGenerationRequest(spec *domain.ProjectSpe
 		Parameters:      make(map[string]string),
 	}
 
+	// Modules needed by field types, as "path@version" arguments for go get
+	var modules []string
+	for _, module := range payload.Modules {
+		if module.Version != "" {
+			modules = append(modules, module.Path+"@"+module.Version)
+		} else {
+			modules = append(modules, module.Path)
+		}
+	}
+	if len(modules) > 0 {
+		request.Parameters["go_modules"] = strings.Join(modules, ",")
+	}
+
 	return request, nil
 }
 
@@ -131,39 +275,46 @@ func (s *OrchestratorService) convertToGenerationRequest(spec *domain.ProjectSpe
 func (s *OrchestratorService) generateEntityElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) ([]domain.CodeElement, error) {
 	var elements []domain.CodeElement
 
+	// Generated fixtures and tests need a valid sample of every field
+	if s.hasTestingFeature(entity, spec) {
+		if err := s.checkSamples(entity); err != nil {
+			return nil, err
+		}
+	}
+
 	// 1. Always generate the struct/model
-	structElement := s.generateStructElement(entity)
+	structElement := s.generateStructElement(entity, spec)
 	elements = append(elements, structElement)
 
 	// 2. Generate constructor function
 	constructorElement := s.generateConstructorElement(entity)
 	elements = append(elements, constructorElement)
 
-	// 3. Generate elements based on features
-	for _, feature := range entity.Features {
-		switch feature {
-		case "crud", "repository":
-			// Generate repository interface
-			repoInterface := s.generateRepositoryInterface(entity)
-			elements = append(elements, repoInterface)
-
-		case "validation":
-			// Generate validation function
-			validationFunc := s.generateValidationFunction(entity)
-			elements = append(elements, validationFunc)
+	// Generate named types for enum fields
+	for _, field := range entity.Fields {
+		if s.isTypedEnum(field) {
+			elements = append(elements, s.generateEnumElement(entity, field))
+		}
+	}
 
-		case "rest_api", "handler":
-			// Generate service function for business logic
-			serviceFunc := s.generateServiceFunction(entity)
-			elements = append(elements, serviceFunc)
+	// 3. Generate elements through the plugin of each enabled feature; features are resolved
+	// before generation, so each one appears once
+	for _, feature := range entity.Features {
+		if plugin, ok := s.features.Lookup(feature); ok {
+			elements = append(elements, plugin.Elements(entity, spec)...)
 		}
 	}
 
+	// 4. Generate tests and fixtures for whatever was generated above
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateTestElements(entity, spec)...)
+	}
+
 	return elements, nil
 }
 
 // generateStructElement generates a struct element from entity specification
-func (s *OrchestratorService) generateStructElement(entity domain.EntitySpecification) domain.CodeElement {
+func (s *OrchestratorService) generateStructElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
 	var fields []domain.FieldElement
 
 	// Add ID field if not explicitly defined
@@ -183,9 +334,12 @@ func (s *OrchestratorService) generateStructElement(entity domain.EntitySpecific
 		})
 	}
 
+	// Tenant-scoped entities carry their tenant, assigned by the repository
+	fields = append(fields, s.tenantFields(entity, spec)...)
+
 	// Convert user fields to struct fields
 	for _, field := range entity.Fields {
-		goType := s.mapFieldType(field.Type)
+		goType := s.fieldGoType(entity, field)
 		tags := s.generateFieldTags(field)
 
 		fields = append(fields, domain.FieldElement{
@@ -208,12 +362,14 @@ func (s *OrchestratorService) generateStructElement(entity domain.EntitySpecific
 			Tags: `json:"updated_at" db:"updated_at"`,
 		},
 	)
+	fields = append(fields, s.lifecycleFields(entity)...)
 
 	return domain.CodeElement{
-		Type:    "struct",
-		Name:    entity.Name,
-		Package: "domain",
-		Fields:  fields,
+		Type:     "struct",
+		Name:     entity.Name,
+		Package:  "domain",
+		Fields:   fields,
+		Metadata: s.importsMetadata(entity.Fields),
 	}
 }
 
@@ -222,13 +378,12 @@ func (s *OrchestratorService) generateConstructorElement(entity domain.EntitySpe
 	var parameters []domain.ParameterElement
 
 	// Add parameters for required fields (excluding ID and timestamps)
-	for _, field := range entity.Fields {
-		if field.Required && strings.ToLower(field.Name) != "id" {
-			parameters = append(parameters, domain.ParameterElement{
-				Name: strings.ToLower(field.Name),
-				Type: s.mapFieldType(field.Type),
-			})
-		}
+	fields := constructorFields(entity)
+	for _, field := range fields {
+		parameters = append(parameters, domain.ParameterElement{
+			Name: strings.ToLower(field.Name),
+			Type: s.fieldGoType(entity, field),
+		})
 	}
 
 	// Generate constructor body
@@ -242,8 +397,20 @@ func (s *OrchestratorService) generateConstructorElement(entity domain.EntitySpe
 		Returns: []domain.ReturnElement{
 			{Type: fmt.Sprintf("*%s", entity.Name)},
 		},
-		Body: body,
+		Body:     body,
+		Metadata: s.importsMetadata(fields),
+	}
+}
+
+// constructorFields returns the required fields, excluding ID, that constructors take as parameters
+func constructorFields(entity domain.EntitySpecification) []domain.FieldSpecification {
+	var fields []domain.FieldSpecification
+	for _, field := range entity.Fields {
+		if field.Required && strings.ToLower(field.Name) != "id" {
+			fields = append(fields, field)
+		}
 	}
+	return fields
 }
 
 // generateRepositoryInterface generates a repository interface
@@ -295,6 +462,16 @@ func (s *OrchestratorService) generateRepositoryInterface(entity domain.EntitySp
 			},
 		},
 	}
+	if s.softDeletes(entity) {
+		methods = append(methods, domain.MethodElement{
+			Name: "Restore",
+			Parameters: []domain.ParameterElement{
+				{Name: "ctx", Type: "context.Context"},
+				{Name: "id", Type: "string"},
+			},
+			Returns: []domain.ReturnElement{{Type: "error"}},
+		})
+	}
 
 	return domain.CodeElement{
 		Type:    "interface",
@@ -304,22 +481,6 @@ func (s *OrchestratorService) generateRepositoryInterface(entity domain.EntitySp
 	}
 }
 
-// generateValidationFunction generates a validation function
-func (s *OrchestratorService) generateValidationFunction(entity domain.EntitySpecification) domain.CodeElement {
-	body := s.generateValidationBody(entity)
-
-	return domain.CodeElement{
-		Type:    "function",
-		Name:    fmt.Sprintf("Validate%s", entity.Name),
-		Package: "domain",
-		Parameters: []domain.ParameterElement{
-			{Name: strings.ToLower(entity.Name), Type: fmt.Sprintf("*%s", entity.Name)},
-		},
-		Returns: []domain.ReturnElement{{Type: "error"}},
-		Body:    body,
-	}
-}
-
 // generateServiceFunction generates a service function for business logic
 func (s *OrchestratorService) generateServiceFunction(entity domain.EntitySpecification) domain.CodeElement {
 	body := fmt.Sprintf(`%s := domain.New%s(%s)
@@ -333,13 +494,12 @@ func (s *OrchestratorService) generateServiceFunction(entity domain.EntitySpecif
 	)
 
 	var parameters []domain.ParameterElement
-	for _, field := range entity.Fields {
-		if field.Required && strings.ToLower(field.Name) != "id" {
-			parameters = append(parameters, domain.ParameterElement{
-				Name: strings.ToLower(field.Name),
-				Type: s.mapFieldType(field.Type),
-			})
-		}
+	fields := constructorFields(entity)
+	for _, field := range fields {
+		parameters = append(parameters, domain.ParameterElement{
+			Name: strings.ToLower(field.Name),
+			Type: s.qualifyDomainType(s.fieldGoType(entity, field)),
+		})
 	}
 
 	return domain.CodeElement{
@@ -351,22 +511,34 @@ func (s *OrchestratorService) generateServiceFunction(entity domain.EntitySpecif
 			{Type: fmt.Sprintf("*domain.%s", entity.Name)},
 			{Type: "error"},
 		},
-		Body: body,
+		Body:     body,
+		Metadata: s.importsMetadata(fields),
 	}
 }
 
 // Helper methods
 
-func (s *OrchestratorService) mapFieldType(userType string) string {
-	if goType, exists := domain.TypeMapping[userType]; exists {
-		return goType
+// fieldGoType returns the Go type of a field, taking generated per-field types such as enums into account
+func (s *OrchestratorService) fieldGoType(entity domain.EntitySpecification, field domain.FieldSpecification) string {
+	if s.isTypedEnum(field) {
+		return s.enumTypeName(entity, field)
 	}
-	return userType // fallback to user-provided type
+	return s.fieldType(field).GoType
+}
+
+// importsMetadata returns element metadata listing the imports needed by the field types,
+// which the generator adds to the rendered element
+func (s *OrchestratorService) importsMetadata(fields []domain.FieldSpecification) map[string]interface{} {
+	imports := s.fieldImports(fields)
+	if len(imports) == 0 {
+		return nil
+	}
+	return map[string]interface{}{"imports": strings.Join(imports, ",")}
 }
 
 func (s *OrchestratorService) generateFieldTags(field domain.FieldSpecification) string {
-	jsonTag := fmt.Sprintf(`json:"%s"`, strings.ToLower(field.Name))
-	dbTag := fmt.Sprintf(`db:"%s"`, strings.ToLower(field.Name))
+	jsonTag := fmt.Sprintf(`json:"%s"`, s.jsonName(field))
+	dbTag := fmt.Sprintf(`db:"%s"`, s.columnName(field))
 
 	tags := []string{jsonTag, dbTag}
 
@@ -376,63 +548,181 @@ func (s *OrchestratorService) generateFieldTags(field domain.FieldSpecification)
 		tags = append(tags, validateTag)
 	}
 
+	// Add any other tags declared on the field
+	for _, key := range sortedKeys(field.Tags) {
+		if key != "json" && key != "db" && key != "validate" {
+			tags = append(tags, fmt.Sprintf(`%s:"%s"`, key, field.Tags[key]))
+		}
+	}
+
 	return strings.Join(tags, " ")
 }
 
-func (s *OrchestratorService) capitalizeFirst(str string) string {
-	if len(str) == 0 {
-		return str
+// jsonName returns the JSON name of a field; Tags["json"] overrides the lowercase field name
+func (s *OrchestratorService) jsonName(field domain.FieldSpecification) string {
+	if name := field.Tags["json"]; name != "" {
+		return name
 	}
-	return strings.ToUpper(string(str[0])) + str[1:]
+	return strings.ToLower(field.Name)
 }
 
-func (s *OrchestratorService) generateConstructorBody(entity domain.EntitySpecification, parameters []domain.ParameterElement) string {
-	var assignments []string
-	assignments = append(assignments, "ID: uuid.New().String()")
-
-	for _, param := range parameters {
-		fieldName := s.capitalizeFirst(param.Name)
-		assignments = append(assignments, fmt.Sprintf("%s: %s", fieldName, param.Name))
+// hasFeature reports whether a feature is present in a feature list
+func (s *OrchestratorService) hasFeature(features []string, feature string) bool {
+	for _, f := range features {
+		if f == feature {
+			return true
+		}
 	}
+	return false
+}
 
-	assignments = append(assignments, "CreatedAt: time.Now()")
-	assignments = append(assignments, "UpdatedAt: time.Now()")
+// hasRepository reports whether a repository interface is generated for the entity
+func (s *OrchestratorService) hasRepository(entity domain.EntitySpecification) bool {
+	return s.hasFeature(entity.Features, "crud") || s.hasFeature(entity.Features, "repository")
+}
 
-	return fmt.Sprintf("return &%s{\n\t\t%s,\n\t}", entity.Name, strings.Join(assignments, ",\n\t\t"))
+// hasHandler reports whether HTTP handler code is generated for the entity
+func (s *OrchestratorService) hasHandler(entity domain.EntitySpecification) bool {
+	return s.hasRepository(entity) && (s.hasFeature(entity.Features, "rest_api") || s.hasFeature(entity.Features, "handler"))
 }
 
-func (s *OrchestratorService) generateValidationBody(entity domain.EntitySpecification) string {
-	var validations []string
+// hasTestingFeature reports whether test support code is generated for the entity,
+// either because the entity asks for it or because the project enables it globally
+func (s *OrchestratorService) hasTestingFeature(entity domain.EntitySpecification, spec *domain.ProjectSpecification) bool {
+	return s.hasFeature(entity.Features, "testing") || s.hasFeature(spec.Features, "testing")
+}
 
+// idFieldName returns the Go name of the entity's identifier field
+func (s *OrchestratorService) idFieldName(entity domain.EntitySpecification) string {
 	for _, field := range entity.Fields {
-		if field.Required {
-			fieldName := s.capitalizeFirst(field.Name)
-			switch field.Type {
-			case "string", "email":
-				validations = append(validations,
-					fmt.Sprintf(`if %s.%s == "" {
-		return fmt.Errorf("%s is required")
-	}`, strings.ToLower(entity.Name), fieldName, field.Name))
-			}
+		if strings.ToLower(field.Name) == "id" {
+			return s.capitalizeFirst(field.Name)
+		}
+	}
+	return "ID"
+}
+
+// newFileElement wraps a complete source file into a code element. Go sources are
+// run through gofmt so that templates don't have to track indentation.
+func (s *OrchestratorService) newFileElement(name, pkg, path, content string) domain.CodeElement {
+	if strings.HasSuffix(path, ".go") {
+		if formatted, err := format.Source([]byte(content)); err == nil {
+			content = string(formatted)
 		}
+	}
+
+	return domain.CodeElement{
+		Type:    "file",
+		Name:    name,
+		Package: pkg,
+		Body:    content,
+		Metadata: map[string]interface{}{
+			"path": path,
+		},
+	}
+}
+
+// qualifyDomainType prefixes entity types declared in the domain package with "domain."
+// so they can be referenced from other packages (e.g. "[]*User" becomes "[]*domain.User")
+func (s *OrchestratorService) qualifyDomainType(goType string) string {
+	base := strings.TrimLeft(goType, "*[]")
+	prefix := goType[:len(goType)-len(base)]
+	if base == "" || strings.Contains(base, ".") || !unicode.IsUpper(rune(base[0])) {
+		return goType
+	}
+	return prefix + "domain." + base
+}
 
-		// Add custom validations
-		for _, validation := range field.Validation {
-			if validation == "email" {
-				fieldName := s.capitalizeFirst(field.Name)
-				validations = append(validations,
-					fmt.Sprintf(`if !isValidEmail(%s.%s) {
-		return fmt.Errorf("invalid email format")
-	}`, strings.ToLower(entity.Name), fieldName))
+// pluralize returns a naive English plural of a lowercase noun
+func (s *OrchestratorService) pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
+
+// resourcePath returns the REST collection path of an entity (e.g. "OrderItem" -> "/order-items")
+func (s *OrchestratorService) resourcePath(entity domain.EntitySpecification) string {
+	return "/" + strings.ReplaceAll(s.pluralize(s.toSnakeCase(entity.Name)), "_", "-")
+}
+
+// toSnakeCase converts a Go or user-supplied name to snake_case (e.g. "OrderItem" -> "order_item")
+func (s *OrchestratorService) toSnakeCase(str string) string {
+	var b strings.Builder
+	runes := []rune(str)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
 			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatImports renders the body of an import block, standard library packages first
+func (s *OrchestratorService) formatImports(imports map[string]bool) string {
+	var std, external []string
+	for imp := range imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") || strings.Contains(imp, "/internal/") {
+			external = append(external, imp)
+		} else {
+			std = append(std, imp)
 		}
 	}
+	sort.Strings(std)
+	sort.Strings(external)
+
+	var b strings.Builder
+	for _, imp := range std {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	if len(std) > 0 && len(external) > 0 {
+		b.WriteString("\n")
+	}
+	for _, imp := range external {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	return b.String()
+}
+
+func (s *OrchestratorService) lowerFirst(str string) string {
+	if len(str) == 0 {
+		return str
+	}
+	return strings.ToLower(string(str[0])) + str[1:]
+}
+
+func (s *OrchestratorService) capitalizeFirst(str string) string {
+	if len(str) == 0 {
+		return str
+	}
+	return strings.ToUpper(string(str[0])) + str[1:]
+}
+
+func (s *OrchestratorService) generateConstructorBody(entity domain.EntitySpecification, parameters []domain.ParameterElement) string {
+	var assignments []string
+	assignments = append(assignments, fmt.Sprintf("%s: uuid.New().String()", s.idFieldName(entity)))
 
-	if len(validations) == 0 {
-		return "return nil"
+	// Parameters are lowercased field names, so map them back through the fields
+	// instead of re-capitalizing (which would turn "userId" into "Userid")
+	for _, field := range entity.Fields {
+		if field.Required && strings.ToLower(field.Name) != "id" {
+			assignments = append(assignments, fmt.Sprintf("%s: %s", s.capitalizeFirst(field.Name), strings.ToLower(field.Name)))
+		}
 	}
 
-	return strings.Join(validations, "\n\t") + "\n\treturn nil"
+	assignments = append(assignments, "CreatedAt: time.Now()")
+	assignments = append(assignments, "UpdatedAt: time.Now()")
+
+	return fmt.Sprintf("return &%s{\n\t\t%s,\n\t}", entity.Name, strings.Join(assignments, ",\n\t\t"))
 }
 
 func (s *OrchestratorService) getConstructorParams(entity domain.EntitySpecification) string {
@@ -446,32 +736,39 @@ func (s *OrchestratorService) getConstructorParams(entity domain.EntitySpecifica
 }
 
 // enhanceProjectSpecification enhances the project specification with defaults and project-type-specific configurations
-func (s *OrchestratorService) enhanceProjectSpecification(spec *domain.ProjectSpecification) (*domain.ProjectSpecification, error) {
+func (s *OrchestratorService) enhanceProjectSpecification(spec *domain.ProjectSpecification) (*domain.ProjectSpecification, []domain.FeatureResolution, error) {
 	enhanced := *spec // Copy the original spec
 
 	// Get project type configuration
 	typeConfig, exists := domain.ProjectTypeMapping[spec.ProjectType]
 	if !exists {
-		return nil, fmt.Errorf("unsupported project type: %s", spec.ProjectType)
+		return nil, nil, fmt.Errorf("unsupported project type: %s", spec.ProjectType)
 	}
 
-	// Merge default features with user-specified features
-	featureSet := make(map[string]bool)
-
-	// Add default features for this project type
-	for _, feature := range typeConfig.DefaultFeatures {
-		featureSet[feature] = true
-	}
-
-	// Add user-specified features
-	for _, feature := range spec.Features {
-		featureSet[feature] = true
+	// Resolve the user-specified and default features with everything they require
+	project, err := s.resolveFeatures("",
+		featureRequest{spec.Features, "requested by the project"},
+		featureRequest{typeConfig.DefaultFeatures, fmt.Sprintf("default for %s projects", spec.ProjectType)},
+	)
+	if err != nil {
+		return nil, nil, err
 	}
+	enhanced.Features = project.Features
+	resolutions := []domain.FeatureResolution{project}
 
-	// Convert back to slice
-	enhanced.Features = make([]string, 0, len(featureSet))
-	for feature := range featureSet {
-		enhanced.Features = append(enhanced.Features, feature)
+	// Resolve the features of each entity on a copy, leaving the caller's entities untouched
+	enhanced.Entities = make([]domain.EntitySpecification, len(spec.Entities))
+	for i, entity := range spec.Entities {
+		resolution, err := s.resolveFeatures(entity.Name, featureRequest{entity.Features, "requested by the entity"})
+		if err != nil {
+			return nil, nil, fmt.Errorf("entity %s: %w", entity.Name, err)
+		}
+		if err := entityFeatureConflict(project, resolution); err != nil {
+			return nil, nil, fmt.Errorf("entity %s: %w", entity.Name, err)
+		}
+		entity.Features = resolution.Features
+		enhanced.Entities[i] = entity
+		resolutions = append(resolutions, resolution)
 	}
 
 	// Merge default dependencies
@@ -493,44 +790,5 @@ func (s *OrchestratorService) enhanceProjectSpecification(spec *domain.ProjectSp
 		enhanced.Dependencies = append(enhanced.Dependencies, dep)
 	}
 
-	return &enhanced, nil
-}
-
-// enhanceEntityForProjectType enhances entity based on project type
-func (s *OrchestratorService) enhanceEntityForProjectType(entity *domain.EntitySpecification, projectType string) domain.EntitySpecification {
-	enhanced := *entity
-
-	// Add default features based on project type
-	switch projectType {
-	case "microservice":
-		enhanced.Features = s.mergeFeatures(enhanced.Features, []string{"crud", "validation", "repository"})
-	case "api":
-		enhanced.Features = s.mergeFeatures(enhanced.Features, []string{"validation", "rest_api"})
-	case "cli":
-		enhanced.Features = s.mergeFeatures(enhanced.Features, []string{"validation"})
-	case "library":
-		enhanced.Features = s.mergeFeatures(enhanced.Features, []string{"validation", "testing"})
-	}
-
-	return enhanced
-}
-
-// mergeFeatures merges two feature slices, avoiding duplicates
-func (s *OrchestratorService) mergeFeatures(existing, additional []string) []string {
-	featureSet := make(map[string]bool)
-
-	for _, feature := range existing {
-		featureSet[feature] = true
-	}
-
-	for _, feature := range additional {
-		featureSet[feature] = true
-	}
-
-	result := make([]string, 0, len(featureSet))
-	for feature := range featureSet {
-		result = append(result, feature)
-	}
-
-	return result
+	return &enhanced, resolutions, nil
 }
@@ -0,0 +1,476 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// sqlColumn is a column of an entity table and the struct field it is read into
+type sqlColumn struct {
+	Name  string // SQL column
+	Field string // Go field of the entity struct
+	Codec string // "json" for JSONB and unknown types, "array" for SQL arrays, "" for driver values
+}
+
+// scan returns the Go expression scanning the column into the field of item
+func (c sqlColumn) scan() string {
+	switch c.Codec {
+	case "json":
+		return fmt.Sprintf("jsonb{&item.%s}", c.Field)
+	case "array":
+		return fmt.Sprintf("pq.Array(&item.%s)", c.Field)
+	}
+	return "&item." + c.Field
+}
+
+// arg returns the Go expression passing the field of item as a query argument
+func (c sqlColumn) arg() string {
+	switch c.Codec {
+	case "json":
+		return fmt.Sprintf("jsonb{&item.%s}", c.Field)
+	case "array":
+		return fmt.Sprintf("pq.Array(item.%s)", c.Field)
+	}
+	return "item." + c.Field
+}
+
+// usesPostgres reports whether the project is configured with a PostgreSQL database, which the
+// generated repositories store entities in
+func (s *OrchestratorService) usesPostgres(spec *domain.ProjectSpecification) bool {
+	_, ok := s.projectSQLDriver(spec)
+	return ok && spec.Configuration.Database.Type == "postgres"
+}
+
+// sqlColumns returns the columns of an entity table in the order of the struct fields, as
+// created by its migration
+func (s *OrchestratorService) sqlColumns(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []sqlColumn {
+	var columns []sqlColumn
+	if s.idFieldName(entity) == "ID" {
+		columns = append(columns, sqlColumn{Name: "id", Field: "ID"})
+	}
+	for _, field := range s.tenantFields(entity, spec) {
+		columns = append(columns, sqlColumn{Name: s.toSnakeCase(field.Name), Field: field.Name})
+	}
+	for _, field := range entity.Fields {
+		column := sqlColumn{Name: s.columnName(field), Field: s.capitalizeFirst(field.Name)}
+		// Typed enums implement the database interfaces; unknown types, such as entity names, are
+		// stored as JSON text
+		def, known := s.types.Lookup(field.Type)
+		switch {
+		case s.isTypedEnum(field):
+		case !known || def.SQLType == "JSONB":
+			column.Codec = "json"
+		case strings.HasSuffix(def.SQLType, "[]"):
+			column.Codec = "array"
+		}
+		columns = append(columns, column)
+	}
+	columns = append(columns, sqlColumn{Name: "created_at", Field: "CreatedAt"}, sqlColumn{Name: "updated_at", Field: "UpdatedAt"})
+	for _, field := range s.lifecycleFields(entity) {
+		columns = append(columns, sqlColumn{Name: s.toSnakeCase(field.Name), Field: field.Name})
+	}
+	return columns
+}
+
+// generatePostgresSupportElement generates the helpers shared by the PostgreSQL repositories
+func (s *OrchestratorService) generatePostgresSupportElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	imports := map[string]bool{
+		"context": true, "database/sql": true, "database/sql/driver": true, "encoding/json": true, "errors": true, "fmt": true,
+		"github.com/lib/pq": true, spec.ModulePath + "/internal/domain": true,
+	}
+	tenancy := ""
+	if s.usesTenancy(spec) {
+		tenancy = `
+// setTenant sets the tenant read by the row-level security policies of the tenant-scoped tables
+// until the end of the transaction
+const setTenant = "SELECT set_config('app.tenant_id', $1, true), set_config('app.client_id', $2, true)"
+
+// inTenant runs fn with queries that see the rows of tenant only. Repositories of a *sql.DB run
+// fn in a transaction of their own; those of a *sql.Tx set the tenant of that transaction.
+func inTenant(ctx context.Context, db DB, tenant domain.Tenant, fn func(q DB) error) error {
+	pool, ok := db.(*sql.DB)
+	if !ok {
+		if _, err := db.ExecContext(ctx, setTenant, tenant.ID, tenant.ClientID); err != nil {
+			return fmt.Errorf("failed to set tenant: %w", err)
+		}
+		return fn(db)
+	}
+
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, setTenant, tenant.ID, tenant.ClientID); err != nil {
+		return fmt.Errorf("failed to set tenant: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+`
+	}
+
+	content := fmt.Sprintf(`package postgres
+
+import (
+%s)
+
+// DB is implemented by *sql.DB and *sql.Tx. Repositories created with a *sql.Tx run in that
+// transaction, so that their writes commit together with the other writes of the transaction,
+// such as those of an outbox.Writer.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// jsonb reads and writes the value it points to as JSON, for JSONB columns
+type jsonb struct {
+	v interface{}
+}
+
+// Value encodes the value as JSON
+func (j jsonb) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan decodes a JSON column into the value; NULL leaves it unchanged
+func (j jsonb) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(src, j.v)
+	case string:
+		return json.Unmarshal([]byte(src), j.v)
+	}
+	return fmt.Errorf("cannot scan %%T into a JSON value", src)
+}
+
+// affected returns domain.ErrNotFound when a statement changed no row
+func affected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a violation of a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+%s`, s.formatImports(imports), tenancy)
+
+	return s.newFileElement("postgres", "postgres", "internal/infrastructure/postgres/postgres.go", content)
+}
+
+// generatePostgresRepositoryElement generates the repository storing an entity in the table
+// created by its migration
+func (s *OrchestratorService) generatePostgresRepositoryElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	table := s.tableName(entity)
+	lower := s.lowerFirst(name)
+	imports := map[string]bool{
+		"context": true, "database/sql": true, "errors": true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	columns := s.sqlColumns(entity, spec)
+	var names, scans, args []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+		scans = append(scans, column.scan())
+		args = append(args, column.arg())
+		if column.Codec == "array" {
+			imports["github.com/lib/pq"] = true
+		}
+	}
+	idColumn := "id"
+	if id := s.idFieldName(entity); id != "ID" {
+		for _, field := range entity.Fields {
+			if s.capitalizeFirst(field.Name) == id {
+				idColumn = s.columnName(field)
+			}
+		}
+	}
+
+	// Rows are filtered by the tenant of the context as well as by row-level security, which
+	// roles bypassing it would skip
+	scoped := s.tenancy(entity, spec)
+	tenantColumns := s.tenantColumns(entity, spec)
+	scope, scopeNil, tenantArg, run := "", "", "", "\treturn fn(r.db)\n"
+	var tenantArgs []string
+	where := func(first int) string {
+		conditions := []string{fmt.Sprintf("%s = $%d", idColumn, first)}
+		for i, column := range tenantColumns {
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", column, first+1+i))
+		}
+		if s.softDeletes(entity) {
+			conditions = append(conditions, "deleted_at IS NULL")
+		}
+		return strings.Join(conditions, " AND ")
+	}
+	if scoped != "" {
+		withClient := scoped == "tenant_client"
+		scope = fmt.Sprintf("\ttenant, err := domain.TenantScope(ctx, %t)\n\tif err != nil {\n\t\treturn err\n\t}\n", withClient)
+		scopeNil = fmt.Sprintf("\ttenant, err := domain.TenantScope(ctx, %t)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", withClient)
+		tenantArg = ", tenant"
+		run = "\treturn inTenant(ctx, r.db, tenant, fn)\n"
+		tenantArgs = []string{"tenant.ID"}
+		if withClient {
+			tenantArgs = append(tenantArgs, "tenant.ClientID")
+		}
+	}
+	keyArgs := strings.Join(append([]string{"id"}, tenantArgs...), ", ")
+	itemKeyArgs := strings.Join(append([]string{"item." + s.idFieldName(entity)}, tenantArgs...), ", ")
+
+	// Create assigns what the repository maintains, as the in-memory repository does
+	var onCreate, onUpdate string
+	if scoped != "" {
+		onCreate = "\titem.TenantID = tenant.ID\n"
+		if scoped == "tenant_client" {
+			onCreate += "\titem.ClientID = tenant.ClientID\n"
+		}
+		onUpdate = onCreate
+	}
+	if s.audited(entity) {
+		onCreate += "\titem.CreatedBy = domain.ActorFromContext(ctx)\n\titem.UpdatedBy = item.CreatedBy\n"
+		onUpdate += "\titem.UpdatedBy = domain.ActorFromContext(ctx)\n"
+	}
+	if s.softDeletes(entity) {
+		onCreate += "\titem.DeletedAt = nil\n"
+		onUpdate += "\titem.DeletedAt = nil\n"
+	}
+	if s.versioned(entity) {
+		onCreate += "\titem.Version = 1\n"
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+
+	// Updates leave the key, tenant and creation columns alone and return the latter
+	maintained := map[string]bool{idColumn: true, "created_at": true, "created_by": true, "deleted_at": true, "version": true}
+	for _, column := range tenantColumns {
+		maintained[column] = true
+	}
+	var sets, updateArgs []string
+	updateArgs = append(updateArgs, itemKeyArgs)
+	next := 2 + len(tenantColumns)
+	for _, column := range columns {
+		if maintained[column.Name] {
+			continue
+		}
+		sets = append(sets, fmt.Sprintf("%s = $%d", column.Name, next))
+		updateArgs = append(updateArgs, column.arg())
+		next++
+	}
+	updateWhere := where(1)
+	if s.versioned(entity) {
+		sets = append(sets, "version = version + 1")
+		updateWhere += fmt.Sprintf(" AND version = $%d", next)
+		updateArgs = append(updateArgs, "item.Version")
+	}
+	returning, returned := "created_at", "&item.CreatedAt"
+	if s.audited(entity) {
+		returning, returned = "created_at, created_by", "&item.CreatedAt, &item.CreatedBy"
+	}
+	update := fmt.Sprintf("UPDATE %s SET %s WHERE %s RETURNING %s", table, strings.Join(sets, ", "), updateWhere, returning)
+
+	// A versioned update matching no row is a conflict when the entity exists at another version
+	missing := "\t\t\treturn domain.ErrNotFound\n"
+	updated := ""
+	if s.versioned(entity) {
+		missing = fmt.Sprintf(`			var exists bool
+			if err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM %s WHERE %s)", %s).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return domain.ErrConflict
+			}
+			return domain.ErrNotFound
+`, table, where(1), itemKeyArgs)
+		updated = "\titem.Version++\n"
+	}
+
+	remove := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where(1))
+	restore := ""
+	if s.softDeletes(entity) {
+		remove = fmt.Sprintf("UPDATE %s SET deleted_at = now() WHERE %s", table, where(1))
+		restore = fmt.Sprintf(`
+// Restore brings back the deleted %[1]s with the given ID
+func (r *%[1]sRepository) Restore(ctx context.Context, id string) error {
+%[2]s	return r.run(ctx%[3]s, func(q DB) error {
+		return affected(q.ExecContext(ctx, %[4]q, %[5]s))
+	})
+}
+`, name, scope, tenantArg, fmt.Sprintf("UPDATE %s SET deleted_at = NULL WHERE %s", table, strings.Replace(where(1), "deleted_at IS NULL", "deleted_at IS NOT NULL", 1)), keyArgs)
+	}
+
+	var listConditions []string
+	for i, column := range tenantColumns {
+		listConditions = append(listConditions, fmt.Sprintf("%s = $%d", column, i+1))
+	}
+	if s.softDeletes(entity) {
+		listConditions = append(listConditions, "deleted_at IS NULL")
+	}
+	list := " FROM " + table
+	if len(listConditions) > 0 {
+		list += " WHERE " + strings.Join(listConditions, " AND ")
+	}
+	list += " ORDER BY " + idColumn
+	listArgs := ""
+	if len(tenantArgs) > 0 {
+		listArgs = ", " + strings.Join(tenantArgs, ", ")
+	}
+
+	runSignature := "func (r *%[2]sRepository) run(ctx context.Context, fn func(q DB) error) error"
+	runDoc := "// run runs fn with the database of the repository"
+	if scoped != "" {
+		runSignature = "func (r *%[2]sRepository) run(ctx context.Context, tenant domain.Tenant, fn func(q DB) error) error"
+		runDoc = "// run runs fn with queries that see the rows of tenant only"
+	}
+
+	content := fmt.Sprintf(`package postgres
+
+import (
+%[1]s)
+
+// %[2]sRepository is the PostgreSQL implementation of domain.%[2]sRepository, storing entities
+// in the %[3]s table
+type %[2]sRepository struct {
+	db DB
+}
+
+var _ domain.%[2]sRepository = (*%[2]sRepository)(nil)
+
+// New%[2]sRepository creates a repository querying db, a *sql.DB or a *sql.Tx
+func New%[2]sRepository(db DB) *%[2]sRepository {
+	return &%[2]sRepository{db: db}
+}
+
+const %[4]sColumns = %[5]q
+
+// scan%[2]s reads a row of %[4]sColumns
+func scan%[2]s(row scanner) (*domain.%[2]s, error) {
+	var item domain.%[2]s
+	if err := row.Scan(%[6]s); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create stores a new %[2]s
+func (r *%[2]sRepository) Create(ctx context.Context, item *domain.%[2]s) error {
+%[7]s%[8]s	return r.run(ctx%[9]s, func(q DB) error {
+		_, err := q.ExecContext(ctx, %[10]q, %[11]s)
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	})
+}
+
+// GetByID returns the %[2]s with the given ID
+func (r *%[2]sRepository) GetByID(ctx context.Context, id string) (*domain.%[2]s, error) {
+%[12]s	var item *domain.%[2]s
+	if err := r.run(ctx%[9]s, func(q DB) error {
+		var err error
+		item, err = scan%[2]s(q.QueryRowContext(ctx, "SELECT "+%[4]sColumns+%[13]q, %[14]s))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Update replaces an existing %[2]s
+func (r *%[2]sRepository) Update(ctx context.Context, item *domain.%[2]s) error {
+%[7]s%[15]s	if err := r.run(ctx%[9]s, func(q DB) error {
+		err := q.QueryRowContext(ctx, %[16]q, %[17]s).Scan(%[18]s)
+		if errors.Is(err, sql.ErrNoRows) {
+%[19]s		}
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+%[20]s	return nil
+}
+
+// Delete removes the %[2]s with the given ID
+func (r *%[2]sRepository) Delete(ctx context.Context, id string) error {
+%[7]s	return r.run(ctx%[9]s, func(q DB) error {
+		return affected(q.ExecContext(ctx, %[21]q, %[14]s))
+	})
+}
+
+// List returns the stored %[2]s entities ordered by ID
+func (r *%[2]sRepository) List(ctx context.Context) ([]*domain.%[2]s, error) {
+%[12]s	var items []*domain.%[2]s
+	if err := r.run(ctx%[9]s, func(q DB) error {
+		rows, err := q.QueryContext(ctx, "SELECT "+%[4]sColumns+%[22]q%[23]s)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		items = []*domain.%[2]s{}
+		for rows.Next() {
+			item, err := scan%[2]s(rows)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	}); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+%[24]s
+%[25]s
+`+runSignature+` {
+%[26]s}
+`,
+		s.formatImports(imports), name, table, lower, strings.Join(names, ", "), strings.Join(scans, ", "),
+		scope, onCreate, tenantArg, insert, strings.Join(args, ", "),
+		scopeNil, fmt.Sprintf(" FROM %s WHERE %s", table, where(1)), keyArgs,
+		onUpdate, update, strings.Join(updateArgs, ", "), returned, missing, updated,
+		remove, list, listArgs, restore, runDoc, run)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sPostgresRepository", name),
+		"postgres",
+		fmt.Sprintf("internal/infrastructure/postgres/%s_repository.go", s.toSnakeCase(name)),
+		content,
+	)
+}
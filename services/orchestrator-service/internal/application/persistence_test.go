@@ -0,0 +1,85 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// persistenceSpec returns a service storing its entities in the configured database
+func persistenceSpec(database string, features ...string) *domain.ProjectSpecification {
+	max := 100
+	return &domain.ProjectSpecification{
+		Name:          "shop",
+		ModulePath:    "example.com/shop",
+		ProjectType:   "microservice",
+		Features:      append([]string{"config"}, features...),
+		Options:       map[string]string{"tenancy": "tenant"},
+		Configuration: domain.ProjectConfiguration{Database: &domain.DatabaseConfiguration{Type: database, Host: "localhost", Database: "shop"}},
+		Entities: []domain.EntitySpecification{
+			{
+				Name:     "Customer",
+				Features: []string{"crud", "rest_api", "validation"},
+				Options:  map[string]string{"soft_delete": "true", "audit": "true", "optimistic_locking": "true"},
+				Fields: []domain.FieldSpecification{
+					{Name: "name", Type: "string", Required: true, Max: &max},
+					{Name: "status", Type: "enum", Enum: []string{"active", "blocked"}},
+					{Name: "tags", Type: "slice"},
+					{Name: "settings", Type: "map"},
+					{Name: "balance", Type: "decimal"},
+				},
+			},
+			{
+				Name:     "Country",
+				Features: []string{"crud", "rest_api"},
+				Options:  map[string]string{"tenancy": "none"},
+				Fields:   []domain.FieldSpecification{{Name: "code", Type: "string", Required: true}},
+			},
+		},
+	}
+}
+
+func TestGeneratedMainServesEntitiesWithoutTesting(t *testing.T) {
+	for _, database := range []string{"postgres", "sqlite"} {
+		t.Run(database, func(t *testing.T) {
+			dir := checkGeneratedProject(t, persistenceSpec(database))
+
+			main, err := os.ReadFile(filepath.Join(dir, "cmd", "shop", "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(main), "handlers.NewCustomerHandler(repos.Customer)") {
+				t.Errorf("main.go does not serve the Customer handler:\n%s", main)
+			}
+			_, err = os.Stat(filepath.Join(dir, "internal", "infrastructure", "postgres", "customer_repository.go"))
+			if generated := err == nil; generated != (database == "postgres") {
+				t.Errorf("PostgreSQL repository generated = %v for database %s", generated, database)
+			}
+		})
+	}
+}
+
+func TestGeneratedPostgresRepositoriesWithTesting(t *testing.T) {
+	checkGeneratedProject(t, persistenceSpec("postgres", "testing"))
+}
+
+func TestPostgresRepositorySetsTenant(t *testing.T) {
+	s := NewOrchestratorService()
+	spec := persistenceSpec("postgres")
+
+	support := s.generatePostgresSupportElement(spec).Body
+	if !strings.Contains(support, "set_config('app.tenant_id', $1, true)") {
+		t.Errorf("postgres.go does not set the tenant of the transaction:\n%s", support)
+	}
+	scoped := s.generatePostgresRepositoryElement(spec.Entities[0], spec).Body
+	if !strings.Contains(scoped, "inTenant(ctx, r.db, tenant, fn)") {
+		t.Errorf("tenant-scoped repository does not run in the tenant:\n%s", scoped)
+	}
+	unscoped := s.generatePostgresRepositoryElement(spec.Entities[1], spec).Body
+	if strings.Contains(unscoped, "inTenant") {
+		t.Errorf("unscoped repository runs in a tenant:\n%s", unscoped)
+	}
+}
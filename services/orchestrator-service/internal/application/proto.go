@@ -0,0 +1,665 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// Field numbers 19000 to 19999 are reserved for the protobuf implementation
+const (
+	protoImplementationFirst = 19000
+	protoImplementationLast  = 19999
+	protoFieldNumberMax      = 1<<29 - 1
+)
+
+// protoConversion is how a generated gRPC server converts a field between the domain struct and
+// its protobuf message
+type protoConversion int
+
+const (
+	convertAssign    protoConversion = iota // Same Go type on both sides
+	convertCast                             // Numeric conversion, e.g. int <-> int64
+	convertTimestamp                        // time.Time <-> google.protobuf.Timestamp
+	convertEnum                             // Generated enum type <-> protobuf enum
+	convertJSON                             // Any other type, carried as its JSON encoding in a string
+)
+
+// protoScalar is the protobuf representation of a Go type
+type protoScalar struct {
+	protoType string
+	goType    string // Go type of the generated message field
+	kind      descriptorpb.FieldDescriptorProto_Type
+	repeated  bool
+}
+
+// protoScalars maps the Go types of fields to protobuf types. Types missing from the map are
+// carried as JSON text.
+var protoScalars = map[string]protoScalar{
+	"string":   {"string", "string", descriptorpb.FieldDescriptorProto_TYPE_STRING, false},
+	"bool":     {"bool", "bool", descriptorpb.FieldDescriptorProto_TYPE_BOOL, false},
+	"int":      {"int64", "int64", descriptorpb.FieldDescriptorProto_TYPE_INT64, false},
+	"int32":    {"int32", "int32", descriptorpb.FieldDescriptorProto_TYPE_INT32, false},
+	"int64":    {"int64", "int64", descriptorpb.FieldDescriptorProto_TYPE_INT64, false},
+	"uint":     {"uint64", "uint64", descriptorpb.FieldDescriptorProto_TYPE_UINT64, false},
+	"uint32":   {"uint32", "uint32", descriptorpb.FieldDescriptorProto_TYPE_UINT32, false},
+	"uint64":   {"uint64", "uint64", descriptorpb.FieldDescriptorProto_TYPE_UINT64, false},
+	"float64":  {"double", "float64", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, false},
+	"float32":  {"float", "float32", descriptorpb.FieldDescriptorProto_TYPE_FLOAT, false},
+	"[]byte":   {"bytes", "[]byte", descriptorpb.FieldDescriptorProto_TYPE_BYTES, false},
+	"[]string": {"string", "[]string", descriptorpb.FieldDescriptorProto_TYPE_STRING, true},
+}
+
+// protoSchema is the protobuf API of the entities served over gRPC, in a single file
+type protoSchema struct {
+	Package   string // e.g. "shop.v1"
+	Path      string // Path of the .proto file below api/proto, e.g. "shop/v1/shop.proto"
+	GoImport  string // Import path of the generated Go package
+	GoPackage string // Name of the generated Go package, e.g. "shopv1"
+	Enums     []protoEnum
+	Messages  []protoMessage
+	Services  []protoService
+	Lock      domain.ProtoLock // Numbering to record for the next generation
+}
+
+type protoMessage struct {
+	Name          string
+	Comment       string
+	Fields        []protoField // Ordered by number
+	Reserved      []int32
+	ReservedNames []string
+}
+
+type protoField struct {
+	Name     string // snake_case field name
+	Type     string // Scalar type, or the name of a message or enum as written in the .proto file
+	Number   int32
+	Repeated bool
+	Kind     descriptorpb.FieldDescriptorProto_Type
+
+	// Conversion from and to the domain struct
+	DomainField string
+	DomainType  string
+	MessageType string // Go type of the message field
+	Conversion  protoConversion
+}
+
+type protoEnum struct {
+	Name          string
+	Comment       string
+	Values        []protoEnumValue // Ordered by number, starting with the UNSPECIFIED zero value
+	Reserved      []int32
+	ReservedNames []string
+
+	DomainType string // Go type generated for the enum field in the domain package
+}
+
+type protoEnumValue struct {
+	Name        string
+	Number      int32
+	DomainConst string // Empty for the UNSPECIFIED zero value
+}
+
+// protoService is the CRUD service of an entity
+type protoService struct {
+	Entity  domain.EntitySpecification
+	Name    string // e.g. "UserService"
+	Message string // e.g. "User"
+	Plural  string // e.g. "Users"
+}
+
+// protoMethod is an RPC of a protoService
+type protoMethod struct {
+	Name, Input, Output string
+}
+
+// Methods returns the RPCs of the service in declaration order
+func (p protoService) Methods() []protoMethod {
+	return []protoMethod{
+		{"Create" + p.Message, "Create" + p.Message + "Request", p.Message},
+		{"Get" + p.Message, "Get" + p.Message + "Request", p.Message},
+		{"Update" + p.Message, "Update" + p.Message + "Request", p.Message},
+		{"Delete" + p.Message, "Delete" + p.Message + "Request", "google.protobuf.Empty"},
+		{"List" + p.Plural, "List" + p.Plural + "Request", "List" + p.Plural + "Response"},
+	}
+}
+
+// protoPackageName returns the lowercase alphanumeric name of a project, used as the protobuf
+// package and Go package prefix (e.g. "order-service" -> "orderservice")
+func (s *OrchestratorService) protoPackageName(spec *domain.ProjectSpecification) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(spec.Name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || (unicode.IsDigit(r) && b.Len() > 0)) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "api"
+	}
+	return b.String()
+}
+
+// usesGRPC reports whether any entity of the project is served over gRPC
+func (s *OrchestratorService) usesGRPC(spec *domain.ProjectSpecification) bool {
+	return s.anyEntity(spec, func(entity domain.EntitySpecification) bool {
+		return s.hasFeature(entity.Features, "grpc_api")
+	})
+}
+
+// protoSchema builds the protobuf API of the entities with the grpc_api feature. Field and enum
+// value numbers come from spec.ProtoLock when present, so that regenerating never renumbers.
+func (s *OrchestratorService) protoSchema(spec *domain.ProjectSpecification) protoSchema {
+	pkg := s.protoPackageName(spec)
+	schema := protoSchema{
+		Package:   pkg + ".v1",
+		Path:      fmt.Sprintf("%s/v1/%s.proto", pkg, pkg),
+		GoImport:  fmt.Sprintf("%s/gen/proto/%s/v1", spec.ModulePath, pkg),
+		GoPackage: pkg + "v1",
+		Lock: domain.ProtoLock{
+			Messages: make(map[string]domain.ProtoNumbering),
+			Enums:    make(map[string]domain.ProtoNumbering),
+		},
+	}
+	var lock domain.ProtoLock
+	if spec.ProtoLock != nil {
+		lock = *spec.ProtoLock
+	}
+
+	for _, entity := range spec.Entities {
+		if !s.hasFeature(entity.Features, "grpc_api") {
+			continue
+		}
+
+		fields := []protoField{s.protoScalarField("id", s.idFieldName(entity), "string")}
+		for _, field := range entity.Fields {
+			if strings.ToLower(field.Name) == "id" {
+				continue
+			}
+			if s.isTypedEnum(field) {
+				enum := s.protoEnumOf(entity, field, lock.Enums[s.enumTypeName(entity, field)])
+				schema.Enums = append(schema.Enums, enum)
+				schema.Lock.Enums[enum.Name] = enumNumbering(enum)
+			}
+			fields = append(fields, s.protoFieldOf(entity, field))
+		}
+		fields = append(fields,
+			protoTimestampField("created_at", "CreatedAt"),
+			protoTimestampField("updated_at", "UpdatedAt"),
+		)
+		if s.versioned(entity) {
+			fields = append(fields, s.protoScalarField("version", "Version", "int64"))
+		}
+
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = field.Name
+		}
+		numbering := assignProtoNumbers(lock.Messages[entity.Name], names, true)
+		for i := range fields {
+			fields[i].Number = numbering.Numbers[fields[i].Name]
+		}
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Number < fields[j].Number })
+
+		comment := entity.Description
+		if comment == "" {
+			comment = fmt.Sprintf("%s mirrors the %s entity of the domain package", entity.Name, entity.Name)
+		}
+		schema.Messages = append(schema.Messages, protoMessage{
+			Name:          entity.Name,
+			Comment:       comment,
+			Fields:        fields,
+			Reserved:      numbering.Reserved,
+			ReservedNames: numbering.ReservedNames,
+		})
+		schema.Lock.Messages[entity.Name] = numbering
+
+		service := protoService{Entity: entity, Name: entity.Name + "Service", Message: entity.Name, Plural: s.pluralize(entity.Name)}
+		schema.Messages = append(schema.Messages, s.protoRequestMessages(service)...)
+		schema.Services = append(schema.Services, service)
+	}
+
+	// Keep the numbering of messages and enums that are gone, in case they come back
+	for name, numbering := range lock.Messages {
+		if _, ok := schema.Lock.Messages[name]; !ok {
+			schema.Lock.Messages[name] = numbering
+		}
+	}
+	for name, numbering := range lock.Enums {
+		if _, ok := schema.Lock.Enums[name]; !ok {
+			schema.Lock.Enums[name] = numbering
+		}
+	}
+	return schema
+}
+
+// protoRequestMessages returns the request and response messages of the RPCs of a service
+func (s *OrchestratorService) protoRequestMessages(service protoService) []protoMessage {
+	item := protoField{Name: s.toSnakeCase(service.Message), Type: service.Message, Number: 1, Kind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE}
+	id := protoField{Name: "id", Type: "string", Number: 1, Kind: descriptorpb.FieldDescriptorProto_TYPE_STRING}
+	items := protoField{Name: s.toSnakeCase(service.Plural), Type: service.Message, Number: 1, Repeated: true, Kind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE}
+
+	return []protoMessage{
+		{Name: "Create" + service.Message + "Request", Fields: []protoField{item}},
+		{Name: "Get" + service.Message + "Request", Fields: []protoField{id}},
+		{Name: "Update" + service.Message + "Request", Fields: []protoField{item}},
+		{Name: "Delete" + service.Message + "Request", Fields: []protoField{id}},
+		{Name: "List" + service.Plural + "Request"},
+		{Name: "List" + service.Plural + "Response", Fields: []protoField{items}},
+	}
+}
+
+// protoFieldOf maps an entity field to a message field
+func (s *OrchestratorService) protoFieldOf(entity domain.EntitySpecification, field domain.FieldSpecification) protoField {
+	name := s.toSnakeCase(field.Name)
+	goName := s.capitalizeFirst(field.Name)
+
+	if s.isTypedEnum(field) {
+		enum := s.enumTypeName(entity, field)
+		return protoField{
+			Name: name, Type: enum, Kind: descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+			DomainField: goName, DomainType: enum, MessageType: protoGoName(enum), Conversion: convertEnum,
+		}
+	}
+
+	goType := s.fieldGoType(entity, field)
+	if goType == "time.Time" {
+		return protoTimestampField(name, goName)
+	}
+	if _, ok := protoScalars[goType]; ok {
+		return s.protoScalarField(name, goName, goType)
+	}
+	return protoField{
+		Name: name, Type: "string", Kind: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		DomainField: goName, DomainType: goType, MessageType: "string", Conversion: convertJSON,
+	}
+}
+
+// protoScalarField returns a field whose Go type has a scalar protobuf counterpart
+func (s *OrchestratorService) protoScalarField(name, goName, goType string) protoField {
+	scalar := protoScalars[goType]
+	conversion := convertAssign
+	if scalar.goType != goType {
+		conversion = convertCast
+	}
+	return protoField{
+		Name: name, Type: scalar.protoType, Repeated: scalar.repeated, Kind: scalar.kind,
+		DomainField: goName, DomainType: goType, MessageType: scalar.goType, Conversion: conversion,
+	}
+}
+
+func protoTimestampField(name, goName string) protoField {
+	return protoField{
+		Name: name, Type: "google.protobuf.Timestamp", Kind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		DomainField: goName, DomainType: "time.Time", MessageType: "*timestamppb.Timestamp", Conversion: convertTimestamp,
+	}
+}
+
+// protoEnumOf maps an enum field to a protobuf enum. Value names are prefixed with the enum name,
+// as protobuf enum values share the scope of their enclosing package.
+func (s *OrchestratorService) protoEnumOf(entity domain.EntitySpecification, field domain.FieldSpecification, previous domain.ProtoNumbering) protoEnum {
+	name := s.enumTypeName(entity, field)
+	prefix := strings.ToUpper(s.toSnakeCase(name)) + "_"
+
+	names := make([]string, len(field.Enum))
+	consts := make(map[string]string, len(field.Enum))
+	for i, value := range field.Enum {
+		names[i] = prefix + strings.ToUpper(s.toSnakeCase(s.toPascalCase(value)))
+		consts[names[i]] = s.enumConstName(entity, field, value)
+	}
+	numbering := assignProtoNumbers(previous, names, false)
+
+	enum := protoEnum{
+		Name:          name,
+		Comment:       fmt.Sprintf("%s enumerates the values of %s.%s", name, entity.Name, s.toSnakeCase(field.Name)),
+		Values:        []protoEnumValue{{Name: prefix + "UNSPECIFIED"}},
+		Reserved:      numbering.Reserved,
+		ReservedNames: numbering.ReservedNames,
+		DomainType:    name,
+	}
+	for _, value := range names {
+		enum.Values = append(enum.Values, protoEnumValue{Name: value, Number: numbering.Numbers[value], DomainConst: consts[value]})
+	}
+	sort.SliceStable(enum.Values, func(i, j int) bool { return enum.Values[i].Number < enum.Values[j].Number })
+	return enum
+}
+
+// enumNumbering returns the lock entry of an enum, leaving out the UNSPECIFIED zero value
+func enumNumbering(enum protoEnum) domain.ProtoNumbering {
+	numbering := domain.ProtoNumbering{Numbers: make(map[string]int32), Reserved: enum.Reserved, ReservedNames: enum.ReservedNames}
+	for _, value := range enum.Values {
+		if value.Number != 0 {
+			numbering.Numbers[value.Name] = value.Number
+		}
+	}
+	return numbering
+}
+
+// assignProtoNumbers numbers names, keeping the numbers found in previous. Names that are no
+// longer present are reserved, and new names are numbered after every number used or reserved so
+// far, so that a number is never given to another field. A name that comes back gets a new number.
+func assignProtoNumbers(previous domain.ProtoNumbering, names []string, skipImplementationRange bool) domain.ProtoNumbering {
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+
+	numbering := domain.ProtoNumbering{
+		Numbers:  make(map[string]int32, len(names)),
+		Reserved: append([]int32(nil), previous.Reserved...),
+	}
+	for _, name := range previous.ReservedNames {
+		if !present[name] {
+			numbering.ReservedNames = append(numbering.ReservedNames, name)
+		}
+	}
+
+	var last int32
+	for _, number := range previous.Reserved {
+		if number > last {
+			last = number
+		}
+	}
+	for _, name := range sortedKeys(previous.Numbers) {
+		number := previous.Numbers[name]
+		if number > last {
+			last = number
+		}
+		if present[name] {
+			numbering.Numbers[name] = number
+			continue
+		}
+		numbering.Reserved = append(numbering.Reserved, number)
+		numbering.ReservedNames = append(numbering.ReservedNames, name)
+	}
+
+	for _, name := range names {
+		if _, ok := numbering.Numbers[name]; ok {
+			continue
+		}
+		last++
+		if skipImplementationRange && last >= protoImplementationFirst && last <= protoImplementationLast {
+			last = protoImplementationLast + 1
+		}
+		numbering.Numbers[name] = last
+	}
+
+	sort.Slice(numbering.Reserved, func(i, j int) bool { return numbering.Reserved[i] < numbering.Reserved[j] })
+	sort.Strings(numbering.ReservedNames)
+	return numbering
+}
+
+// render returns the .proto source of the schema
+func (p protoSchema) render() string {
+	var b strings.Builder
+	b.WriteString("// Field numbers are recorded in api/proto/proto.lock.json. Pass its content back as the\n")
+	b.WriteString("// proto_lock of the project specification to keep them stable across regenerations.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", p.Package)
+	b.WriteString("import \"google/protobuf/empty.proto\";\n")
+	b.WriteString("import \"google/protobuf/timestamp.proto\";\n\n")
+	fmt.Fprintf(&b, "option go_package = %q;\n", p.GoImport+";"+p.GoPackage)
+
+	for _, enum := range p.Enums {
+		fmt.Fprintf(&b, "\n// %s\nenum %s {\n", enum.Comment, enum.Name)
+		for _, value := range enum.Values {
+			fmt.Fprintf(&b, "  %s = %d;\n", value.Name, value.Number)
+		}
+		writeProtoReserved(&b, enum.Reserved, enum.ReservedNames)
+		b.WriteString("}\n")
+	}
+
+	for _, message := range p.Messages {
+		b.WriteString("\n")
+		if message.Comment != "" {
+			fmt.Fprintf(&b, "// %s\n", message.Comment)
+		}
+		if len(message.Fields) == 0 && len(message.Reserved) == 0 {
+			fmt.Fprintf(&b, "message %s {}\n", message.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "message %s {\n", message.Name)
+		for _, field := range message.Fields {
+			repeated := ""
+			if field.Repeated {
+				repeated = "repeated "
+			}
+			comment := ""
+			if field.Conversion == convertJSON {
+				comment = fmt.Sprintf(" // JSON encoding of %s", field.DomainType)
+			}
+			fmt.Fprintf(&b, "  %s%s %s = %d;%s\n", repeated, field.Type, field.Name, field.Number, comment)
+		}
+		writeProtoReserved(&b, message.Reserved, message.ReservedNames)
+		b.WriteString("}\n")
+	}
+
+	for _, service := range p.Services {
+		fmt.Fprintf(&b, "\n// %s manages %s entities\nservice %s {\n", service.Name, service.Message, service.Name)
+		for _, method := range service.Methods() {
+			fmt.Fprintf(&b, "  rpc %s(%s) returns (%s);\n", method.Name, method.Input, method.Output)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeProtoReserved(b *strings.Builder, numbers []int32, names []string) {
+	if len(numbers) > 0 {
+		values := make([]string, len(numbers))
+		for i, number := range numbers {
+			values[i] = fmt.Sprint(number)
+		}
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(values, ", "))
+	}
+	if len(names) > 0 {
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = fmt.Sprintf("%q", name)
+		}
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(values, ", "))
+	}
+}
+
+// descriptor returns the descriptor protoc would produce from the rendered .proto source
+func (p protoSchema) descriptor() *descriptorpb.FileDescriptorProto {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(p.Path),
+		Package:    proto.String(p.Package),
+		Dependency: []string{"google/protobuf/empty.proto", "google/protobuf/timestamp.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String(p.GoImport + ";" + p.GoPackage)},
+		Syntax:     proto.String("proto3"),
+	}
+
+	for _, enum := range p.Enums {
+		descriptor := &descriptorpb.EnumDescriptorProto{Name: proto.String(enum.Name), ReservedName: enum.ReservedNames}
+		for _, value := range enum.Values {
+			descriptor.Value = append(descriptor.Value, &descriptorpb.EnumValueDescriptorProto{
+				Name:   proto.String(value.Name),
+				Number: proto.Int32(value.Number),
+			})
+		}
+		for _, number := range enum.Reserved {
+			descriptor.ReservedRange = append(descriptor.ReservedRange, &descriptorpb.EnumDescriptorProto_EnumReservedRange{
+				Start: proto.Int32(number),
+				End:   proto.Int32(number), // Inclusive
+			})
+		}
+		file.EnumType = append(file.EnumType, descriptor)
+	}
+
+	for _, message := range p.Messages {
+		descriptor := &descriptorpb.DescriptorProto{Name: proto.String(message.Name), ReservedName: message.ReservedNames}
+		for _, field := range message.Fields {
+			label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+			if field.Repeated {
+				label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+			}
+			fieldDescriptor := &descriptorpb.FieldDescriptorProto{
+				Name:     proto.String(field.Name),
+				Number:   proto.Int32(field.Number),
+				Label:    label.Enum(),
+				Type:     field.Kind.Enum(),
+				JsonName: proto.String(protoJSONName(field.Name)),
+			}
+			if field.Kind == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || field.Kind == descriptorpb.FieldDescriptorProto_TYPE_ENUM {
+				fieldDescriptor.TypeName = proto.String(p.fullName(field.Type))
+			}
+			descriptor.Field = append(descriptor.Field, fieldDescriptor)
+		}
+		for _, number := range message.Reserved {
+			descriptor.ReservedRange = append(descriptor.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+				Start: proto.Int32(number),
+				End:   proto.Int32(number + 1), // Exclusive
+			})
+		}
+		file.MessageType = append(file.MessageType, descriptor)
+	}
+
+	for _, service := range p.Services {
+		descriptor := &descriptorpb.ServiceDescriptorProto{Name: proto.String(service.Name)}
+		for _, method := range service.Methods() {
+			descriptor.Method = append(descriptor.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(method.Name),
+				InputType:  proto.String(p.fullName(method.Input)),
+				OutputType: proto.String(p.fullName(method.Output)),
+			})
+		}
+		file.Service = append(file.Service, descriptor)
+	}
+	return file
+}
+
+// fullName returns the fully-qualified name of a message or enum referenced in the schema
+func (p protoSchema) fullName(name string) string {
+	if strings.Contains(name, ".") {
+		return "." + name
+	}
+	return "." + p.Package + "." + name
+}
+
+// protocGenGo is the protoc plugin generating the Go code of protobuf messages. It must be the
+// version of protobufModule, the runtime the generated projects require.
+const protocGenGo = "protoc-gen-go"
+
+// protocGenGoPath returns the path of the protoc-gen-go plugin on the PATH, checking its version
+func protocGenGoPath() (string, error) {
+	install := fmt.Sprintf("go install google.golang.org/protobuf/cmd/protoc-gen-go@%s", protobufModule.Version)
+	path, err := exec.LookPath(protocGenGo)
+	if err != nil {
+		return "", fmt.Errorf("gRPC services need %s on the PATH: %s", protocGenGo, install)
+	}
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", path, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 || fields[len(fields)-1] != protobufModule.Version {
+		return "", fmt.Errorf("%s reports version %q, want %s: %s", path, strings.TrimSpace(string(out)), protobufModule.Version, install)
+	}
+	return path, nil
+}
+
+// generateGoCode runs the protoc-gen-go plugin on the schema and returns the generated .pb.go
+// source. The plugin reads a CodeGeneratorRequest on its standard input, as protoc sends it.
+func (p protoSchema) generateGoCode() (string, error) {
+	path, err := protocGenGoPath()
+	if err != nil {
+		return "", err
+	}
+	request, err := proto.Marshal(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{p.Path},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			p.descriptor(),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w: %s", protocGenGo, err, strings.TrimSpace(stderr.String()))
+	}
+	var response pluginpb.CodeGeneratorResponse
+	if err := proto.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return "", fmt.Errorf("%s: invalid response: %w", protocGenGo, err)
+	}
+	if response.Error != nil {
+		return "", fmt.Errorf("%s: %s", protocGenGo, response.GetError())
+	}
+	if len(response.File) != 1 {
+		return "", fmt.Errorf("%s generated %d files, want 1", protocGenGo, len(response.File))
+	}
+	return response.File[0].GetContent(), nil
+}
+
+// lockFile returns the JSON content of the numbering lockfile
+func (p protoSchema) lockFile() string {
+	data, err := json.MarshalIndent(p.Lock, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+	return string(data) + "\n"
+}
+
+// protoGoName returns the Go name protoc-gen-go gives a snake_case field or a message
+// (e.g. "created_at" -> "CreatedAt", "address_line1" -> "AddressLine1")
+func protoGoName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b.WriteByte('X')
+		case c == '_' && i+1 < len(name) && 'a' <= name[i+1] && name[i+1] <= 'z':
+			// Dropped; the next word starts with an upper case letter
+		case '0' <= c && c <= '9':
+			b.WriteByte(c)
+		default:
+			if 'a' <= c && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			b.WriteByte(c)
+			for ; i+1 < len(name) && 'a' <= name[i+1] && name[i+1] <= 'z'; i++ {
+				b.WriteByte(name[i+1])
+			}
+		}
+	}
+	return b.String()
+}
+
+// protoJSONName returns the JSON name protoc gives a field (e.g. "created_at" -> "createdAt")
+func protoJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
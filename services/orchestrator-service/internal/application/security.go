@@ -0,0 +1,904 @@
+package application
+
+import (
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// securitySchemes are the security schemes of endpoints and configuration.security.authentication;
+// true when the generated main creates an authenticator for the scheme
+var securitySchemes = map[string]bool{"jwt": true, "bearer": true, "oauth": true, "oauth2": true, "api_key": true, "basic": false}
+
+// usesSecurity reports whether the project gets the security middleware package: it declares
+// authentication, rate limiting or CORS, endpoints restricted to schemes or roles, or
+// tenant-scoped entities, whose tenant the middleware resolves
+func (s *OrchestratorService) usesSecurity(spec *domain.ProjectSpecification) bool {
+	if s.usesCLI(spec) {
+		return false
+	}
+	if s.hasFeature(spec.Features, "security") || s.usesTenancy(spec) {
+		return true
+	}
+	if security := spec.Configuration.Security; security != nil {
+		if len(security.Authentication) > 0 || security.RateLimit != nil && security.RateLimit.Requests > 0 {
+			return true
+		}
+	}
+	if server := spec.Configuration.Server; server != nil && server.CORS != nil && len(server.CORS.Origins) > 0 {
+		return true
+	}
+	for _, endpoint := range spec.Endpoints {
+		if len(endpoint.Security) > 0 || len(endpoint.Roles) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointSchemes returns the security schemes an endpoint authenticates with: its own, or
+// those of configuration.security.authentication, like its OpenAPI operation
+func endpointSchemes(endpoint domain.EndpointSpecification, spec *domain.ProjectSpecification) []string {
+	if len(endpoint.Security) > 0 {
+		return endpoint.Security
+	}
+	if security := spec.Configuration.Security; security != nil {
+		return security.Authentication
+	}
+	return nil
+}
+
+// generateSecurityElements generates the security middleware package: authenticators for JWT
+// and API keys, role checks, rate limiting, CORS and the resolution of tenants
+func (s *OrchestratorService) generateSecurityElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	elements := []domain.CodeElement{
+		s.generateAuthMiddlewareElement(),
+		s.generateJWTMiddlewareElement(),
+		s.generateAPIKeyMiddlewareElement(),
+		s.generateRateLimitMiddlewareElement(),
+		s.generateCORSMiddlewareElement(),
+	}
+	if s.anyEntity(spec, s.audited) {
+		elements = append(elements, s.generateActorMiddlewareElement(spec))
+	}
+	if s.usesTenancy(spec) {
+		elements = append(elements, s.generateTenantMiddlewareElement(spec))
+	}
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateMiddlewareTestElement(spec))
+		if s.usesTenancy(spec) {
+			elements = append(elements, s.generateTenantMiddlewareTestElement(spec))
+		}
+	}
+	return elements
+}
+
+// generateAuthMiddlewareElement generates principals, the Authenticator interface, and the Authenticate and RequireRoles middleware
+func (s *OrchestratorService) generateAuthMiddlewareElement() domain.CodeElement {
+	content := `package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no credentials of its scheme
+var ErrNoCredentials = errors.New("no credentials")
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string
+	Scheme  string   // Security scheme the principal authenticated with
+	Roles   []string // Roles checked by RequireRoles
+	Claims  map[string]interface{}
+}
+
+// HasRole reports whether the principal has one of the roles
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if containsString(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of an authenticated request
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// Authenticator identifies the principal of a request with the credentials of a security scheme
+type Authenticator interface {
+	// Authenticate returns the principal of the request, ErrNoCredentials when the request has
+	// no credentials of the scheme, or another error when they are invalid
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Authenticators holds the authenticator of each security scheme, by name
+type Authenticators map[string]Authenticator
+
+// Of returns the authenticators of the schemes, in order
+func (a Authenticators) Of(schemes ...string) ([]Authenticator, error) {
+	authenticators := make([]Authenticator, len(schemes))
+	for i, scheme := range schemes {
+		authenticator, ok := a[scheme]
+		if !ok {
+			return nil, fmt.Errorf("no authenticator for security scheme %q", scheme)
+		}
+		authenticators[i] = authenticator
+	}
+	return authenticators, nil
+}
+
+// Authenticate requires requests to authenticate with one of the authenticators, tried in
+// order, and stores the principal in the request context. Requests without credentials and
+// requests with invalid ones are answered with 401.
+func Authenticate(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, authenticator := range authenticators {
+				principal, err := authenticator.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					writeError(w, http.StatusUnauthorized, "invalid credentials")
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+				return
+			}
+			writeError(w, http.StatusUnauthorized, "authentication required")
+		})
+	}
+}
+
+// RequireRoles lets through the requests of principals having one of the roles. It runs
+// after Authenticate: requests without a principal are answered with 401, principals
+// without any of the roles with 403.
+func RequireRoles(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			if !principal.HasRole(roles...) {
+				writeError(w, http.StatusForbidden, "insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+`
+	return s.newFileElement("AuthMiddleware", "middleware", "internal/interfaces/http/middleware/auth.go", content)
+}
+
+// generateJWTMiddlewareElement generates the JWT verifier authenticating bearer tokens signed with HMAC or RSA keys
+func (s *OrchestratorService) generateJWTMiddlewareElement() domain.CodeElement {
+	content := `package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers the SHA-256 hash of HS256 and RS256
+	_ "crypto/sha512" // Registers the SHA-384 and SHA-512 hashes
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// jwtHashes are the hash functions of the supported signing algorithms
+var jwtHashes = map[string]crypto.Hash{
+	"HS256": crypto.SHA256, "HS384": crypto.SHA384, "HS512": crypto.SHA512,
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+}
+
+// clockSkew is the tolerance applied to the exp and nbf claims
+const clockSkew = time.Minute
+
+// Errors of JWTVerifier.Verify
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrTokenNotYetValid = errors.New("token not valid yet")
+	ErrInvalidClaims    = errors.New("invalid token claims")
+)
+
+// JWTVerifier verifies JSON Web Tokens signed with an HMAC secret (HS256, HS384, HS512) or an
+// RSA key (RS256, RS384, RS512). Tokens signed with any other algorithm are rejected.
+type JWTVerifier struct {
+	algorithm string
+	hash      crypto.Hash
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string // Required iss claim, if not empty
+	audience  string // Required aud claim, if not empty
+}
+
+// NewHMACVerifier creates a verifier of tokens signed with the secret
+func NewHMACVerifier(algorithm string, secret []byte) (*JWTVerifier, error) {
+	if !strings.HasPrefix(algorithm, "HS") || jwtHashes[algorithm] == 0 {
+		return nil, fmt.Errorf("unsupported HMAC algorithm %q", algorithm)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("empty HMAC secret")
+	}
+	return &JWTVerifier{algorithm: algorithm, hash: jwtHashes[algorithm], secret: secret}, nil
+}
+
+// NewRSAVerifier creates a verifier of tokens signed with the private key of the PEM-encoded
+// public key (PKIX "PUBLIC KEY" or PKCS #1 "RSA PUBLIC KEY")
+func NewRSAVerifier(algorithm string, publicKeyPEM []byte) (*JWTVerifier, error) {
+	if !strings.HasPrefix(algorithm, "RS") || jwtHashes[algorithm] == 0 {
+		return nil, fmt.Errorf("unsupported RSA algorithm %q", algorithm)
+	}
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded public key")
+	}
+	var publicKey *rsa.PublicKey
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA public key: %w", err)
+		}
+		publicKey = key
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%T is not an RSA public key", key)
+		}
+		publicKey = rsaKey
+	}
+	return &JWTVerifier{algorithm: algorithm, hash: jwtHashes[algorithm], publicKey: publicKey}, nil
+}
+
+// WithIssuer requires tokens to be issued by issuer
+func (v *JWTVerifier) WithIssuer(issuer string) *JWTVerifier {
+	v.issuer = issuer
+	return v
+}
+
+// WithAudience requires tokens to be intended for audience
+func (v *JWTVerifier) WithAudience(audience string) *JWTVerifier {
+	v.audience = audience
+	return v
+}
+
+// Verify checks the signature, the algorithm and the time, issuer and audience claims of a
+// token and returns its claims
+func (v *JWTVerifier) Verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	var header struct {
+		Algorithm string ` + "`" + `json:"alg"` + "`" + `
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+	// The algorithm is fixed by the verifier, never chosen by the token
+	if header.Algorithm != v.algorithm {
+		return nil, ErrInvalidSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !v.validSignature(parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	now := time.Now()
+	if exp, ok := claims["exp"]; ok {
+		expiry, ok := exp.(float64)
+		if !ok {
+			return nil, ErrInvalidClaims
+		}
+		if now.After(time.Unix(int64(expiry), 0).Add(clockSkew)) {
+			return nil, ErrTokenExpired
+		}
+	}
+	if nbf, ok := claims["nbf"]; ok {
+		notBefore, ok := nbf.(float64)
+		if !ok {
+			return nil, ErrInvalidClaims
+		}
+		if now.Add(clockSkew).Before(time.Unix(int64(notBefore), 0)) {
+			return nil, ErrTokenNotYetValid
+		}
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, ErrInvalidClaims
+	}
+	if v.audience != "" && !containsString(stringClaims(claims["aud"]), v.audience) {
+		return nil, ErrInvalidClaims
+	}
+	return claims, nil
+}
+
+// validSignature checks the signature of the signing input of a token
+func (v *JWTVerifier) validSignature(input string, signature []byte) bool {
+	if v.publicKey != nil {
+		hasher := v.hash.New()
+		hasher.Write([]byte(input))
+		return rsa.VerifyPKCS1v15(v.publicKey, v.hash, hasher.Sum(nil), signature) == nil
+	}
+	mac := hmac.New(v.hash.New, v.secret)
+	mac.Write([]byte(input))
+	return hmac.Equal(mac.Sum(nil), signature)
+}
+
+// Authenticate authenticates requests with a bearer token in the Authorization header. The
+// principal is the sub claim, with the roles of the roles claim.
+func (v *JWTVerifier) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+	claims, err := v.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := claims["sub"].(string)
+	return &Principal{Subject: subject, Scheme: "jwt", Roles: stringClaims(claims["roles"]), Claims: claims}, nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// stringClaims returns the strings of a claim holding a string or an array of strings
+func stringClaims(claim interface{}) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []interface{}:
+		values := make([]string, 0, len(claim))
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+`
+	return s.newFileElement("JWTMiddleware", "middleware", "internal/interfaces/http/middleware/jwt.go", content)
+}
+
+// generateAPIKeyMiddlewareElement generates the authenticator of API keys
+func (s *OrchestratorService) generateAPIKeyMiddlewareElement() domain.CodeElement {
+	content := `package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+)
+
+// ErrInvalidAPIKey is returned for requests with an unknown API key
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyAuthenticator authenticates requests with one of a set of API keys sent in a header
+type APIKeyAuthenticator struct {
+	header string
+	keys   [][sha256.Size]byte
+}
+
+// NewAPIKeyAuthenticator creates an authenticator accepting the keys in the header
+func NewAPIKeyAuthenticator(header string, keys ...string) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{header: header}
+	for _, key := range keys {
+		a.keys = append(a.keys, sha256.Sum256([]byte(key)))
+	}
+	return a
+}
+
+// Authenticate accepts requests with a known key. Keys are compared in constant time, as
+// hashes so that their length is not revealed either. API keys carry no roles.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(a.header)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	sum := sha256.Sum256([]byte(key))
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], a.keys[i][:]) == 1 {
+			return &Principal{Subject: "api_key", Scheme: "api_key"}, nil
+		}
+	}
+	return nil, ErrInvalidAPIKey
+}
+`
+	return s.newFileElement("APIKeyMiddleware", "middleware", "internal/interfaces/http/middleware/apikey.go", content)
+}
+
+// generateRateLimitMiddlewareElement generates the token-bucket rate limiter
+func (s *OrchestratorService) generateRateLimitMiddlewareElement() domain.CodeElement {
+	content := `package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxIdleBuckets bounds the buckets kept for clients; full buckets are dropped beyond it
+const maxIdleBuckets = 10000
+
+// RateLimiter is a token-bucket rate limiter allowing each client a burst of Requests, refilled
+// at Requests per Window
+type RateLimiter struct {
+	capacity float64
+	rate     float64 // Tokens per second
+	key      func(r *http.Request) string
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing requests per window to each client, identified by
+// its IP address
+func NewRateLimiter(requests int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		capacity: float64(requests),
+		rate:     float64(requests) / window.Seconds(),
+		key:      clientIP,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// WithKey identifies the clients by key instead of their IP address
+func (l *RateLimiter) WithKey(key func(r *http.Request) string) *RateLimiter {
+	l.key = key
+	return l
+}
+
+// Allow takes a token from the bucket of the client, returning how long to wait for the next
+// one when the bucket is empty
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.dropFullBuckets(now)
+		}
+		b = &bucket{tokens: l.capacity, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.capacity, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// dropFullBuckets forgets the clients whose buckets have refilled, as new ones would be
+func (l *RateLimiter) dropFullBuckets(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.capacity {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// Middleware answers the requests of clients over their limit with 429 and a Retry-After header
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := l.Allow(l.key(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP address of the client of a request. Forwarding headers are not
+// trusted: behind a proxy, identify clients WithKey.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+`
+	return s.newFileElement("RateLimitMiddleware", "middleware", "internal/interfaces/http/middleware/ratelimit.go", content)
+}
+
+// generateCORSMiddlewareElement generates the CORS middleware
+func (s *OrchestratorService) generateCORSMiddlewareElement() domain.CodeElement {
+	content := `package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultCORSMethods are the methods allowed when CORS lists none
+var defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// CORS allows cross-origin requests from the origins ("*" for any) with the methods and
+// request headers. Preflight requests are answered directly: with 204 when allowed, with 403
+// otherwise. Without headers, the headers a preflight request asks for are allowed.
+func CORS(origins, methods, headers []string) func(http.Handler) http.Handler {
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	anyOrigin := containsString(origins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			allowed := anyOrigin || containsString(origins, origin)
+			if !allowed {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				// The browser hides the response of requests without the CORS headers
+				next.ServeHTTP(w, r)
+				return
+			}
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !containsFold(methods, r.Header.Get("Access-Control-Request-Method")) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				w.Header().Set("Access-Control-Allow-Headers", requested)
+			}
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+`
+	return s.newFileElement("CORSMiddleware", "middleware", "internal/interfaces/http/middleware/cors.go", content)
+}
+
+// generateMiddlewareTestElement tests the verification of tokens, authentication, roles, rate
+// limiting and CORS
+func (s *OrchestratorService) generateMiddlewareTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package middleware_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"` + spec.ModulePath + `/internal/interfaces/http/middleware"
+)
+
+// signHS256 signs claims into a token with the HS256 algorithm
+func signHS256(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	input := tokenInput(t, "HS256", claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS256 signs claims into a token with the RS256 algorithm
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	input := tokenInput(t, "RS256", claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func tokenInput(t *testing.T, algorithm string, claims map[string]interface{}) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// serve sends a request through the middleware to a handler answering 200
+func serve(middleware func(http.Handler) http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHMACVerifier(t *testing.T) {
+	verifier, err := middleware.NewHMACVerifier("HS256", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier.WithIssuer("issuer").WithAudience("api")
+	now := time.Now().Unix()
+	valid := map[string]interface{}{"sub": "alice", "iss": "issuer", "aud": []string{"api"}, "exp": now + 60, "roles": []string{"admin"}}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"valid", signHS256(t, "secret", valid), nil},
+		{"wrong secret", signHS256(t, "other", valid), middleware.ErrInvalidSignature},
+		{"expired", signHS256(t, "secret", map[string]interface{}{"iss": "issuer", "aud": "api", "exp": now - 3600}), middleware.ErrTokenExpired},
+		{"not yet valid", signHS256(t, "secret", map[string]interface{}{"iss": "issuer", "aud": "api", "nbf": now + 3600}), middleware.ErrTokenNotYetValid},
+		{"wrong issuer", signHS256(t, "secret", map[string]interface{}{"iss": "other", "aud": "api"}), middleware.ErrInvalidClaims},
+		{"wrong audience", signHS256(t, "secret", map[string]interface{}{"iss": "issuer", "aud": "web"}), middleware.ErrInvalidClaims},
+		{"unsigned", tokenInput(t, "none", valid) + ".", middleware.ErrInvalidSignature},
+		{"malformed", "not-a-token", middleware.ErrMalformedToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claims["sub"] != "alice" {
+				t.Errorf("sub = %v, want alice", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestRSAVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := middleware.NewRSAVerifier("RS256", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]interface{}{"sub": "alice"}
+	if _, err := verifier.Verify(signRS256(t, key, claims)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(signRS256(t, other, claims)); !errors.Is(err, middleware.ErrInvalidSignature) {
+		t.Errorf("Verify() of a token of another key error = %v, want %v", err, middleware.ErrInvalidSignature)
+	}
+	// An HMAC token keyed with the public key must not pass for an RSA one
+	if _, err := verifier.Verify(signHS256(t, string(der), claims)); !errors.Is(err, middleware.ErrInvalidSignature) {
+		t.Errorf("Verify() of an HS256 token error = %v, want %v", err, middleware.ErrInvalidSignature)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	verifier, err := middleware.NewHMACVerifier("HS256", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate := middleware.Authenticate(verifier, middleware.NewAPIKeyAuthenticator("X-API-Key", "key"))
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"bearer token", map[string]string{"Authorization": "Bearer " + signHS256(t, "secret", map[string]interface{}{"sub": "alice"})}, http.StatusOK},
+		{"api key", map[string]string{"X-API-Key": "key"}, http.StatusOK},
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"invalid token", map[string]string{"Authorization": "Bearer " + signHS256(t, "other", nil)}, http.StatusUnauthorized},
+		{"invalid api key", map[string]string{"X-API-Key": "other"}, http.StatusUnauthorized},
+		{"invalid token with api key", map[string]string{"Authorization": "Bearer invalid", "X-API-Key": "key"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if rec := serve(authenticate, req); rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRequireRoles(t *testing.T) {
+	requireAdmin := middleware.RequireRoles("admin", "owner")
+	tests := []struct {
+		name       string
+		principal  *middleware.Principal
+		wantStatus int
+	}{
+		{"role", &middleware.Principal{Roles: []string{"user", "owner"}}, http.StatusOK},
+		{"other roles", &middleware.Principal{Roles: []string{"user"}}, http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.principal != nil {
+				req = req.WithContext(middleware.WithPrincipal(req.Context(), tt.principal))
+			}
+			if rec := serve(requireAdmin, req); rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := middleware.NewRateLimiter(2, time.Hour)
+	request := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		return serve(limiter.Middleware, req).Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if status := request("192.0.2.1:1234"); status != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i+1, status, http.StatusOK)
+		}
+	}
+	if status := request("192.0.2.1:5678"); status != http.StatusTooManyRequests {
+		t.Errorf("request over the limit: status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	if status := request("192.0.2.2:1234"); status != http.StatusOK {
+		t.Errorf("request of another client: status = %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	cors := middleware.CORS([]string{"https://app.example"}, []string{"GET", "POST"}, []string{"Authorization"})
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"allowed origin", "GET", map[string]string{"Origin": "https://app.example"}, http.StatusOK, "https://app.example"},
+		{"other origin", "GET", map[string]string{"Origin": "https://evil.example"}, http.StatusOK, ""},
+		{"same origin", "GET", nil, http.StatusOK, ""},
+		{"preflight", "OPTIONS", map[string]string{"Origin": "https://app.example", "Access-Control-Request-Method": "POST"}, http.StatusNoContent, "https://app.example"},
+		{"preflight of another method", "OPTIONS", map[string]string{"Origin": "https://app.example", "Access-Control-Request-Method": "DELETE"}, http.StatusForbidden, "https://app.example"},
+		{"preflight of another origin", "OPTIONS", map[string]string{"Origin": "https://evil.example", "Access-Control-Request-Method": "GET"}, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := serve(cors, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", origin, tt.wantOrigin)
+			}
+		})
+	}
+}
+`
+	return s.newFileElement("MiddlewareTest", "middleware_test", "internal/interfaces/http/middleware/middleware_test.go", content)
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// securedEndpointTest calls the generated handler of an endpoint secured with API keys
+const securedEndpointTest = `package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/shop/internal/interfaces/http/middleware"
+)
+
+func TestSecuredEndpoint(t *testing.T) {
+	authenticators := middleware.Authenticators{"api_key": middleware.NewAPIKeyAuthenticator("X-API-Key", "k1")}
+	mux := http.NewServeMux()
+	NewEndpointHandler(UnimplementedEndpointService{}, nil, authenticators).RegisterRoutes(mux)
+
+	for key, want := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "k1": http.StatusNotImplemented} {
+		req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("key %q: status = %d, want %d", key, rec.Code, want)
+		}
+	}
+}
+`
+
+func TestGeneratedSecurityMiddleware(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"config", "testing"},
+		Configuration: domain.ProjectConfiguration{
+			Server: &domain.ServerConfiguration{CORS: &domain.CORSConfiguration{Origins: []string{"https://shop.example"}}},
+			Security: &domain.SecurityConfiguration{
+				Authentication: []string{"jwt"},
+				Authorization:  []string{"rbac"},
+				RateLimit:      &domain.RateLimitConfig{Requests: 100, Window: "1m"},
+			},
+		},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+		Endpoints: []domain.EndpointSpecification{{
+			Path:     "/reports",
+			Method:   "GET",
+			Handler:  "GetReports",
+			Security: []string{"api_key"},
+		}},
+	}
+
+	dir := generateProject(t, NewOrchestratorService(), spec)
+	test := filepath.Join(dir, "internal", "interfaces", "http", "handlers", "secured_endpoint_test.go")
+	if err := os.WriteFile(test, []byte(securedEndpointTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkProject(t, dir)
+
+	main, err := os.ReadFile(filepath.Join(dir, "cmd", "shop", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"protected = middleware.Authenticate(chain...)(protected)",
+		"middleware.NewRateLimiter(cfg.Security.RateLimit.Requests, cfg.Security.RateLimit.Window.Duration)",
+		"middleware.CORS(cfg.Server.CORS.Origins, cfg.Server.CORS.Methods, cfg.Server.CORS.Headers)",
+	} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("main.go does not contain %s:\n%s", want, main)
+		}
+	}
+}
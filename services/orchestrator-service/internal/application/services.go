@@ -0,0 +1,252 @@
+package application
+
+import (
+	"fmt"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// serviceFeatureElements generates the application service of an entity that has a repository to
+// work on, with its tests when testing is enabled
+func (s *OrchestratorService) serviceFeatureElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	if !s.hasRepository(entity) {
+		return nil
+	}
+	elements := []domain.CodeElement{s.generateApplicationServiceElement(entity, spec)}
+	if s.hasTestingFeature(entity, spec) {
+		elements = append(elements, s.generateApplicationServiceTestElement(entity, spec))
+	}
+	return elements
+}
+
+// generateApplicationServiceElement generates the service implementing the CRUD use cases of an
+// entity on top of its repository. API servers other than the HTTP handlers go through it.
+func (s *OrchestratorService) generateApplicationServiceElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+
+	validate := ""
+	if s.hasFeature(entity.Features, "validation") {
+		validate = fmt.Sprintf(`
+	if err := domain.Validate%s(item); err != nil {
+		return nil, err
+	}`, name)
+	}
+
+	restore := ""
+	if s.softDeletes(entity) {
+		restore = fmt.Sprintf(`
+// Restore brings back the deleted %[1]s with the given ID
+func (s *%[1]sService) Restore(ctx context.Context, id string) (*domain.%[1]s, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+`, name)
+	}
+
+	content := fmt.Sprintf(`package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"%[1]s/internal/domain"
+)
+
+// %[2]sService implements the %[2]s use cases on top of its repository
+type %[2]sService struct {
+	repo domain.%[2]sRepository
+}
+
+// New%[2]sService creates a %[2]s service storing entities in repo
+func New%[2]sService(repo domain.%[2]sRepository) *%[2]sService {
+	return &%[2]sService{repo: repo}
+}
+
+// Create validates and stores a new %[2]s, assigning its ID when empty and its timestamps
+func (s *%[2]sService) Create(ctx context.Context, item *domain.%[2]s) (*domain.%[2]s, error) {
+	if item.%[3]s == "" {
+		item.%[3]s = uuid.New().String()
+	}
+	now := time.Now()
+	item.CreatedAt, item.UpdatedAt = now, now
+%[4]s
+	if err := s.repo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Get returns the %[2]s with the given ID
+func (s *%[2]sService) Get(ctx context.Context, id string) (*domain.%[2]s, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns all %[2]s entities
+func (s *%[2]sService) List(ctx context.Context) ([]*domain.%[2]s, error) {
+	return s.repo.List(ctx)
+}
+
+// Update validates and replaces an existing %[2]s, keeping its creation time
+func (s *%[2]sService) Update(ctx context.Context, item *domain.%[2]s) (*domain.%[2]s, error) {
+	existing, err := s.repo.GetByID(ctx, item.%[3]s)
+	if err != nil {
+		return nil, err
+	}
+	item.CreatedAt = existing.CreatedAt
+	item.UpdatedAt = time.Now()
+%[4]s
+	if err := s.repo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Delete removes the %[2]s with the given ID
+func (s *%[2]sService) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+%[5]s`, spec.ModulePath, name, id, validate, restore)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sService", name),
+		"application",
+		fmt.Sprintf("internal/application/%s_service.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// generateApplicationServiceTestElement tests the service against the in-memory repository
+func (s *OrchestratorService) generateApplicationServiceTestElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	// Tenants see their own entities only, and contexts without a tenant none
+	optionTests := ""
+	if s.tenancy(entity, spec) != "" {
+		optionTests = fmt.Sprintf(`
+func Test%[1]sServiceIsolatesTenants(t *testing.T) {
+	item := fixtures.New%[1]sBuilder().Build()
+	service := application.New%[1]sService(memory.New%[1]sRepository(item))
+
+	other := domain.WithTenant(context.Background(), domain.Tenant{ID: fixtures.NextID(), ClientID: fixtures.NextID()})
+	if _, err := service.Get(other, item.%[2]s); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get() of another tenant error = %%v, want domain.ErrNotFound", err)
+	}
+	if items, err := service.List(other); err != nil || len(items) != 0 {
+		t.Errorf("List() of another tenant = %%d items, %%v, want none", len(items), err)
+	}
+	if err := service.Delete(other, item.%[2]s); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete() of another tenant error = %%v, want domain.ErrNotFound", err)
+	}
+	if _, err := service.Get(context.Background(), item.%[2]s); !errors.Is(err, domain.ErrTenantRequired) {
+		t.Errorf("Get() without a tenant error = %%v, want domain.ErrTenantRequired", err)
+	}
+	if _, err := service.Get(fixtures.WithTenant(context.Background()), item.%[2]s); err != nil {
+		t.Errorf("Get() of the tenant error = %%v", err)
+	}
+}
+`, entity.Name, s.idFieldName(entity))
+	}
+
+	// Audited entities record the actor of the context creating and updating them
+	if s.audited(entity) {
+		optionTests += fmt.Sprintf(`
+func Test%[1]sServiceRecordsActors(t *testing.T) {
+	ctx := %[3]s
+	service := application.New%[1]sService(memory.New%[1]sRepository())
+
+	item := fixtures.New%[1]sBuilder().Build()
+	created, err := service.Create(domain.WithActor(ctx, "alice"), item)
+	if err != nil {
+		t.Fatalf("Create() error = %%v", err)
+	}
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" {
+		t.Errorf("Create() CreatedBy = %%q, UpdatedBy = %%q, want alice", created.CreatedBy, created.UpdatedBy)
+	}
+
+	update := *created
+	update.CreatedBy = "mallory"
+	updated, err := service.Update(domain.WithActor(ctx, "bob"), &update)
+	if err != nil {
+		t.Fatalf("Update() error = %%v", err)
+	}
+	if updated.CreatedBy != "alice" || updated.UpdatedBy != "bob" {
+		t.Errorf("Update() CreatedBy = %%q, UpdatedBy = %%q, want alice and bob", updated.CreatedBy, updated.UpdatedBy)
+	}
+}
+`, entity.Name, s.idFieldName(entity), s.testContext(entity, spec))
+	}
+
+	content := fmt.Sprintf(`package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"%[1]s/internal/application"
+	"%[1]s/internal/domain"
+	"%[1]s/internal/fixtures"
+	"%[1]s/internal/infrastructure/memory"
+)
+
+func Test%[2]sServiceLifecycle(t *testing.T) {
+	ctx := %[4]s
+	service := application.New%[2]sService(memory.New%[2]sRepository())
+
+	item := fixtures.New%[2]sBuilder().Build()
+	item.%[3]s = ""
+	created, err := service.Create(ctx, item)
+	if err != nil {
+		t.Fatalf("Create() error = %%v", err)
+	}
+	if created.%[3]s == "" || created.CreatedAt.IsZero() {
+		t.Fatalf("Create() did not assign an ID and timestamps: %%+v", created)
+	}
+
+	got, err := service.Get(ctx, created.%[3]s)
+	if err != nil {
+		t.Fatalf("Get() error = %%v", err)
+	}
+	if got.%[3]s != created.%[3]s {
+		t.Errorf("Get() ID = %%v, want %%v", got.%[3]s, created.%[3]s)
+	}
+
+	update := *got
+	update.CreatedAt = update.CreatedAt.AddDate(-1, 0, 0)
+	updated, err := service.Update(ctx, &update)
+	if err != nil {
+		t.Fatalf("Update() error = %%v", err)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Update() CreatedAt = %%v, want it kept at %%v", updated.CreatedAt, created.CreatedAt)
+	}
+
+	if items, err := service.List(ctx); err != nil || len(items) != 1 {
+		t.Fatalf("List() = %%d items, %%v, want 1", len(items), err)
+	}
+	if err := service.Delete(ctx, created.%[3]s); err != nil {
+		t.Fatalf("Delete() error = %%v", err)
+	}
+	if _, err := service.Get(ctx, created.%[3]s); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %%v, want domain.ErrNotFound", err)
+	}
+}
+
+func Test%[2]sServiceUpdateMissing(t *testing.T) {
+	service := application.New%[2]sService(memory.New%[2]sRepository())
+	if _, err := service.Update(%[4]s, fixtures.New%[2]sBuilder().Build()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Update() error = %%v, want domain.ErrNotFound", err)
+	}
+}
+%[5]s`, spec.ModulePath, entity.Name, s.idFieldName(entity), s.testContext(entity, spec), optionTests)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sServiceTest", entity.Name),
+		"application_test",
+		fmt.Sprintf("internal/application/%s_service_test.go", s.toSnakeCase(entity.Name)),
+		content,
+	)
+}
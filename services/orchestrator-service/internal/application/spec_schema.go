@@ -0,0 +1,169 @@
+package application
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// specSchemaRequired lists the required properties of each specification type, matching the
+// values that ValidateSpecification reports as missing
+var specSchemaRequired = map[string][]string{
+	"ProjectSpecification":      {"name", "module_path", "output_path", "entities"},
+	"EntitySpecification":       {"name", "fields"},
+	"FieldSpecification":        {"name", "type"},
+	"RelationshipSpecification": {"type", "target"},
+	"ConstraintSpecification":   {"type"},
+	"IndexSpecification":        {"fields"},
+	"CommandSpecification":      {"name"},
+	"FlagSpecification":         {"name"},
+	"EndpointSpecification":     {"path", "method"},
+	"ParameterSpecification":    {"name"},
+	"TypeDefinition":            {"name", "go_type"},
+}
+
+// SpecificationSchema returns a JSON Schema (draft 2020-12) describing ProjectSpecification and every
+// nested specification type. Project types, features, field types and relationship kinds are
+// enumerated from the live ProjectTypeMapping, FeatureMapping, type registry and RelationshipMapping.
+func (s *OrchestratorService) SpecificationSchema() map[string]interface{} {
+	defs := make(map[string]interface{})
+	root := s.schemaForType(reflect.TypeOf(domain.ProjectSpecification{}), defs)
+
+	return map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "ProjectSpecification",
+		"description": "High-level project specification accepted by the orchestrator service",
+		"$ref":        root["$ref"],
+		"$defs":       defs,
+	}
+}
+
+// schemaForType converts a Go type to a schema, registering structs under $defs
+func (s *OrchestratorService) schemaForType(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return s.schemaForType(t.Elem(), defs)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.schemaForType(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schemaForType(t.Elem(), defs)}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref
+		}
+		// Register before recursing so that self-referencing types (sub_commands) terminate
+		definition := map[string]interface{}{"type": "object", "additionalProperties": false}
+		defs[t.Name()] = definition
+
+		properties := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			property := s.schemaForType(field.Type, defs)
+			if override := s.specPropertySchema(t.Name(), name); override != nil {
+				property = override
+			}
+			properties[name] = property
+		}
+		definition["properties"] = properties
+		if required, ok := specSchemaRequired[t.Name()]; ok {
+			definition["required"] = required
+		}
+		return ref
+	}
+	return map[string]interface{}{}
+}
+
+// specPropertySchema returns the schema of properties restricted to a set of values
+func (s *OrchestratorService) specPropertySchema(typeName, property string) map[string]interface{} {
+	enum := func(values ...string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	arrayOf := func(item map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "array", "items": item, "uniqueItems": true}
+	}
+
+	switch typeName + "." + property {
+	case "ProjectSpecification.project_type":
+		return enum(sortedKeys(domain.ProjectTypeMapping)...)
+	case "ProjectSpecification.features", "EntitySpecification.features":
+		return arrayOf(enum(s.knownFeatures()...))
+	case "FieldSpecification.type":
+		// Entity names and custom types declared in "types" may also be used as field types
+		return map[string]interface{}{
+			"anyOf": []interface{}{
+				enum(s.types.Names()...),
+				map[string]interface{}{"type": "string", "pattern": "^[A-Za-z][A-Za-z0-9_]*$"},
+			},
+		}
+	case "TypeDefinition.name":
+		return map[string]interface{}{"type": "string", "pattern": "^[A-Za-z][A-Za-z0-9_]*$"}
+	case "TypeDefinition.json_type":
+		return enum("string", "integer", "number", "boolean", "array", "object")
+	case "EntitySpecification.name":
+		return map[string]interface{}{"type": "string", "pattern": "^[A-Z][A-Za-z0-9_]*$"}
+	case "FieldSpecification.name":
+		return map[string]interface{}{"type": "string", "pattern": "^[A-Za-z_][A-Za-z0-9_]*$"}
+	case "RelationshipSpecification.type":
+		return enum(sortedKeys(domain.RelationshipMapping)...)
+	case "RelationshipSpecification.on_delete", "RelationshipSpecification.on_update":
+		return enum("cascade", "set_null", "restrict")
+	case "ConstraintSpecification.type":
+		return enum(sortedKeys(validConstraintTypes)...)
+	case "IndexSpecification.type":
+		return enum(sortedKeys(validIndexTypes)...)
+	case "EndpointSpecification.method":
+		return enum(sortedKeys(validHTTPMethods)...)
+	case "EndpointSpecification.security", "SecurityConfiguration.authentication":
+		return arrayOf(enum(sortedKeys(securitySchemes)...))
+	case "ParameterSpecification.type":
+		return enum("path", "query", "header")
+	case "LoggingConfiguration.level":
+		return enum("debug", "info", "warn", "error")
+	case "LoggingConfiguration.format":
+		return enum("json", "text")
+	}
+	return nil
+}
+
+// knownFeatures returns every feature of FeatureMapping, the feature plugins and the project type defaults
+func (s *OrchestratorService) knownFeatures() []string {
+	features := make(map[string]bool)
+	for feature := range domain.FeatureMapping {
+		features[feature] = true
+	}
+	for _, feature := range s.features.Names() {
+		features[feature] = true
+	}
+	for _, config := range domain.ProjectTypeMapping {
+		for _, feature := range config.DefaultFeatures {
+			features[feature] = true
+		}
+	}
+	return sortedKeys(features)
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,194 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// checkSchema reports the values of document that schema rejects, supporting the keywords
+// used by SpecificationSchema
+func checkSchema(schema, defs map[string]interface{}, document interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return checkSchema(defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}), defs, document, path)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, alternative := range anyOf {
+			if len(checkSchema(alternative.(map[string]interface{}), defs, document, path)) == 0 {
+				return nil
+			}
+		}
+		return []string{path + ": matches no alternative"}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, value := range enum {
+			if value == document {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, document, enum)}
+	}
+
+	var problems []string
+	switch value := document.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, item := range value {
+			if property, ok := properties[key]; ok {
+				problems = append(problems, checkSchema(property.(map[string]interface{}), defs, item, path+"/"+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				problems = append(problems, checkSchema(additional, defs, item, path+"/"+key)...)
+			} else if schema["additionalProperties"] == false {
+				problems = append(problems, path+": unknown property "+key)
+			}
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := value[key.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing %s", path, key))
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				problems = append(problems, checkSchema(items, defs, item, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// schemaProperty returns the schema of a property of a $defs type
+func schemaProperty(t *testing.T, defs map[string]interface{}, typeName, property string) map[string]interface{} {
+	t.Helper()
+	definition, _ := defs[typeName].(map[string]interface{})
+	properties, _ := definition["properties"].(map[string]interface{})
+	schema, ok := properties[property].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no schema for %s.%s", typeName, property)
+	}
+	return schema
+}
+
+// enumValues returns the values of an enum schema
+func enumValues(schema map[string]interface{}) []string {
+	var values []string
+	for _, value := range schema["enum"].([]interface{}) {
+		values = append(values, value.(string))
+	}
+	return values
+}
+
+func TestSpecificationSchema(t *testing.T) {
+	s := NewOrchestratorService()
+	if err := s.RegisterFeature(auditLogFeature{s}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Types().Register(domain.TypeDefinition{Name: "percentage", GoType: "float64", Faker: "number"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The schema as served, through JSON
+	var schema map[string]interface{}
+	content, err := json.Marshal(s.SpecificationSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema["$schema"] != "https://json-schema.org/draft/2020-12/schema" || schema["$ref"] != "#/$defs/ProjectSpecification" {
+		t.Errorf("schema = %v %v, want a draft 2020-12 schema of ProjectSpecification", schema["$schema"], schema["$ref"])
+	}
+	defs := schema["$defs"].(map[string]interface{})
+
+	// Enumerations follow the live mappings and registries
+	if got := enumValues(schemaProperty(t, defs, "ProjectSpecification", "project_type")); !reflect.DeepEqual(got, sortedKeys(domain.ProjectTypeMapping)) {
+		t.Errorf("project types = %v, want %v", got, sortedKeys(domain.ProjectTypeMapping))
+	}
+	if got := enumValues(schemaProperty(t, defs, "RelationshipSpecification", "type")); !reflect.DeepEqual(got, sortedKeys(domain.RelationshipMapping)) {
+		t.Errorf("relationship types = %v, want %v", got, sortedKeys(domain.RelationshipMapping))
+	}
+	features := make(map[string]bool)
+	for _, feature := range enumValues(schemaProperty(t, defs, "EntitySpecification", "features")["items"].(map[string]interface{})) {
+		features[feature] = true
+	}
+	for _, want := range append(sortedKeys(domain.FeatureMapping), "audit_log") {
+		if !features[want] {
+			t.Errorf("features %v do not contain %s", features, want)
+		}
+	}
+	fieldTypes := enumValues(schemaProperty(t, defs, "FieldSpecification", "type")["anyOf"].([]interface{})[0].(map[string]interface{}))
+	if _, ok := s.Types().Lookup("percentage"); !ok || !reflect.DeepEqual(fieldTypes, s.Types().Names()) {
+		t.Errorf("field types = %v, want the registered types %v", fieldTypes, s.Types().Names())
+	}
+
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		OutputPath:  "out",
+		ProjectType: "microservice",
+		Features:    []string{"config", "testing"},
+		Entities: []domain.EntitySpecification{
+			{
+				Name:     "Customer",
+				Features: []string{"crud", "rest_api"},
+				Fields:   []domain.FieldSpecification{{Name: "email", Type: "email", Required: true}},
+			},
+			{
+				Name:     "Order",
+				Features: []string{"crud", "rest_api", "validation", "audit_log"},
+				Fields: []domain.FieldSpecification{
+					{Name: "customer_id", Type: "string", Required: true},
+					{Name: "discount", Type: "percentage"},
+					{Name: "status", Type: "enum", Enum: []string{"open", "paid"}},
+				},
+				Relationships: []domain.RelationshipSpecification{{Name: "customer", Type: "belongs_to", Target: "Customer", ForeignKey: "customer_id"}},
+			},
+		},
+	}
+	if report := s.ValidateSpecification(spec); !report.Valid {
+		t.Fatalf("ValidateSpecification() = %+v", report.Issues)
+	}
+
+	// The schema accepts the specification and rejects unknown properties and values
+	var document map[string]interface{}
+	content, err = json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatal(err)
+	}
+	if problems := checkSchema(schema, defs, document, ""); len(problems) > 0 {
+		t.Errorf("schema rejects a valid specification: %v", problems)
+	}
+	entity := document["entities"].([]interface{})[1].(map[string]interface{})
+	entity["colour"] = "red"
+	entity["features"] = []interface{}{"crud", "telepathy"}
+	document["project_type"] = "mainframe"
+	want := []string{
+		"/entities/1: unknown property colour",
+		"/entities/1/features/1: telepathy is not one of",
+		"/project_type: mainframe is not one of",
+	}
+	problems := checkSchema(schema, defs, document, "")
+	if len(problems) != len(want) {
+		t.Errorf("schema problems = %v, want %d", problems, len(want))
+	}
+	for _, w := range want {
+		found := false
+		for _, problem := range problems {
+			found = found || strings.HasPrefix(problem, w)
+		}
+		if !found {
+			t.Errorf("schema problems %v do not contain %s", problems, w)
+		}
+	}
+
+	checkProjectGeneratedBy(t, s, spec)
+}
@@ -0,0 +1,837 @@
+package application
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// predeclaredIdentifiers are Go's predeclared names. Field names are used as lowercase
+// constructor parameters, where shadowing one of them breaks the generated code.
+var predeclaredIdentifiers = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true, "int32": true,
+	"int64": true, "rune": true, "string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "uintptr": true, "true": true, "false": true, "iota": true, "nil": true, "append": true,
+	"cap": true, "clear": true, "close": true, "complex": true, "copy": true, "delete": true, "imag": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+}
+
+// generatedFieldNames are struct fields added to every entity by the orchestrator
+var generatedFieldNames = map[string]bool{"createdat": true, "updatedat": true}
+
+// validIndexTypes are the index methods accepted in IndexSpecification.Type
+var validIndexTypes = map[string]bool{"btree": true, "hash": true, "gin": true, "gist": true, "brin": true}
+
+// validConstraintTypes are the constraint types accepted in ConstraintSpecification.Type
+var validConstraintTypes = map[string]bool{"check": true, "unique": true, "foreign_key": true, "primary_key": true}
+
+// validHTTPMethods are the methods accepted in EndpointSpecification.Method
+var validHTTPMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true}
+
+// specValidator collects every issue of a specification instead of stopping at the first one
+type specValidator struct {
+	service *OrchestratorService
+	spec    *domain.ProjectSpecification
+	issues  []domain.ValidationIssue
+}
+
+// ValidateSpecification checks a project specification and reports every issue found with a
+// JSON pointer to the offending value: missing values, unknown types and features, references
+// to missing entities or fields, duplicate names, invalid Go identifiers and reserved words
+func (s *OrchestratorService) ValidateSpecification(spec *domain.ProjectSpecification) *domain.ValidationReport {
+	v := &specValidator{service: s, spec: spec}
+	v.validateTypes()
+	v.validateProject()
+
+	report := &domain.ValidationReport{Issues: v.issues}
+	if report.Issues == nil {
+		report.Issues = []domain.ValidationIssue{}
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == "error" {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+
+	return report
+}
+
+// jsonPointer builds a JSON pointer (RFC 6901) from reference tokens
+func jsonPointer(tokens ...interface{}) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(fmt.Sprint(t)))
+	}
+	return b.String()
+}
+
+func (v *specValidator) errorf(path, code, format string, args ...interface{}) {
+	v.issues = append(v.issues, domain.ValidationIssue{Path: path, Code: code, Message: fmt.Sprintf(format, args...), Severity: "error"})
+}
+
+func (v *specValidator) warnf(path, code, format string, args ...interface{}) {
+	v.issues = append(v.issues, domain.ValidationIssue{Path: path, Code: code, Message: fmt.Sprintf(format, args...), Severity: "warning"})
+}
+
+func (v *specValidator) validateProject() {
+	spec := v.spec
+
+	if spec.Name == "" {
+		v.errorf("/name", "required", "project name is required")
+	}
+	if spec.ModulePath == "" {
+		v.errorf("/module_path", "required", "module path is required")
+	}
+	if spec.OutputPath == "" {
+		v.errorf("/output_path", "required", "output path is required")
+	}
+	if spec.ProjectType != "" {
+		if _, ok := domain.ProjectTypeMapping[spec.ProjectType]; !ok {
+			v.errorf("/project_type", "invalid_value", "unknown project type %q", spec.ProjectType)
+		}
+	}
+	if len(spec.Entities) == 0 && len(spec.Commands) == 0 && len(spec.Jobs) == 0 {
+		v.errorf("/entities", "required", "at least one entity, command or job is required")
+	}
+
+	v.validateFeatures("/features", spec.Features)
+	v.validateFeatureConflicts("/features",
+		featureRequest{spec.Features, "requested by the project"},
+		featureRequest{domain.ProjectTypeMapping[spec.ProjectType].DefaultFeatures, fmt.Sprintf("default for %s projects", spec.ProjectType)},
+	)
+	if scope, ok := spec.Options["tenancy"]; ok && !tenancyScopes[scope] {
+		v.errorf("/options/tenancy", "invalid_value", "unknown tenancy %q", scope)
+	}
+
+	seen := make(map[string]int)
+	for i, entity := range spec.Entities {
+		if first, ok := seen[entity.Name]; ok && entity.Name != "" {
+			v.errorf(jsonPointer("entities", i, "name"), "duplicate_name", "entity %q is already defined at %s", entity.Name, jsonPointer("entities", first))
+		} else {
+			seen[entity.Name] = i
+		}
+		v.validateEntity(i, entity)
+	}
+
+	for i, endpoint := range spec.Endpoints {
+		v.validateEndpoint(i, endpoint)
+	}
+	v.validateEndpointNames()
+	v.validateCommands()
+	v.validateJobs()
+
+	v.validateConfiguration()
+	v.validateProtoLock()
+}
+
+// validateProtoLock checks that the numbers of a protobuf lockfile can be kept as they are
+func (v *specValidator) validateProtoLock() {
+	lock := v.spec.ProtoLock
+	if lock == nil {
+		return
+	}
+	check := func(path string, numbering domain.ProtoNumbering, first int32, skipImplementationRange bool) {
+		owners := make(map[int32]string)
+		for _, number := range numbering.Reserved {
+			owners[number] = "a removed field"
+		}
+		for _, name := range sortedKeys(numbering.Numbers) {
+			number := numbering.Numbers[name]
+			switch {
+			case number < first || number > protoFieldNumberMax:
+				v.errorf(path+jsonPointer("numbers", name), "invalid_value", "number %d of %s is out of range", number, name)
+			case skipImplementationRange && number >= protoImplementationFirst && number <= protoImplementationLast:
+				v.errorf(path+jsonPointer("numbers", name), "invalid_value", "number %d of %s is reserved for the protobuf implementation", number, name)
+			case owners[number] != "":
+				v.errorf(path+jsonPointer("numbers", name), "duplicate_number", "number %d of %s is already used by %s", number, name, owners[number])
+			default:
+				owners[number] = name
+			}
+		}
+	}
+	for _, name := range sortedKeys(lock.Messages) {
+		check(jsonPointer("proto_lock", "messages", name), lock.Messages[name], 1, true)
+	}
+	for _, name := range sortedKeys(lock.Enums) {
+		check(jsonPointer("proto_lock", "enums", name), lock.Enums[name], 1, false)
+	}
+}
+
+// validateConfiguration checks the configuration values that drive generated code
+func (v *specValidator) validateConfiguration() {
+	config := v.spec.Configuration
+	durations := make(map[string]string)
+	if server := config.Server; server != nil {
+		if server.Port < 0 || server.Port > 65535 {
+			v.errorf("/configuration/server/port", "invalid_value", "port %d is out of range", server.Port)
+		}
+		if server.GRPCPort < 0 || server.GRPCPort > 65535 {
+			v.errorf("/configuration/server/grpc_port", "invalid_value", "port %d is out of range", server.GRPCPort)
+		}
+		port, grpcPort := server.Port, server.GRPCPort
+		if port == 0 {
+			port = defaultServerPort
+		}
+		if grpcPort == 0 {
+			grpcPort = defaultGRPCPort
+		}
+		if port == grpcPort {
+			v.errorf("/configuration/server/grpc_port", "invalid_value", "gRPC port %d is the HTTP port", grpcPort)
+		}
+		durations["/configuration/server/timeout"] = server.Timeout
+	}
+	if database := config.Database; database != nil {
+		if database.Type != "" && !databaseTypes[database.Type] {
+			v.errorf("/configuration/database/type", "invalid_value", "unknown database type %q", database.Type)
+		} else if _, ok := sqlDrivers[database.Type]; database.Type != "" && !ok {
+			v.warnf("/configuration/database/type", "invalid_value", "no database/sql driver is opened for %q; connect to it in main", database.Type)
+		}
+		if database.Port < 0 || database.Port > 65535 {
+			v.errorf("/configuration/database/port", "invalid_value", "port %d is out of range", database.Port)
+		}
+		if pool := database.Pooling; pool != nil {
+			path := "/configuration/database/pooling"
+			if pool.MaxOpen < 0 || pool.MaxIdle < 0 {
+				v.errorf(path, "invalid_value", "pool sizes must not be negative")
+			} else if pool.MaxOpen > 0 && pool.MaxIdle > pool.MaxOpen {
+				v.errorf(path+"/max_idle", "invalid_value", "max_idle %d exceeds max_open %d", pool.MaxIdle, pool.MaxOpen)
+			}
+			durations[path+"/max_lifetime"] = pool.MaxLifetime
+		}
+	}
+	if logging := config.Logging; logging != nil {
+		if logging.Level != "" && !logLevels[logging.Level] {
+			v.errorf("/configuration/logging/level", "invalid_value", "unknown log level %q", logging.Level)
+		}
+		if logging.Format != "" && !logFormats[logging.Format] {
+			v.errorf("/configuration/logging/format", "invalid_value", "unknown log format %q", logging.Format)
+		}
+	}
+	if security := config.Security; security != nil {
+		v.validateSecuritySchemes("/configuration/security/authentication", security.Authentication)
+		if security.RateLimit != nil {
+			if security.RateLimit.Requests < 0 {
+				v.errorf("/configuration/security/rate_limit/requests", "invalid_value", "requests must not be negative")
+			}
+			durations["/configuration/security/rate_limit/window"] = security.RateLimit.Window
+		}
+	}
+	if performance := config.Performance; performance != nil && performance.Buffering != nil {
+		durations["/configuration/performance/buffering/timeout"] = performance.Buffering.Timeout
+	}
+	for _, path := range sortedKeys(durations) {
+		if value := durations[path]; value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				v.errorf(path, "invalid_value", "%q is not a positive duration", value)
+			}
+		}
+	}
+
+	if performance := v.spec.Configuration.Performance; performance != nil && performance.Caching != nil {
+		caching := performance.Caching
+		path := "/configuration/performance/caching"
+		if generated, known := cacheBackends[caching.Type]; caching.Type != "" && !known {
+			v.errorf(path+"/type", "invalid_value", "unknown caching type %q", caching.Type)
+		} else if caching.Type != "" && !generated {
+			v.warnf(path+"/type", "invalid_value", "no cache is generated for %q; implement cache.Cache for it", caching.Type)
+		}
+		if caching.TTL != "" {
+			if ttl, err := time.ParseDuration(caching.TTL); err != nil || ttl <= 0 {
+				v.errorf(path+"/ttl", "invalid_value", "ttl %q is not a positive duration", caching.TTL)
+			}
+		}
+		if caching.Size < 0 {
+			v.errorf(path+"/size", "invalid_value", "size must not be negative")
+		}
+	}
+}
+
+// validateTypes registers the custom types of the project, so that fields may use them, and
+// reports the definitions the type registry rejects
+func (v *specValidator) validateTypes() {
+	if len(v.spec.Types) == 0 {
+		return
+	}
+	project := *v.service
+	project.types = v.service.types.clone()
+	v.service = &project
+
+	seen := make(map[string]int)
+	for i, def := range v.spec.Types {
+		path := jsonPointer("types", i)
+		if first, ok := seen[def.Name]; ok && def.Name != "" {
+			v.errorf(path+"/name", "duplicate_name", "type %q is already defined at %s", def.Name, jsonPointer("types", first))
+			continue
+		}
+		seen[def.Name] = i
+
+		switch {
+		case def.Name == "":
+			v.errorf(path+"/name", "required", "type name is required")
+		case def.GoType == "":
+			v.errorf(path+"/go_type", "required", "go_type is required")
+		default:
+			if err := project.types.Register(def); err != nil {
+				v.errorf(path, "invalid_value", "%v", err)
+			}
+		}
+	}
+}
+
+func (v *specValidator) validateFeatures(path string, features []string) {
+	known := make(map[string]bool)
+	for _, feature := range v.service.knownFeatures() {
+		known[feature] = true
+	}
+	for i, feature := range features {
+		if !known[feature] {
+			v.warnf(path+jsonPointer(i), "unknown_feature", "unknown feature %q is ignored", feature)
+		}
+	}
+}
+
+// validateFeatureConflicts reports features that conflict once their requirements are resolved
+func (v *specValidator) validateFeatureConflicts(path string, requests ...featureRequest) {
+	if _, err := v.service.resolveFeatures("", requests...); err != nil {
+		v.errorf(path, "feature_conflict", "%v", err)
+	}
+}
+
+// validateProjectFeatureConflicts reports entity features that conflict with the features enabled
+// for the project. Conflicts within either set are reported on their own.
+func (v *specValidator) validateProjectFeatureConflicts(path string, entity domain.EntitySpecification) {
+	project, err := v.service.resolveFeatures("",
+		featureRequest{v.spec.Features, "requested by the project"},
+		featureRequest{domain.ProjectTypeMapping[v.spec.ProjectType].DefaultFeatures, fmt.Sprintf("default for %s projects", v.spec.ProjectType)},
+	)
+	if err != nil {
+		return
+	}
+	resolution, err := v.service.resolveFeatures(entity.Name, featureRequest{entity.Features, "requested by the entity"})
+	if err != nil {
+		return
+	}
+	if err := entityFeatureConflict(project, resolution); err != nil {
+		v.errorf(path, "feature_conflict", "%v", err)
+	}
+}
+
+// validateIdentifier checks that a name can be used as a Go identifier, both as given and in the
+// lowercase form used for generated parameters and variables
+func (v *specValidator) validateIdentifier(path, kind, name string) {
+	if lower := strings.ToLower(name); token.IsKeyword(lower) || predeclaredIdentifiers[lower] {
+		v.errorf(path, "reserved_word", "%s name %q is a reserved Go word", kind, name)
+		return
+	}
+	if !token.IsIdentifier(name) {
+		v.errorf(path, "invalid_identifier", "%s name %q is not a valid Go identifier", kind, name)
+	}
+}
+
+func (v *specValidator) validateEntity(i int, entity domain.EntitySpecification) {
+	base := jsonPointer("entities", i)
+
+	if entity.Name == "" {
+		v.errorf(base+"/name", "required", "entity name is required")
+	} else if !token.IsIdentifier(entity.Name) || !token.IsExported(entity.Name) {
+		v.errorf(base+"/name", "invalid_identifier", "entity name %q must be an exported Go identifier", entity.Name)
+	}
+
+	if len(entity.Fields) == 0 {
+		v.errorf(base+"/fields", "required", "at least one field is required")
+	}
+
+	if scope, ok := entity.Options["tenancy"]; ok && !tenancyScopes[scope] {
+		v.errorf(base+"/options/tenancy", "invalid_value", "unknown tenancy %q", scope)
+	}
+	tenantFields := make(map[string]bool)
+	for _, field := range v.service.tenantFields(entity, v.spec) {
+		tenantFields[strings.ToLower(field.Name)] = true
+	}
+	lifecycleFields := make(map[string]string)
+	for _, option := range sortedKeys(lifecycleOptions) {
+		value, ok := entity.Options[option]
+		if !ok {
+			continue
+		}
+		if _, err := strconv.ParseBool(value); err != nil {
+			v.errorf(base+"/options/"+option, "invalid_value", "option %s must be a boolean, got %q", option, value)
+		}
+		if v.service.entityOption(entity, option) {
+			for _, name := range lifecycleOptions[option] {
+				lifecycleFields[strings.ToLower(name)] = option
+			}
+		}
+	}
+
+	fields := make(map[string]int)
+	for j, field := range entity.Fields {
+		path := base + jsonPointer("fields", j)
+		key := strings.ToLower(field.Name)
+
+		if first, ok := fields[key]; ok && field.Name != "" {
+			v.errorf(path+"/name", "duplicate_name", "field %q is already defined at %s", field.Name, base+jsonPointer("fields", first))
+		} else {
+			fields[key] = j
+		}
+		if generatedFieldNames[key] {
+			v.errorf(path+"/name", "duplicate_name", "field %q conflicts with the generated timestamp field", field.Name)
+		}
+		if tenantFields[key] {
+			v.errorf(path+"/name", "duplicate_name", "field %q conflicts with the generated tenancy field", field.Name)
+		}
+		if option, ok := lifecycleFields[key]; ok {
+			v.errorf(path+"/name", "duplicate_name", "field %q conflicts with the field generated by option %s", field.Name, option)
+		}
+
+		v.validateField(path, field)
+	}
+
+	v.validateFeatures(base+"/features", entity.Features)
+	v.validateFeatureConflicts(base+"/features", featureRequest{entity.Features, "requested by the entity"})
+	v.validateProjectFeatureConflicts(base+"/features", entity)
+
+	hasField := func(name string) bool {
+		key := strings.ToLower(name)
+		_, ok := fields[key]
+		return ok || key == "id" || key == "created_at" || key == "updated_at" || tenantFields[strings.ReplaceAll(key, "_", "")] || lifecycleFields[strings.ReplaceAll(key, "_", "")] != ""
+	}
+
+	for j, rel := range entity.Relationships {
+		path := base + jsonPointer("relationships", j)
+		if _, ok := domain.RelationshipMapping[rel.Type]; !ok {
+			v.errorf(path+"/type", "invalid_value", "unknown relationship type %q", rel.Type)
+		}
+		target, ok := v.service.findEntity(v.spec, rel.Target)
+		if !ok {
+			v.errorf(path+"/target", "unknown_entity", "relationship %q targets unknown entity %q", rel.Name, rel.Target)
+			continue
+		}
+		if rel.ForeignKey == "" {
+			continue
+		}
+		// The foreign key lives on the target for one_to_many, on the entity otherwise
+		owner := entity
+		if rel.Type == "one_to_many" {
+			owner = target
+		}
+		found := false
+		for _, field := range owner.Fields {
+			found = found || strings.EqualFold(field.Name, rel.ForeignKey)
+		}
+		if !found {
+			v.errorf(path+"/foreign_key", "unknown_field", "foreign key %q is not a field of %s", rel.ForeignKey, owner.Name)
+		}
+	}
+
+	for j, index := range entity.Indexes {
+		path := base + jsonPointer("indexes", j)
+		if index.Type != "" && !validIndexTypes[index.Type] {
+			v.errorf(path+"/type", "invalid_value", "unknown index type %q", index.Type)
+		}
+		if len(index.Fields) == 0 {
+			v.errorf(path+"/fields", "required", "index needs at least one field")
+		}
+		for k, name := range index.Fields {
+			if !hasField(name) {
+				v.errorf(path+jsonPointer("fields", k), "unknown_field", "index field %q is not a field of %s", name, entity.Name)
+			}
+		}
+	}
+
+	for j, constraint := range entity.Constraints {
+		path := base + jsonPointer("constraints", j)
+		if !validConstraintTypes[constraint.Type] {
+			v.errorf(path+"/type", "invalid_value", "unknown constraint type %q", constraint.Type)
+		}
+		if constraint.Type == "check" && constraint.Expression == "" {
+			v.errorf(path+"/expression", "required", "check constraint needs an expression")
+		}
+		for k, name := range constraint.Fields {
+			if !hasField(name) {
+				v.errorf(path+jsonPointer("fields", k), "unknown_field", "constraint field %q is not a field of %s", name, entity.Name)
+			}
+		}
+	}
+}
+
+func (v *specValidator) validateField(path string, field domain.FieldSpecification) {
+	if field.Name == "" {
+		v.errorf(path+"/name", "required", "field name is required")
+	} else {
+		v.validateIdentifier(path+"/name", "field", field.Name)
+	}
+
+	if field.Type == "" {
+		v.errorf(path+"/type", "required", "field type is required")
+	} else if _, ok := v.service.types.Lookup(field.Type); !ok {
+		if _, isEntity := v.service.findEntity(v.spec, field.Type); !isEntity {
+			v.errorf(path+"/type", "unknown_type", "unknown field type %q", field.Type)
+		}
+	}
+
+	if field.Type == "enum" && len(field.Enum) == 0 {
+		v.errorf(path+"/enum", "required", "enum field needs at least one allowed value")
+	}
+	// Each value becomes a constant named after its letters and digits, so values differing only
+	// by case or punctuation collide, and values without any have no name of their own
+	seen := make(map[string]string)
+	for k, value := range field.Enum {
+		name := v.service.toPascalCase(value)
+		switch previous, taken := seen[name]; {
+		case name == "":
+			v.errorf(path+jsonPointer("enum", k), "invalid_value", "enum value %q has no letter or digit to name its constant", value)
+		case taken && previous == value:
+			v.errorf(path+jsonPointer("enum", k), "duplicate_name", "enum value %q is listed more than once", value)
+		case taken:
+			v.errorf(path+jsonPointer("enum", k), "duplicate_name", "enum values %q and %q both generate the constant suffix %s", previous, value, name)
+		default:
+			seen[name] = value
+		}
+	}
+
+	// Bounds come from Min and Max or from the validation list, and no value satisfies
+	// contradicting ones
+	rules := v.service.sampleRules(field)
+	min, hasMin := rules["min"]
+	max, hasMax := rules["max"]
+	if hasMin && hasMax && min.N > max.N {
+		minPath := path + "/validation"
+		if field.Min != nil {
+			minPath = path + "/min"
+		}
+		v.errorf(minPath, "invalid_value", "min %d is greater than max %d", min.N, max.N)
+	}
+	if length, ok := rules["len"]; ok && v.service.valueCategory(v.service.fieldType(field).GoType) != "number" {
+		if hasMin && length.N < min.N {
+			v.errorf(path+"/validation", "invalid_value", "len %d is less than min %d", length.N, min.N)
+		}
+		if hasMax && length.N > max.N {
+			v.errorf(path+"/validation", "invalid_value", "len %d is greater than max %d", length.N, max.N)
+		}
+	}
+
+	if field.Reference != "" {
+		if _, ok := v.service.findEntity(v.spec, field.Reference); !ok {
+			v.errorf(path+"/reference", "unknown_entity", "field references unknown entity %q", field.Reference)
+		}
+	}
+
+	for _, rule := range v.service.fieldRules(field) {
+		if rule.Kind == "unsupported" {
+			v.warnf(path+"/validation", "invalid_value", "validation rule %q is not supported and is ignored", rule.Arg)
+		}
+	}
+}
+
+func (v *specValidator) validateEndpoint(i int, endpoint domain.EndpointSpecification) {
+	base := jsonPointer("endpoints", i)
+
+	if !strings.HasPrefix(endpoint.Path, "/") {
+		v.errorf(base+"/path", "invalid_value", "endpoint path %q must start with \"/\"", endpoint.Path)
+	}
+	if !validHTTPMethods[strings.ToUpper(endpoint.Method)] {
+		v.errorf(base+"/method", "invalid_value", "unknown HTTP method %q", endpoint.Method)
+	}
+	if endpoint.Handler != "" {
+		v.validateIdentifier(base+"/handler", "handler", endpoint.Handler)
+	}
+
+	template := pathParamPattern.ReplaceAllString(endpoint.Path, "{$1}")
+	seen := make(map[string]int)
+	for j, param := range endpoint.Parameters {
+		path := base + jsonPointer("parameters", j)
+		in := param.Type
+		if in == "" {
+			in = "query"
+		}
+		if param.Name == "" {
+			v.errorf(path+"/name", "required", "parameter name is required")
+		} else if first, ok := seen[in+" "+param.Name]; ok {
+			v.errorf(path+"/name", "duplicate_name", "parameter %q is already defined at %s", param.Name, base+jsonPointer("parameters", first))
+		} else {
+			seen[in+" "+param.Name] = j
+		}
+		switch param.Type {
+		case "", "path", "query", "header":
+		default:
+			v.errorf(path+"/type", "invalid_value", "unknown parameter location %q", param.Type)
+		}
+		if param.Type == "path" && param.Name != "" && !strings.Contains(template, "{"+param.Name+"}") && !strings.Contains(template, "{"+param.Name+"...}") {
+			v.errorf(path+"/name", "unknown_field", "path parameter %q does not appear in path %q", param.Name, endpoint.Path)
+		}
+		if _, ok := v.service.endpointParamGoType(param.DataType); !ok {
+			v.errorf(path+"/data_type", "invalid_value", "parameter data type %q cannot be converted from a request value", param.DataType)
+		}
+	}
+
+	v.validateSecuritySchemes(base+"/security", endpoint.Security)
+	if len(endpoint.Roles) > 0 && len(endpointSchemes(endpoint, v.spec)) == 0 {
+		v.errorf(base+"/roles", "invalid_value", "roles are checked after authentication; declare the security of the endpoint or configuration.security.authentication")
+	}
+
+	if endpoint.Request != nil {
+		v.validateSchemaName(base+"/request/schema", endpoint.Request.Schema)
+	}
+	if endpoint.Response != nil {
+		v.validateSchemaName(base+"/response/schema", endpoint.Response.Schema)
+		if code := endpoint.Response.StatusCode; code != 0 && (code < 100 || code > 599) {
+			v.errorf(base+"/response/status_code", "invalid_value", "status code %d is not a valid HTTP status", code)
+		}
+	}
+}
+
+// validateSecuritySchemes checks that security schemes are known, warning about those the
+// generated main has no authenticator for
+func (v *specValidator) validateSecuritySchemes(path string, schemes []string) {
+	for i, scheme := range schemes {
+		if generated, known := securitySchemes[scheme]; !known {
+			v.errorf(path+jsonPointer(i), "invalid_value", "unknown security scheme %q", scheme)
+		} else if !generated {
+			v.warnf(path+jsonPointer(i), "invalid_value", "no authenticator is generated for %q; add one to newAuthenticators in main", scheme)
+		}
+	}
+}
+
+// validateEndpointNames checks that endpoints have distinct routes, not served by the
+// observability package, and distinct Go names for their handlers, whether declared or derived
+// from the route
+func (v *specValidator) validateEndpointNames() {
+	routes := make(map[string]int)
+	names := make(map[string]int)
+	observability := v.service.usesObservability(v.spec)
+	for i, endpoint := range v.spec.Endpoints {
+		base := jsonPointer("endpoints", i)
+		route := strings.ToUpper(endpoint.Method) + " " + pathParamPattern.ReplaceAllString(endpoint.Path, "{$1}")
+		if observability && observabilityRoutes[route] {
+			v.errorf(base+"/path", "reserved_word", "route %s is served by the generated observability package", route)
+		} else if first, ok := routes[route]; ok {
+			v.errorf(base+"/path", "duplicate_name", "route %s is already defined at %s", route, jsonPointer("endpoints", first))
+		} else {
+			routes[route] = i
+		}
+
+		name := v.service.endpointName(endpoint)
+		switch first, ok := names[name]; {
+		case name == "RegisterRoutes":
+			v.errorf(base+"/handler", "reserved_word", "handler name %q is declared by the generated endpoint handler", endpoint.Handler)
+		case ok:
+			v.errorf(base+"/handler", "duplicate_name", "handler %s is already used by %s", name, jsonPointer("endpoints", first))
+		default:
+			names[name] = i
+		}
+	}
+}
+
+// validateCommands checks the command tree of a cli project: command and flag names, flag types
+// and defaults, shorthands, and the Go names generated for commands and handlers
+func (v *specValidator) validateCommands() {
+	if len(v.spec.Commands) == 0 {
+		return
+	}
+	if !v.service.hasFeature(v.spec.Features, "cli") && !v.service.hasFeature(domain.ProjectTypeMapping[v.spec.ProjectType].DefaultFeatures, "cli") {
+		v.warnf("/commands", "unused_value", "commands are only generated for projects with the cli feature")
+	}
+
+	goNames := make(map[string]string)
+	handlers := make(map[string]string)
+	var walk func(base string, commands []domain.CommandSpecification, parent []string)
+	walk = func(base string, commands []domain.CommandSpecification, parent []string) {
+		siblings := make(map[string]int)
+		for i, command := range commands {
+			path := base + jsonPointer(i)
+			v.validateCommand(path, command, len(parent) == 0)
+			if first, ok := siblings[command.Name]; ok && command.Name != "" {
+				v.errorf(path+"/name", "duplicate_name", "command %q is already defined at %s", command.Name, base+jsonPointer(first))
+				continue
+			}
+			siblings[command.Name] = i
+
+			cmd := cliCommand{CommandSpecification: command, Path: append(append([]string{}, parent...), command.Name)}
+			if command.Name != "" {
+				name := cmd.GoName(v.service)
+				if first, ok := goNames[name]; ok {
+					v.errorf(path+"/name", "duplicate_name", "command %q generates the same Go name %s as %s", strings.Join(cmd.Path, " "), name, first)
+				} else {
+					goNames[name] = path
+				}
+				if handler := cmd.HandlerName(v.service); handler != "" {
+					if first, ok := handlers[handler]; ok {
+						v.errorf(path+"/handler", "duplicate_name", "handler %s is already used by %s", handler, first)
+					} else {
+						handlers[handler] = path
+					}
+				}
+			}
+			walk(path+"/sub_commands", command.SubCommands, cmd.Path)
+		}
+	}
+	walk("/commands", v.spec.Commands, nil)
+}
+
+func (v *specValidator) validateCommand(path string, command domain.CommandSpecification, top bool) {
+	switch {
+	case command.Name == "":
+		v.errorf(path+"/name", "required", "command name is required")
+	case !validCLIName(command.Name):
+		v.errorf(path+"/name", "invalid_value", "command name %q must start with a letter and contain only letters, digits and dashes", command.Name)
+	case command.Name == "help" || top && command.Name == "completion":
+		v.errorf(path+"/name", "reserved_word", "command name %q is reserved by the generated CLI", command.Name)
+	}
+	if command.Handler != "" {
+		if !token.IsIdentifier(command.Handler) {
+			v.errorf(path+"/handler", "invalid_identifier", "handler name %q is not a valid Go identifier", command.Handler)
+		} else if cliReservedHandlers[v.service.capitalizeFirst(command.Handler)] {
+			v.errorf(path+"/handler", "reserved_word", "handler name %q is declared by the generated commands package", command.Handler)
+		}
+	}
+
+	names := make(map[string]int)
+	shorts := make(map[string]int)
+	for j, flag := range command.Flags {
+		flagPath := path + jsonPointer("flags", j)
+		switch {
+		case flag.Name == "":
+			v.errorf(flagPath+"/name", "required", "flag name is required")
+		case !validCLIName(flag.Name):
+			v.errorf(flagPath+"/name", "invalid_value", "flag name %q must start with a letter and contain only letters, digits and dashes", flag.Name)
+		case flag.Name == "help":
+			v.errorf(flagPath+"/name", "reserved_word", "flag name %q is reserved by the generated CLI", flag.Name)
+		}
+		if first, ok := names[flag.Name]; ok && flag.Name != "" {
+			v.errorf(flagPath+"/name", "duplicate_name", "flag %q is already defined at %s", flag.Name, path+jsonPointer("flags", first))
+		} else {
+			names[flag.Name] = j
+		}
+
+		if flag.Short != "" {
+			if len(flag.Short) != 1 || !validCLIName("x"+flag.Short) || flag.Short == "-" {
+				v.errorf(flagPath+"/short", "invalid_value", "flag shorthand %q must be a single letter or digit", flag.Short)
+			} else if flag.Short == "h" {
+				v.errorf(flagPath+"/short", "reserved_word", "flag shorthand %q is reserved for --help", flag.Short)
+			} else if first, ok := shorts[flag.Short]; ok {
+				v.errorf(flagPath+"/short", "duplicate_name", "flag shorthand %q is already used at %s", flag.Short, path+jsonPointer("flags", first))
+			} else {
+				shorts[flag.Short] = j
+			}
+		}
+
+		if _, ok := cliFlagTypes[flag.Type]; !ok && flag.Type != "" {
+			v.errorf(flagPath+"/type", "invalid_value", "unknown flag type %q (expected string, bool, int or array)", flag.Type)
+		}
+		if flag.Default != "" {
+			var err error
+			switch flag.Type {
+			case "bool":
+				_, err = strconv.ParseBool(flag.Default)
+			case "int":
+				_, err = strconv.Atoi(flag.Default)
+			}
+			if err != nil {
+				v.errorf(flagPath+"/default", "invalid_value", "default %q is not a valid %s", flag.Default, flag.Type)
+			}
+			if flag.Required {
+				v.warnf(flagPath+"/default", "unused_value", "default of a required flag is never used")
+			}
+		}
+	}
+}
+
+// validateJobs checks the jobs of a worker project: names and the Go names generated for them,
+// queues, schedules and retry settings
+func (v *specValidator) validateJobs() {
+	if len(v.spec.Jobs) == 0 {
+		return
+	}
+	if !v.service.hasFeature(v.spec.Features, "queue_processing") && !v.service.hasFeature(domain.ProjectTypeMapping[v.spec.ProjectType].DefaultFeatures, "queue_processing") {
+		v.warnf("/jobs", "unused_value", "jobs are only generated for projects with the queue_processing feature")
+	} else if database := v.spec.Configuration.Database; database != nil && database.Type != "" && database.Type != "postgres" {
+		v.warnf("/configuration/database/type", "unused_value", "jobs are only stored in postgres databases; the worker keeps them in memory")
+	}
+
+	names := make(map[string]int)
+	goNames := make(map[string]string)
+	for i, job := range v.spec.Jobs {
+		path := jsonPointer("jobs", i)
+		switch {
+		case job.Name == "":
+			v.errorf(path+"/name", "required", "job name is required")
+		case !validJobName(job.Name):
+			v.errorf(path+"/name", "invalid_value", "job name %q must start with a letter and contain only letters, digits, dashes and underscores", job.Name)
+		default:
+			if first, ok := names[job.Name]; ok {
+				v.errorf(path+"/name", "duplicate_name", "job %q is already defined at %s", job.Name, jsonPointer("jobs", first))
+				break
+			}
+			names[job.Name] = i
+			// Each job declares its name constant and handler in the jobs package
+			name := v.service.toPascalCase(job.Name)
+			for _, ident := range []string{name, "Handle" + name} {
+				if jobsReserved[ident] {
+					v.errorf(path+"/name", "reserved_word", "job name %q generates %s, which the jobs package declares itself", job.Name, ident)
+				} else if first, ok := goNames[ident]; ok {
+					v.errorf(path+"/name", "duplicate_name", "job %q generates the same Go name %s as %s", job.Name, ident, first)
+				} else {
+					goNames[ident] = path
+				}
+			}
+		}
+		if job.Queue != "" && !validJobName(job.Queue) {
+			v.errorf(path+"/queue", "invalid_value", "queue name %q must start with a letter and contain only letters, digits, dashes and underscores", job.Queue)
+		}
+		if job.Schedule != "" {
+			if err := parseCronSchedule(job.Schedule); err != nil {
+				v.errorf(path+"/schedule", "invalid_value", "schedule %q is invalid: %v", job.Schedule, err)
+			}
+		}
+		if job.MaxAttempts < 0 {
+			v.errorf(path+"/max_attempts", "invalid_value", "max_attempts must not be negative")
+		}
+		for _, setting := range []struct{ name, value string }{{"backoff", job.Backoff}, {"timeout", job.Timeout}} {
+			if setting.value == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(setting.value); err != nil || d <= 0 {
+				v.errorf(path+"/"+setting.name, "invalid_value", "%s %q is not a positive duration", setting.name, setting.value)
+			}
+		}
+	}
+}
+
+// validCLIName reports whether a command or flag name can be typed on the command line and
+// turned into a Go identifier
+func validCLIName(name string) bool {
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// validateSchemaName checks that a request or response schema names an entity or a known type
+func (v *specValidator) validateSchemaName(path, name string) {
+	if name == "" {
+		return
+	}
+	base := strings.TrimLeft(name, "[]*")
+	if _, ok := v.service.types.Lookup(base); ok {
+		return
+	}
+	if _, ok := v.service.findEntity(v.spec, base); !ok {
+		v.errorf(path, "unknown_entity", "schema %q is neither an entity nor a known type", name)
+	}
+}
@@ -0,0 +1,317 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bounds of the expansion of a YAML specification. Aliases and includes can repeat a node many
+// times over, so a small document could otherwise decode into an unbounded specification.
+const (
+	maxYAMLNodes    = 100000 // Nodes decoded, counting each node again for every alias or include repeating it
+	maxYAMLIncludes = 1000   // Files included, counting each file again for every include of it
+)
+
+// yamlDecoder decodes YAML specifications into the json-tagged domain types, resolving
+// "$ref" includes and reporting decoding errors with their file, line and column
+type yamlDecoder struct {
+	root     string                // Directory includes must stay within; includes are disabled when empty
+	origins  map[*yaml.Node]string // File each included node was read from
+	nodes    int                   // Nodes decoded so far
+	includes int                   // Files included so far
+}
+
+// yamlError is a decoding error located in a YAML document
+type yamlError struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *yamlError) Error() string {
+	location := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ": " + location
+	}
+	return location + ": " + e.Msg
+}
+
+// DecodeYAML decodes a YAML document into target using its json tags. Mappings of the form
+// {"$ref": "entities/user.yaml"} are replaced by the referenced file, optionally narrowed by a
+// JSON pointer fragment ("common.yaml#/entities/0"); an include that resolves to a list inside
+// a list is spliced into it. Include paths are resolved relative to the including file and must
+// stay within baseDir. Includes are rejected when baseDir is empty.
+func (s *OrchestratorService) DecodeYAML(data []byte, baseDir string, target interface{}) error {
+	d := &yamlDecoder{root: baseDir, origins: make(map[*yaml.Node]string)}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if _, err := d.resolve(&document, baseDir, "", nil); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", target)
+	}
+	return d.decode(&document, value.Elem(), "")
+}
+
+// resolve replaces include mappings below node. It reports whether node itself was an include.
+func (d *yamlDecoder) resolve(node *yaml.Node, dir, file string, stack []string) (bool, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if _, err := d.resolve(child, dir, file, stack); err != nil {
+				return false, err
+			}
+		}
+	case yaml.SequenceNode:
+		var items []*yaml.Node
+		for _, child := range node.Content {
+			included, err := d.resolve(child, dir, file, stack)
+			if err != nil {
+				return false, err
+			}
+			if included && child.Kind == yaml.SequenceNode {
+				for _, item := range child.Content {
+					d.origins[item] = d.origins[child]
+				}
+				items = append(items, child.Content...)
+				continue
+			}
+			items = append(items, child)
+		}
+		node.Content = items
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			if node.Content[i].Value != "$ref" {
+				continue
+			}
+			if len(node.Content) != 2 {
+				return false, d.errorf(node, file, "$ref cannot be combined with other keys")
+			}
+			return true, d.include(node, node.Content[i+1], dir, file, stack)
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if _, err := d.resolve(node.Content[i], dir, file, stack); err != nil {
+				return false, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// include replaces node with the content referenced by ref
+func (d *yamlDecoder) include(node, ref *yaml.Node, dir, file string, stack []string) error {
+	if d.root == "" {
+		return d.errorf(ref, file, "includes are not enabled")
+	}
+
+	target, fragment, _ := strings.Cut(ref.Value, "#")
+	if target == "" || filepath.IsAbs(target) {
+		return d.errorf(ref, file, "include %q must be a relative file path", ref.Value)
+	}
+
+	// Symlinks are resolved on both sides so that a link inside the base directory cannot
+	// include a file outside of it
+	path := filepath.Join(dir, filepath.FromSlash(target))
+	root, err := evalAbs(d.root)
+	if err != nil {
+		return d.errorf(ref, file, "invalid base directory: %v", err)
+	}
+	absolute, err := evalAbs(path)
+	if err != nil {
+		return d.errorf(ref, file, "failed to read include %q: %v", ref.Value, err)
+	}
+	if rel, err := filepath.Rel(root, absolute); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return d.errorf(ref, file, "include %q is outside the base directory", ref.Value)
+	}
+	for _, parent := range stack {
+		if parent == absolute {
+			return d.errorf(ref, file, "include %q is circular", ref.Value)
+		}
+	}
+	if d.includes++; d.includes > maxYAMLIncludes {
+		return d.errorf(ref, file, "specification includes more than %d files", maxYAMLIncludes)
+	}
+
+	data, err := os.ReadFile(absolute)
+	if err != nil {
+		return d.errorf(ref, file, "failed to read include %q: %v", ref.Value, err)
+	}
+	name := filepath.ToSlash(filepath.Join(filepath.Dir(filepath.FromSlash(file)), filepath.FromSlash(target)))
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(document.Content) == 0 {
+		return d.errorf(ref, file, "include %q is empty", ref.Value)
+	}
+
+	content, err := d.pointer(document.Content[0], fragment, name)
+	if err != nil {
+		return err
+	}
+	if _, err := d.resolve(content, filepath.Dir(absolute), name, append(stack, absolute)); err != nil {
+		return err
+	}
+
+	*node = *content
+	d.origins[node] = name
+	return nil
+}
+
+// evalAbs returns the absolute path of path with every symlink resolved
+func evalAbs(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(absolute)
+}
+
+// pointer navigates a JSON pointer fragment ("/entities/0") within a YAML node
+func (d *yamlDecoder) pointer(node *yaml.Node, fragment, file string) (*yaml.Node, error) {
+	if fragment == "" || fragment == "/" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+			}
+		}
+		if next == nil {
+			return nil, d.errorf(node, file, "fragment %q not found", fragment)
+		}
+		node = next
+	}
+	return node, nil
+}
+
+// decode assigns node to v, matching mapping keys against json tags. Unknown keys are
+// ignored, as they are for JSON requests.
+func (d *yamlDecoder) decode(node *yaml.Node, v reflect.Value, file string) error {
+	if origin, ok := d.origins[node]; ok {
+		file = origin
+	}
+	if d.nodes++; d.nodes > maxYAMLNodes {
+		return d.errorf(node, file, "specification expands to more than %d nodes", maxYAMLNodes)
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return d.decode(node.Content[0], v, file)
+	case yaml.AliasNode:
+		return d.decode(node.Alias, v, file)
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(node, v.Elem(), file)
+
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return d.errorf(node, file, "expected a mapping for %s", v.Type().Name())
+		}
+		fields := make(map[string]int)
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				fields[name] = i
+			}
+		}
+		for i := 0; i < len(node.Content); i += 2 {
+			index, ok := fields[node.Content[i].Value]
+			if !ok {
+				continue
+			}
+			if err := d.decode(node.Content[i+1], v.Field(index), file); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return d.errorf(node, file, "expected a list")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(node.Content), len(node.Content))
+		for i, item := range node.Content {
+			if err := d.decode(item, slice.Index(i), file); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return d.errorf(node, file, "expected a mapping")
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(node.Content)/2)
+		for i := 0; i < len(node.Content); i += 2 {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(node.Content[i+1], elem, file); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(node.Content[i].Value).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+		return nil
+
+	case reflect.Interface:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return d.errorf(node, file, "%v", err)
+		}
+		if value != nil {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return d.errorf(node, file, "expected a %s value", v.Type())
+	}
+	if err := node.Decode(v.Addr().Interface()); err != nil {
+		return d.errorf(node, file, "cannot use %q as %s", node.Value, v.Type())
+	}
+	return nil
+}
+
+// errorf returns a decoding error located at node
+func (d *yamlDecoder) errorf(node *yaml.Node, file, format string, args ...interface{}) error {
+	return &yamlError{File: file, Line: node.Line, Column: node.Column, Msg: fmt.Sprintf(format, args...)}
+}
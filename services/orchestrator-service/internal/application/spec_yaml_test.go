@@ -0,0 +1,121 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestDecodeYAMLIncludes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "entities", "note.yaml"), "name: Note\nfields:\n  - name: title\n    type: string\n")
+
+	var spec domain.ProjectSpecification
+	data := []byte("name: notes\nentities:\n  - $ref: entities/note.yaml\n")
+	if err := NewOrchestratorService().DecodeYAML(data, root, &spec); err != nil {
+		t.Fatalf("DecodeYAML() error = %v", err)
+	}
+	if len(spec.Entities) != 1 || spec.Entities[0].Name != "Note" {
+		t.Errorf("DecodeYAML() entities = %+v, want the included Note entity", spec.Entities)
+	}
+}
+
+func TestDecodeYAMLRejectsIncludesOutsideBaseDir(t *testing.T) {
+	outside := t.TempDir()
+	writeFile(t, filepath.Join(outside, "secret.yaml"), "name: Secret\n")
+
+	root := filepath.Join(outside, "spec")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "linked")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+	}{
+		{name: "parent directory", ref: "../secret.yaml"},
+		{name: "symlinked directory", ref: "linked/secret.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec domain.ProjectSpecification
+			data := []byte("name: notes\nentities:\n  - $ref: " + tt.ref + "\n")
+			err := NewOrchestratorService().DecodeYAML(data, root, &spec)
+			if err == nil || !strings.Contains(err.Error(), "outside the base directory") {
+				t.Fatalf("DecodeYAML() error = %v, want the include to be rejected", err)
+			}
+			if len(spec.Entities) != 0 {
+				t.Errorf("DecodeYAML() entities = %+v, want none", spec.Entities)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeYAMLBoundsExpansion(t *testing.T) {
+	root := t.TempDir()
+
+	// Each level includes the next one twice, doubling the included files at each level
+	for level := 0; level < 12; level++ {
+		content := "- name: Leaf\n"
+		if level < 11 {
+			next := fmt.Sprintf("level%d.yaml", level+1)
+			content = "- $ref: " + next + "\n- $ref: " + next + "\n"
+		}
+		writeFile(t, filepath.Join(root, fmt.Sprintf("level%d.yaml", level)), content)
+	}
+
+	// One anchored entity of 3000 fields, repeated by 3000 aliases
+	var fields, aliases strings.Builder
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&fields, "      - name: f%d\n        type: string\n", i)
+		aliases.WriteString("  - *E\n")
+	}
+	aliasBomb := "name: shop\ndefinitions:\n  - &E\n    name: Order\n    fields:\n" + fields.String() + "entities:\n" + aliases.String()
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "aliases", data: aliasBomb, want: "expands to more than"},
+		{name: "includes", data: "name: shop\nentities:\n  - $ref: level0.yaml\n", want: "includes more than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec domain.ProjectSpecification
+			err := NewOrchestratorService().DecodeYAML([]byte(tt.data), root, &spec)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("DecodeYAML() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeYAMLAliases(t *testing.T) {
+	data := []byte("name: shop\nentities:\n  - &order\n    name: Order\n    fields: &fields\n      - name: total\n        type: integer\n  - name: Invoice\n    fields: *fields\n")
+
+	var spec domain.ProjectSpecification
+	if err := NewOrchestratorService().DecodeYAML(data, "", &spec); err != nil {
+		t.Fatalf("DecodeYAML() error = %v", err)
+	}
+	if len(spec.Entities) != 2 || len(spec.Entities[1].Fields) != 1 || spec.Entities[1].Fields[0].Name != "total" {
+		t.Errorf("DecodeYAML() entities = %+v, want Invoice with the fields of Order", spec.Entities)
+	}
+}
@@ -0,0 +1,495 @@
+package application
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// sqlNullTypes maps database/sql nullable wrappers to the logical type they hold
+var sqlNullTypes = map[string]string{
+	"sql.NullString":  "string",
+	"sql.NullInt16":   "integer",
+	"sql.NullInt32":   "int32",
+	"sql.NullInt64":   "int64",
+	"sql.NullFloat64": "float",
+	"sql.NullBool":    "boolean",
+	"sql.NullTime":    "timestamp",
+}
+
+// defaultImportedFeatures are the features of entities imported from a database or Go source
+var defaultImportedFeatures = []string{"crud", "validation", "rest_api"}
+
+// preferredLogicalTypes break ties when several logical types map to the same Go type
+var preferredLogicalTypes = map[string]bool{
+	"string": true, "integer": true, "float": true, "boolean": true, "timestamp": true, "json": true, "bytes": true, "decimal": true,
+}
+
+// goField is a struct field, with embedded structs flattened into their parent
+type goField struct {
+	Name string
+	Type ast.Expr
+	Tag  reflect.StructTag
+	Doc  string
+}
+
+// structImporter converts the struct declarations of a set of Go files to entities
+type structImporter struct {
+	structs  map[string]*ast.TypeSpec // Struct declarations by name
+	named    map[string]ast.Expr      // Underlying types of other type declarations
+	embedded map[string]bool          // Structs embedded in other structs
+	consts   map[string][]string      // String constants declared for each named type
+	selected map[string]bool          // Structs that become entities
+	goTypes  map[string]string        // Go types mapped back to logical types
+}
+
+// ImportGoStructs parses Go source files and converts their exported struct declarations to
+// entity specifications. Go types are mapped back through the type registry, json/db tags are kept as
+// field tags, validate/binding tags become Required and Validation, ID fields named after another
+// struct become references and slices of other structs become one_to_many relationships. Paths are
+// resolved within baseDir and are rejected when baseDir is empty.
+func (s *OrchestratorService) ImportGoStructs(req *domain.StructImportRequest, baseDir string) ([]domain.EntitySpecification, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, source := range req.Files {
+		file, err := parser.ParseFile(fset, source.Name, source.Content, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	for _, path := range req.Paths {
+		names, err := s.goSourceFiles(baseDir, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go source files to import")
+	}
+
+	imp := &structImporter{
+		structs:  make(map[string]*ast.TypeSpec),
+		named:    make(map[string]ast.Expr),
+		embedded: make(map[string]bool),
+		consts:   make(map[string][]string),
+		selected: make(map[string]bool),
+		goTypes:  s.inverseTypeMapping(),
+	}
+	var order []string
+	for _, file := range files {
+		order = append(order, imp.collect(file)...)
+	}
+
+	if len(req.Structs) > 0 {
+		for _, name := range req.Structs {
+			if _, ok := imp.structs[name]; !ok {
+				return nil, fmt.Errorf("struct %s not found", name)
+			}
+			imp.selected[name] = true
+		}
+	} else {
+		// Structs embedded in others (e.g. a shared Base) are promoted rather than imported
+		for _, name := range order {
+			imp.selected[name] = ast.IsExported(name) && !imp.embedded[name]
+		}
+	}
+
+	features := req.Features
+	if len(features) == 0 {
+		features = defaultImportedFeatures
+	}
+
+	var entities []domain.EntitySpecification
+	for _, name := range order {
+		if imp.selected[name] {
+			entities = append(entities, imp.entity(imp.structs[name], features))
+		}
+	}
+	imp.linkRelationships(entities)
+
+	return entities, nil
+}
+
+// goSourceFiles returns the Go files at path, a file or a directory relative to baseDir.
+// Test files are skipped when reading a directory.
+func (s *OrchestratorService) goSourceFiles(baseDir, path string) ([]string, error) {
+	if baseDir == "" {
+		return nil, fmt.Errorf("file imports are not enabled")
+	}
+	if filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path %q must be relative", path)
+	}
+
+	root, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base directory: %w", err)
+	}
+	absolute := filepath.Join(root, filepath.FromSlash(path))
+	if rel, err := filepath.Rel(root, absolute); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path %q is outside the base directory", path)
+	}
+
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	if !info.IsDir() {
+		if filepath.Ext(absolute) != ".go" {
+			return nil, fmt.Errorf("path %q is not a Go file", path)
+		}
+		return []string{absolute}, nil
+	}
+
+	entries, err := os.ReadDir(absolute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			names = append(names, filepath.Join(absolute, name))
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no Go files in %q", path)
+	}
+	return names, nil
+}
+
+// inverseTypeMapping maps the Go types of the type registry back to logical types
+func (s *OrchestratorService) inverseTypeMapping() map[string]string {
+	goTypes := map[string]string{"uuid.UUID": "uuid"}
+	for _, def := range s.types.Definitions() {
+		if _, ok := goTypes[def.GoType]; !ok || preferredLogicalTypes[def.Name] {
+			goTypes[def.GoType] = def.Name
+		}
+	}
+	return goTypes
+}
+
+// collect registers the type and constant declarations of a file and returns its struct names in order
+func (imp *structImporter) collect(file *ast.File) []string {
+	var structs []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		switch gen.Tok {
+		case token.TYPE:
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Doc == nil && len(gen.Specs) == 1 {
+					typeSpec.Doc = gen.Doc
+				}
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.TypeParams == nil {
+					imp.structs[typeSpec.Name.Name] = typeSpec
+					structs = append(structs, typeSpec.Name.Name)
+					for _, field := range structType.Fields.List {
+						if len(field.Names) == 0 {
+							imp.embedded[strings.TrimPrefix(types.ExprString(field.Type), "*")] = true
+						}
+					}
+				} else {
+					imp.named[typeSpec.Name.Name] = typeSpec.Type
+				}
+			}
+		case token.CONST:
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				ident, ok := value.Type.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				for _, v := range value.Values {
+					if lit, ok := v.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if text, err := strconv.Unquote(lit.Value); err == nil {
+							imp.consts[ident.Name] = append(imp.consts[ident.Name], text)
+						}
+					}
+				}
+			}
+		}
+	}
+	return structs
+}
+
+// fields returns the fields of a struct, promoting the fields of embedded structs declared in
+// the imported files. Other embedded types (e.g. gorm.Model) are skipped.
+func (imp *structImporter) fields(spec *ast.TypeSpec, visited map[string]bool) []goField {
+	visited[spec.Name.Name] = true
+	var fields []goField
+	for _, field := range spec.Type.(*ast.StructType).Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		doc := strings.TrimSpace(field.Doc.Text())
+		if doc == "" {
+			doc = strings.TrimSpace(field.Comment.Text())
+		}
+
+		if len(field.Names) == 0 {
+			name := strings.TrimPrefix(types.ExprString(field.Type), "*")
+			if embedded, ok := imp.structs[name]; ok && !visited[name] {
+				fields = append(fields, imp.fields(embedded, visited)...)
+			}
+			continue
+		}
+		for _, name := range field.Names {
+			fields = append(fields, goField{Name: name.Name, Type: field.Type, Tag: tag, Doc: doc})
+		}
+	}
+	return fields
+}
+
+// entity converts a struct declaration to an entity specification
+func (imp *structImporter) entity(spec *ast.TypeSpec, features []string) domain.EntitySpecification {
+	entity := domain.EntitySpecification{
+		Name:        spec.Name.Name,
+		Description: strings.TrimSpace(spec.Doc.Text()),
+		Features:    append([]string(nil), features...),
+	}
+
+	for _, field := range imp.fields(spec, make(map[string]bool)) {
+		if !ast.IsExported(field.Name) {
+			continue
+		}
+
+		// ID and timestamps are generated for every entity
+		switch field.Name {
+		case "ID", "Id":
+			if goType := types.ExprString(field.Type); goType != "string" && goType != "uuid.UUID" {
+				entity.Description = strings.TrimSpace(entity.Description + fmt.Sprintf("\nField %s is %s; generated entities use string UUID identifiers", field.Name, goType))
+			}
+			continue
+		case "CreatedAt", "UpdatedAt":
+			continue
+		}
+
+		// Fields holding other entities become relationships
+		expr, pointer := field.Type, false
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr, pointer = star.X, true
+		}
+		if target := imp.entityName(expr); target != "" {
+			entity.Relationships = append(entity.Relationships, domain.RelationshipSpecification{
+				Name: imp.fieldName(field.Name), Type: "one_to_one", Target: target,
+			})
+			continue
+		}
+		if array, ok := expr.(*ast.ArrayType); ok && !pointer {
+			elem := array.Elt
+			if star, ok := elem.(*ast.StarExpr); ok {
+				elem = star.X
+			}
+			if target := imp.entityName(elem); target != "" {
+				entity.Relationships = append(entity.Relationships, domain.RelationshipSpecification{
+					Name: imp.fieldName(field.Name), Type: "one_to_many", Target: target,
+				})
+				continue
+			}
+		}
+
+		spec := imp.field(field, expr, pointer)
+		if target := imp.referencedEntity(field.Name); target != "" && target != entity.Name {
+			spec.Reference = target
+			entity.Relationships = append(entity.Relationships, domain.RelationshipSpecification{
+				Name: imp.fieldName(target), Type: "belongs_to", Target: target, ForeignKey: spec.Name,
+			})
+		}
+		entity.Fields = append(entity.Fields, spec)
+	}
+
+	return entity
+}
+
+// field converts a struct field to a field specification
+func (imp *structImporter) field(field goField, expr ast.Expr, pointer bool) domain.FieldSpecification {
+	spec := domain.FieldSpecification{
+		Name:        imp.fieldName(field.Name),
+		Nullable:    pointer,
+		Description: field.Doc,
+	}
+
+	var nullable bool
+	spec.Type, spec.Enum, nullable = imp.logicalType(expr, make(map[string]bool))
+	spec.Nullable = spec.Nullable || nullable
+	if spec.Type == "" {
+		spec.Type = "string"
+		spec.Description = strings.TrimSpace(spec.Description + fmt.Sprintf("\nImported from Go type %s", types.ExprString(field.Type)))
+	}
+
+	// Tags that differ from the generated defaults are kept, as are tags for other libraries
+	tags := make(map[string]string)
+	if name := imp.tagName(field.Tag, "json"); name != "" && name != strings.ToLower(spec.Name) {
+		tags["json"] = name
+	}
+	if name := imp.tagName(field.Tag, "db"); name != "" && name != strings.ToLower(spec.Name) {
+		tags["db"] = name
+	}
+	for _, key := range imp.tagKeys(field.Tag) {
+		if key != "json" && key != "db" && key != "validate" && key != "binding" {
+			tags[key] = field.Tag.Get(key)
+		}
+	}
+	if len(tags) > 0 {
+		spec.Tags = tags
+	}
+
+	for _, key := range []string{"validate", "binding"} {
+		for _, rule := range strings.Split(field.Tag.Get(key), ",") {
+			rule = strings.TrimSpace(rule)
+			name, arg, _ := strings.Cut(strings.Replace(rule, "=", ":", 1), ":")
+			switch name {
+			case "", "omitempty":
+				continue
+			case "required":
+				spec.Required = true
+				continue
+			case "regexp":
+				name = "regex"
+			}
+			if arg != "" {
+				name += ":" + arg
+			}
+			spec.Validation = append(spec.Validation, name)
+		}
+	}
+
+	return spec
+}
+
+// logicalType maps a Go type back to a logical type. Named string types with string constants
+// declared in the imported files become enums. It returns an empty type when there is no mapping.
+func (imp *structImporter) logicalType(expr ast.Expr, visited map[string]bool) (string, []string, bool) {
+	goType := types.ExprString(expr)
+	if logical, ok := sqlNullTypes[goType]; ok {
+		return logical, nil, true
+	}
+	if logical, ok := imp.goTypes[goType]; ok {
+		return logical, nil, false
+	}
+
+	underlying, ok := imp.named[goType]
+	if !ok || visited[goType] {
+		return "", nil, false
+	}
+	visited[goType] = true
+	logical, _, nullable := imp.logicalType(underlying, visited)
+	if values := imp.consts[goType]; logical == "string" && len(values) > 0 {
+		return "enum", values, nullable
+	}
+	return logical, nil, nullable
+}
+
+// entityName returns the struct name of expr when it is an imported entity
+func (imp *structImporter) entityName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok && imp.selected[ident.Name] {
+		return ident.Name
+	}
+	return ""
+}
+
+// referencedEntity returns the entity an ID field is named after (e.g. "UserID" -> "User")
+func (imp *structImporter) referencedEntity(name string) string {
+	for _, suffix := range []string{"ID", "Id"} {
+		if target := strings.TrimSuffix(name, suffix); target != name && imp.selected[target] {
+			return target
+		}
+	}
+	return ""
+}
+
+// fieldName converts a Go field name to a field name. Only a leading capital followed by a
+// lowercase letter is lowered, so that initialisms survive ("Email" -> "email", "URL" -> "URL").
+func (imp *structImporter) fieldName(name string) string {
+	runes := []rune(name)
+	if len(runes) > 1 && unicode.IsUpper(runes[0]) && unicode.IsLower(runes[1]) {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes)
+}
+
+// tagName returns the name part of a struct tag (`json:"user_id,omitempty"` -> "user_id")
+func (imp *structImporter) tagName(tag reflect.StructTag, key string) string {
+	name, _, _ := strings.Cut(tag.Get(key), ",")
+	return name
+}
+
+// tagKeys returns the keys of a struct tag in sorted order
+func (imp *structImporter) tagKeys(tag reflect.StructTag) []string {
+	var keys []string
+	for rest := strings.TrimSpace(string(tag)); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, ":")
+		if !ok || !strings.HasPrefix(value, `"`) {
+			break
+		}
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+		rest = value[len(quoted):]
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// linkRelationships sets the foreign key of one_to_many relationships to the target field
+// referencing the owner. Two entities holding slices of each other without such a
+// field are linked many_to_many.
+func (imp *structImporter) linkRelationships(entities []domain.EntitySpecification) {
+	index := make(map[string]*domain.EntitySpecification)
+	for i := range entities {
+		index[entities[i].Name] = &entities[i]
+	}
+
+	for i := range entities {
+		owner := &entities[i]
+		for j := range owner.Relationships {
+			rel := &owner.Relationships[j]
+			if rel.Type != "one_to_many" {
+				continue
+			}
+			target := index[rel.Target]
+			for _, field := range target.Fields {
+				if field.Reference == owner.Name {
+					rel.ForeignKey = field.Name
+					break
+				}
+			}
+			if rel.ForeignKey != "" {
+				continue
+			}
+			for k := range target.Relationships {
+				back := &target.Relationships[k]
+				if back.Target == owner.Name && (back.Type == "one_to_many" || back.Type == "many_to_many") {
+					rel.Type, back.Type = "many_to_many", "many_to_many"
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+const importedModels = `package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Base holds the columns shared by every model
+type Base struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Tier string
+
+const (
+	TierStandard Tier = "standard"
+	TierGold     Tier = "gold"
+)
+
+// Customer buys orders
+type Customer struct {
+	Base
+	Name   string         ` + "`json:\"name\" validate:\"required,min=2,max=50\"`" + `
+	Email  string         ` + "`json:\"email\" validate:\"required,email\"`" + `
+	Tier   Tier           ` + "`json:\"tier\"`" + `
+	Phone  sql.NullString ` + "`json:\"phone\"`" + `
+	Orders []Order
+}
+
+type Order struct {
+	Base
+	CustomerID string  ` + "`json:\"customer_id\" validate:\"required\"`" + `
+	Total      float64 ` + "`json:\"total\" validate:\"min=0\"`" + `
+}
+`
+
+func TestImportGoStructs(t *testing.T) {
+	s := NewOrchestratorService()
+	entities, err := s.ImportGoStructs(&domain.StructImportRequest{
+		Files:    []domain.SourceFile{{Name: "models.go", Content: importedModels}},
+		Features: []string{"crud", "validation", "rest_api", "testing"},
+	}, "")
+	if err != nil {
+		t.Fatalf("ImportGoStructs() error = %v", err)
+	}
+	if len(entities) != 2 || entities[0].Name != "Customer" || entities[1].Name != "Order" {
+		t.Fatalf("entities = %+v, want Customer and Order", entities)
+	}
+
+	fields := make(map[string]domain.FieldSpecification)
+	for _, field := range entities[0].Fields {
+		fields[field.Name] = field
+	}
+	if name := fields["name"]; !name.Required || !reflect.DeepEqual(name.Validation, []string{"min:2", "max:50"}) {
+		t.Errorf("name = %+v, want required with min:2 and max:50", name)
+	}
+	if tier := fields["tier"]; tier.Type != "enum" || !reflect.DeepEqual(tier.Enum, []string{"standard", "gold"}) {
+		t.Errorf("tier = %+v, want an enum of the Tier constants", tier)
+	}
+	if phone := fields["phone"]; phone.Type != "string" || !phone.Nullable {
+		t.Errorf("phone = %+v, want a nullable string", phone)
+	}
+	if orders := entities[0].Relationships; len(orders) != 1 || orders[0].Type != "one_to_many" || orders[0].ForeignKey != "customerID" {
+		t.Errorf("Customer relationships = %+v, want one_to_many Order through customerID", orders)
+	}
+	if customer := entities[1].Relationships; len(customer) != 1 || customer[0].Type != "belongs_to" || customer[0].Target != "Customer" {
+		t.Errorf("Order relationships = %+v, want belongs_to Customer", customer)
+	}
+
+	// The imported entities are ready to be generated
+	checkGeneratedProject(t, &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Entities:    entities,
+	})
+}
@@ -0,0 +1,385 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// tenancyScopes are the values of the "tenancy" option of projects and entities: "tenant" scopes
+// the entities to a tenant, "tenant_client" to a client of a tenant, and "none" opts an entity
+// out of the tenancy of its project
+var tenancyScopes = map[string]bool{"tenant": true, "tenant_client": true, "none": true}
+
+// tenancy returns the scope of an entity, "tenant" or "tenant_client", or "" when every tenant
+// sees its entities. Options["tenancy"] of the entity overrides that of the project.
+func (s *OrchestratorService) tenancy(entity domain.EntitySpecification, spec *domain.ProjectSpecification) string {
+	scope, ok := entity.Options["tenancy"]
+	if !ok {
+		scope = spec.Options["tenancy"]
+	}
+	if scope == "tenant" || scope == "tenant_client" {
+		return scope
+	}
+	return ""
+}
+
+// usesTenancy reports whether the project has tenant-scoped entities
+func (s *OrchestratorService) usesTenancy(spec *domain.ProjectSpecification) bool {
+	for _, entity := range spec.Entities {
+		if s.tenancy(entity, spec) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantFields returns the struct fields that scope an entity to its tenant
+func (s *OrchestratorService) tenantFields(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.FieldElement {
+	switch s.tenancy(entity, spec) {
+	case "tenant":
+		return []domain.FieldElement{{Name: "TenantID", Type: "string", Tags: `json:"tenant_id" db:"tenant_id"`}}
+	case "tenant_client":
+		return []domain.FieldElement{
+			{Name: "TenantID", Type: "string", Tags: `json:"tenant_id" db:"tenant_id"`},
+			{Name: "ClientID", Type: "string", Tags: `json:"client_id" db:"client_id"`},
+		}
+	}
+	return nil
+}
+
+// tenantColumns returns the columns scoping the rows of an entity table to their tenant
+func (s *OrchestratorService) tenantColumns(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []string {
+	switch s.tenancy(entity, spec) {
+	case "tenant":
+		return []string{"tenant_id"}
+	case "tenant_client":
+		return []string{"tenant_id", "client_id"}
+	}
+	return nil
+}
+
+// rowLevelSecuritySQL renders the row-level security policy restricting the rows of a table to
+// the tenant (and client) set for the transaction with set_config('app.tenant_id', ...)
+func (s *OrchestratorService) rowLevelSecuritySQL(table string, columns []string) string {
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("%s = NULLIF(current_setting('app.%s', true), '')::uuid", column, column)
+	}
+	condition := strings.Join(conditions, " AND ")
+	return fmt.Sprintf(`-- Rows are visible to the tenant set with set_config('app.tenant_id', ...); FORCE applies the
+-- policy to the table owner too, so that the service cannot skip it
+ALTER TABLE %[1]s ENABLE ROW LEVEL SECURITY;
+ALTER TABLE %[1]s FORCE ROW LEVEL SECURITY;
+CREATE POLICY %[1]s_tenant_isolation ON %[1]s
+    USING (%[2]s)
+    WITH CHECK (%[2]s);`, table, condition)
+}
+
+// generateTenantElement generates the tenant of the domain, carried by contexts to the
+// repositories of tenant-scoped entities
+func (s *OrchestratorService) generateTenantElement() domain.CodeElement {
+	content := `package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTenantRequired is returned by the repositories of tenant-scoped entities for contexts
+	// without a tenant
+	ErrTenantRequired = errors.New("tenant required")
+	// ErrInvalidTenant is returned for tenants whose IDs are not UUIDs, the type of the tenant
+	// columns and of the tenant set for row-level security
+	ErrInvalidTenant = errors.New("tenant and client IDs must be UUIDs")
+)
+
+// Tenant scopes the entities a request sees: tenant-scoped entities are visible to their tenant
+// only, and client-scoped ones to their client of the tenant only
+type Tenant struct {
+	ID       string
+	ClientID string
+}
+
+// Validate returns ErrInvalidTenant unless the ID, and the client ID when set, are UUIDs in their
+// canonical form
+func (t Tenant) Validate() error {
+	if !isUUID(t.ID) || t.ClientID != "" && !isUUID(t.ClientID) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// isUUID reports whether id is a UUID in its canonical form
+func isUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil && len(id) == 36
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok && tenant.ID != ""
+}
+
+// TenantScope returns the tenant the queries of ctx are scoped to, or ErrTenantRequired when ctx
+// has no tenant, or no client when withClient is set
+func TenantScope(ctx context.Context, withClient bool) (Tenant, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok || withClient && tenant.ClientID == "" {
+		return Tenant{}, ErrTenantRequired
+	}
+	return tenant, nil
+}
+`
+	return s.newFileElement("Tenant", "domain", "internal/domain/tenant.go", content)
+}
+
+// generateTenantMiddlewareElement generates the middleware resolving the tenant of requests from
+// the claims of their token and from headers
+func (s *OrchestratorService) generateTenantMiddlewareElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package middleware
+
+import (
+	"net/http"
+
+	"` + spec.ModulePath + `/internal/domain"
+)
+
+// TenantResolver returns the tenant a request names, if any
+type TenantResolver func(r *http.Request) (domain.Tenant, bool)
+
+// TenantFromClaims resolves the tenant from the tenantClaim and clientClaim of the token of the
+// authenticated principal
+func TenantFromClaims(tenantClaim, clientClaim string) TenantResolver {
+	return func(r *http.Request) (domain.Tenant, bool) {
+		principal, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			return domain.Tenant{}, false
+		}
+		tenantID, _ := principal.Claims[tenantClaim].(string)
+		clientID, _ := principal.Claims[clientClaim].(string)
+		return domain.Tenant{ID: tenantID, ClientID: clientID}, tenantID != ""
+	}
+}
+
+// TenantFromHeaders resolves the tenant from the tenantHeader and clientHeader of a request
+func TenantFromHeaders(tenantHeader, clientHeader string) TenantResolver {
+	return func(r *http.Request) (domain.Tenant, bool) {
+		tenant := domain.Tenant{ID: r.Header.Get(tenantHeader), ClientID: r.Header.Get(clientHeader)}
+		return tenant, tenant.ID != ""
+	}
+}
+
+// ResolveTenant stores the tenant the resolvers name in the context of each request. Requests
+// whose resolvers name different tenants or clients, such as a header contradicting the token,
+// are answered with 403, and those naming IDs that are not UUIDs with 400. Requests naming no
+// tenant pass without one: the repositories of tenant-scoped entities reject them with
+// domain.ErrTenantRequired.
+func ResolveTenant(resolvers ...TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tenant domain.Tenant
+			for _, resolve := range resolvers {
+				named, ok := resolve(r)
+				if !ok {
+					continue
+				}
+				if tenant.ID != "" && named.ID != tenant.ID || tenant.ClientID != "" && named.ClientID != "" && named.ClientID != tenant.ClientID {
+					writeError(w, http.StatusForbidden, "conflicting tenants")
+					return
+				}
+				tenant.ID = named.ID
+				if tenant.ClientID == "" {
+					tenant.ClientID = named.ClientID
+				}
+			}
+			if tenant.ID != "" {
+				if err := tenant.Validate(); err != nil {
+					writeError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				r = r.WithContext(domain.WithTenant(r.Context(), tenant))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+`
+	return s.newFileElement("TenantMiddleware", "middleware", "internal/interfaces/http/middleware/tenant.go", content)
+}
+
+// generateTenantMiddlewareTestElement tests the resolution of tenants from claims and headers
+func (s *OrchestratorService) generateTenantMiddlewareTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"` + spec.ModulePath + `/internal/domain"
+	"` + spec.ModulePath + `/internal/interfaces/http/middleware"
+)
+
+// Tenants and clients are UUIDs, the type of the tenant columns
+const (
+	t1 = "00000000-0000-4000-a000-000000000001"
+	t2 = "00000000-0000-4000-a000-000000000002"
+	c1 = "00000000-0000-4000-b000-000000000001"
+	c2 = "00000000-0000-4000-b000-000000000002"
+)
+
+func TestResolveTenant(t *testing.T) {
+	resolve := middleware.ResolveTenant(
+		middleware.TenantFromClaims("tenant_id", "client_id"),
+		middleware.TenantFromHeaders("X-Tenant-ID", "X-Client-ID"),
+	)
+	tests := []struct {
+		name       string
+		claims     map[string]interface{}
+		headers    map[string]string
+		wantStatus int
+		want       domain.Tenant
+	}{
+		{"claims", map[string]interface{}{"tenant_id": t1, "client_id": c1}, nil, http.StatusOK, domain.Tenant{ID: t1, ClientID: c1}},
+		{"headers", nil, map[string]string{"X-Tenant-ID": t1, "X-Client-ID": c1}, http.StatusOK, domain.Tenant{ID: t1, ClientID: c1}},
+		{"client header of the token tenant", map[string]interface{}{"tenant_id": t1}, map[string]string{"X-Tenant-ID": t1, "X-Client-ID": c1}, http.StatusOK, domain.Tenant{ID: t1, ClientID: c1}},
+		{"header contradicting the token", map[string]interface{}{"tenant_id": t1}, map[string]string{"X-Tenant-ID": t2}, http.StatusForbidden, domain.Tenant{}},
+		{"client header contradicting the token", map[string]interface{}{"tenant_id": t1, "client_id": c1}, map[string]string{"X-Client-ID": c2, "X-Tenant-ID": t1}, http.StatusForbidden, domain.Tenant{}},
+		{"tenant that is not a UUID", nil, map[string]string{"X-Tenant-ID": "t1"}, http.StatusBadRequest, domain.Tenant{}},
+		{"client that is not a UUID", nil, map[string]string{"X-Tenant-ID": t1, "X-Client-ID": "' OR 1=1"}, http.StatusBadRequest, domain.Tenant{}},
+		{"no tenant", nil, nil, http.StatusOK, domain.Tenant{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.claims != nil {
+				req = req.WithContext(middleware.WithPrincipal(req.Context(), &middleware.Principal{Claims: tt.claims}))
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			var got domain.Tenant
+			rec := httptest.NewRecorder()
+			resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = domain.TenantFromContext(r.Context())
+			})).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got != tt.want {
+				t.Errorf("tenant = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+`
+	return s.newFileElement("TenantMiddlewareTest", "middleware_test", "internal/interfaces/http/middleware/tenant_test.go", content)
+}
+
+// generateGRPCTenantElement generates the interceptor resolving the tenant of gRPC calls from
+// their metadata and, with security, the claims of the authenticated principal
+func (s *OrchestratorService) generateGRPCTenantElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	imports := map[string]bool{
+		"context": true, "google.golang.org/grpc": true, "google.golang.org/grpc/codes": true,
+		"google.golang.org/grpc/metadata": true, "google.golang.org/grpc/status": true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+	claims := ""
+	if s.usesSecurity(spec) {
+		imports[spec.ModulePath+"/internal/interfaces/http/middleware"] = true
+		claims = `
+// TenantFromClaims resolves the tenant from the tenantClaim and clientClaim of the principal
+// AuthInterceptor authenticated
+func TenantFromClaims(tenantClaim, clientClaim string) TenantResolver {
+	return func(ctx context.Context) (domain.Tenant, bool) {
+		principal, ok := middleware.PrincipalFromContext(ctx)
+		if !ok {
+			return domain.Tenant{}, false
+		}
+		tenantID, _ := principal.Claims[tenantClaim].(string)
+		clientID, _ := principal.Claims[clientClaim].(string)
+		return domain.Tenant{ID: tenantID, ClientID: clientID}, tenantID != ""
+	}
+}
+`
+	}
+
+	content := fmt.Sprintf(`package servers
+
+import (
+%s)
+
+// TenantResolver returns the tenant a call names, if any
+type TenantResolver func(ctx context.Context) (domain.Tenant, bool)
+
+// TenantFromMetadata resolves the tenant from the tenantKey and clientKey metadata of a call
+func TenantFromMetadata(tenantKey, clientKey string) TenantResolver {
+	return func(ctx context.Context) (domain.Tenant, bool) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		tenant := domain.Tenant{ID: firstValue(md.Get(tenantKey)), ClientID: firstValue(md.Get(clientKey))}
+		return tenant, tenant.ID != ""
+	}
+}
+%s
+// TenantInterceptor stores the tenant the resolvers name in the context of each call, as
+// middleware.ResolveTenant does for HTTP requests. Calls whose resolvers name different tenants
+// or clients fail with PermissionDenied, and those naming IDs that are not UUIDs with
+// InvalidArgument. Calls naming no tenant pass without one: the repositories of tenant-scoped
+// entities reject them with domain.ErrTenantRequired.
+func TenantInterceptor(resolvers ...TenantResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var tenant domain.Tenant
+		for _, resolve := range resolvers {
+			named, ok := resolve(ctx)
+			if !ok {
+				continue
+			}
+			if tenant.ID != "" && named.ID != tenant.ID || tenant.ClientID != "" && named.ClientID != "" && named.ClientID != tenant.ClientID {
+				return nil, status.Error(codes.PermissionDenied, "conflicting tenants")
+			}
+			tenant.ID = named.ID
+			if tenant.ClientID == "" {
+				tenant.ClientID = named.ClientID
+			}
+		}
+		if tenant.ID != "" {
+			if err := tenant.Validate(); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			ctx = domain.WithTenant(ctx, tenant)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+`, s.formatImports(imports), claims)
+	return s.newFileElement("tenant", "servers", "internal/interfaces/grpc/servers/tenant.go", content)
+}
+
+// testContext returns the Go expression of the context generated tests call the repositories
+// of an entity with: that of the tenant of the fixtures for tenant-scoped entities
+func (s *OrchestratorService) testContext(entity domain.EntitySpecification, spec *domain.ProjectSpecification) string {
+	if s.tenancy(entity, spec) != "" {
+		return "fixtures.WithTenant(context.Background())"
+	}
+	return "context.Background()"
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedTenantResolutionRequiresUUIDs(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Options:     map[string]string{"tenancy": "tenant_client"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api", "grpc_api"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+		}},
+	}
+	withProtocGenGo(t)
+	s := NewOrchestratorService()
+
+	for _, element := range []domain.CodeElement{s.generateTenantMiddlewareElement(spec), s.generateGRPCTenantElement(spec)} {
+		if !strings.Contains(element.Body, "tenant.Validate()") {
+			t.Errorf("%s does not validate the tenant:\n%s", element.Metadata["path"], element.Body)
+		}
+	}
+	// The generated TestResolveTenant covers the IDs that are not UUIDs
+	checkGeneratedProject(t, spec)
+}
+
+func TestTenancyImpliesRowLevelSecurityMigrations(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:          "shop",
+		ModulePath:    "example.com/shop",
+		ProjectType:   "microservice",
+		Features:      []string{"config"},
+		Options:       map[string]string{"tenancy": "tenant"},
+		Configuration: domain.ProjectConfiguration{Database: &domain.DatabaseConfiguration{Type: "postgres", Host: "localhost", Database: "shop"}},
+		Entities: []domain.EntitySpecification{
+			{
+				Name:     "Order",
+				Features: []string{"rest_api", "service", "events", "cache"},
+				Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer", Required: true}},
+			},
+			{
+				Name:     "Country",
+				Features: []string{"crud"},
+				Options:  map[string]string{"tenancy": "none"},
+				Fields:   []domain.FieldSpecification{{Name: "code", Type: "string", Required: true}},
+			},
+		},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	orders, err := os.ReadFile(filepath.Join(dir, "migrations", "000001_create_orders.up.sql"))
+	if err != nil {
+		t.Fatalf("the table of Order is not migrated: %v", err)
+	}
+	if !strings.Contains(string(orders), "CREATE POLICY orders_tenant_isolation ON orders") {
+		t.Errorf("the migration of Order does not isolate the tenants:\n%s", orders)
+	}
+	countries, err := os.ReadFile(filepath.Join(dir, "migrations", "000002_create_countries.up.sql"))
+	if err != nil {
+		t.Fatalf("the table of Country is not migrated: %v", err)
+	}
+	if strings.Contains(string(countries), "ROW LEVEL SECURITY") {
+		t.Errorf("the migration of the unscoped Country isolates tenants:\n%s", countries)
+	}
+}
@@ -0,0 +1,433 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// generateDomainErrorsElement generates the sentinel errors shared by repository implementations
+func (s *OrchestratorService) generateDomainErrorsElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	conflict := ""
+	if s.anyEntity(spec, s.versioned) {
+		conflict = "\t// ErrConflict is returned for updates of entities changed since the version they were read at\n\tErrConflict = errors.New(\"version conflict\")\n"
+	}
+	content := fmt.Sprintf(`package domain
+
+import "errors"
+
+// Sentinel errors returned by repository implementations
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+%s)
+`, conflict)
+
+	return s.newFileElement("errors", "domain", "internal/domain/errors.go", content)
+}
+
+// generateMockRecorderElement generates the call recorder embedded by every generated mock
+func (s *OrchestratorService) generateMockRecorderElement() domain.CodeElement {
+	content := `package mocks
+
+import "sync"
+
+// Call is a single recorded invocation of a mock method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records calls made to a mock. It is embedded by every generated mock.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Record appends a call to the recording
+func (r *Recorder) Record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in invocation order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallsTo returns the recorded calls of a single method
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CallCount returns how many times a method was called
+func (r *Recorder) CallCount(method string) int {
+	return len(r.CallsTo(method))
+}
+
+// Reset clears all recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+`
+
+	return s.newFileElement("recorder", "mocks", "internal/mocks/recorder.go", content)
+}
+
+// generateInMemoryRepositoryElement generates a concurrency-safe in-memory repository
+func (s *OrchestratorService) generateInMemoryRepositoryElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	imports := map[string]bool{
+		"context": true, "sort": true, "sync": true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	// Tenant-scoped repositories keep the entities of every tenant and show each tenant its own,
+	// and soft-deleting ones keep deleted entities out of sight until restored
+	var scope, scopeNil, hidden, owns, onCreate, onUpdate, restore string
+	if scoped := s.tenancy(entity, spec); scoped != "" {
+		withClient := scoped == "tenant_client"
+		scope = fmt.Sprintf("\ttenant, err := domain.TenantScope(ctx, %t)\n\tif err != nil {\n\t\treturn err\n\t}\n", withClient)
+		scopeNil = fmt.Sprintf("\ttenant, err := domain.TenantScope(ctx, %t)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", withClient)
+		onCreate = "\titem.TenantID = tenant.ID\n"
+		condition := "item.TenantID == tenant.ID"
+		if withClient {
+			onCreate += "\titem.ClientID = tenant.ClientID\n"
+			condition += " && item.ClientID == tenant.ClientID"
+		}
+		onUpdate = onCreate
+		hidden = " || !r.owns(stored, tenant)"
+		owns = fmt.Sprintf(`
+// owns reports whether the stored %[1]s belongs to the tenant
+func (r *%[1]sRepository) owns(item *domain.%[1]s, tenant domain.Tenant) bool {
+	return %[2]s
+}
+`, name, condition)
+	}
+	owned := hidden
+	if s.audited(entity) {
+		onCreate += "\titem.CreatedBy = domain.ActorFromContext(ctx)\n\titem.UpdatedBy = item.CreatedBy\n"
+		onUpdate += "\titem.CreatedBy = stored.CreatedBy\n\titem.UpdatedBy = domain.ActorFromContext(ctx)\n"
+	}
+	if s.softDeletes(entity) {
+		imports["time"] = true
+		hidden += " || stored.DeletedAt != nil"
+		onCreate += "\titem.DeletedAt = nil\n"
+		onUpdate += "\titem.DeletedAt = nil\n"
+	}
+	if s.versioned(entity) {
+		onCreate += "\titem.Version = 1\n"
+		onUpdate = "\tif item.Version != stored.Version {\n\t\treturn domain.ErrConflict\n\t}\n" + onUpdate + "\titem.Version = stored.Version + 1\n"
+	}
+
+	lookup := fmt.Sprintf("\tif _, exists := r.items[item.%s]; !exists {\n", id)
+	if hidden != "" || onUpdate != "" {
+		lookup = fmt.Sprintf("\tstored, exists := r.items[item.%s]\n\tif !exists%s {\n", id, hidden)
+	}
+	remove := "\tif _, exists := r.items[id]; !exists {\n\t\treturn domain.ErrNotFound\n\t}\n\tdelete(r.items, id)\n"
+	listed := ""
+	if hidden != "" {
+		remove = fmt.Sprintf("\tstored, exists := r.items[id]\n\tif !exists%s {\n\t\treturn domain.ErrNotFound\n\t}\n\tdelete(r.items, id)\n", hidden)
+		listed = fmt.Sprintf("\t\tif %s {\n\t\t\tcontinue\n\t\t}\n", strings.TrimPrefix(hidden, " || "))
+	}
+	if s.softDeletes(entity) {
+		remove = fmt.Sprintf(`	stored, exists := r.items[id]
+	if !exists%s {
+		return domain.ErrNotFound
+	}
+	deleted := *stored
+	now := time.Now()
+	deleted.DeletedAt = &now
+	r.items[id] = &deleted
+`, hidden)
+		restore = fmt.Sprintf(`
+// Restore brings back the deleted %[1]s with the given ID
+func (r *%[1]sRepository) Restore(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+%[2]s
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.items[id]
+	if !exists%[3]s || stored.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+	restored := *stored
+	restored.DeletedAt = nil
+	r.items[id] = &restored
+	return nil
+}
+`, name, scope, owned)
+	}
+
+	content := fmt.Sprintf(`package memory
+
+import (
+%[1]s)
+
+// %[2]sRepository is a concurrency-safe in-memory implementation of domain.%[2]sRepository.
+// Entities are copied on the way in and out, so callers can't mutate stored state.
+type %[2]sRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.%[2]s
+}
+
+var _ domain.%[2]sRepository = (*%[2]sRepository)(nil)
+
+// New%[2]sRepository creates an empty in-memory %[2]s repository
+func New%[2]sRepository(seed ...*domain.%[2]s) *%[2]sRepository {
+	r := &%[2]sRepository{items: make(map[string]*domain.%[2]s)}
+	for _, item := range seed {
+		stored := *item
+		r.items[item.%[3]s] = &stored
+	}
+	return r
+}
+
+// Create stores a new %[2]s
+func (r *%[2]sRepository) Create(ctx context.Context, item *domain.%[2]s) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+%[4]s
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.items[item.%[3]s]; exists {
+		return domain.ErrAlreadyExists
+	}
+%[7]s	stored := *item
+	r.items[item.%[3]s] = &stored
+	return nil
+}
+
+// GetByID returns the %[2]s with the given ID
+func (r *%[2]sRepository) GetByID(ctx context.Context, id string) (*domain.%[2]s, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+%[5]s
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.items[id]
+	if !exists%[6]s {
+		return nil, domain.ErrNotFound
+	}
+	item := *stored
+	return &item, nil
+}
+
+// Update replaces an existing %[2]s
+func (r *%[2]sRepository) Update(ctx context.Context, item *domain.%[2]s) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+%[4]s
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+%[9]s		return domain.ErrNotFound
+	}
+%[8]s	updated := *item
+	r.items[item.%[3]s] = &updated
+	return nil
+}
+
+// Delete removes the %[2]s with the given ID
+func (r *%[2]sRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+%[4]s
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+%[10]s	return nil
+}
+
+// List returns the stored %[2]s entities ordered by ID
+func (r *%[2]sRepository) List(ctx context.Context) ([]*domain.%[2]s, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+%[5]s
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]*domain.%[2]s, 0, len(r.items))
+	for _, stored := range r.items {
+%[11]s		item := *stored
+		items = append(items, &item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].%[3]s < items[j].%[3]s })
+	return items, nil
+}
+%[12]s%[13]s`, s.formatImports(imports), name, id, scope, scopeNil, hidden, onCreate, onUpdate, lookup, remove, listed, restore, owns)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sMemoryRepository", name),
+		"memory",
+		fmt.Sprintf("internal/infrastructure/memory/%s_repository.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// generateInterfaceMockElement generates a configurable mock for a domain interface.
+// Every method records its call, then returns the next scripted result if one is queued,
+// otherwise delegates to the optional <Method>Func field, otherwise returns zero values.
+func (s *OrchestratorService) generateInterfaceMockElement(iface domain.CodeElement, spec *domain.ProjectSpecification) domain.CodeElement {
+	mockName := iface.Name + "Mock"
+
+	var fields, scripts, methods strings.Builder
+	imports := map[string]bool{
+		"sync":                               true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	for _, method := range iface.Methods {
+		var params, paramNames []string
+		for _, param := range method.Parameters {
+			paramType := s.qualifyDomainType(param.Type)
+			if strings.HasPrefix(paramType, "context.") {
+				imports["context"] = true
+			}
+			params = append(params, fmt.Sprintf("%s %s", param.Name, paramType))
+			paramNames = append(paramNames, param.Name)
+		}
+
+		var returnTypes, resultFields, resultValues, zeroValues []string
+		valueCount := 0
+		for _, ret := range method.Returns {
+			if ret.Type != "error" {
+				valueCount++
+			}
+		}
+		for i, ret := range method.Returns {
+			retType := s.qualifyDomainType(ret.Type)
+			returnTypes = append(returnTypes, retType)
+			zeroValues = append(zeroValues, zeroValueOf(retType))
+
+			fieldName := "Err"
+			if ret.Type != "error" {
+				fieldName = "Value"
+				if valueCount > 1 {
+					fieldName = fmt.Sprintf("Value%d", i)
+				}
+			}
+			resultFields = append(resultFields, fmt.Sprintf("%s %s", fieldName, retType))
+			resultValues = append(resultValues, "result."+fieldName)
+		}
+
+		signature := fmt.Sprintf("(%s)", strings.Join(params, ", "))
+		switch len(returnTypes) {
+		case 0:
+		case 1:
+			signature += " " + returnTypes[0]
+		default:
+			signature += fmt.Sprintf(" (%s)", strings.Join(returnTypes, ", "))
+		}
+
+		fmt.Fprintf(&fields, "\t%sFunc func%s\n", method.Name, signature)
+
+		recordArgs := ""
+		if len(paramNames) > 0 {
+			recordArgs = ", " + strings.Join(paramNames, ", ")
+		}
+
+		if len(returnTypes) == 0 {
+			fmt.Fprintf(&methods, `
+// %[2]s records the call and delegates to %[2]sFunc when set
+func (m *%[1]s) %[2]s%[3]s {
+	m.Record(%[2]q%[4]s)
+	if m.%[2]sFunc != nil {
+		m.%[2]sFunc(%[5]s)
+	}
+}
+`, mockName, method.Name, signature, recordArgs, strings.Join(paramNames, ", "))
+			continue
+		}
+
+		resultType := fmt.Sprintf("%s%sResult", iface.Name, method.Name)
+		queue := s.lowerFirst(method.Name) + "Results"
+
+		fmt.Fprintf(&scripts, "\t%s []%s\n", queue, resultType)
+
+		fmt.Fprintf(&methods, `
+// %[6]s is a scripted return value for %[1]s.%[2]s
+type %[6]s struct {
+	%[7]s
+}
+
+// Script%[2]s queues results returned by subsequent %[2]s calls, in order
+func (m *%[1]s) Script%[2]s(results ...%[6]s) {
+	m.scriptMu.Lock()
+	defer m.scriptMu.Unlock()
+	m.%[8]s = append(m.%[8]s, results...)
+}
+
+// %[2]s records the call and returns the next scripted result, the result of %[2]sFunc, or zero values
+func (m *%[1]s) %[2]s%[3]s {
+	m.Record(%[2]q%[4]s)
+
+	m.scriptMu.Lock()
+	if len(m.%[8]s) > 0 {
+		result := m.%[8]s[0]
+		m.%[8]s = m.%[8]s[1:]
+		m.scriptMu.Unlock()
+		return %[9]s
+	}
+	m.scriptMu.Unlock()
+
+	if m.%[2]sFunc != nil {
+		return m.%[2]sFunc(%[5]s)
+	}
+	return %[10]s
+}
+`, mockName, method.Name, signature, recordArgs, strings.Join(paramNames, ", "),
+			resultType, strings.Join(resultFields, "\n\t"), queue,
+			strings.Join(resultValues, ", "), strings.Join(zeroValues, ", "))
+	}
+
+	content := fmt.Sprintf(`package mocks
+
+import (
+%[1]s)
+
+// %[2]s is a configurable mock of domain.%[3]s
+type %[2]s struct {
+	Recorder
+
+%[4]s
+	scriptMu sync.Mutex
+%[5]s}
+
+var _ domain.%[3]s = (*%[2]s)(nil)
+%[6]s`, s.formatImports(imports), mockName, iface.Name, fields.String(), scripts.String(), methods.String())
+
+	return s.newFileElement(
+		mockName,
+		"mocks",
+		fmt.Sprintf("internal/mocks/%s_mock.go", s.toSnakeCase(iface.Name)),
+		content,
+	)
+}
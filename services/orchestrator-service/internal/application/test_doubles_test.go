@@ -0,0 +1,114 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// orderDoublesTest uses the generated mock and in-memory repository of an entity
+const orderDoublesTest = `package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"example.com/shop/internal/domain"
+	"example.com/shop/internal/infrastructure/memory"
+)
+
+func TestOrderRepositoryMock(t *testing.T) {
+	ctx := context.Background()
+	mock := &OrderRepositoryMock{}
+	stored := &domain.Order{ID: "o-1", Total: 5}
+	mock.ScriptGetByID(OrderRepositoryGetByIDResult{Value: stored}, OrderRepositoryGetByIDResult{Err: domain.ErrNotFound})
+	mock.DeleteFunc = func(ctx context.Context, id string) error { return fmt.Errorf("delete %s", id) }
+
+	if got, err := mock.GetByID(ctx, "o-1"); got != stored || err != nil {
+		t.Errorf("first GetByID() = %v, %v, want the first scripted result", got, err)
+	}
+	if _, err := mock.GetByID(ctx, "o-2"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second GetByID() error = %v, want the second scripted result", err)
+	}
+	if got, err := mock.GetByID(ctx, "o-3"); got != nil || err != nil {
+		t.Errorf("unscripted GetByID() = %v, %v, want zero values", got, err)
+	}
+	if err := mock.Delete(ctx, "o-1"); err == nil || err.Error() != "delete o-1" {
+		t.Errorf("Delete() error = %v, want the result of DeleteFunc", err)
+	}
+
+	if n := mock.CallCount("GetByID"); n != 3 {
+		t.Errorf("CallCount(GetByID) = %d, want 3", n)
+	}
+	if calls := mock.CallsTo("Delete"); len(calls) != 1 || calls[0].Args[1] != "o-1" {
+		t.Errorf("CallsTo(Delete) = %+v, want one call with o-1", calls)
+	}
+	mock.Reset()
+	if calls := mock.Calls(); len(calls) != 0 {
+		t.Errorf("Calls() after Reset() = %+v", calls)
+	}
+}
+
+func TestOrderMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOrderRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := repo.Create(ctx, &domain.Order{ID: fmt.Sprintf("o-%02d", i), Total: i}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	items, err := repo.List(ctx)
+	if err != nil || len(items) != 50 || items[0].ID != "o-00" || items[49].ID != "o-49" {
+		t.Fatalf("List() = %d items, %v, want the 50 orders ordered by ID", len(items), err)
+	}
+
+	// Stored entities are copies, unaffected by changes of returned ones
+	items[0].Total = 1000
+	if got, _ := repo.GetByID(ctx, "o-00"); got.Total != 0 {
+		t.Errorf("GetByID() after changing a listed order = %+v", got)
+	}
+	if err := repo.Create(ctx, &domain.Order{ID: "o-00"}); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Errorf("Create() of an existing ID error = %v, want ErrAlreadyExists", err)
+	}
+	if err := repo.Delete(ctx, "o-00"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(ctx, "o-00"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID() of a deleted order error = %v, want ErrNotFound", err)
+	}
+}
+`
+
+func TestGeneratedTestDoubles(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"repository"},
+			Fields:   []domain.FieldSpecification{{Name: "total", Type: "integer"}},
+		}},
+	}
+
+	dir := generateProject(t, NewOrchestratorService(), spec)
+	test := filepath.Join(dir, "internal", "mocks", "order_doubles_test.go")
+	if err := os.WriteFile(test, []byte(orderDoublesTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkProject(t, dir)
+}
@@ -0,0 +1,703 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// validationCase is an invalid variation of a valid entity fixture
+type validationCase struct {
+	Name    string   // Test case name
+	Field   string   // Go field name to overwrite
+	Value   string   // Go expression assigned to the field
+	Imports []string // Imports needed by the value expression
+}
+
+// generateTestElements generates fixture builders and table-driven tests for the entity.
+// generated tells which feature groups ("repository", "validation", "handler") produced code.
+func (s *OrchestratorService) generateTestElements(entity domain.EntitySpecification, spec *domain.ProjectSpecification) []domain.CodeElement {
+	withValidation := s.hasFeature(entity.Features, "validation")
+	elements := []domain.CodeElement{
+		s.generateFixtureBuilderElement(entity, spec),
+		s.generateDomainTestElement(entity, spec, withValidation),
+	}
+
+	if s.hasHandler(entity) {
+		elements = append(elements, s.generateHandlerTestElement(entity, spec, withValidation))
+	}
+
+	return elements
+}
+
+// generateFixturesSupportElement generates the ID sequence shared by fixture builders, and the
+// tenant of the fixtures of tenant-scoped entities
+func (s *OrchestratorService) generateFixturesSupportElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := `package fixtures
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var sequence int64
+
+// NextID returns a unique, UUID-shaped identifier for fixtures
+func NextID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", atomic.AddInt64(&sequence, 1))
+}
+`
+	if s.usesTenancy(spec) {
+		content = `package fixtures
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"` + spec.ModulePath + `/internal/domain"
+)
+
+var sequence int64
+
+// NextID returns a unique, UUID-shaped identifier for fixtures
+func NextID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", atomic.AddInt64(&sequence, 1))
+}
+
+// Tenant owns the fixtures of tenant-scoped entities
+var Tenant = domain.Tenant{
+	ID:       "00000000-0000-4000-a000-000000000001",
+	ClientID: "00000000-0000-4000-a000-000000000002",
+}
+
+// WithTenant returns a copy of ctx carrying the Tenant of the fixtures
+func WithTenant(ctx context.Context) context.Context {
+	return domain.WithTenant(ctx, Tenant)
+}
+`
+	}
+
+	return s.newFileElement("fixtures", "fixtures", "internal/fixtures/fixtures.go", content)
+}
+
+// generateFixtureBuilderElement generates a builder producing valid entity fixtures
+func (s *OrchestratorService) generateFixtureBuilderElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	imports := map[string]bool{
+		"time":                               true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	var values, setters strings.Builder
+	if id == "ID" {
+		fmt.Fprintf(&values, "\t\tID: NextID(),\n")
+		fmt.Fprintf(&setters, `
+// WithID sets the ID of the built %[1]s
+func (b *%[1]sBuilder) WithID(value string) *%[1]sBuilder {
+	b.item.ID = value
+	return b
+}
+`, name)
+	}
+	// Tenant-scoped fixtures belong to the Tenant of the fixtures
+	for _, field := range s.tenantFields(entity, spec) {
+		value := "Tenant.ID"
+		if field.Name == "ClientID" {
+			value = "Tenant.ClientID"
+		}
+		fmt.Fprintf(&values, "\t\t%s: %s,\n", field.Name, value)
+	}
+	// Versioned fixtures are as created, at their first version
+	if s.versioned(entity) {
+		values.WriteString("\t\tVersion: 1,\n")
+	}
+
+	for _, field := range entity.Fields {
+		fieldName := s.capitalizeFirst(field.Name)
+		goType := s.qualifyDomainType(s.fieldGoType(entity, field))
+		for _, imp := range s.fieldImports([]domain.FieldSpecification{field}) {
+			imports[imp] = true
+		}
+
+		value, valueImports := s.sampleValue(entity, field)
+		if fieldName == id && goType == "string" {
+			value, valueImports = "NextID()", nil
+		}
+		for _, imp := range valueImports {
+			imports[imp] = true
+		}
+
+		fmt.Fprintf(&values, "\t\t%s: %s,\n", fieldName, value)
+		fmt.Fprintf(&setters, `
+// With%[2]s sets the %[2]s of the built %[1]s
+func (b *%[1]sBuilder) With%[2]s(value %[3]s) *%[1]sBuilder {
+	b.item.%[2]s = value
+	return b
+}
+`, name, fieldName, goType)
+	}
+
+	content := fmt.Sprintf(`package fixtures
+
+import (
+%[1]s)
+
+// %[2]sBuilder builds valid %[2]s fixtures for tests
+type %[2]sBuilder struct {
+	item domain.%[2]s
+}
+
+// New%[2]sBuilder returns a builder pre-populated with valid sample values
+func New%[2]sBuilder() *%[2]sBuilder {
+	now := time.Now()
+	return &%[2]sBuilder{item: domain.%[2]s{
+%[3]s		CreatedAt: now,
+		UpdatedAt: now,
+	}}
+}
+%[4]s
+// Build returns a copy of the built %[2]s
+func (b *%[2]sBuilder) Build() *domain.%[2]s {
+	item := b.item
+	return &item
+}
+`, s.formatImports(imports), name, values.String(), setters.String())
+
+	return s.newFileElement(
+		fmt.Sprintf("%sBuilder", name),
+		"fixtures",
+		fmt.Sprintf("internal/fixtures/%s_builder.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// generateDomainTestElement generates table-driven tests for the entity constructor and validation
+func (s *OrchestratorService) generateDomainTestElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification, withValidation bool) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	imports := map[string]bool{
+		"reflect":                            true,
+		"testing":                            true,
+		spec.ModulePath + "/internal/domain": true,
+	}
+
+	// Sample values are bound once so that non-deterministic faker expressions compare equal
+	var args []string
+	var wants, checks strings.Builder
+	for _, field := range entity.Fields {
+		if !field.Required || strings.ToLower(field.Name) == "id" {
+			continue
+		}
+		value, valueImports := s.sampleValue(entity, field)
+		for _, imp := range valueImports {
+			imports[imp] = true
+		}
+		fieldName := s.capitalizeFirst(field.Name)
+		args = append(args, "want"+fieldName)
+		fmt.Fprintf(&wants, "\twant%s := %s\n", fieldName, value)
+		fmt.Fprintf(&checks, "\t\t{%q, got.%s, want%s},\n", fieldName, fieldName, fieldName)
+	}
+
+	content := fmt.Sprintf(`
+func TestNew%[1]s(t *testing.T) {
+%[5]s	got := domain.New%[1]s(%[2]s)
+
+	if got.%[3]s == "" {
+		t.Error("expected %[3]s to be generated")
+	}
+	if got.CreatedAt.IsZero() || got.UpdatedAt.IsZero() {
+		t.Error("expected timestamps to be set")
+	}
+
+	tests := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+%[4]s	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%%s = %%v, want %%v", tt.field, tt.got, tt.want)
+		}
+	}
+}
+`, name, strings.Join(args, ", "), id, checks.String(), wants.String())
+
+	if withValidation {
+		imports[spec.ModulePath+"/internal/fixtures"] = true
+
+		var cases strings.Builder
+		for _, tc := range s.validationCases(entity) {
+			for _, imp := range tc.Imports {
+				imports[imp] = true
+			}
+			fmt.Fprintf(&cases, "\t\t{name: %q, modify: func(item *domain.%s) { item.%s = %s }, wantErr: true},\n",
+				tc.Name, name, tc.Field, tc.Value)
+		}
+
+		content += fmt.Sprintf(`
+func TestValidate%[1]s(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(item *domain.%[1]s)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*domain.%[1]s) {}, wantErr: false},
+%[2]s	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := fixtures.New%[1]sBuilder().Build()
+			tt.modify(item)
+
+			err := domain.Validate%[1]s(item)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate%[1]s() error = %%v, wantErr %%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+`, name, cases.String())
+	}
+
+	content = fmt.Sprintf("package domain_test\n\nimport (\n%s)\n%s", s.formatImports(imports), content)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sTest", name),
+		"domain_test",
+		fmt.Sprintf("internal/domain/%s_test.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// generateHandlerTestElement generates httptest-based tests for the entity HTTP handler
+func (s *OrchestratorService) generateHandlerTestElement(entity domain.EntitySpecification, spec *domain.ProjectSpecification, withValidation bool) domain.CodeElement {
+	name := entity.Name
+	id := s.idFieldName(entity)
+	path := s.resourcePath(entity)
+	imports := map[string]bool{
+		"encoding/json":                        true,
+		"net/http":                             true,
+		"net/http/httptest":                    true,
+		"strings":                              true,
+		"testing":                              true,
+		spec.ModulePath + "/internal/domain":   true,
+		spec.ModulePath + "/internal/fixtures": true,
+		spec.ModulePath + "/internal/infrastructure/memory":    true,
+		spec.ModulePath + "/internal/interfaces/http/handlers": true,
+	}
+
+	invalidCase := ""
+	if cases := s.validationCases(entity); withValidation && len(cases) > 0 {
+		tc := cases[0]
+		for _, imp := range tc.Imports {
+			imports[imp] = true
+		}
+		invalidCase = fmt.Sprintf(`
+	invalid := fixtures.New%sBuilder().Build()
+	invalid.%s = %s
+	tests = append(tests, struct {
+		name       string
+		body       string
+		wantStatus int
+	}{%q, mustMarshal(t, invalid), http.StatusBadRequest})
+`, name, tc.Field, tc.Value, tc.Name)
+	}
+
+	// Requests to tenant-scoped entities are those of the tenant of the fixtures
+	withTenant, tenantTest := "", ""
+	if s.tenancy(entity, spec) != "" {
+		withTenant = "\treq = req.WithContext(fixtures.WithTenant(req.Context()))\n"
+		tenantTest = fmt.Sprintf(`
+func Test%[1]sHandler_RequiresTenant(t *testing.T) {
+	rec := httptest.NewRecorder()
+	new%[1]sTestServer(fixtures.New%[1]sBuilder().Build()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, %[2]q, nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %%d, want %%d (body: %%s)", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
+`, name, path)
+	}
+	lifecycleTests := s.handlerLifecycleTests(entity)
+
+	content := fmt.Sprintf(`package handlers_test
+
+import (
+%[1]s)
+
+func new%[2]sTestServer(seed ...*domain.%[2]s) *http.ServeMux {
+	mux := http.NewServeMux()
+	handlers.New%[2]sHandler(memory.New%[2]sRepository(seed...)).RegisterRoutes(mux)
+	return mux
+}
+
+func serve%[2]s(mux *http.ServeMux, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+%[6]s	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test%[2]sHandler_Create(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid", mustMarshal(t, fixtures.New%[2]sBuilder().Build()), http.StatusCreated},
+		{"malformed body", "{", http.StatusBadRequest},
+	}
+%[5]s
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve%[2]s(new%[2]sTestServer(), http.MethodPost, %[3]q, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %%d, want %%d (body: %%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test%[2]sHandler_Get(t *testing.T) {
+	existing := fixtures.New%[2]sBuilder().Build()
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing", existing.%[4]s, http.StatusOK},
+		{"missing", "missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve%[2]s(new%[2]sTestServer(existing), http.MethodGet, %[3]q+"/"+tt.id, "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %%d, want %%d (body: %%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test%[2]sHandler_List(t *testing.T) {
+	mux := new%[2]sTestServer(fixtures.New%[2]sBuilder().Build(), fixtures.New%[2]sBuilder().Build())
+
+	rec := serve%[2]s(mux, http.MethodGet, %[3]q, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %%d, want %%d", rec.Code, http.StatusOK)
+	}
+
+	var items []domain.%[2]s
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatalf("failed to decode response: %%v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("len(items) = %%d, want 2", len(items))
+	}
+}
+
+func Test%[2]sHandler_Update(t *testing.T) {
+	existing := fixtures.New%[2]sBuilder().Build()
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing", existing.%[4]s, http.StatusOK},
+		{"missing", "missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve%[2]s(new%[2]sTestServer(existing), http.MethodPut, %[3]q+"/"+tt.id, mustMarshal(t, existing))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %%d, want %%d (body: %%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test%[2]sHandler_Delete(t *testing.T) {
+	existing := fixtures.New%[2]sBuilder().Build()
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing", existing.%[4]s, http.StatusNoContent},
+		{"missing", "missing", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve%[2]s(new%[2]sTestServer(existing), http.MethodDelete, %[3]q+"/"+tt.id, "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %%d, want %%d (body: %%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+%[7]s%[8]s`, s.formatImports(imports), name, path, id, invalidCase, withTenant, tenantTest, lifecycleTests)
+
+	return s.newFileElement(
+		fmt.Sprintf("%sHandlerTest", name),
+		"handlers_test",
+		fmt.Sprintf("internal/interfaces/http/handlers/%s_handler_test.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// handlerLifecycleTests returns the tests of the restore route of soft-deleted entities and of the
+// rejection of stale updates of versioned ones
+func (s *OrchestratorService) handlerLifecycleTests(entity domain.EntitySpecification) string {
+	name, path, id := entity.Name, s.resourcePath(entity), s.idFieldName(entity)
+	var b strings.Builder
+	if s.softDeletes(entity) {
+		fmt.Fprintf(&b, `
+func Test%[1]sHandler_Restore(t *testing.T) {
+	existing := fixtures.New%[1]sBuilder().Build()
+	mux := new%[1]sTestServer(existing)
+	target := %[2]q + "/" + existing.%[3]s
+
+	steps := []struct {
+		method, target string
+		wantStatus     int
+	}{
+		{http.MethodPost, target + "/restore", http.StatusNotFound},
+		{http.MethodDelete, target, http.StatusNoContent},
+		{http.MethodGet, target, http.StatusNotFound},
+		{http.MethodGet, %[2]q, http.StatusOK},
+		{http.MethodPost, target + "/restore", http.StatusOK},
+		{http.MethodGet, target, http.StatusOK},
+	}
+	for _, step := range steps {
+		rec := serve%[1]s(mux, step.method, step.target, "")
+		if rec.Code != step.wantStatus {
+			t.Fatalf("%%s %%s: status = %%d, want %%d (body: %%s)", step.method, step.target, rec.Code, step.wantStatus, rec.Body.String())
+		}
+		if step.method == http.MethodGet && step.target == %[2]q && strings.Contains(rec.Body.String(), existing.%[3]s) {
+			t.Errorf("List() after Delete() = %%s, want the deleted %[1]s left out", rec.Body.String())
+		}
+	}
+}
+`, name, path, id)
+	}
+	if s.versioned(entity) {
+		fmt.Fprintf(&b, `
+func Test%[1]sHandler_UpdateConflict(t *testing.T) {
+	existing := fixtures.New%[1]sBuilder().Build()
+	mux := new%[1]sTestServer(existing)
+	target := %[2]q + "/" + existing.%[3]s
+
+	rec := serve%[1]s(mux, http.MethodPut, target, mustMarshal(t, existing))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %%d, want %%d (body: %%s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var updated domain.%[1]s
+	if err := json.Unmarshal(rec.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("failed to decode response: %%v", err)
+	}
+	if updated.Version != existing.Version+1 {
+		t.Errorf("Version = %%d, want %%d", updated.Version, existing.Version+1)
+	}
+
+	// The update was made from the version the first update replaced
+	rec = serve%[1]s(mux, http.MethodPut, target, mustMarshal(t, existing))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("stale update status = %%d, want %%d (body: %%s)", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+}
+`, name, path, id)
+	}
+	return b.String()
+}
+
+// generateHandlerTestHelpersElement generates helpers shared by generated handler tests
+func (s *OrchestratorService) generateHandlerTestHelpersElement() domain.CodeElement {
+	content := `package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustMarshal(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal request body: %v", err)
+	}
+	return string(data)
+}
+`
+
+	return s.newFileElement("helpers_test", "handlers_test", "internal/interfaces/http/handlers/helpers_test.go", content)
+}
+
+// checkSamples returns an error naming the first field of the entity whose rules leave no valid
+// sample value for the generated fixtures and tests
+func (s *OrchestratorService) checkSamples(entity domain.EntitySpecification) error {
+	for _, field := range entity.Fields {
+		if s.isTypedEnum(field) || s.fieldType(field).Faker != "text" {
+			continue
+		}
+		if _, err := s.sampleString(field, s.sampleRules(field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleRules returns the rules of a field by kind, keeping the first rule of each kind
+func (s *OrchestratorService) sampleRules(field domain.FieldSpecification) map[string]fieldRule {
+	rules := make(map[string]fieldRule)
+	for _, rule := range s.fieldRules(field) {
+		if _, exists := rules[rule.Kind]; !exists {
+			rules[rule.Kind] = rule
+		}
+	}
+	return rules
+}
+
+// sampleValue returns a Go expression holding a valid sample value for the field,
+// along with the imports the expression needs
+func (s *OrchestratorService) sampleValue(entity domain.EntitySpecification, field domain.FieldSpecification) (string, []string) {
+	if s.isTypedEnum(field) {
+		return "domain." + s.enumConstName(entity, field, field.Enum[0]), nil
+	}
+
+	def := s.fieldType(field)
+	goType := def.GoType
+
+	rules := s.sampleRules(field)
+	number := 1
+	if min, ok := rules["min"]; ok {
+		number = min.N
+	} else if max, ok := rules["max"]; ok && max.N < number {
+		number = max.N
+	}
+
+	var imports []string
+	if def.Import != "" {
+		imports = []string{def.Import}
+	}
+
+	switch def.Faker {
+	case "text":
+		// Fields without a valid sample are rejected by checkSamples before tests are generated
+		sample, _ := s.sampleString(field, rules)
+		return strconv.Quote(sample), nil
+	case "number":
+		if goType == "int" {
+			return fmt.Sprintf("%d", number), nil
+		}
+		return fmt.Sprintf("%s(%d)", goType, number), nil
+	case "boolean":
+		return "true", nil
+	case "time":
+		return "time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)", []string{"time"}
+	case "json":
+		return "json.RawMessage(`{}`)", []string{"encoding/json"}
+	case "bytes":
+		return `[]byte("sample")`, nil
+	case "strings":
+		return `[]string{"sample"}`, nil
+	case "list", "map":
+		return goType + "{}", imports
+	case "decimal":
+		return fmt.Sprintf("decimal.NewFromInt(%d)", number), imports
+	case "":
+		return def.ZeroValue, imports
+	}
+
+	// Any other faker is a Go expression supplied with the type definition
+	return def.Faker, imports
+}
+
+// sampleString returns a string satisfying the field rules, or an error when the rules of the
+// field leave no sample to derive
+func (s *OrchestratorService) sampleString(field domain.FieldSpecification, rules map[string]fieldRule) (string, error) {
+	if enum, ok := rules["enum"]; ok && len(enum.Values) > 0 {
+		return enum.Values[0], nil
+	}
+
+	// Formatted values can't be padded without breaking the format
+	formatted := map[string]string{
+		"email": "user@example.com", "url": "https://example.com", "uuid": "00000000-0000-4000-8000-000000000000",
+		"json": "{}", "base64": "c2FtcGxl",
+	}
+	for _, kind := range []string{"email", "url", "uuid", "json", "base64"} {
+		if _, ok := rules[kind]; ok {
+			return formatted[kind], nil
+		}
+	}
+	if field.Type == "id" {
+		return formatted["uuid"], nil
+	}
+
+	for _, kind := range []string{"min", "len"} {
+		if rule, ok := rules[kind]; ok && rule.N > maxSampleLength {
+			return "", fmt.Errorf("no sample value of field %s is within %d characters, as %s is %d", field.Name, maxSampleLength, kind, rule.N)
+		}
+	}
+	if min, max := rules["min"], rules["max"]; min.Kind != "" && max.Kind != "" && min.N > max.N {
+		return "", fmt.Errorf("no sample value of field %s is between min %d and max %d", field.Name, min.N, max.N)
+	}
+
+	if regex, ok := rules["regex"]; ok {
+		maxRepeat := 16
+		for _, kind := range []string{"min", "max", "len"} {
+			if rule, ok := rules[kind]; ok && rule.N > maxRepeat {
+				maxRepeat = rule.N
+			}
+		}
+		if sample, ok := s.regexSample(regex.Arg, maxRepeat, func(sample string) bool { return sampleLengthValid(sample, rules) }); ok {
+			return sample, nil
+		}
+		return "", fmt.Errorf("no sample value of field %s matches %q and its length rules", field.Name, regex.Arg)
+	}
+
+	value, pad := "sample", "a"
+	switch {
+	case rules["numeric"].Kind != "":
+		value, pad = "12345", "1"
+	case rules["hexadecimal"].Kind != "":
+		value, pad = "abc123", "a"
+	}
+
+	if length, ok := rules["len"]; ok {
+		return strings.Repeat(pad, length.N), nil
+	}
+	if min, ok := rules["min"]; ok && len(value) < min.N {
+		value += strings.Repeat(pad, min.N-len(value))
+	}
+	if max, ok := rules["max"]; ok && len(value) > max.N {
+		value = value[:max.N]
+	}
+	return value, nil
+}
+
+// sampleLengthValid reports whether a string sample satisfies the length rules of its field
+func sampleLengthValid(sample string, rules map[string]fieldRule) bool {
+	n := utf8.RuneCountInString(sample)
+	if rule, ok := rules["min"]; ok && n < rule.N {
+		return false
+	}
+	if rule, ok := rules["max"]; ok && n > rule.N {
+		return false
+	}
+	if rule, ok := rules["len"]; ok && n != rule.N {
+		return false
+	}
+	return true
+}
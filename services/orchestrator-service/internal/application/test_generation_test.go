@@ -0,0 +1,70 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedTestsCoverValidationRules(t *testing.T) {
+	min, max := 1, 20
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Order",
+			Features: []string{"crud", "rest_api", "validation"},
+			Fields: []domain.FieldSpecification{
+				{Name: "total", Type: "integer", Required: true, Min: &min},
+				{Name: "status", Type: "enum", Enum: []string{"open", "paid"}},
+				{Name: "note", Type: "string", Max: &max},
+			},
+		}},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	for _, path := range []string{"internal/domain/order_test.go", "internal/interfaces/http/handlers/order_handler_test.go", "internal/fixtures/order_builder.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("%s is not generated: %v", path, err)
+		}
+	}
+
+	// Each rule gets a case of its own
+	out, err := goCommand(dir, "test", "-count=1", "-v", "-run", "TestValidateOrder", "./internal/domain")
+	if err != nil {
+		t.Fatalf("go test: %v\n%s", err, out)
+	}
+	for _, want := range []string{"TestValidateOrder/valid", "TestValidateOrder/total_min", "TestValidateOrder/status_enum", "TestValidateOrder/note_max"} {
+		if !strings.Contains(out, "--- PASS: "+want) {
+			t.Errorf("%s did not pass:\n%s", want, out)
+		}
+	}
+
+	// Without the min rule, the cases of the domain and handler tests covering it fail
+	validation := filepath.Join(dir, "internal", "domain", "order_validation.go")
+	code, err := os.ReadFile(validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := strings.Replace(string(code), "Total < 1", "Total < 0", 1)
+	if broken == string(code) {
+		t.Fatalf("order_validation.go does not check the min of Total:\n%s", code)
+	}
+	if err := os.WriteFile(validation, []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = goCommand(dir, "test", "-count=1", "./...")
+	if err == nil {
+		t.Fatalf("generated tests pass without the min rule:\n%s", out)
+	}
+	for _, want := range []string{"--- FAIL: TestValidateOrder/total_min", "--- FAIL: TestOrderHandler_Create/total_min"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("go test output does not contain %s:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"fmt"
+	"go/parser"
+	"sort"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// fakerStrategies are the sample value strategies understood by sampleValue. Any other
+// TypeDefinition.Faker value is used as a Go expression.
+var fakerStrategies = map[string]bool{
+	"text": true, "number": true, "boolean": true, "time": true, "json": true,
+	"list": true, "strings": true, "map": true, "bytes": true, "decimal": true,
+}
+
+// TypeRegistry resolves logical field types to their Go, SQL and JSON representations
+type TypeRegistry struct {
+	types map[string]domain.TypeDefinition
+}
+
+// NewTypeRegistry creates a registry holding the built-in types
+func NewTypeRegistry() *TypeRegistry {
+	r := &TypeRegistry{types: make(map[string]domain.TypeDefinition)}
+	for _, def := range domain.BuiltinTypes {
+		if err := r.Register(def); err != nil {
+			panic(fmt.Sprintf("invalid built-in type %s: %v", def.Name, err))
+		}
+	}
+	return r
+}
+
+// Register adds a type, replacing any type of the same name. Missing zero values and JSON
+// types are derived from the Go type.
+func (r *TypeRegistry) Register(def domain.TypeDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("type name is required")
+	}
+	if def.GoType == "" {
+		return fmt.Errorf("type %s: go_type is required", def.Name)
+	}
+	if _, err := parser.ParseExpr(def.GoType); err != nil {
+		return fmt.Errorf("type %s: go_type %q is not a Go type", def.Name, def.GoType)
+	}
+	if qualifier, _, ok := strings.Cut(strings.TrimLeft(def.GoType, "*[]"), "."); ok && def.Import == "" {
+		return fmt.Errorf("type %s: go_type %q needs an import for package %s", def.Name, def.GoType, qualifier)
+	}
+	if def.Version != "" && def.Module == "" {
+		return fmt.Errorf("type %s: version %s needs a module", def.Name, def.Version)
+	}
+	if def.Module != "" && def.Import != def.Module && !strings.HasPrefix(def.Import, def.Module+"/") {
+		return fmt.Errorf("type %s: import %q is not provided by module %q", def.Name, def.Import, def.Module)
+	}
+	if def.Faker != "" && !fakerStrategies[def.Faker] {
+		if _, err := parser.ParseExpr(def.Faker); err != nil {
+			return fmt.Errorf("type %s: faker %q is neither a strategy nor a Go expression", def.Name, def.Faker)
+		}
+	}
+
+	if def.SQLType == "" {
+		def.SQLType = "TEXT"
+	}
+	if def.ZeroValue == "" {
+		def.ZeroValue = zeroValueOf(def.GoType)
+	}
+	if def.JSONType == "" && def.GoType != "json.RawMessage" {
+		def.JSONType = jsonTypeOf(def.GoType)
+	}
+	r.types[def.Name] = def
+	return nil
+}
+
+// Lookup returns the definition of a type
+func (r *TypeRegistry) Lookup(name string) (domain.TypeDefinition, bool) {
+	def, ok := r.types[name]
+	return def, ok
+}
+
+// Names returns the registered type names in sorted order
+func (r *TypeRegistry) Names() []string {
+	return sortedKeys(r.types)
+}
+
+// Definitions returns the registered types sorted by name
+func (r *TypeRegistry) Definitions() []domain.TypeDefinition {
+	defs := make([]domain.TypeDefinition, 0, len(r.types))
+	for _, name := range r.Names() {
+		defs = append(defs, r.types[name])
+	}
+	return defs
+}
+
+// Modules returns the modules required by the given types, sorted by path
+func (r *TypeRegistry) Modules(names []string) []domain.ModuleDependency {
+	versions := make(map[string]string)
+	for _, name := range names {
+		if def, ok := r.types[name]; ok && def.Module != "" {
+			versions[def.Module] = def.Version
+		}
+	}
+	modules := make([]domain.ModuleDependency, 0, len(versions))
+	for _, path := range sortedKeys(versions) {
+		modules = append(modules, domain.ModuleDependency{Path: path, Version: versions[path]})
+	}
+	return modules
+}
+
+// clone returns a copy of the registry that can be extended independently
+func (r *TypeRegistry) clone() *TypeRegistry {
+	c := &TypeRegistry{types: make(map[string]domain.TypeDefinition, len(r.types))}
+	for name, def := range r.types {
+		c.types[name] = def
+	}
+	return c
+}
+
+// zeroValueOf returns the Go zero value literal for a type
+func zeroValueOf(goType string) string {
+	switch {
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return "0"
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["),
+		strings.HasPrefix(goType, "func"), strings.HasPrefix(goType, "chan"),
+		goType == "error", goType == "interface{}", goType == "any", goType == "json.RawMessage":
+		return "nil"
+	default:
+		return goType + "{}"
+	}
+}
+
+// jsonTypeOf returns the JSON Schema type a Go type encodes to with encoding/json
+func jsonTypeOf(goType string) string {
+	switch {
+	case goType == "string", goType == "[]byte":
+		return "string"
+	case goType == "bool":
+		return "boolean"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		return "integer"
+	case strings.HasPrefix(goType, "float"):
+		return "number"
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// forProject returns a copy of the service whose type registry also holds the custom types of spec.
+// Built-in feature plugins are rebound to the copy so that they generate code with those types.
+func (s *OrchestratorService) forProject(spec *domain.ProjectSpecification) (*OrchestratorService, error) {
+	if len(spec.Types) == 0 {
+		return s, nil
+	}
+	project := *s
+	project.types = s.types.clone()
+	project.features = s.features.bind(&project)
+	for _, def := range spec.Types {
+		if err := project.types.Register(def); err != nil {
+			return nil, err
+		}
+	}
+	return &project, nil
+}
+
+// Types returns the registry of built-in types
+func (s *OrchestratorService) Types() *TypeRegistry {
+	return s.types
+}
+
+// fieldType returns the definition of a field's type. Unknown types, such as entity names,
+// are used as Go types as-is.
+func (s *OrchestratorService) fieldType(field domain.FieldSpecification) domain.TypeDefinition {
+	if def, ok := s.types.Lookup(field.Type); ok {
+		return def
+	}
+	return domain.TypeDefinition{Name: field.Type, GoType: field.Type, SQLType: "TEXT", ZeroValue: zeroValueOf(field.Type)}
+}
+
+// fieldImports returns the sorted imports needed by the Go types of fields
+func (s *OrchestratorService) fieldImports(fields []domain.FieldSpecification) []string {
+	imports := make(map[string]bool)
+	for _, field := range fields {
+		if def := s.fieldType(field); def.Import != "" && !s.isTypedEnum(field) {
+			imports[def.Import] = true
+		}
+	}
+	return sortedKeys(imports)
+}
+
+// projectModules returns the modules required by the field types and generated infrastructure of a project
+func (s *OrchestratorService) projectModules(spec *domain.ProjectSpecification) []domain.ModuleDependency {
+	var types []string
+	for _, entity := range spec.Entities {
+		for _, field := range entity.Fields {
+			types = append(types, field.Type)
+		}
+	}
+	modules := s.types.Modules(types)
+
+	if s.usesRedisCache(spec) {
+		modules = append(modules, redisModule)
+	}
+	if s.usesGRPC(spec) {
+		modules = append(modules, grpcModule, protobufModule)
+	}
+	if s.usesGraphQL(spec) {
+		modules = append(modules, graphqlModule)
+	}
+	if s.usesCLI(spec) {
+		modules = append(modules, cobraModule)
+	}
+	if driver, ok := s.projectSQLDriver(spec); ok {
+		modules = append(modules, driver)
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+	return modules
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestTypeRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     domain.TypeDefinition
+		wantErr string
+	}{
+		{name: "local type", def: domain.TypeDefinition{Name: "percentage", GoType: "float64"}},
+		{name: "imported type", def: domain.TypeDefinition{Name: "ulid", GoType: "ulid.ULID", Import: "github.com/oklog/ulid", Module: "github.com/oklog/ulid", Version: "v1.3.1"}},
+		{name: "missing name", def: domain.TypeDefinition{GoType: "string"}, wantErr: "name is required"},
+		{name: "invalid Go type", def: domain.TypeDefinition{Name: "bad", GoType: "map["}, wantErr: "is not a Go type"},
+		{name: "missing import", def: domain.TypeDefinition{Name: "ulid", GoType: "ulid.ULID"}, wantErr: "needs an import"},
+		{name: "version without module", def: domain.TypeDefinition{Name: "ulid", GoType: "ulid.ULID", Import: "github.com/oklog/ulid", Version: "v1.3.1"}, wantErr: "needs a module"},
+		{name: "import outside module", def: domain.TypeDefinition{Name: "ulid", GoType: "ulid.ULID", Import: "github.com/oklog/ulid", Module: "github.com/other/ulid"}, wantErr: "is not provided by module"},
+		{name: "invalid faker", def: domain.TypeDefinition{Name: "percentage", GoType: "float64", Faker: "float64("}, wantErr: "neither a strategy nor a Go expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTypeRegistry()
+			err := r.Register(tt.def)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Register() error = %v", err)
+				}
+				if def, _ := r.Lookup(tt.def.Name); def.SQLType != "TEXT" || def.ZeroValue == "" {
+					t.Errorf("Register() did not derive the defaults: %+v", def)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Register() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeneratedProjectWithCustomTypes(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Types: []domain.TypeDefinition{
+			{Name: "ulid", GoType: "ulid.ULID", Import: "github.com/oklog/ulid", Module: "github.com/oklog/ulid", Version: "v1.3.1", SQLType: "CHAR(26)", JSONFormat: "ulid", Faker: "ulid.ULID{1}"},
+			{Name: "percentage", GoType: "float64", SQLType: "REAL", Faker: "number"},
+		},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Invoice",
+			Features: []string{"crud", "rest_api", "validation"},
+			Fields: []domain.FieldSpecification{
+				{Name: "reference", Type: "ulid", Required: true},
+				{Name: "amount", Type: "money", Required: true},
+				{Name: "rate", Type: "decimal"},
+				{Name: "discount", Type: "percentage"},
+			},
+		}},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, module := range []string{"github.com/oklog/ulid v1.3.1", "github.com/shopspring/decimal v1.4.0"} {
+		if !strings.Contains(string(gomod), module) {
+			t.Errorf("go.mod does not require %s:\n%s", module, gomod)
+		}
+	}
+}
@@ -0,0 +1,576 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// fieldRule is a single validation rule of a field, normalized from Required, Min, Max,
+// Enum, Format, the field type and the Validation list
+type fieldRule struct {
+	Kind   string   // "required", "min", "max", "len", "enum", "regex", "email", "url", "uuid", ...
+	N      int      // Bound for "min", "max" and "len"
+	Arg    string   // Pattern for "regex", original rule for "unsupported"
+	Values []string // Allowed values for "enum"
+}
+
+// formatRules are string rules implemented by a helper in the generated validation_helpers.go
+var formatRules = map[string]struct {
+	helper  string
+	message string
+}{
+	"email":       {"isValidEmail", "must be a valid email address"},
+	"url":         {"isValidURL", "must be a valid URL"},
+	"uuid":        {"isValidUUID", "must be a valid UUID"},
+	"alpha":       {"isAlpha", "must contain only letters"},
+	"alphanum":    {"isAlphanumeric", "must contain only letters and digits"},
+	"numeric":     {"isNumeric", "must be numeric"},
+	"hexadecimal": {"isHexadecimal", "must be hexadecimal"},
+	"base64":      {"isBase64", "must be base64 encoded"},
+	"json":        {"isValidJSON", "must be valid JSON"},
+}
+
+// fieldRules normalizes every rule declared on a field. Rules declared more than once
+// (e.g. type "email" plus validation "email") are only kept once.
+func (s *OrchestratorService) fieldRules(field domain.FieldSpecification) []fieldRule {
+	var rules []fieldRule
+	seen := make(map[string]bool)
+	add := func(rule fieldRule) {
+		if rule.Kind != "regex" && rule.Kind != "unsupported" {
+			if seen[rule.Kind] {
+				return
+			}
+			seen[rule.Kind] = true
+		}
+		rules = append(rules, rule)
+	}
+
+	if field.Required {
+		add(fieldRule{Kind: "required"})
+	}
+	if field.Min != nil {
+		add(fieldRule{Kind: "min", N: *field.Min})
+	}
+	if field.Max != nil {
+		add(fieldRule{Kind: "max", N: *field.Max})
+	}
+	if len(field.Enum) > 0 {
+		add(fieldRule{Kind: "enum", Values: field.Enum})
+	}
+	if _, ok := formatRules[field.Type]; ok {
+		add(fieldRule{Kind: field.Type})
+	}
+	if _, ok := formatRules[field.Format]; ok {
+		add(fieldRule{Kind: field.Format})
+	}
+
+	for _, validation := range field.Validation {
+		name, arg, _ := strings.Cut(validation, ":")
+		switch name {
+		case "required":
+			add(fieldRule{Kind: "required"})
+		case "min", "max", "len":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				add(fieldRule{Kind: "unsupported", Arg: validation})
+				continue
+			}
+			add(fieldRule{Kind: name, N: n})
+		case "regex":
+			if _, err := regexp.Compile(arg); err != nil {
+				add(fieldRule{Kind: "unsupported", Arg: validation})
+				continue
+			}
+			add(fieldRule{Kind: "regex", Arg: arg})
+		case "oneof":
+			add(fieldRule{Kind: "enum", Values: strings.Fields(arg)})
+		default:
+			if _, ok := formatRules[name]; ok {
+				add(fieldRule{Kind: name})
+				continue
+			}
+			add(fieldRule{Kind: "unsupported", Arg: validation})
+		}
+	}
+
+	return rules
+}
+
+// valueCategory groups Go types by how validation rules apply to them
+func (s *OrchestratorService) valueCategory(goType string) string {
+	switch {
+	case goType == "string":
+		return "string"
+	case goType == "bool":
+		return "bool"
+	case goType == "time.Time":
+		return "time"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return "number"
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["), goType == "json.RawMessage":
+		return "collection"
+	default:
+		return "other"
+	}
+}
+
+// generateValidationElement generates a Validate method implementing every field rule in plain Go,
+// plus the ValidateX function kept for callers of the previous function-style API
+func (s *OrchestratorService) generateValidationElement(entity domain.EntitySpecification) domain.CodeElement {
+	name := entity.Name
+	recv := strings.ToLower(name[:1])
+	imports := make(map[string]bool)
+
+	var patterns, body strings.Builder
+	for _, field := range entity.Fields {
+		fieldName := s.capitalizeFirst(field.Name)
+		goType := s.fieldGoType(entity, field)
+		category := s.valueCategory(goType)
+		expr := recv + "." + fieldName
+		path := s.jsonName(field)
+
+		// Typed enums are strings underneath; string helpers need an explicit conversion
+		strExpr := expr
+		if s.isTypedEnum(field) {
+			category, strExpr = "string", fmt.Sprintf("string(%s)", expr)
+		}
+
+		var required bool
+		var checks []string
+		patternCount := 0
+		for _, rule := range s.fieldRules(field) {
+			if rule.Kind == "required" {
+				required = true
+				continue
+			}
+			if rule.Kind == "regex" && category == "string" {
+				patternCount++
+				patternName := fmt.Sprintf("%s%sPattern", s.lowerFirst(name), fieldName)
+				if patternCount > 1 {
+					patternName = fmt.Sprintf("%s%sPattern%d", s.lowerFirst(name), fieldName, patternCount)
+				}
+				fmt.Fprintf(&patterns, "\t%s = regexp.MustCompile(%q)\n", patternName, rule.Arg)
+				imports["regexp"] = true
+				checks = append(checks, fmt.Sprintf("\t\tif !%s.MatchString(%s) {\n\t\t\terrs.Add(%q, \"regex\", %q)\n\t\t}",
+					patternName, strExpr, path, "must match pattern "+rule.Arg))
+				continue
+			}
+			if rule.Kind == "enum" && s.isTypedEnum(field) {
+				checks = append(checks, fmt.Sprintf("\t\tif !%s.IsValid() {\n\t\t\terrs.Add(%q, \"enum\", %q)\n\t\t}",
+					expr, path, "must be one of: "+strings.Join(rule.Values, ", ")))
+				continue
+			}
+			check, checkImports := s.validationCheck(rule, category, goType, strExpr, path)
+			for _, imp := range checkImports {
+				imports[imp] = true
+			}
+			checks = append(checks, check)
+		}
+
+		// Values that can be absent are only checked when present
+		requiredCond, presentCond := "", ""
+		switch category {
+		case "string":
+			requiredCond, presentCond = fmt.Sprintf("%s == \"\"", expr), fmt.Sprintf("%s != \"\"", expr)
+		case "time":
+			requiredCond, presentCond = fmt.Sprintf("%s.IsZero()", expr), fmt.Sprintf("!%s.IsZero()", expr)
+		case "collection":
+			requiredCond, presentCond = fmt.Sprintf("len(%s) == 0", expr), fmt.Sprintf("len(%s) > 0", expr)
+		}
+
+		if len(checks) == 0 && (!required || requiredCond == "") {
+			continue
+		}
+
+		fmt.Fprintf(&body, "\n\t// %s\n", path)
+		checkBlock := strings.Join(checks, "\n")
+		switch {
+		case required && requiredCond != "":
+			fmt.Fprintf(&body, "\tif %s {\n\t\terrs.Add(%q, \"required\", \"is required\")\n\t}", requiredCond, path)
+			if len(checks) > 0 {
+				fmt.Fprintf(&body, " else {\n%s\n\t}", checkBlock)
+			}
+			body.WriteString("\n")
+		case presentCond != "":
+			fmt.Fprintf(&body, "\tif %s {\n%s\n\t}\n", presentCond, checkBlock)
+		default:
+			body.WriteString(checkBlock + "\n")
+		}
+	}
+
+	patternBlock := ""
+	if patterns.Len() > 0 {
+		patternBlock = fmt.Sprintf("\n// Patterns are compiled once at package initialization\nvar (\n%s)\n", patterns.String())
+	}
+
+	importBlock := ""
+	if len(imports) > 0 {
+		importBlock = fmt.Sprintf("\nimport (\n%s)\n", s.formatImports(imports))
+	}
+
+	content := fmt.Sprintf(`package domain
+%[1]s%[2]s
+// Validate checks every field rule of the %[3]s and returns all violations as ValidationErrors
+func (%[4]s *%[3]s) Validate() error {
+	var errs ValidationErrors
+%[5]s
+	return errs.ErrOrNil()
+}
+
+// Validate%[3]s validates a %[3]s
+func Validate%[3]s(%[6]s *%[3]s) error {
+	return %[6]s.Validate()
+}
+`, importBlock, patternBlock, name, recv, body.String(), strings.ToLower(name))
+
+	return s.newFileElement(
+		fmt.Sprintf("Validate%s", name),
+		"domain",
+		fmt.Sprintf("internal/domain/%s_validation.go", s.toSnakeCase(name)),
+		content,
+	)
+}
+
+// validationCheck renders the Go statement enforcing a single rule
+func (s *OrchestratorService) validationCheck(rule fieldRule, category, goType, expr, path string) (string, []string) {
+	fail := func(cond, ruleName, message string) string {
+		return fmt.Sprintf("\t\tif %s {\n\t\t\terrs.Add(%q, %q, %q)\n\t\t}", cond, path, ruleName, message)
+	}
+	unsupported := fmt.Sprintf("\t\t// rule %q is not applicable to %s values", rule.Kind, goType)
+
+	switch rule.Kind {
+	case "min", "max", "len":
+		op, message := map[string]string{"min": "<", "max": ">", "len": "!="}[rule.Kind], ""
+		switch category {
+		case "string":
+			message = map[string]string{"min": "must be at least %d characters", "max": "must be at most %d characters", "len": "must be exactly %d characters"}[rule.Kind]
+			return fail(fmt.Sprintf("utf8.RuneCountInString(%s) %s %d", expr, op, rule.N), rule.Kind, fmt.Sprintf(message, rule.N)), []string{"unicode/utf8"}
+		case "collection":
+			message = map[string]string{"min": "must contain at least %d items", "max": "must contain at most %d items", "len": "must contain exactly %d items"}[rule.Kind]
+			return fail(fmt.Sprintf("len(%s) %s %d", expr, op, rule.N), rule.Kind, fmt.Sprintf(message, rule.N)), nil
+		case "number":
+			if rule.Kind == "len" {
+				return unsupported, nil
+			}
+			message = map[string]string{"min": "must be at least %d", "max": "must be at most %d"}[rule.Kind]
+			return fail(fmt.Sprintf("%s %s %d", expr, op, rule.N), rule.Kind, fmt.Sprintf(message, rule.N)), nil
+		}
+
+	case "enum":
+		if category != "string" {
+			return unsupported, nil
+		}
+		quoted := make([]string, len(rule.Values))
+		for i, value := range rule.Values {
+			quoted[i] = strconv.Quote(value)
+		}
+		return fmt.Sprintf("\t\tswitch %s {\n\t\tcase %s:\n\t\tdefault:\n\t\t\terrs.Add(%q, \"enum\", %q)\n\t\t}",
+			expr, strings.Join(quoted, ", "), path, "must be one of: "+strings.Join(rule.Values, ", ")), nil
+
+	case "unsupported":
+		return fmt.Sprintf("\t\t// unsupported validation rule %q ignored", rule.Arg), nil
+
+	default:
+		format, ok := formatRules[rule.Kind]
+		if !ok {
+			return unsupported, nil
+		}
+		switch {
+		case category == "string":
+			return fail(fmt.Sprintf("!%s(%s)", format.helper, expr), rule.Kind, format.message), nil
+		case rule.Kind == "json" && goType == "json.RawMessage":
+			return fail(fmt.Sprintf("!%s(string(%s))", format.helper, expr), rule.Kind, format.message), nil
+		}
+	}
+
+	return unsupported, nil
+}
+
+// generateValidationHelpersElement generates the error types and format helpers referenced
+// by generated Validate methods
+func (s *OrchestratorService) generateValidationHelpersElement() domain.CodeElement {
+	content := `package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// FieldError describes a single failed validation rule
+type FieldError struct {
+	Field   string ` + "`json:\"field\"`" + `
+	Rule    string ` + "`json:\"rule\"`" + `
+	Message string ` + "`json:\"message\"`" + `
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationErrors collects every rule violation found while validating an entity
+type ValidationErrors []FieldError
+
+// Error implements the error interface
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Add records a rule violation for the field at path
+func (e *ValidationErrors) Add(path, rule, message string) {
+	*e = append(*e, FieldError{Field: path, Rule: rule, Message: message})
+}
+
+// ErrOrNil returns nil when no violation was recorded
+func (e ValidationErrors) ErrOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+var (
+	uuidPattern        = regexp.MustCompile(` + "`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`" + `)
+	alphaPattern       = regexp.MustCompile(` + "`^[a-zA-Z]+$`" + `)
+	alphanumPattern    = regexp.MustCompile(` + "`^[a-zA-Z0-9]+$`" + `)
+	numericPattern     = regexp.MustCompile(` + "`^[-+]?[0-9]+(\\.[0-9]+)?$`" + `)
+	hexadecimalPattern = regexp.MustCompile(` + "`^(0[xX])?[0-9a-fA-F]+$`" + `)
+)
+
+// isValidEmail reports whether value is a single RFC 5322 address without a display name
+func isValidEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+// isValidURL reports whether value is an absolute URL
+func isValidURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isValidUUID(value string) bool    { return uuidPattern.MatchString(value) }
+func isAlpha(value string) bool        { return alphaPattern.MatchString(value) }
+func isAlphanumeric(value string) bool { return alphanumPattern.MatchString(value) }
+func isNumeric(value string) bool      { return numericPattern.MatchString(value) }
+func isHexadecimal(value string) bool  { return hexadecimalPattern.MatchString(value) }
+func isValidJSON(value string) bool    { return json.Valid([]byte(value)) }
+
+func isBase64(value string) bool {
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
+`
+
+	return s.newFileElement("validation_helpers", "domain", "internal/domain/validation_helpers.go", content)
+}
+
+// validationCases derives invalid variations of a valid fixture, one per enforceable field rule
+func (s *OrchestratorService) validationCases(entity domain.EntitySpecification) []validationCase {
+	var cases []validationCase
+
+	for _, field := range entity.Fields {
+		goType := s.fieldType(field).GoType // typed enums share the string representation
+		for _, rule := range s.fieldRules(field) {
+			value, imports, ok := s.invalidValue(field, rule, goType)
+			if !ok {
+				continue
+			}
+			cases = append(cases, validationCase{
+				Name:    fmt.Sprintf("%s %s", field.Name, rule.Kind),
+				Field:   s.capitalizeFirst(field.Name),
+				Value:   value,
+				Imports: imports,
+			})
+		}
+	}
+
+	return cases
+}
+
+// invalidValue returns a Go expression violating the rule, or false when no such value can be derived
+func (s *OrchestratorService) invalidValue(field domain.FieldSpecification, rule fieldRule, goType string) (string, []string, bool) {
+	switch s.valueCategory(goType) {
+	case "string":
+		switch rule.Kind {
+		case "required":
+			return `""`, nil, true
+		case "min":
+			// Empty optional values are skipped by validation, so they can't violate "min"
+			if rule.N < 1 || (rule.N == 1 && !field.Required) || rule.N > maxSampleLength {
+				return "", nil, false
+			}
+			return strconv.Quote(strings.Repeat("a", rule.N-1)), nil, true
+		case "max", "len":
+			if rule.N >= maxSampleLength {
+				return "", nil, false
+			}
+			return strconv.Quote(strings.Repeat("a", rule.N+1)), nil, true
+		case "enum":
+			return `"__invalid__"`, nil, true
+		case "regex":
+			for _, candidate := range []string{"!", "~", "0", "a", "A", " ", strings.Repeat("a", 64)} {
+				if matched, _ := regexp.MatchString(rule.Arg, candidate); !matched {
+					return strconv.Quote(candidate), nil, true
+				}
+			}
+			return "", nil, false
+		}
+		invalid := map[string]string{
+			"email": "not-an-email", "url": "not a url", "uuid": "not-a-uuid", "alpha": "abc123",
+			"alphanum": "abc-123", "numeric": "abc", "hexadecimal": "xyz", "base64": "!!!", "json": "{",
+		}
+		if value, ok := invalid[rule.Kind]; ok {
+			return strconv.Quote(value), nil, true
+		}
+
+	case "number":
+		literal := func(n int) string {
+			if goType == "int" {
+				return strconv.Itoa(n)
+			}
+			return fmt.Sprintf("%s(%d)", goType, n)
+		}
+		switch rule.Kind {
+		case "min":
+			return literal(rule.N - 1), nil, true
+		case "max":
+			return literal(rule.N + 1), nil, true
+		}
+
+	case "time":
+		if rule.Kind == "required" {
+			return "time.Time{}", []string{"time"}, true
+		}
+
+	case "collection":
+		switch {
+		case rule.Kind == "required":
+			return "nil", nil, true
+		case rule.Kind == "json" && goType == "json.RawMessage":
+			return "json.RawMessage(`{`)", []string{"encoding/json"}, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// Bounds of the search for a sample matching a pattern. Nested quantifiers multiply the length
+// of samples and the work of writing them, so the search gives up rather than run unbounded.
+const (
+	maxSampleLength = 1024    // Characters of a sample
+	maxSampleSteps  = 1 << 20 // Pattern nodes written over every attempt of a search
+)
+
+// regexSample derives a short string matching pattern that accept also accepts, or false when
+// none can be derived. Repeated parts of the pattern are written 0, 1, 2... times, up to
+// maxRepeat, until a sample is accepted; samples only grow with the repeat count, so the search
+// stops at the first one longer than maxSampleLength.
+func (s *OrchestratorService) regexSample(pattern string, maxRepeat int, accept func(string) bool) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+
+	steps := 0
+	for repeat := 0; repeat <= maxRepeat && repeat <= maxSampleLength; repeat++ {
+		var b strings.Builder
+		if !s.writeRegexSample(&b, re, repeat, &steps) {
+			return "", false
+		}
+		if sample := b.String(); compiled.MatchString(sample) && accept(sample) {
+			return sample, true
+		}
+	}
+	return "", false
+}
+
+// writeRegexSample writes a string matching re, repeating the repeatable parts of re repeat
+// times where they allow it. It fails once the sample is longer than maxSampleLength or steps,
+// the nodes written so far, exceeds maxSampleSteps.
+func (s *OrchestratorService) writeRegexSample(b *strings.Builder, re *syntax.Regexp, repeat int, steps *int) bool {
+	if *steps++; *steps > maxSampleSteps || b.Len() > maxSampleLength {
+		return false
+	}
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		// Prefer a readable character from the class when there is one
+		chosen := re.Rune[0]
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			for _, r := range []rune{'a', 'A', '0'} {
+				if lo <= r && r <= hi {
+					b.WriteRune(r)
+					return true
+				}
+			}
+			if lo >= ' ' && chosen < ' ' {
+				chosen = lo
+			}
+		}
+		b.WriteRune(chosen)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		return s.writeRegexSample(b, re.Sub[0], repeat, steps)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := 0, -1
+		switch re.Op {
+		case syntax.OpPlus:
+			min = 1
+		case syntax.OpQuest:
+			max = 1
+		case syntax.OpRepeat:
+			min, max = re.Min, re.Max
+		}
+		count := repeat
+		if count < min {
+			count = min
+		}
+		if max >= 0 && count > max {
+			count = max
+		}
+		for i := 0; i < count; i++ {
+			if !s.writeRegexSample(b, re.Sub[0], repeat, steps) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !s.writeRegexSample(b, sub, repeat, steps) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return s.writeRegexSample(b, re.Sub[0], repeat, steps)
+	case syntax.OpEmptyMatch,
+		syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		// Matches the empty string
+	default:
+		return false
+	}
+	return b.Len() <= maxSampleLength
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestSampleStringSatisfiesRegexAndLengthRules(t *testing.T) {
+	min, max := 2, 50
+	tests := []struct {
+		name  string
+		field domain.FieldSpecification
+		want  string
+	}{
+		{
+			name:  "padded to the minimum",
+			field: domain.FieldSpecification{Name: "name", Type: "string", Min: &min, Max: &max, Validation: []string{`regex:^[A-Z][a-z]*$`}},
+			want:  "Aa",
+		},
+		{
+			name:  "within a bounded repeat",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Validation: []string{`regex:^[A-Z]{2,6}$`, "len:4"}},
+			want:  "AAAA",
+		},
+		{
+			name:  "fixed length pattern",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Validation: []string{`regex:^[A-Z]{3}$`}},
+			want:  "AAA",
+		},
+	}
+	s := NewOrchestratorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.sampleString(tt.field, s.sampleRules(tt.field))
+			if err != nil || got != tt.want {
+				t.Errorf("sampleString() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleStringGivesUp(t *testing.T) {
+	min, max := 6, 8
+	tests := []struct {
+		name  string
+		field domain.FieldSpecification
+	}{
+		{
+			name:  "length beyond the samples",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Validation: []string{`regex:^((ab)*(cd)*)*$`, "len:99999"}},
+		},
+		{
+			name:  "nested quantifiers outside the length rules",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Max: &max, Validation: []string{`regex:^((ab)*(cd)*)*x$`}},
+		},
+		{
+			name:  "nested empty quantifiers",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Validation: []string{`regex:^((((()*)*)*)*)*a$`}},
+		},
+		{
+			name:  "min greater than max",
+			field: domain.FieldSpecification{Name: "code", Type: "string", Validation: []string{`regex:^a+$`, "min:99999", "max:5"}},
+		},
+	}
+	s := NewOrchestratorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			got, err := s.sampleString(tt.field, s.sampleRules(tt.field))
+			if err == nil {
+				t.Errorf("sampleString() = %q, want no sample", got)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("sampleString() took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestValidateContradictingBounds(t *testing.T) {
+	min := 10
+	tests := []struct {
+		name  string
+		field domain.FieldSpecification
+		want  string
+	}{
+		{"min greater than max", domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Validation: []string{"max:5"}}, "/entities/0/fields/0/min"},
+		{"listed min greater than max", domain.FieldSpecification{Name: "code", Type: "string", Validation: []string{"min:9", "max:5"}}, "/entities/0/fields/0/validation"},
+		{"len below min", domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Validation: []string{"len:4"}}, "/entities/0/fields/0/validation"},
+		{"len within bounds", domain.FieldSpecification{Name: "code", Type: "string", Min: &min, Validation: []string{"len:12"}}, ""},
+	}
+	s := NewOrchestratorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &domain.ProjectSpecification{
+				Name:        "shop",
+				ModulePath:  "example.com/shop",
+				ProjectType: "microservice",
+				Entities:    []domain.EntitySpecification{{Name: "Customer", Fields: []domain.FieldSpecification{tt.field}}},
+			}
+			var got string
+			for _, issue := range s.ValidateSpecification(spec).Issues {
+				if issue.Code == "invalid_value" && strings.HasPrefix(issue.Path, "/entities/0/fields/0") {
+					got = issue.Path
+				}
+			}
+			if got != tt.want {
+				t.Errorf("invalid_value issue at %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsatisfiableSampleFailsGeneration(t *testing.T) {
+	min := 5
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Customer",
+			Features: []string{"crud", "validation"},
+			Fields:   []domain.FieldSpecification{{Name: "code", Type: "string", Min: &min, Validation: []string{`regex:^[A-Z]{3}$`}}},
+		}},
+	}
+
+	_, err := NewOrchestratorService().OrchestrateMicroservice(spec)
+	if err == nil || !strings.Contains(err.Error(), "no sample value of field code") {
+		t.Fatalf("OrchestrateMicroservice() error = %v, want no sample value of field code", err)
+	}
+}
+
+func TestGeneratedValidationAcceptsFixtures(t *testing.T) {
+	min, max := 2, 50
+	spec := &domain.ProjectSpecification{
+		Name:        "shop",
+		ModulePath:  "example.com/shop",
+		ProjectType: "microservice",
+		Features:    []string{"testing"},
+		Entities: []domain.EntitySpecification{{
+			Name:     "Customer",
+			Features: []string{"crud", "repository", "validation", "rest_api"},
+			Fields: []domain.FieldSpecification{
+				{Name: "name", Type: "string", Required: true, Min: &min, Max: &max, Validation: []string{`regex:^[A-Z][a-z]*$`}},
+				{Name: "code", Type: "string", Required: true, Validation: []string{`regex:^[A-Z0-9]+$`, "len:6"}},
+			},
+		}},
+	}
+
+	checkGeneratedProject(t, spec)
+}
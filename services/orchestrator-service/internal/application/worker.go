@@ -0,0 +1,1525 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+// Defaults of the jobs for settings the specification leaves out
+const (
+	defaultJobQueue       = "default"
+	defaultJobMaxAttempts = 3
+	defaultJobBackoff     = time.Second
+)
+
+// jobsReserved are the names the generated jobs package declares itself
+var jobsReserved = map[string]bool{"Register": true, "Schedule": true, "Enqueue": true, "ErrNotImplemented": true}
+
+// cronDescriptors are the schedules accepted in place of a cron expression
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronFields are the fields of cron expressions with their ranges
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// workerBinary returns the name of the worker executable: that of the project for worker
+// projects, which run no server, and "<project>-worker" next to the server of other projects
+func (s *OrchestratorService) workerBinary(spec *domain.ProjectSpecification) string {
+	if spec.ProjectType == "worker" {
+		return s.binaryName(spec)
+	}
+	return s.binaryName(spec) + "-worker"
+}
+
+// jobQueue returns the queue a job is enqueued on
+func jobQueue(job domain.JobSpecification) string {
+	if job.Queue != "" {
+		return job.Queue
+	}
+	return defaultJobQueue
+}
+
+// jobRetryPolicy renders the workers.RetryPolicy literal of a job
+func jobRetryPolicy(job domain.JobSpecification) string {
+	attempts := job.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultJobMaxAttempts
+	}
+	policy := fmt.Sprintf("workers.RetryPolicy{MaxAttempts: %d, Backoff: %s, MaxBackoff: time.Hour",
+		attempts, durationLiteral(parseDuration(job.Backoff, defaultJobBackoff)))
+	if timeout := parseDuration(job.Timeout, 0); timeout > 0 {
+		policy += ", Timeout: " + durationLiteral(timeout)
+	}
+	return policy + "}"
+}
+
+// validJobName reports whether a job or queue name can be stored as is and turned into a Go
+// identifier
+func validJobName(name string) bool {
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-' || r == '_'):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// parseCronSchedule checks a schedule the way the generated workers.ParseSchedule parses it: five
+// fields of values, ranges, lists and steps, a descriptor such as "@daily", or "@every <duration>"
+func parseCronSchedule(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if every, ok := strings.CutPrefix(expr, "@every "); ok {
+		if d, err := time.ParseDuration(strings.TrimSpace(every)); err != nil || d <= 0 {
+			return fmt.Errorf("@every needs a positive duration")
+		}
+		return nil
+	}
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("has %d fields, want 5: minute, hour, day of month, month and day of week", len(fields))
+	}
+	for i, field := range cronFields {
+		for _, part := range strings.Split(fields[i], ",") {
+			values, step, stepped := strings.Cut(part, "/")
+			if n, err := strconv.Atoi(step); stepped && (err != nil || n < 1) {
+				return fmt.Errorf("%s: step %q is not a positive number", field.name, step)
+			}
+			lo, hi := field.min, field.max
+			if values != "*" {
+				first, last, ranged := strings.Cut(values, "-")
+				var err error
+				if lo, err = strconv.Atoi(first); err != nil {
+					return fmt.Errorf("%s: value %q is not a number", field.name, first)
+				}
+				hi = lo
+				if ranged {
+					if hi, err = strconv.Atoi(last); err != nil {
+						return fmt.Errorf("%s: value %q is not a number", field.name, last)
+					}
+				}
+			}
+			if lo < field.min || hi > field.max || lo > hi {
+				return fmt.Errorf("%s: %q is out of range %d-%d", field.name, part, field.min, field.max)
+			}
+		}
+	}
+	return nil
+}
+
+// workerFeatureFiles generates the worker scaffold of the project: the workers package with its
+// queues, pool and scheduler, the jobs package with a handler per job of the specification, the
+// worker main and the migration of the job table, plus their tests when testing is enabled
+func (s *OrchestratorService) workerFeatureFiles(spec *domain.ProjectSpecification) []domain.CodeElement {
+	elements := []domain.CodeElement{
+		s.generateWorkerJobElement(),
+		s.generateWorkerQueueElement(),
+		s.generateWorkerMemoryQueueElement(),
+		s.generateWorkerPostgresQueueElement(),
+		s.generateWorkerPoolElement(),
+		s.generateWorkerScheduleElement(),
+		s.generateJobsElement(spec),
+	}
+	for _, job := range spec.Jobs {
+		elements = append(elements, s.generateJobHandlerElement(spec, job))
+	}
+	elements = append(elements, s.generateWorkerMainElement(spec))
+	elements = append(elements, s.generateJobMigrationElements(spec)...)
+	if s.hasTestingFeature(domain.EntitySpecification{}, spec) {
+		elements = append(elements, s.generateWorkersTestElement(spec), s.generateJobsTestElement(spec))
+	}
+	return elements
+}
+
+// generateWorkerJobElement generates the jobs, handlers and retry policies of the workers package
+func (s *OrchestratorService) generateWorkerJobElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// Job is a unit of work stored in a queue until the handler registered for its name runs it
+type Job struct {
+	ID        string
+	Name      string
+	Queue     string
+	Payload   json.RawMessage
+	Attempts  int       // Attempts made so far, the running one included
+	RunAt     time.Time // Earliest time the job runs; zero runs it right away
+	LastError string    // Error of the last failed attempt
+}
+
+// Decode decodes the JSON payload of the job into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Handler runs jobs. Errors retry the job with backoff until its RetryPolicy gives up and
+// dead-letters it; errors wrapped with Permanent dead-letter it right away.
+type Handler interface {
+	Handle(ctx context.Context, job *Job) error
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Handle calls f(ctx, job)
+func (f HandlerFunc) Handle(ctx context.Context, job *Job) error {
+	return f(ctx, job)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks the error of a job that retrying cannot fix, such as an invalid payload
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// RetryPolicy tells how often and when failed jobs are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts before the job is dead-lettered; 1 disables retries
+	Backoff     time.Duration // Delay before the first retry, doubled on each later one
+	MaxBackoff  time.Duration // Cap of the delay; 0 leaves it uncapped
+	Timeout     time.Duration // Deadline of each attempt; 0 sets none
+}
+
+// DefaultRetryPolicy is the policy of jobs registered without one
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Hour}
+
+// Delay returns the delay before retrying a job whose attempt-th attempt failed
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+`
+	return s.newFileElement("Job", "workers", "internal/workers/job.go", content)
+}
+
+// generateWorkerQueueElement generates the queue interface the pool and scheduler work with
+func (s *OrchestratorService) generateWorkerQueueElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoJob is returned by Queue.Dequeue when no job is ready
+var ErrNoJob = errors.New("no job ready")
+
+// ErrUnknownJob is returned when settling a job the queue does not hold as running, or an
+// attempt of a job that has since been claimed again
+var ErrUnknownJob = errors.New("job is not running")
+
+// Queue stores jobs until workers run them. Concurrent workers, of one process or more, never
+// dequeue the same job while it runs.
+type Queue interface {
+	// Enqueue adds a job, assigning its ID. Jobs without a queue go to "default".
+	Enqueue(ctx context.Context, job *Job) error
+	// Dequeue claims the ready job of the queues that is due first, of any queue when none is
+	// given, counting an attempt. It returns ErrNoJob when no job is ready.
+	Dequeue(ctx context.Context, queues ...string) (*Job, error)
+	// Complete removes a job that ran successfully
+	Complete(ctx context.Context, job *Job) error
+	// Retry releases a failed job with its LastError, to run again at runAt
+	Retry(ctx context.Context, job *Job, runAt time.Time) error
+	// DeadLetter keeps a job that will not be retried, with its LastError, for inspection
+	DeadLetter(ctx context.Context, job *Job) error
+}
+`
+	return s.newFileElement("Queue", "workers", "internal/workers/queue.go", content)
+}
+
+// generateWorkerMemoryQueueElement generates the in-memory queue of tests and single processes
+func (s *OrchestratorService) generateWorkerMemoryQueueElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryQueue is a Queue held in memory, for tests and single processes that can lose their
+// jobs on exit
+type MemoryQueue struct {
+	mu      sync.Mutex
+	lastID  int64
+	pending []*Job
+	running map[string]*Job
+	dead    []*Job
+}
+
+// NewMemoryQueue creates an empty in-memory queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{running: make(map[string]*Job)}
+}
+
+// Enqueue adds a copy of the job
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.lastID++
+	job.ID = strconv.FormatInt(q.lastID, 10)
+	if job.Queue == "" {
+		job.Queue = "default"
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	stored := *job
+	q.pending = append(q.pending, &stored)
+	return nil
+}
+
+// Dequeue claims the ready job of the queues that is due first
+func (q *MemoryQueue) Dequeue(ctx context.Context, queues ...string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	next := -1
+	for i, job := range q.pending {
+		if job.RunAt.After(now) || !inQueues(job.Queue, queues) {
+			continue
+		}
+		if next < 0 || job.RunAt.Before(q.pending[next].RunAt) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, ErrNoJob
+	}
+	job := q.pending[next]
+	q.pending = append(q.pending[:next], q.pending[next+1:]...)
+	job.Attempts++
+	q.running[job.ID] = job
+	claimed := *job
+	return &claimed, nil
+}
+
+func inQueues(queue string, queues []string) bool {
+	if len(queues) == 0 {
+		return true
+	}
+	for _, q := range queues {
+		if q == queue {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete removes a running job
+func (q *MemoryQueue) Complete(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if stored, ok := q.running[job.ID]; !ok || stored.Attempts != job.Attempts {
+		return ErrUnknownJob
+	}
+	delete(q.running, job.ID)
+	return nil
+}
+
+// Retry releases a running job, to run again at runAt
+func (q *MemoryQueue) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, ok := q.running[job.ID]
+	if !ok || stored.Attempts != job.Attempts {
+		return ErrUnknownJob
+	}
+	delete(q.running, job.ID)
+	stored.RunAt, stored.LastError = runAt, job.LastError
+	q.pending = append(q.pending, stored)
+	return nil
+}
+
+// DeadLetter moves a running job to the dead letters
+func (q *MemoryQueue) DeadLetter(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, ok := q.running[job.ID]
+	if !ok || stored.Attempts != job.Attempts {
+		return ErrUnknownJob
+	}
+	delete(q.running, job.ID)
+	stored.LastError = job.LastError
+	q.dead = append(q.dead, stored)
+	return nil
+}
+
+// Len returns the number of pending and running jobs
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) + len(q.running)
+}
+
+// DeadLetters returns copies of the dead-lettered jobs, in the order they were dead-lettered
+func (q *MemoryQueue) DeadLetters() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]Job, len(q.dead))
+	for i, job := range q.dead {
+		jobs[i] = *job
+	}
+	return jobs
+}
+`
+	return s.newFileElement("MemoryQueue", "workers", "internal/workers/memory.go", content)
+}
+
+// generateWorkerPostgresQueueElement generates the queue stored in the worker_jobs table, which
+// workers claim jobs of with SELECT ... FOR UPDATE SKIP LOCKED
+func (s *OrchestratorService) generateWorkerPostgresQueueElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLease is the time a worker holds the jobs it claims from a PostgresQueue
+const DefaultLease = 5 * time.Minute
+
+// PostgresQueue is a Queue stored in the worker_jobs table of a PostgreSQL database. Workers
+// claim jobs with SELECT ... FOR UPDATE SKIP LOCKED, so that the workers of any number of
+// processes share the table without claiming a job twice; the jobs of a worker that stopped
+// without settling them are claimed again once their lease expires.
+type PostgresQueue struct {
+	db *sql.DB
+	// Lease is the time a claimed job stays with its worker; it must exceed the run time of jobs
+	Lease time.Duration
+}
+
+// NewPostgresQueue creates the queue of the worker_jobs table of db with the DefaultLease
+func NewPostgresQueue(db *sql.DB) *PostgresQueue {
+	return &PostgresQueue{db: db, Lease: DefaultLease}
+}
+
+// Enqueue inserts the job
+func (q *PostgresQueue) Enqueue(ctx context.Context, job *Job) error {
+	if job.Queue == "" {
+		job.Queue = "default"
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	payload := string(job.Payload)
+	if payload == "" {
+		payload = "null"
+	}
+	err := q.db.QueryRowContext(ctx,
+		"INSERT INTO worker_jobs (name, queue, payload, run_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		job.Name, job.Queue, payload, job.RunAt,
+	).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job %s: %w", job.Name, err)
+	}
+	return nil
+}
+
+// dequeueSQL claims the ready job due first, skipping the rows other workers are claiming
+const dequeueSQL = ` + "`" + `
+UPDATE worker_jobs
+SET status = 'running', attempts = attempts + 1, locked_until = now() + make_interval(secs => $2)
+WHERE id = (
+    SELECT id FROM worker_jobs
+    WHERE ($1 = '' OR queue = ANY(string_to_array($1, ',')))
+      AND run_at <= now()
+      AND (status = 'pending' OR status = 'running' AND locked_until < now())
+    ORDER BY run_at, id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, name, queue, payload, attempts, run_at, last_error` + "`" + `
+
+// Dequeue claims the ready job of the queues that is due first
+func (q *PostgresQueue) Dequeue(ctx context.Context, queues ...string) (*Job, error) {
+	var job Job
+	var payload string
+	err := q.db.QueryRowContext(ctx, dequeueSQL, strings.Join(queues, ","), q.Lease.Seconds()).
+		Scan(&job.ID, &job.Name, &job.Queue, &payload, &job.Attempts, &job.RunAt, &job.LastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoJob
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+	}
+	job.Payload = []byte(payload)
+	return &job, nil
+}
+
+// Complete deletes a running job
+func (q *PostgresQueue) Complete(ctx context.Context, job *Job) error {
+	return q.settle(ctx, job, "DELETE FROM worker_jobs WHERE id = $1 AND attempts = $2 AND status = 'running'")
+}
+
+// Retry releases a running job, to run again at runAt
+func (q *PostgresQueue) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	return q.settle(ctx, job,
+		"UPDATE worker_jobs SET status = 'pending', locked_until = NULL, last_error = $3, run_at = $4 WHERE id = $1 AND attempts = $2 AND status = 'running'",
+		job.LastError, runAt)
+}
+
+// DeadLetter marks a running job as dead, keeping it in the table for inspection
+func (q *PostgresQueue) DeadLetter(ctx context.Context, job *Job) error {
+	return q.settle(ctx, job,
+		"UPDATE worker_jobs SET status = 'dead', locked_until = NULL, last_error = $3 WHERE id = $1 AND attempts = $2 AND status = 'running'",
+		job.LastError)
+}
+
+// settle runs a query settling the attempt of a job the worker claimed. Queries are fenced on the
+// attempt number, so that a worker whose lease expired cannot settle the job once another worker
+// claimed it again; settle returns ErrUnknownJob then.
+func (q *PostgresQueue) settle(ctx context.Context, job *Job, query string, args ...interface{}) error {
+	result, err := q.db.ExecContext(ctx, query, append([]interface{}{job.ID, job.Attempts}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to settle job %s: %w", job.ID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrUnknownJob
+	}
+	return nil
+}
+`
+	return s.newFileElement("PostgresQueue", "workers", "internal/workers/postgres.go", content)
+}
+
+// generateWorkerPoolElement generates the pool running jobs with retries, dead-lettering and
+// graceful shutdown
+func (s *OrchestratorService) generateWorkerPoolElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// settleTimeout bounds the time the queue has to complete, retry or dead-letter a job
+const settleTimeout = 10 * time.Second
+
+// Pool runs the jobs of a queue with a fixed number of workers
+type Pool struct {
+	queue       Queue
+	concurrency int
+	handlers    map[string]registration
+
+	// Queues are the queues the workers dequeue from; empty means all of them
+	Queues []string
+	// PollInterval is the wait of idle workers before they poll the queue again
+	PollInterval time.Duration
+	// ShutdownTimeout is the time running jobs have to finish once Run is stopped, after which
+	// their contexts are cancelled
+	ShutdownTimeout time.Duration
+	Logger          *slog.Logger
+}
+
+type registration struct {
+	handler Handler
+	policy  RetryPolicy
+}
+
+// NewPool creates a pool of concurrency workers, or of GOMAXPROCS workers when it is not positive
+func NewPool(queue Queue, concurrency int) *Pool {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{
+		queue:           queue,
+		concurrency:     concurrency,
+		handlers:        make(map[string]registration),
+		PollInterval:    time.Second,
+		ShutdownTimeout: 30 * time.Second,
+		Logger:          slog.Default(),
+	}
+}
+
+// Concurrency returns the number of workers of the pool
+func (p *Pool) Concurrency() int {
+	return p.concurrency
+}
+
+// Register sets the handler and retry policy of the jobs of a name. It is called before Run.
+func (p *Pool) Register(name string, handler Handler, policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	p.handlers[name] = registration{handler: handler, policy: policy}
+}
+
+// Run runs jobs until ctx is done, then waits for the running jobs to finish. Jobs still running
+// after the ShutdownTimeout are cancelled, and released by their queue once their handlers return.
+func (p *Pool) Run(ctx context.Context) error {
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, jobCtx)
+		}()
+	}
+	<-ctx.Done()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(p.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		cancelJobs()
+		<-done
+		return errors.New("shutdown timed out, running jobs were cancelled")
+	}
+}
+
+// work runs jobs until ctx is done; jobs run with jobCtx, which outlives ctx during shutdown
+func (p *Pool) work(ctx, jobCtx context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.queue.Dequeue(jobCtx, p.Queues...)
+		if err != nil {
+			if !errors.Is(err, ErrNoJob) {
+				p.Logger.Error("failed to dequeue job", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(p.PollInterval):
+			}
+			continue
+		}
+		p.process(jobCtx, job)
+	}
+}
+
+// process runs a job and completes, retries or dead-letters it
+func (p *Pool) process(ctx context.Context, job *Job) {
+	logger := p.Logger.With("job", job.Name, "id", job.ID, "attempt", job.Attempts)
+	reg, ok := p.handlers[job.Name]
+	if !ok {
+		reg.policy.MaxAttempts = 1
+	}
+
+	start := time.Now()
+	err := p.run(ctx, reg, job)
+
+	// Jobs cancelled at shutdown are still settled, so the queue gets a context of its own
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+	switch {
+	case err == nil:
+		logger.Info("job completed", "duration", time.Since(start))
+		err = p.queue.Complete(ctx, job)
+	case job.Attempts >= reg.policy.MaxAttempts || IsPermanent(err):
+		job.LastError = err.Error()
+		logger.Error("job dead-lettered", "error", err)
+		err = p.queue.DeadLetter(ctx, job)
+	default:
+		job.LastError = err.Error()
+		delay := reg.policy.Delay(job.Attempts)
+		logger.Warn("job failed, retrying", "error", err, "delay", delay)
+		err = p.queue.Retry(ctx, job, time.Now().Add(delay))
+	}
+	if err != nil {
+		logger.Error("failed to settle job", "error", err)
+	}
+}
+
+// run calls the handler of a job within the timeout of its policy, turning panics into errors
+func (p *Pool) run(ctx context.Context, reg registration, job *Job) (err error) {
+	if reg.handler == nil {
+		return fmt.Errorf("no handler is registered for job %s", job.Name)
+	}
+	if reg.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, reg.policy.Timeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return reg.handler.Handle(ctx, job)
+}
+`
+	return s.newFileElement("Pool", "workers", "internal/workers/pool.go", content)
+}
+
+// generateWorkerScheduleElement generates the cron schedules and the scheduler enqueuing jobs
+// on them
+func (s *OrchestratorService) generateWorkerScheduleElement() domain.CodeElement {
+	content := `package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the schedules accepted in place of a cron expression
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// fields are the fields of cron expressions with their ranges
+var fields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Schedule tells when a scheduled job is enqueued
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Sets of the matching values, by bit
+	domStar, dowStar              bool   // Whether the day fields start with *
+	every                         time.Duration
+}
+
+// ParseSchedule parses a cron expression of five fields: minute, hour, day of month, month and
+// day of week, Sunday being 0 or 7. Fields are *, values, ranges such as 1-5 and lists of them,
+// stepped with /n as in */15. When both day fields are restricted, days matching either match.
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly are accepted too, as is
+// "@every <duration>" for fixed intervals.
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if every, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || d <= 0 {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: @every needs a positive duration", expr)
+		}
+		return Schedule{every: d}, nil
+	}
+	cron := expr
+	if descriptor, ok := descriptors[expr]; ok {
+		cron = descriptor
+	}
+	values := strings.Fields(cron)
+	if len(values) != len(fields) {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: want 5 fields, minute, hour, day of month, month and day of week", expr)
+	}
+
+	var schedule Schedule
+	sets := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range fields {
+		set, err := parseField(values[i], field.min, field.max)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: %s: %w", expr, field.name, err)
+		}
+		*sets[i] = set
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domStar, schedule.dowStar = strings.HasPrefix(values[2], "*"), strings.HasPrefix(values[4], "*")
+	return schedule, nil
+}
+
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		values, stepText, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("step %q is not a positive number", stepText)
+			}
+		}
+		lo, hi := min, max
+		if values != "*" {
+			first, last, ranged := strings.Cut(values, "-")
+			var err error
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("value %q is not a number", first)
+			}
+			hi = lo
+			if ranged {
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("value %q is not a number", last)
+				}
+			} else if stepped {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first time after t matching the schedule, in the location of t, or the zero
+// time when none does within five years, as for February 30
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Scheduler enqueues jobs on their schedules. Every running scheduler enqueues the scheduled
+// jobs, so a deployment of several processes runs it in one of them only.
+type Scheduler struct {
+	queue   Queue
+	entries []entry
+	Logger  *slog.Logger
+}
+
+type entry struct {
+	schedule Schedule
+	job      Job
+}
+
+// NewScheduler creates a scheduler enqueuing jobs on queue
+func NewScheduler(queue Queue) *Scheduler {
+	return &Scheduler{queue: queue, Logger: slog.Default()}
+}
+
+// Add enqueues a copy of job at the times of the schedule, parsed with ParseSchedule
+func (s *Scheduler) Add(expr string, job Job) error {
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return err
+	}
+	s.entries = append(s.entries, entry{schedule: schedule, job: job})
+	return nil
+}
+
+// Run enqueues the jobs on their schedules until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	next := make([]time.Time, len(s.entries))
+	now := time.Now()
+	for i, e := range s.entries {
+		next[i] = e.schedule.Next(now)
+	}
+	for {
+		var due time.Time
+		for _, t := range next {
+			if !t.IsZero() && (due.IsZero() || t.Before(due)) {
+				due = t
+			}
+		}
+		if due.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(due))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		for i, e := range s.entries {
+			if next[i].IsZero() || next[i].After(due) {
+				continue
+			}
+			job := e.job
+			job.RunAt = next[i]
+			if err := s.queue.Enqueue(ctx, &job); err != nil {
+				s.Logger.Error("failed to enqueue scheduled job", "job", job.Name, "error", err)
+			}
+			next[i] = e.schedule.Next(next[i])
+		}
+	}
+}
+`
+	return s.newFileElement("Schedule", "workers", "internal/workers/schedule.go", content)
+}
+
+// generateJobsElement generates the jobs package registering the handlers and schedules of the
+// jobs of the specification
+func (s *OrchestratorService) generateJobsElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	var register, schedule, queues strings.Builder
+	imports := map[string]bool{
+		"context": true, "encoding/json": true, "errors": true, "fmt": true,
+		spec.ModulePath + "/internal/workers": true,
+	}
+	for _, job := range spec.Jobs {
+		name := s.toPascalCase(job.Name)
+		fmt.Fprintf(&register, "\tpool.Register(%s, workers.HandlerFunc(Handle%s), %s)\n", name, name, jobRetryPolicy(job))
+		fmt.Fprintf(&queues, "\t%s: %q,\n", name, jobQueue(job))
+		if job.Schedule != "" {
+			fmt.Fprintf(&schedule, `	if err := scheduler.Add(%q, workers.Job{Name: %s, Queue: %q}); err != nil {
+		return fmt.Errorf("job %%s: %%w", %s, err)
+	}
+`, strings.TrimSpace(job.Schedule), name, jobQueue(job), name)
+		}
+	}
+	if register.Len() > 0 {
+		imports["time"] = true
+	}
+
+	content := fmt.Sprintf(`package jobs
+
+import (
+%[1]s)
+
+// ErrNotImplemented is returned by the job handlers until they are implemented
+var ErrNotImplemented = errors.New("not implemented")
+
+// queues are the queues of the jobs, by name
+var queues = map[string]string{
+%[2]s}
+
+// Register registers the handlers of the jobs with their retry policies
+func Register(pool *workers.Pool) {
+%[3]s}
+
+// Schedule adds the jobs with a schedule to the scheduler
+func Schedule(scheduler *workers.Scheduler) error {
+%[4]s	return nil
+}
+
+// Enqueue enqueues the job of the name on its queue, with payload encoded as JSON
+func Enqueue(ctx context.Context, queue workers.Queue, name string, payload interface{}) error {
+	q, ok := queues[name]
+	if !ok {
+		return fmt.Errorf("unknown job %%q", name)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode the payload of job %%s: %%w", name, err)
+	}
+	return queue.Enqueue(ctx, &workers.Job{Name: name, Queue: q, Payload: data})
+}
+`, s.formatImports(imports), queues.String(), register.String(), schedule.String())
+
+	return s.newFileElement("Jobs", "jobs", "internal/jobs/jobs.go", content)
+}
+
+// generateJobHandlerElement generates the name of a job and the stub of its handler
+func (s *OrchestratorService) generateJobHandlerElement(spec *domain.ProjectSpecification, job domain.JobSpecification) domain.CodeElement {
+	name := s.toPascalCase(job.Name)
+	doc := fmt.Sprintf("// %s is the name of the %q job", name, job.Name)
+	if description := strings.TrimSpace(job.Description); description != "" {
+		doc += ": " + strings.Join(strings.Fields(description), " ")
+	}
+
+	content := fmt.Sprintf(`package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"%[1]s/internal/workers"
+)
+
+%[2]s
+const %[3]s = %[4]q
+
+// Handle%[3]s runs the %[4]q job
+func Handle%[3]s(ctx context.Context, job *workers.Job) error {
+	// TODO: implement the %[4]q job
+	return fmt.Errorf("%[4]s: %%w", ErrNotImplemented)
+}
+`, spec.ModulePath, doc, name, job.Name)
+
+	return s.newFileElement(name+"Job", "jobs", fmt.Sprintf("internal/jobs/%s.go", s.toSnakeCase(name)), content)
+}
+
+// generateWorkerMainElement generates the main package of the worker: it loads the configuration,
+// runs the pool and the scheduler on the PostgreSQL queue, or an in-memory one without a
+// postgres database, and serves the health probes until interrupted
+func (s *OrchestratorService) generateWorkerMainElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	binary := s.workerBinary(spec)
+	imports := map[string]bool{
+		"context": true, "log/slog": true, "os": true, "os/signal": true, "syscall": true,
+		spec.ModulePath + "/internal/config":  true,
+		spec.ModulePath + "/internal/jobs":    true,
+		spec.ModulePath + "/internal/workers": true,
+	}
+	observe := s.usesObservability(spec)
+
+	health, probes := "", ""
+	if observe {
+		imports["errors"], imports["net/http"] = true, true
+		imports[spec.ModulePath+"/internal/observability"] = true
+		health = "\n\t// The readiness probe runs the checks of the dependencies added to health\n\thealth := observability.NewHealth()\n"
+		probes = `
+	if cfg.Monitoring.Health || cfg.Monitoring.Profiling {
+		mux := http.NewServeMux()
+		if cfg.Monitoring.Health {
+			mux.Handle("GET /health/live", health.LivenessHandler())
+			mux.Handle("GET /health/ready", health.ReadinessHandler())
+		}
+		if cfg.Monitoring.Profiling {
+			observability.RegisterProfiling(mux)
+		}
+		server := &http.Server{Addr: cfg.Server.Address(), Handler: mux, ReadHeaderTimeout: cfg.Server.Timeout.Duration}
+		go func() {
+			slog.Info("serving probes", "address", server.Addr)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("probe server failed", "error", err)
+			}
+		}()
+		defer server.Close()
+	}
+`
+	}
+
+	driver, hasDriver := s.projectSQLDriver(spec)
+	postgres := hasDriver && spec.Configuration.Database.Type == "postgres"
+	queue := "\t// Jobs are kept in memory, and lost on exit, without a postgres database\n\tvar queue workers.Queue = workers.NewMemoryQueue()\n"
+	if postgres {
+		imports["database/sql"], imports["fmt"], imports["time"] = true, true, true
+		check := ""
+		if observe {
+			check = "\t\thealth.AddCheck(\"database\", db.PingContext)\n"
+		}
+		queue += fmt.Sprintf(`	if cfg.Database.Type == "postgres" {
+		db, err := openDatabase(ctx, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+%s		queue = workers.NewPostgresQueue(db)
+	}
+`, check)
+	}
+
+	content := fmt.Sprintf(`package main
+
+import (
+%[1]s)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("%[2]s stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load(os.Getenv("%[3]s_CONFIG_FILE"))
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(newLogger(cfg.Logging))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+%[4]s
+%[5]s
+	pool := workers.NewPool(queue, cfg.Performance.Workers)
+	pool.ShutdownTimeout = cfg.Server.ShutdownTimeout.Duration
+	jobs.Register(pool)
+	scheduler := workers.NewScheduler(queue)
+	if err := jobs.Schedule(scheduler); err != nil {
+		return err
+	}
+%[6]s
+	go scheduler.Run(ctx)
+	slog.Info("worker started", "workers", pool.Concurrency())
+	// Run returns once interrupted and the running jobs finished or the shutdown timeout expired
+	return pool.Run(ctx)
+}
+`, s.formatImports(imports), binary, s.envPrefix(spec), health, queue, probes)
+	content += newLoggerFunc
+
+	if postgres {
+		content = strings.Replace(content, "\n\t\""+spec.ModulePath+"/internal/config\"", fmt.Sprintf("\n\t_ %q\n\n\t%q", driver.Path, spec.ModulePath+"/internal/config"), 1)
+		content += openDatabaseFunc
+	}
+
+	return s.newFileElement("main", "main", fmt.Sprintf("cmd/%s/main.go", binary), content)
+}
+
+// generateJobMigrationElements generates the migration of the worker_jobs table of the
+// PostgreSQL queue, numbered after those of the entities
+func (s *OrchestratorService) generateJobMigrationElements(spec *domain.ProjectSpecification) []domain.CodeElement {
+	base := fmt.Sprintf("%06d_create_worker_jobs", len(s.migrationOrder(spec))+1)
+	up := `-- Create worker_jobs table for the jobs of the PostgreSQL queue
+CREATE TABLE IF NOT EXISTS worker_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    queue TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT 'null',
+    status TEXT NOT NULL DEFAULT 'pending' CONSTRAINT worker_jobs_status_check CHECK (status IN ('pending', 'running', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Workers claim the jobs due first; dead jobs stay for inspection only
+CREATE INDEX IF NOT EXISTS idx_worker_jobs_run_at ON worker_jobs (run_at, id) WHERE status <> 'dead';
+`
+	down := `-- Drop worker_jobs table
+DROP TABLE IF EXISTS worker_jobs;
+`
+	return []domain.CodeElement{
+		{
+			Type:     "file",
+			Name:     base + ".up",
+			Package:  "migrations",
+			Body:     up,
+			Metadata: map[string]interface{}{"path": "migrations/" + base + ".up.sql"},
+		},
+		{
+			Type:     "file",
+			Name:     base + ".down",
+			Package:  "migrations",
+			Body:     down,
+			Metadata: map[string]interface{}{"path": "migrations/" + base + ".down.sql"},
+		},
+	}
+}
+
+// generateWorkersTestElement tests the retry policies, schedules, pool and scheduler of the
+// workers package on the in-memory queue
+func (s *OrchestratorService) generateWorkersTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	content := fmt.Sprintf(`package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"%s/internal/workers"
+)
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %%s", what)
+		}
+	}
+}
+
+// startPool runs a pool of two workers polling every millisecond, and returns the function
+// stopping it with the error of Run
+func startPool(t *testing.T, pool *workers.Pool) func() error {
+	t.Helper()
+	pool.PollInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- pool.Run(ctx) }()
+	t.Cleanup(cancel)
+	return func() error {
+		cancel()
+		return <-errc
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := workers.RetryPolicy{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 100: 10 * time.Second} {
+		if got := policy.Delay(attempt); got != want {
+			t.Errorf("Delay(%%d) = %%v, want %%v", attempt, got, want)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2024, time.March, 8, 10, 7, 30, 0, time.UTC) // A Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, time.March, 8, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)},
+		{"30 8,20 * * *", time.Date(2024, time.March, 8, 20, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 8, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		schedule, err := workers.ParseSchedule(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%%q) error = %%v", tt.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%%q: Next() = %%v, want %%v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every -1s"} {
+		if _, err := workers.ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%%q) error = nil, want an invalid schedule", expr)
+		}
+	}
+}
+
+func TestMemoryQueueSettlesOnlyTheClaimedAttempt(t *testing.T) {
+	queue := workers.NewMemoryQueue()
+	ctx := context.Background()
+	if err := queue.Enqueue(ctx, &workers.Job{Name: "send"}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := queue.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Retry(ctx, first, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	second, err := queue.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := queue.Complete(ctx, first); !errors.Is(err, workers.ErrUnknownJob) {
+		t.Errorf("Complete() of a previous attempt error = %%v, want ErrUnknownJob", err)
+	}
+	if err := queue.DeadLetter(ctx, first); !errors.Is(err, workers.ErrUnknownJob) {
+		t.Errorf("DeadLetter() of a previous attempt error = %%v, want ErrUnknownJob", err)
+	}
+	if err := queue.Complete(ctx, second); err != nil {
+		t.Errorf("Complete() of the claimed attempt error = %%v", err)
+	}
+}
+
+func TestPoolRetriesThenCompletes(t *testing.T) {
+	queue := workers.NewMemoryQueue()
+	pool := workers.NewPool(queue, 2)
+	var calls atomic.Int32
+	pool.Register("flaky", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		if calls.Add(1) < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}), workers.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	stop := startPool(t, pool)
+
+	if err := queue.Enqueue(context.Background(), &workers.Job{Name: "flaky"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the job to complete", func() bool { return queue.Len() == 0 })
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 3 || len(queue.DeadLetters()) != 0 {
+		t.Errorf("calls = %%d, dead letters = %%v, want 3 calls and none", calls.Load(), queue.DeadLetters())
+	}
+}
+
+func TestPoolDeadLetters(t *testing.T) {
+	queue := workers.NewMemoryQueue()
+	pool := workers.NewPool(queue, 2)
+	pool.Register("failing", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		return errors.New("always fails")
+	}), workers.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	pool.Register("invalid", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		return workers.Permanent(errors.New("invalid payload"))
+	}), workers.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+	pool.Register("panicking", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		panic("boom")
+	}), workers.RetryPolicy{MaxAttempts: 1})
+	stop := startPool(t, pool)
+
+	want := map[string]int{"failing": 3, "invalid": 1, "panicking": 1, "unregistered": 1}
+	for _, name := range []string{"failing", "invalid", "panicking", "unregistered"} {
+		if err := queue.Enqueue(context.Background(), &workers.Job{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "the jobs to be dead-lettered", func() bool { return len(queue.DeadLetters()) == len(want) })
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range queue.DeadLetters() {
+		if job.Attempts != want[job.Name] || job.LastError == "" {
+			t.Errorf("dead letter %%s: attempts = %%d, last error %%q, want %%d attempts and an error", job.Name, job.Attempts, job.LastError, want[job.Name])
+		}
+	}
+}
+
+func TestPoolGracefulShutdown(t *testing.T) {
+	queue := workers.NewMemoryQueue()
+	pool := workers.NewPool(queue, 1)
+	started, release := make(chan struct{}), make(chan struct{})
+	var finished atomic.Bool
+	pool.Register("slow", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		close(started)
+		select {
+		case <-release:
+			finished.Store(true)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}), workers.DefaultRetryPolicy)
+	stop := startPool(t, pool)
+
+	if err := queue.Enqueue(context.Background(), &workers.Job{Name: "slow"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	if err := stop(); err != nil || !finished.Load() {
+		t.Fatalf("Run() error = %%v, job finished = %%t, want the running job to finish", err, finished.Load())
+	}
+	if queue.Len() != 0 {
+		t.Errorf("queue holds %%d jobs after shutdown, want the finished job completed", queue.Len())
+	}
+}
+
+// contextQueue is a MemoryQueue that, like a database queue, fails to settle jobs with a done context
+type contextQueue struct {
+	*workers.MemoryQueue
+}
+
+func (q contextQueue) Complete(ctx context.Context, job *workers.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return q.MemoryQueue.Complete(ctx, job)
+}
+
+func (q contextQueue) Retry(ctx context.Context, job *workers.Job, runAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return q.MemoryQueue.Retry(ctx, job, runAt)
+}
+
+func (q contextQueue) DeadLetter(ctx context.Context, job *workers.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return q.MemoryQueue.DeadLetter(ctx, job)
+}
+
+func TestPoolShutdownTimeout(t *testing.T) {
+	queue := contextQueue{workers.NewMemoryQueue()}
+	pool := workers.NewPool(queue, 1)
+	pool.ShutdownTimeout = 10 * time.Millisecond
+	started := make(chan struct{})
+	pool.Register("stuck", workers.HandlerFunc(func(ctx context.Context, job *workers.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}), workers.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+	stop := startPool(t, pool)
+
+	if err := queue.Enqueue(context.Background(), &workers.Job{Name: "stuck"}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := stop(); err == nil {
+		t.Fatal("Run() error = nil, want the shutdown to time out")
+	}
+	// The cancelled job is released to be retried
+	var job *workers.Job
+	waitFor(t, "the cancelled job to be released", func() bool {
+		job, _ = queue.Dequeue(context.Background())
+		return job != nil
+	})
+	if job.Name != "stuck" || job.LastError == "" {
+		t.Errorf("Dequeue() = %%+v, want the cancelled job with its error", job)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	queue := workers.NewMemoryQueue()
+	scheduler := workers.NewScheduler(queue)
+	if err := scheduler.Add("@every 5ms", workers.Job{Name: "tick", Queue: "ticks"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Add("not a schedule", workers.Job{Name: "invalid"}); err == nil {
+		t.Error("Add() error = nil, want an invalid schedule")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, "scheduled jobs", func() bool { return queue.Len() >= 2 })
+	cancel()
+	<-done
+
+	job, err := queue.Dequeue(context.Background(), "ticks")
+	if err != nil || job.Name != "tick" {
+		t.Fatalf("Dequeue() = %%v, %%v, want a tick job", job, err)
+	}
+	if _, err := queue.Dequeue(context.Background(), "other"); !errors.Is(err, workers.ErrNoJob) {
+		t.Errorf("Dequeue() of another queue error = %%v, want ErrNoJob", err)
+	}
+}
+`, spec.ModulePath)
+	return s.newFileElement("WorkersTest", "workers_test", "internal/workers/workers_test.go", content)
+}
+
+// generateJobsTestElement tests that the schedules of the jobs parse and that the jobs are
+// enqueued on their queues
+func (s *OrchestratorService) generateJobsTestElement(spec *domain.ProjectSpecification) domain.CodeElement {
+	var cases strings.Builder
+	for _, job := range spec.Jobs {
+		fmt.Fprintf(&cases, "\t\t{jobs.%s, %q},\n", s.toPascalCase(job.Name), jobQueue(job))
+	}
+
+	content := fmt.Sprintf(`package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"%[1]s/internal/jobs"
+	"%[1]s/internal/workers"
+)
+
+func TestSchedule(t *testing.T) {
+	if err := jobs.Schedule(workers.NewScheduler(workers.NewMemoryQueue())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	tests := []struct {
+		name  string
+		queue string
+	}{
+%[2]s	}
+	for _, tt := range tests {
+		queue := workers.NewMemoryQueue()
+		if err := jobs.Enqueue(context.Background(), queue, tt.name, map[string]string{"key": "value"}); err != nil {
+			t.Fatalf("Enqueue(%%s) error = %%v", tt.name, err)
+		}
+		job, err := queue.Dequeue(context.Background(), tt.queue)
+		if err != nil {
+			t.Fatalf("%%s: Dequeue(%%s) error = %%v", tt.name, tt.queue, err)
+		}
+		var payload map[string]string
+		if err := job.Decode(&payload); err != nil || payload["key"] != "value" {
+			t.Errorf("%%s: payload = %%s, want the enqueued one", tt.name, job.Payload)
+		}
+	}
+
+	if err := jobs.Enqueue(context.Background(), workers.NewMemoryQueue(), "unknown", nil); err == nil {
+		t.Error("Enqueue() of an unknown job error = nil, want an error")
+	}
+}
+`, spec.ModulePath, cases.String())
+	return s.newFileElement("JobsTest", "jobs_test", "internal/jobs/jobs_test.go", content)
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+)
+
+func TestGeneratedWorker(t *testing.T) {
+	spec := &domain.ProjectSpecification{
+		Name:        "mailer",
+		ModulePath:  "example.com/mailer",
+		ProjectType: "worker",
+		Features:    []string{"testing"},
+		Configuration: domain.ProjectConfiguration{
+			Monitoring: &domain.MonitoringConfiguration{Health: true},
+		},
+		Jobs: []domain.JobSpecification{
+			{Name: "send_digest", Schedule: "0 7 * * 1-5", MaxAttempts: 5, Backoff: "30s", Timeout: "5m"},
+			{Name: "deliver-email", Queue: "emails"},
+		},
+	}
+
+	dir := checkGeneratedProject(t, spec)
+	for _, path := range []string{"internal/jobs/send_digest.go", "internal/jobs/deliver_email.go", "internal/workers/pool.go", "internal/workers/schedule.go"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("%s is not generated: %v", path, err)
+		}
+	}
+	jobs, err := os.ReadFile(filepath.Join(dir, "internal", "jobs", "jobs.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"workers.RetryPolicy{MaxAttempts: 5, Backoff: 30 * time.Second",
+		`scheduler.Add("0 7 * * 1-5", workers.Job{Name: SendDigest, Queue: "default"})`,
+		`DeliverEmail: "emails"`,
+	} {
+		if !strings.Contains(string(jobs), want) {
+			t.Errorf("jobs.go does not contain %s:\n%s", want, jobs)
+		}
+	}
+
+	// The worker serves its probes until SIGTERM, then stops cleanly
+	binary := filepath.Join(t.TempDir(), "mailer")
+	if out, err := goCommand(dir, "build", "-o", binary, "./cmd/mailer"); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cmd := exec.Command(binary)
+	cmd.Env = append(os.Environ(), "MAILER_SERVER_HOST=127.0.0.1", fmt.Sprintf("MAILER_SERVER_PORT=%d", port))
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	defer cmd.Process.Kill()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/health/live", port)
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(50 * time.Millisecond) {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("GET /health/live = %d", resp.StatusCode)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /health/live: %v", err)
+		}
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Errorf("worker exited with %v after SIGTERM", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Error("worker did not stop after SIGTERM")
+	}
+}
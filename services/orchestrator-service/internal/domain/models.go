@@ -77,11 +77,29 @@ type ProjectSpecification struct {
 	Entities      []EntitySpecification   `json:"entities"`
 	Commands      []CommandSpecification  `json:"commands,omitempty"`  // For CLI projects
 	Endpoints     []EndpointSpecification `json:"endpoints,omitempty"` // For API projects
+	Jobs          []JobSpecification      `json:"jobs,omitempty"`      // For worker projects
 	Services      []ServiceSpecification  `json:"services,omitempty"`  // For microservice projects
 	Features      []string                `json:"features,omitempty"`  // ["docker", "makefile", "tests", "monitoring", "logging"]
 	Dependencies  []string                `json:"dependencies,omitempty"`
+	Types         []TypeDefinition        `json:"types,omitempty"` // Custom field types, in addition to BuiltinTypes
 	Configuration ProjectConfiguration    `json:"configuration,omitempty"`
 	Options       map[string]string       `json:"options,omitempty"`
+	ProtoLock     *ProtoLock              `json:"proto_lock,omitempty"` // Protobuf numbering of the previous generation, to keep it stable
+}
+
+// ProtoLock records the numbers assigned to protobuf fields and enum values, keyed by message or
+// enum name. Generated projects carry it in api/proto/proto.lock.json; passing it back as
+// ProjectSpecification.ProtoLock keeps existing numbers and reserves the numbers of removed ones.
+type ProtoLock struct {
+	Messages map[string]ProtoNumbering `json:"messages,omitempty"`
+	Enums    map[string]ProtoNumbering `json:"enums,omitempty"`
+}
+
+// ProtoNumbering is the numbering of the fields of a message or the values of an enum
+type ProtoNumbering struct {
+	Numbers       map[string]int32 `json:"numbers"`                  // Proto field or value name -> number
+	Reserved      []int32          `json:"reserved,omitempty"`       // Numbers of removed fields or values
+	ReservedNames []string         `json:"reserved_names,omitempty"` // Names of removed fields or values
 }
 
 // CommandSpecification represents CLI commands for CLI projects
@@ -94,6 +112,17 @@ type CommandSpecification struct {
 	Handler     string                 `json:"handler,omitempty"` // Handler function name
 }
 
+// JobSpecification represents background jobs of worker projects
+type JobSpecification struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Queue       string `json:"queue,omitempty"`        // Queue the job is enqueued on, "default" when empty
+	Schedule    string `json:"schedule,omitempty"`     // Cron expression (e.g. "*/15 * * * *") or descriptor (e.g. "@daily") enqueuing the job
+	MaxAttempts int    `json:"max_attempts,omitempty"` // Attempts before the job is dead-lettered, 3 when 0
+	Backoff     string `json:"backoff,omitempty"`      // Delay before the first retry, doubled on each later one, "1s" when empty
+	Timeout     string `json:"timeout,omitempty"`      // Deadline of each attempt, none when empty
+}
+
 // FlagSpecification represents CLI flags
 type FlagSpecification struct {
 	Name        string `json:"name"`
@@ -115,6 +144,7 @@ type EndpointSpecification struct {
 	Request     *RequestSpecification    `json:"request,omitempty"`
 	Response    *ResponseSpecification   `json:"response,omitempty"`
 	Security    []string                 `json:"security,omitempty"` // ["jwt", "api_key", "oauth"]
+	Roles       []string                 `json:"roles,omitempty"`    // Roles allowed by rbac authorization, any of
 }
 
 // ParameterSpecification represents endpoint parameters
@@ -164,6 +194,7 @@ type ProjectConfiguration struct {
 // ServerConfiguration represents server configuration
 type ServerConfiguration struct {
 	Port       int                `json:"port,omitempty"`
+	GRPCPort   int                `json:"grpc_port,omitempty"`
 	Host       string             `json:"host,omitempty"`
 	TLS        bool               `json:"tls,omitempty"`
 	Timeout    string             `json:"timeout,omitempty"`
@@ -239,9 +270,10 @@ type RateLimitConfig struct {
 }
 
 type CachingConfiguration struct {
-	Type string `json:"type,omitempty"` // "redis", "memory", "memcached"
-	TTL  string `json:"ttl,omitempty"`
-	Size int    `json:"size,omitempty"`
+	Type    string `json:"type,omitempty"` // "redis", "memory", "memcached"
+	TTL     string `json:"ttl,omitempty"`
+	Size    int    `json:"size,omitempty"`
+	Address string `json:"address,omitempty"` // host:port of the redis server
 }
 
 type BufferingConfig struct {
@@ -263,22 +295,23 @@ type GenerationRequest struct {
 
 // GeneratorPayload represents the detailed payload sent to the generator service (legacy)
 type GeneratorPayload struct {
-	OutputPath string        `json:"output_path"`
-	ModulePath string        `json:"module_path"`
-	Elements   []CodeElement `json:"elements"`
+	OutputPath string             `json:"output_path"`
+	ModulePath string             `json:"module_path"`
+	Elements   []CodeElement      `json:"elements"`
+	Modules    []ModuleDependency `json:"modules,omitempty"` // Modules to require in go.mod for the field types used
 }
 
 // CodeElement represents a code element in the generator payload
 type CodeElement struct {
-	Type       string                 `json:"type"` // "struct", "function", "interface"
+	Type       string                 `json:"type"` // "struct", "function", "interface", "file"
 	Name       string                 `json:"name"`
 	Package    string                 `json:"package"`
 	Fields     []FieldElement         `json:"fields,omitempty"`
 	Parameters []ParameterElement     `json:"parameters,omitempty"`
 	Returns    []ReturnElement        `json:"returns,omitempty"`
-	Body       string                 `json:"body,omitempty"`
+	Body       string                 `json:"body,omitempty"` // Function body, or the full content for "file" elements
 	Methods    []MethodElement        `json:"methods,omitempty"`
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"` // "file" elements carry their output path as metadata["path"]
 }
 
 // FieldElement represents a struct field
@@ -316,81 +349,177 @@ type OrchestrationResult struct {
 	ErrorMessage      string               `json:"error_message,omitempty"`
 	GeneratedFiles    int                  `json:"generated_files"`
 	ProcessingTime    time.Duration        `json:"processing_time"`
+	Features          []FeatureResolution  `json:"features,omitempty"` // Features enabled for the project and each entity
 	CreatedAt         time.Time            `json:"created_at"`
 }
 
-// TypeMapping maps user-friendly types to Go types
-var TypeMapping = map[string]string{
-	"string":    "string",
-	"integer":   "int",
-	"int":       "int",
-	"int32":     "int32",
-	"int64":     "int64",
-	"float":     "float64",
-	"float32":   "float32",
-	"float64":   "float64",
-	"boolean":   "bool",
-	"bool":      "bool",
-	"email":     "string",
-	"password":  "string",
-	"url":       "string",
-	"timestamp": "time.Time",
-	"datetime":  "time.Time",
-	"date":      "time.Time",
-	"time":      "time.Time",
-	"uuid":      "string",
-	"id":        "string",
-	"text":      "string",
-	"longtext":  "string",
-	"json":      "json.RawMessage",
-	"jsonb":     "json.RawMessage",
-	"array":     "[]interface{}",
-	"slice":     "[]string",
-	"map":       "map[string]interface{}",
-	"binary":    "[]byte",
-	"bytes":     "[]byte",
-	"decimal":   "decimal.Decimal",
-	"money":     "decimal.Decimal",
-	"enum":      "string",
-}
-
-// FeatureMapping maps features to implementation details
-var FeatureMapping = map[string][]string{
+// FeatureResolution lists the features enabled for an entity, or for the project when Entity is
+// empty, in the order they were enabled, with the reason each one was enabled
+type FeatureResolution struct {
+	Entity   string            `json:"entity,omitempty"`
+	Features []string          `json:"features"`
+	Reasons  map[string]string `json:"reasons"` // e.g. "requested by the entity", "required by rest_api"
+}
+
+// IntrospectionRequest represents a request to reverse-engineer a project specification from an existing database
+type IntrospectionRequest struct {
+	Connection    DatabaseConnection `json:"connection"`
+	Schema        string             `json:"schema,omitempty"`         // Defaults to "public"
+	Tables        []string           `json:"tables,omitempty"`         // Only introspect these tables
+	ExcludeTables []string           `json:"exclude_tables,omitempty"` // Tables to skip
+	Features      []string           `json:"features,omitempty"`       // Entity features, defaults to ["crud", "validation", "rest_api"]
+	ProjectName   string             `json:"project_name,omitempty"`   // Defaults to the database name
+	ModulePath    string             `json:"module_path,omitempty"`
+	OutputPath    string             `json:"output_path,omitempty"`
+	ProjectType   string             `json:"project_type,omitempty"` // Defaults to "microservice"
+}
+
+// DatabaseConnection represents the connection settings of a database to introspect
+type DatabaseConnection struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"` // Defaults to 5432
+	User     string `json:"user"`
+	Password string `json:"password,omitempty"`
+	Database string `json:"database"`
+	SSLMode  string `json:"ssl_mode,omitempty"` // Defaults to "disable"
+}
+
+// StructImportRequest represents a request to import entity specifications from Go struct definitions
+type StructImportRequest struct {
+	Files    []SourceFile `json:"files,omitempty"`    // Inline Go source files
+	Paths    []string     `json:"paths,omitempty"`    // Go files or directories, relative to the specification base directory
+	Structs  []string     `json:"structs,omitempty"`  // Only import these structs
+	Features []string     `json:"features,omitempty"` // Entity features, defaults to ["crud", "validation", "rest_api"]
+}
+
+// SourceFile represents a named source file sent inline with a request
+type SourceFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// ValidationIssue describes a single problem found in a project specification
+type ValidationIssue struct {
+	Path     string `json:"path"` // JSON pointer into the specification (e.g. "/entities/0/fields/2/type")
+	Code     string `json:"code"` // "required", "unknown_type", "unknown_feature", "unknown_entity", "unknown_field", "duplicate_name", "invalid_identifier", "reserved_word", "invalid_value", "feature_conflict", "duplicate_number", "unused_value"
+	Message  string `json:"message"`
+	Severity string `json:"severity"` // "error", "warning"
+}
+
+// ValidationReport represents the result of validating a project specification.
+// The specification is valid when no issue has "error" severity.
+type ValidationReport struct {
+	Valid    bool              `json:"valid"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// TypeDefinition describes how a logical field type is generated
+type TypeDefinition struct {
+	Name        string `json:"name"`
+	GoType      string `json:"go_type"`
+	Import      string `json:"import,omitempty"`      // Import path GoType needs (e.g. "github.com/shopspring/decimal")
+	Module      string `json:"module,omitempty"`      // Module to require in go.mod for Import
+	Version     string `json:"version,omitempty"`     // Version of Module (e.g. "v1.4.0")
+	SQLType     string `json:"sql_type,omitempty"`    // PostgreSQL column type, defaults to "TEXT"
+	ZeroValue   string `json:"zero_value,omitempty"`  // Go zero value literal, derived from GoType when empty
+	JSONType    string `json:"json_type,omitempty"`   // JSON Schema type: "string", "integer", "number", "boolean", "array", "object"
+	JSONFormat  string `json:"json_format,omitempty"` // JSON Schema format (e.g. "date-time", "uuid")
+	Faker       string `json:"faker,omitempty"`       // Sample value strategy ("text", "number", "boolean", "time", "json", "list", "strings", "map", "bytes", "decimal") or a Go expression
+	Description string `json:"description,omitempty"`
+}
+
+// ModuleDependency represents a Go module required by generated code
+type ModuleDependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// BuiltinTypes are the logical field types available to every project. Projects register
+// additional types through ProjectSpecification.Types.
+var BuiltinTypes = []TypeDefinition{
+	{Name: "string", GoType: "string", SQLType: "TEXT", JSONType: "string", Faker: "text", Description: "Basic text string"},
+	{Name: "text", GoType: "string", SQLType: "TEXT", JSONType: "string", Faker: "text", Description: "Long text"},
+	{Name: "longtext", GoType: "string", SQLType: "TEXT", JSONType: "string", Faker: "text", Description: "Long text"},
+	{Name: "enum", GoType: "string", SQLType: "TEXT", JSONType: "string", Faker: "text", Description: "Predefined set of values"},
+	{Name: "email", GoType: "string", SQLType: "VARCHAR(320)", JSONType: "string", JSONFormat: "email", Faker: "text", Description: "Email address with validation"},
+	{Name: "password", GoType: "string", SQLType: "TEXT", JSONType: "string", JSONFormat: "password", Faker: "text", Description: "Password or other secret"},
+	{Name: "url", GoType: "string", SQLType: "TEXT", JSONType: "string", JSONFormat: "uri", Faker: "text", Description: "URL with validation"},
+	{Name: "uuid", GoType: "string", SQLType: "UUID", JSONType: "string", JSONFormat: "uuid", Faker: "text", Description: "Universally unique identifier"},
+	{Name: "id", GoType: "string", SQLType: "UUID", JSONType: "string", JSONFormat: "uuid", Faker: "text", Description: "Universally unique identifier"},
+	{Name: "integer", GoType: "int", SQLType: "INTEGER", JSONType: "integer", JSONFormat: "int64", Faker: "number", Description: "Whole number"},
+	{Name: "int", GoType: "int", SQLType: "INTEGER", JSONType: "integer", JSONFormat: "int64", Faker: "number", Description: "Whole number"},
+	{Name: "int32", GoType: "int32", SQLType: "INTEGER", JSONType: "integer", JSONFormat: "int32", Faker: "number", Description: "Whole number (32-bit)"},
+	{Name: "int64", GoType: "int64", SQLType: "BIGINT", JSONType: "integer", JSONFormat: "int64", Faker: "number", Description: "Large whole number (64-bit)"},
+	{Name: "float", GoType: "float64", SQLType: "DOUBLE PRECISION", JSONType: "number", JSONFormat: "double", Faker: "number", Description: "Decimal number (64-bit)"},
+	{Name: "float32", GoType: "float32", SQLType: "REAL", JSONType: "number", JSONFormat: "float", Faker: "number", Description: "Decimal number (32-bit)"},
+	{Name: "float64", GoType: "float64", SQLType: "DOUBLE PRECISION", JSONType: "number", JSONFormat: "double", Faker: "number", Description: "Decimal number (64-bit)"},
+	{Name: "boolean", GoType: "bool", SQLType: "BOOLEAN", JSONType: "boolean", Faker: "boolean", Description: "True/false value"},
+	{Name: "bool", GoType: "bool", SQLType: "BOOLEAN", JSONType: "boolean", Faker: "boolean", Description: "True/false value"},
+	// All time types are time.Time, which encodes as RFC 3339
+	{Name: "timestamp", GoType: "time.Time", Import: "time", SQLType: "TIMESTAMPTZ", JSONType: "string", JSONFormat: "date-time", Faker: "time", Description: "Date and time with timezone"},
+	{Name: "datetime", GoType: "time.Time", Import: "time", SQLType: "TIMESTAMPTZ", JSONType: "string", JSONFormat: "date-time", Faker: "time", Description: "Date and time with timezone"},
+	{Name: "date", GoType: "time.Time", Import: "time", SQLType: "DATE", JSONType: "string", JSONFormat: "date-time", Faker: "time", Description: "Date only"},
+	{Name: "time", GoType: "time.Time", Import: "time", SQLType: "TIMESTAMPTZ", JSONType: "string", JSONFormat: "date-time", Faker: "time", Description: "Date and time with timezone"},
+	{Name: "json", GoType: "json.RawMessage", Import: "encoding/json", SQLType: "JSONB", Faker: "json", Description: "JSON data structure"},
+	{Name: "jsonb", GoType: "json.RawMessage", Import: "encoding/json", SQLType: "JSONB", Faker: "json", Description: "JSON data structure"},
+	{Name: "array", GoType: "[]interface{}", SQLType: "JSONB", JSONType: "array", Faker: "list", Description: "Array of values"},
+	{Name: "slice", GoType: "[]string", SQLType: "TEXT[]", JSONType: "array", Faker: "strings", Description: "List of strings"},
+	{Name: "map", GoType: "map[string]interface{}", SQLType: "JSONB", JSONType: "object", Faker: "map", Description: "Key/value map"},
+	{Name: "binary", GoType: "[]byte", SQLType: "BYTEA", JSONType: "string", JSONFormat: "byte", Faker: "bytes", Description: "Binary data (byte array)"},
+	{Name: "bytes", GoType: "[]byte", SQLType: "BYTEA", JSONType: "string", JSONFormat: "byte", Faker: "bytes", Description: "Binary data (byte array)"},
+	{Name: "decimal", GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal", Module: "github.com/shopspring/decimal", Version: "v1.4.0", SQLType: "NUMERIC(19,4)", JSONType: "string", JSONFormat: "decimal", Faker: "decimal", Description: "High-precision decimal number"},
+	{Name: "money", GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal", Module: "github.com/shopspring/decimal", Version: "v1.4.0", SQLType: "NUMERIC(19,4)", JSONType: "string", JSONFormat: "decimal", Faker: "decimal", Description: "Monetary amount"},
+}
+
+// FeatureDefinition describes what a feature implements and how it relates to other features
+type FeatureDefinition struct {
+	Implementations []string `json:"implementations"`
+	Requires        []string `json:"requires,omitempty"`  // Enabled along with the feature
+	Conflicts       []string `json:"conflicts,omitempty"` // Cannot be enabled together with the feature
+	Optional        []string `json:"optional,omitempty"`  // Integrated with when also enabled, never enabled automatically
+}
+
+// FeatureMapping is the feature graph: each feature's implementation details and its relations
+var FeatureMapping = map[string]FeatureDefinition{
 	// Core features
-	"crud":        {"repository", "service", "handler", "validation"},
-	"validation":  {"validation_tags", "validation_functions", "sanitization"},
-	"rest_api":    {"gin_handlers", "swagger_docs", "middleware"},
-	"graphql_api": {"graphql_resolvers", "graphql_schema"},
-	"grpc_api":    {"grpc_service", "protobuf_definitions"},
+	"crud":        {Implementations: []string{"repository", "service", "handler", "validation"}, Requires: []string{"repository", "validation"}, Optional: []string{"rest_api", "testing"}},
+	"validation":  {Implementations: []string{"validation_tags", "validation_functions", "sanitization"}},
+	"rest_api":    {Implementations: []string{"gin_handlers", "swagger_docs", "middleware"}, Requires: []string{"handler", "service", "validation"}, Optional: []string{"repository", "security"}},
+	"handler":     {Implementations: []string{"http_handlers"}, Requires: []string{"service"}, Optional: []string{"repository"}},
+	"graphql_api": {Implementations: []string{"graphql_resolvers", "graphql_schema"}, Requires: []string{"service"}, Optional: []string{"repository"}},
+	"grpc_api":    {Implementations: []string{"grpc_service", "protobuf_definitions"}, Requires: []string{"service"}, Optional: []string{"repository"}},
 
 	// Data layer
-	"repository": {"database_repository", "query_builders"},
-	"cache":      {"redis_cache", "memory_cache"},
-	"events":     {"event_publisher", "event_subscribers"},
-	"migrations": {"database_migrations", "schema_versioning"},
+	"repository": {Implementations: []string{"database_repository", "query_builders"}, Optional: []string{"migrations"}},
+	"cache":      {Implementations: []string{"redis_cache", "memory_cache"}, Requires: []string{"repository"}, Optional: []string{"config"}},
+	"events":     {Implementations: []string{"event_publisher", "event_subscribers"}, Requires: []string{"repository"}, Optional: []string{"messaging", "migrations"}},
+	"migrations": {Implementations: []string{"database_migrations", "schema_versioning"}},
 
 	// Business layer
-	"service":   {"business_logic_service", "domain_service"},
-	"use_cases": {"application_use_cases", "command_handlers"},
-	"workflows": {"workflow_orchestration", "step_definitions"},
+	"service":   {Implementations: []string{"business_logic_service", "domain_service"}, Optional: []string{"repository", "validation"}},
+	"use_cases": {Implementations: []string{"application_use_cases", "command_handlers"}, Requires: []string{"service"}},
+	"workflows": {Implementations: []string{"workflow_orchestration", "step_definitions"}, Requires: []string{"service"}},
 
 	// Infrastructure layer
-	"monitoring":    {"metrics", "health_checks", "tracing"},
-	"logging":       {"structured_logging", "log_levels"},
-	"security":      {"authentication", "authorization", "encryption"},
-	"rate_limiting": {"rate_limiter", "throttling"},
+	"monitoring":    {Implementations: []string{"metrics", "health_checks", "tracing"}, Optional: []string{"logging"}},
+	"logging":       {Implementations: []string{"structured_logging", "log_levels"}},
+	"security":      {Implementations: []string{"authentication", "authorization", "encryption"}, Requires: []string{"config"}},
+	"rate_limiting": {Implementations: []string{"rate_limiter", "throttling"}, Optional: []string{"security", "monitoring"}},
 
 	// Integration features
-	"messaging":     {"message_queue", "pub_sub"},
-	"file_storage":  {"file_upload", "file_management"},
-	"notifications": {"email", "sms", "push_notifications"},
-	"search":        {"full_text_search", "indexing"},
+	"messaging":        {Implementations: []string{"message_queue", "pub_sub"}, Requires: []string{"config"}},
+	"file_storage":     {Implementations: []string{"file_upload", "file_management"}, Requires: []string{"config"}},
+	"notifications":    {Implementations: []string{"email", "sms", "push_notifications"}, Requires: []string{"config"}, Optional: []string{"messaging"}},
+	"search":           {Implementations: []string{"full_text_search", "indexing"}, Requires: []string{"repository"}},
+	"queue_processing": {Implementations: []string{"job_queue", "worker_pool", "job_scheduler"}, Requires: []string{"config"}, Optional: []string{"messaging", "monitoring"}, Conflicts: []string{"cli"}},
 
 	// Development features
-	"testing":       {"unit_tests", "integration_tests", "test_fixtures"},
-	"documentation": {"swagger", "api_docs", "code_comments"},
-	"cli":           {"cobra_commands", "flag_parsing"},
-	"config":        {"environment_config", "config_validation"},
+	"testing":       {Implementations: []string{"unit_tests", "integration_tests", "test_fixtures"}, Optional: []string{"repository", "validation", "handler"}},
+	"documentation": {Implementations: []string{"swagger", "api_docs", "code_comments"}, Optional: []string{"rest_api"}},
+	"cli":           {Implementations: []string{"cobra_commands", "flag_parsing"}, Requires: []string{"config"}, Conflicts: []string{"rest_api", "handler", "graphql_api", "grpc_api"}},
+	"config":        {Implementations: []string{"environment_config", "config_validation"}},
 }
 
 // ProjectTypeMapping maps project types to their default features and structure
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"go-factory-platform/services/orchestrator-service/internal/application"
@@ -11,9 +13,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// maxYAMLBody is the size limit of YAML request bodies
+const maxYAMLBody = 1 << 20
+
 // OrchestratorHandler handles HTTP requests for orchestration
 type OrchestratorHandler struct {
-	service *application.OrchestratorService
+	service            *application.OrchestratorService
+	specBaseDir        string
+	introspectionHosts map[string]bool
 }
 
 // NewOrchestratorHandler creates a new orchestrator handler
@@ -23,11 +30,40 @@ func NewOrchestratorHandler(service *application.OrchestratorService) *Orchestra
 	}
 }
 
+// SetSpecBaseDir enables "$ref" includes in YAML specifications, resolved within dir
+func (h *OrchestratorHandler) SetSpecBaseDir(dir string) {
+	h.specBaseDir = dir
+}
+
+// SetIntrospectionHosts enables database introspection for the databases of hosts. Introspection
+// connects wherever requests ask, so it is disabled until the hosts it may reach are listed.
+func (h *OrchestratorHandler) SetIntrospectionHosts(hosts ...string) {
+	h.introspectionHosts = make(map[string]bool)
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			h.introspectionHosts[host] = true
+		}
+	}
+}
+
+// bindRequest decodes the request body as YAML when the content type asks for it and as JSON otherwise
+func (h *OrchestratorHandler) bindRequest(c *gin.Context, target interface{}) error {
+	switch c.ContentType() {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxYAMLBody))
+		if err != nil {
+			return err
+		}
+		return h.service.DecodeYAML(data, h.specBaseDir, target)
+	}
+	return c.ShouldBindJSON(target)
+}
+
 // OrchestrateMicroservice handles microservice orchestration requests
 // @Summary Orchestrate microservice generation
 // @Description Convert high-level project specification to detailed generator payload
 // @Tags orchestrator
-// @Accept json
+// @Accept json,yaml
 // @Produce json
 // @Param project body domain.ProjectSpecification true "Project specification"
 // @Success 200 {object} domain.OrchestrationResult
@@ -36,14 +72,13 @@ func NewOrchestratorHandler(service *application.OrchestratorService) *Orchestra
 // @Router /api/v1/orchestrate/microservice [post]
 func (h *OrchestratorHandler) OrchestrateMicroservice(c *gin.Context) {
 	var spec domain.ProjectSpecification
-	if err := c.ShouldBindJSON(&spec); err != nil {
+	if err := h.bindRequest(c, &spec); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
 		return
 	}
 
 	// Validate specification
-	if err := h.validateProjectSpecification(&spec); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid specification: " + err.Error()})
+	if h.rejectInvalidSpecification(c, &spec) {
 		return
 	}
 
@@ -60,7 +95,7 @@ func (h *OrchestratorHandler) OrchestrateMicroservice(c *gin.Context) {
 // @Summary Get generator payload
 // @Description Convert project specification to generator payload only
 // @Tags orchestrator
-// @Accept json
+// @Accept json,yaml
 // @Produce json
 // @Param project body domain.ProjectSpecification true "Project specification"
 // @Success 200 {object} domain.GeneratorPayload
@@ -69,14 +104,13 @@ func (h *OrchestratorHandler) OrchestrateMicroservice(c *gin.Context) {
 // @Router /api/v1/orchestrate/payload [post]
 func (h *OrchestratorHandler) GetGeneratorPayload(c *gin.Context) {
 	var spec domain.ProjectSpecification
-	if err := c.ShouldBindJSON(&spec); err != nil {
+	if err := h.bindRequest(c, &spec); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
 		return
 	}
 
 	// Validate specification
-	if err := h.validateProjectSpecification(&spec); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid specification: " + err.Error()})
+	if h.rejectInvalidSpecification(c, &spec) {
 		return
 	}
 
@@ -93,7 +127,7 @@ func (h *OrchestratorHandler) GetGeneratorPayload(c *gin.Context) {
 // @Summary Create entity payload
 // @Description Convert single entity specification to generator payload
 // @Tags orchestrator
-// @Accept json
+// @Accept json,yaml
 // @Produce json
 // @Param entity body CreateEntityRequest true "Entity specification"
 // @Success 200 {object} domain.GeneratorPayload
@@ -102,7 +136,7 @@ func (h *OrchestratorHandler) GetGeneratorPayload(c *gin.Context) {
 // @Router /api/v1/orchestrate/entity [post]
 func (h *OrchestratorHandler) CreateEntityPayload(c *gin.Context) {
 	var req CreateEntityRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := h.bindRequest(c, &req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
 		return
 	}
@@ -125,6 +159,94 @@ func (h *OrchestratorHandler) CreateEntityPayload(c *gin.Context) {
 	c.JSON(http.StatusOK, result.GeneratorPayload)
 }
 
+// ValidateSpecification handles specification validation requests
+// @Summary Validate project specification
+// @Description Check a project specification and report every issue with a JSON pointer to the offending value
+// @Tags orchestrator
+// @Accept json,yaml
+// @Produce json
+// @Param project body domain.ProjectSpecification true "Project specification"
+// @Success 200 {object} domain.ValidationReport
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/orchestrate/validate [post]
+func (h *OrchestratorHandler) ValidateSpecification(c *gin.Context) {
+	var spec domain.ProjectSpecification
+	if err := h.bindRequest(c, &spec); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.ValidateSpecification(&spec))
+}
+
+// IntrospectDatabase handles database introspection requests
+// @Summary Introspect database
+// @Description Reverse-engineer a project specification from the tables, constraints, indexes and foreign keys of a PostgreSQL schema
+// @Tags orchestrator
+// @Accept json,yaml
+// @Produce json
+// @Param request body domain.IntrospectionRequest true "Database connection and filters"
+// @Success 200 {object} domain.ProjectSpecification
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/v1/orchestrate/introspect [post]
+func (h *OrchestratorHandler) IntrospectDatabase(c *gin.Context) {
+	if len(h.introspectionHosts) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "database introspection is not enabled"})
+		return
+	}
+
+	var req domain.IntrospectionRequest
+	if err := h.bindRequest(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if req.Connection.Host == "" || req.Connection.User == "" || req.Connection.Database == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "connection host, user and database are required"})
+		return
+	}
+	if !h.introspectionHosts[strings.ToLower(req.Connection.Host)] {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("host %q is not allowed for introspection", req.Connection.Host)})
+		return
+	}
+
+	spec, err := h.service.IntrospectDatabase(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to introspect database: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, spec)
+}
+
+// ImportGoStructs handles Go struct import requests
+// @Summary Import Go structs
+// @Description Convert Go struct definitions to entity specifications; paths are resolved within SPEC_BASE_DIR
+// @Tags orchestrator
+// @Accept json,yaml
+// @Produce json
+// @Param request body domain.StructImportRequest true "Go source files and filters"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/orchestrate/import/go [post]
+func (h *OrchestratorHandler) ImportGoStructs(c *gin.Context) {
+	var req domain.StructImportRequest
+	if err := h.bindRequest(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	entities, err := h.service.ImportGoStructs(&req, h.specBaseDir)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to import Go structs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entities": entities})
+}
+
 // HealthCheck handles health check requests
 // @Summary Health check
 // @Description Check if the orchestrator service is healthy
@@ -154,6 +276,9 @@ func (h *OrchestratorHandler) RegisterRoutes(router *gin.Engine) {
 			orchestrate.POST("/microservice", h.OrchestrateMicroservice)
 			orchestrate.POST("/payload", h.GetGeneratorPayload)
 			orchestrate.POST("/entity", h.CreateEntityPayload)
+			orchestrate.POST("/validate", h.ValidateSpecification)
+			orchestrate.POST("/introspect", h.IntrospectDatabase)
+			orchestrate.POST("/import/go", h.ImportGoStructs)
 
 			// Project type specific orchestration
 			orchestrate.POST("/api", h.OrchestrateAPI)
@@ -169,6 +294,7 @@ func (h *OrchestratorHandler) RegisterRoutes(router *gin.Engine) {
 			info.GET("/project-types", h.GetProjectTypes)
 			info.GET("/features", h.GetAvailableFeatures)
 			info.GET("/types", h.GetAvailableTypes)
+			info.GET("/schema", h.GetSpecificationSchema)
 		}
 	}
 }
@@ -185,53 +311,27 @@ type CreateEntityRequest struct {
 
 // Validation methods
 
-func (h *OrchestratorHandler) validateProjectSpecification(spec *domain.ProjectSpecification) error {
-	if spec.Name == "" {
-		return fmt.Errorf("project name is required")
-	}
-
-	if spec.ModulePath == "" {
-		return fmt.Errorf("module path is required")
-	}
-
-	if spec.OutputPath == "" {
-		return fmt.Errorf("output path is required")
-	}
-
-	if len(spec.Entities) == 0 {
-		return fmt.Errorf("at least one entity is required")
-	}
-
-	// Validate each entity
-	for i, entity := range spec.Entities {
-		if err := h.validateEntitySpecification(&entity); err != nil {
-			return fmt.Errorf("entity %d (%s): %w", i, entity.Name, err)
+// rejectInvalidSpecification writes a 400 response listing every error of a specification and
+// reports whether the request was rejected. Warnings do not block orchestration;
+// POST /api/v1/orchestrate/validate reports them as well.
+func (h *OrchestratorHandler) rejectInvalidSpecification(c *gin.Context, spec *domain.ProjectSpecification) bool {
+	var issues []domain.ValidationIssue
+	for _, issue := range h.service.ValidateSpecification(spec).Issues {
+		if issue.Severity == "error" {
+			issues = append(issues, issue)
 		}
 	}
-
-	return nil
-}
-
-func (h *OrchestratorHandler) validateEntitySpecification(entity *domain.EntitySpecification) error {
-	if entity.Name == "" {
-		return fmt.Errorf("entity name is required")
+	if len(issues) == 0 {
+		return false
 	}
 
-	if len(entity.Fields) == 0 {
-		return fmt.Errorf("at least one field is required")
+	message := fmt.Sprintf("Invalid specification: %s: %s", issues[0].Path, issues[0].Message)
+	if len(issues) > 1 {
+		message += fmt.Sprintf(" (and %d more errors)", len(issues)-1)
 	}
 
-	// Validate each field
-	for i, field := range entity.Fields {
-		if field.Name == "" {
-			return fmt.Errorf("field %d: name is required", i)
-		}
-		if field.Type == "" {
-			return fmt.Errorf("field %d (%s): type is required", i, field.Name)
-		}
-	}
-
-	return nil
+	c.JSON(http.StatusBadRequest, gin.H{"error": message, "issues": issues})
+	return true
 }
 
 // Project type specific orchestration handlers
@@ -264,7 +364,7 @@ func (h *OrchestratorHandler) OrchestrateWorker(c *gin.Context) {
 // orchestrateProjectType is a helper method for project type specific orchestration
 func (h *OrchestratorHandler) orchestrateProjectType(c *gin.Context, projectType string) {
 	var spec domain.ProjectSpecification
-	if err := c.ShouldBindJSON(&spec); err != nil {
+	if err := h.bindRequest(c, &spec); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
 		return
 	}
@@ -273,8 +373,7 @@ func (h *OrchestratorHandler) orchestrateProjectType(c *gin.Context, projectType
 	spec.ProjectType = projectType
 
 	// Validate specification
-	if err := h.validateProjectSpecification(&spec); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid specification: " + err.Error()})
+	if h.rejectInvalidSpecification(c, &spec) {
 		return
 	}
 
@@ -312,28 +411,43 @@ func (h *OrchestratorHandler) GetProjectTypes(c *gin.Context) {
 func (h *OrchestratorHandler) GetAvailableFeatures(c *gin.Context) {
 	features := make(map[string]interface{})
 
-	for feature, implementations := range domain.FeatureMapping {
+	for feature, definition := range domain.FeatureMapping {
+		_, plugin := h.service.Features().Lookup(feature)
 		features[feature] = map[string]interface{}{
-			"implementations": implementations,
+			"implementations": definition.Implementations,
+			"requires":        definition.Requires,
+			"conflicts":       definition.Conflicts,
+			"optional":        definition.Optional,
+			"plugin":          plugin,
 			"description":     h.getFeatureDescription(feature),
 		}
 	}
 
+	// Features provided only by a registered plugin
+	for _, feature := range h.service.Features().Names() {
+		if _, exists := features[feature]; exists {
+			continue
+		}
+		plugin, _ := h.service.Features().Lookup(feature)
+		features[feature] = map[string]interface{}{
+			"requires":    plugin.Dependencies(),
+			"plugin":      true,
+			"description": h.getFeatureDescription(feature),
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"features": features,
 		"count":    len(features),
 	})
 }
 
-// GetAvailableTypes returns all available field types and their Go mappings
+// GetAvailableTypes returns all built-in field types with their Go, SQL and JSON representations
 func (h *OrchestratorHandler) GetAvailableTypes(c *gin.Context) {
 	types := make(map[string]interface{})
 
-	for userType, goType := range domain.TypeMapping {
-		types[userType] = map[string]interface{}{
-			"go_type":     goType,
-			"description": h.getTypeDescription(userType),
-		}
+	for _, def := range h.service.Types().Definitions() {
+		types[def.Name] = def
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -342,6 +456,18 @@ func (h *OrchestratorHandler) GetAvailableTypes(c *gin.Context) {
 	})
 }
 
+// GetSpecificationSchema returns the JSON Schema of ProjectSpecification for editors and offline validation
+// @Summary Project specification JSON Schema
+// @Description JSON Schema (draft 2020-12) of ProjectSpecification with enumerations derived from the live mappings
+// @Tags info
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/info/schema [get]
+func (h *OrchestratorHandler) GetSpecificationSchema(c *gin.Context) {
+	c.Header("Content-Type", "application/schema+json")
+	c.JSON(http.StatusOK, h.service.SpecificationSchema())
+}
+
 // Helper methods for descriptions
 
 func (h *OrchestratorHandler) getProjectTypeDescription(projectType string) string {
@@ -381,25 +507,3 @@ func (h *OrchestratorHandler) getFeatureDescription(feature string) string {
 	}
 	return "Custom feature implementation"
 }
-
-func (h *OrchestratorHandler) getTypeDescription(fieldType string) string {
-	descriptions := map[string]string{
-		"string":    "Basic text string",
-		"integer":   "Whole number (32-bit)",
-		"int64":     "Large whole number (64-bit)",
-		"float":     "Decimal number (64-bit)",
-		"boolean":   "True/false value",
-		"uuid":      "Universally unique identifier",
-		"email":     "Email address with validation",
-		"timestamp": "Date and time with timezone",
-		"json":      "JSON data structure",
-		"array":     "Array of values",
-		"decimal":   "High-precision decimal number",
-		"enum":      "Predefined set of values",
-		"binary":    "Binary data (byte array)",
-	}
-	if desc, exists := descriptions[fieldType]; exists {
-		return desc
-	}
-	return "Custom data type"
-}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-factory-platform/services/orchestrator-service/internal/application"
+	"go-factory-platform/services/orchestrator-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIntrospectDatabaseAllowedHosts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name  string
+		hosts []string
+		host  string
+		want  int
+	}{
+		{name: "disabled", host: "127.0.0.1", want: http.StatusForbidden},
+		{name: "host not listed", hosts: []string{"db.internal"}, host: "127.0.0.1", want: http.StatusForbidden},
+		{name: "listed host", hosts: []string{" 127.0.0.1", "db.internal"}, host: "127.0.0.1", want: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewOrchestratorHandler(application.NewOrchestratorService())
+			if tt.hosts != nil {
+				h.SetIntrospectionHosts(tt.hosts...)
+			}
+			router := gin.New()
+			h.RegisterRoutes(router)
+
+			// Nothing listens on port 1, so a listed host fails to connect
+			body := `{"connection": {"host": "` + tt.host + `", "port": 1, "user": "shop", "database": "shop"}}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orchestrate/introspect", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestValidateSpecification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	invalidJSON := `{
+		"name": "shop", "module_path": "example.com/shop", "output_path": "out", "project_type": "microservice",
+		"features": ["config", "telepathy"],
+		"entities": [{
+			"name": "Order", "features": ["crud"],
+			"fields": [{"name": "total", "type": "widget"}, {"name": "type", "type": "string"}],
+			"relationships": [{"name": "customer", "type": "belongs_to", "target": "Customer"}]
+		}]
+	}`
+	invalidYAML := `name: shop
+module_path: example.com/shop
+output_path: out
+project_type: microservice
+features: [config, telepathy]
+entities:
+  - name: Order
+    features: [crud]
+    fields:
+      - {name: total, type: widget}
+      - {name: type, type: string}
+    relationships:
+      - {name: customer, type: belongs_to, target: Customer}
+`
+	invalidIssues := []domain.ValidationIssue{
+		{Path: "/features/1", Code: "unknown_feature", Severity: "warning"},
+		{Path: "/entities/0/fields/0/type", Code: "unknown_type", Severity: "error"},
+		{Path: "/entities/0/fields/1/name", Code: "reserved_word", Severity: "error"},
+		{Path: "/entities/0/relationships/0/target", Code: "unknown_entity", Severity: "error"},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+		errors      int
+		warnings    int
+		issues      []domain.ValidationIssue
+	}{
+		{name: "json", contentType: "application/json", body: invalidJSON, want: http.StatusOK, errors: 3, warnings: 1, issues: invalidIssues},
+		{name: "yaml", contentType: "application/yaml", body: invalidYAML, want: http.StatusOK, errors: 3, warnings: 1, issues: invalidIssues},
+		{
+			name:        "valid",
+			contentType: "application/json",
+			body: `{"name": "shop", "module_path": "example.com/shop", "output_path": "out", "project_type": "microservice",
+				"entities": [{"name": "Order", "features": ["crud"], "fields": [{"name": "total", "type": "integer"}]}]}`,
+			want: http.StatusOK,
+		},
+		{name: "malformed", contentType: "application/json", body: `{"name": `, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			NewOrchestratorHandler(application.NewOrchestratorService()).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orchestrate/validate", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var report domain.ValidationReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+			if report.Valid != (tt.errors == 0) || report.Errors != tt.errors || report.Warnings != tt.warnings {
+				t.Errorf("report valid = %v with %d errors and %d warnings, want %d errors and %d warnings",
+					report.Valid, report.Errors, report.Warnings, tt.errors, tt.warnings)
+			}
+			if len(report.Issues) != len(tt.issues) {
+				t.Fatalf("issues = %+v, want %+v", report.Issues, tt.issues)
+			}
+			for i, want := range tt.issues {
+				got := report.Issues[i]
+				if got.Path != want.Path || got.Code != want.Code || got.Severity != want.Severity || got.Message == "" {
+					t.Errorf("issue %d = %+v, want %s %s at %s", i, got, want.Severity, want.Code, want.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSpecificationSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewOrchestratorHandler(application.NewOrchestratorService()).RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/info/schema", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/schema+json") {
+		t.Errorf("Content-Type = %q, want application/schema+json", got)
+	}
+
+	var schema struct {
+		Schema string                     `json:"$schema"`
+		Ref    string                     `json:"$ref"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Schema != "https://json-schema.org/draft/2020-12/schema" || schema.Defs[strings.TrimPrefix(schema.Ref, "#/$defs/")] == nil {
+		t.Errorf("schema = %s %s with %d definitions, want a draft 2020-12 schema of a definition", schema.Schema, schema.Ref, len(schema.Defs))
+	}
+}
@@ -23,6 +23,7 @@ type Config struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	ConnectTimeout  time.Duration // Limit on establishing each connection; none when zero
 }
 
 // Connection holds database connections
@@ -43,6 +44,10 @@ func NewConnection(config *Config) (*Connection, error) {
 		config.Database,
 		config.SSLMode,
 	)
+	if config.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds, rounded up so that short limits still apply
+		dsn += fmt.Sprintf(" connect_timeout=%d", (config.ConnectTimeout+time.Second-1)/time.Second)
+	}
 
 	// Configure GORM logger
 	newLogger := logger.New(